@@ -1,11 +1,17 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 	"github.com/Hell0W0rID/edgex-go-clone/internal/application/service"
 )
 
@@ -13,21 +19,60 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.AppServiceConfigurableKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59700",
+		ServiceName:        common.AppServiceConfigurableKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		ContentTypes:       []string{common.ContentTypeJSON, common.ContentTypeYAML},
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
 	// Initialize application service
 	appService := service.NewApplicationService(logger)
 
+	// HTTP export targets resolve their credentials from the secret store
+	appService.SetSecretsClient(secrets.NewInMemorySecretsClient(logger))
+
+	// Keep pipelines across restarts when a store file is configured
+	if config.Pipelines.StoreFile != "" {
+		store, err := service.NewFilePipelineStore(config.Pipelines.StoreFile)
+		if err != nil {
+			logger.Fatalf("Failed to open pipeline store: %v", err)
+		}
+		logger.Infof("Using pipeline store file %s", config.Pipelines.StoreFile)
+		appService.SetStore(store)
+	}
+
+	// Feed message bus pipelines from the events topic when a bus is configured
+	if config.MessageBus.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.MessageBus.Host, config.MessageBus.Port)
+		messageClient := messaging.NewRedisMessageClient(address, "", 0, logger)
+		if err := messageClient.Connect(); err != nil {
+			logger.Fatalf("Failed to connect to message bus: %v", err)
+		}
+		defer messageClient.Disconnect()
+
+		appService.SetMessageClient(messageClient, messaging.MessageTopics.Events)
+	}
+
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
 		appService,
@@ -40,4 +85,22 @@ func main() {
 
 	// Bootstrap the service
 	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+}
+// configuration is the App Service Configurable service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Pipelines            pipelinesConfig `json:"Pipelines" yaml:"Pipelines" toml:"Pipelines"`
+}
+
+// pipelinesConfig says where pipelines are kept across restarts, e.g.
+// APP_PIPELINE_STORE_FILE=/var/lib/edgex/pipelines.json; without one they only live in memory
+type pipelinesConfig struct {
+	StoreFile string `json:"StoreFile" yaml:"StoreFile" toml:"StoreFile" env:"APP_PIPELINE_STORE_FILE"`
+}
+
+// newConfiguration returns the default App Service Configurable configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59700),
+	}
+}
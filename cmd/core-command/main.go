@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
@@ -13,20 +17,53 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := bootstrap.NewBaseConfig(59882)
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.CoreCommandServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59882",
+		ServiceName:        common.CoreCommandServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
-	// Initialize core command service
-	commandService := command.NewCoreCommandService(logger)
+	// Initialize core command service, keeping command responses in Redis when configured
+	var commandService *command.CoreCommandService
+	if config.Database.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.Database.Host, config.Database.Port)
+
+		// Credentials come from DATABASE_USERNAME and DATABASE_PASSWORD
+		secretProvider, err := bootstrap.NewSecretProvider(common.CoreCommandServiceKey, config.Database, logger)
+		if err != nil {
+			logger.Fatalf("Failed to set up secrets: %v", err)
+		}
+		store := command.NewRedisCommandResponseStore(address, 0, secretProvider, logger)
+		if err := store.Connect(); err != nil {
+			logger.Fatalf("Failed to initialize command response store: %v", err)
+		}
+		defer store.Close()
+		bootstrap.RegisterHealthCheck("database", store.Ping)
+
+		logger.Infof("Using Redis command response store at %s", address)
+		commandService = command.NewCoreCommandServiceWithStore(logger, store)
+	} else {
+		commandService = command.NewCoreCommandService(logger)
+	}
 
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
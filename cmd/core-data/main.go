@@ -1,14 +1,16 @@
 package main
 
 import (
-	"context"
-	"net/http"
+	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
 	"github.com/Hell0W0rID/edgex-go-clone/internal/core/data"
 )
 
@@ -16,20 +18,69 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.CoreDataServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59880",
+		ServiceName:        common.CoreDataServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		ContentTypes:       []string{common.ContentTypeJSON, common.ContentTypeCBOR},
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
+
+	// Initialize core data service, backed by Redis when configured
+	var dataService *data.CoreDataService
+	if config.Database.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.Database.Host, config.Database.Port)
+
+		// Credentials come from DATABASE_USERNAME and DATABASE_PASSWORD
+		secretProvider, err := bootstrap.NewSecretProvider(common.CoreDataServiceKey, config.Database, logger)
+		if err != nil {
+			logger.Fatalf("Failed to set up secrets: %v", err)
+		}
+		store := data.NewRedisEventStore(address, 0, secretProvider, logger)
+		if err := store.Connect(); err != nil {
+			logger.Fatalf("Failed to initialize event store: %v", err)
+		}
+		defer store.Close()
+
+		logger.Infof("Using Redis event store at %s", address)
+		dataService = data.NewCoreDataServiceWithStore(logger, store)
+	} else {
+		store := data.NewMemoryEventStoreWithCapacity(config.Data.MaxEvents, logger)
+		dataService = data.NewCoreDataServiceWithStore(logger, store)
+	}
 
-	// Initialize core data service
-	dataService := data.NewCoreDataService(logger)
+	// Publish stored events on the message bus when one is configured
+	if config.MessageBus.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.MessageBus.Host, config.MessageBus.Port)
+		messageClient := messaging.NewRedisMessageClient(address, "", 0, logger)
+		if err := messageClient.Connect(); err != nil {
+			logger.Fatalf("Failed to connect to message bus: %v", err)
+		}
+		defer messageClient.Disconnect()
+
+		dataService.SetMessageClient(messageClient, messaging.MessageTopics.Events)
+	}
+
+	// Keep Core Metadata's lastReported current when CORE_DATA_METADATA_URL is set
+	dataService.SetMetadataURL(config.Data.MetadataURL)
 
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
@@ -43,4 +94,27 @@ func main() {
 
 	// Bootstrap the service
 	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+}
+
+// configuration is the Core Data service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Data                 dataConfig `json:"Data" yaml:"Data" toml:"Data"`
+}
+
+// dataConfig bounds the in-memory event store, e.g. CORE_DATA_MAX_EVENTS=500000; zero or less is unbounded
+type dataConfig struct {
+	MaxEvents int `json:"MaxEvents" yaml:"MaxEvents" toml:"MaxEvents" env:"CORE_DATA_MAX_EVENTS"`
+	// MetadataURL is where Core Metadata is told which devices sent events, e.g. http://localhost:59881
+	MetadataURL string `json:"MetadataURL" yaml:"MetadataURL" toml:"MetadataURL" env:"CORE_DATA_METADATA_URL"`
+}
+
+// newConfiguration returns the default Core Data configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59880),
+		Data: dataConfig{
+			MaxEvents: data.DefaultMaxEvents,
+		},
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 	"github.com/Hell0W0rID/edgex-go-clone/internal/core/metadata"
 )
 
@@ -13,31 +18,102 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.CoreMetaDataServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59881",
+		ServiceName:        common.CoreMetaDataServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		ContentTypes:       []string{common.ContentTypeJSON, common.ContentTypeMultipartForm},
+		Config:             config,
+	}
+
+	// Verify bearer tokens when AUTH_JWT_ENABLED is set; the signing key comes from AUTH_JWT_SIGNING_KEY.
+	// Bootstrap installs the check after its standard middleware, so rejected requests are logged.
+	serviceInfo.Middleware, err = authMiddleware(config, logger)
+	if err != nil {
+		logger.Fatalf("Failed to enable JWT authentication: %v", err)
+	}
+
+	router, metadataService := newRouter(config, serviceInfo, logger)
+
+	// Create bootstrap handlers
+	handlers := []bootstrap.BootstrapHandler{
+		metadataService,
+	}
+
+	logger.Infof("Starting %s service", serviceInfo.ServiceName)
+
+	// Bootstrap the service
+	bootstrap.Bootstrap(serviceInfo, handlers, router)
+}
+
+// authMiddleware returns the JWT verification core-metadata serves its routes with, or none when
+// authentication is off
+func authMiddleware(config configuration, logger *logrus.Logger) ([]mux.MiddlewareFunc, error) {
+	if !config.Auth.JWT {
+		return nil, nil
 	}
+	auth, err := bootstrap.NewJWTAuth(config.Auth, secrets.NewInMemorySecretsClient(logger))
+	if err != nil {
+		return nil, err
+	}
+	return []mux.MiddlewareFunc{auth}, nil
+}
 
+// newRouter creates the Core Metadata service and a router serving its routes
+func newRouter(config configuration, serviceInfo bootstrap.ServiceInfo, logger *logrus.Logger) (*mux.Router, *metadata.CoreMetadataService) {
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
 	// Initialize core metadata service
 	metadataService := metadata.NewCoreMetadataService(logger)
 
-	// Create bootstrap handlers
-	handlers := []bootstrap.BootstrapHandler{
-		metadataService,
+	metadataService.SetOperatingStateSweep(config.Metadata.SweepInterval, config.Metadata.DownAfter)
+
+	// With tokens verified, deletes are reserved for admins
+	if config.Auth.JWT {
+		metadataService.RequireAdminForDeletes()
 	}
 
 	// Add service-specific routes
 	metadataService.AddRoutes(router)
 
-	logger.Infof("Starting %s service", serviceInfo.ServiceName)
+	return router, metadataService
+}
 
-	// Bootstrap the service
-	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+// configuration is the Core Metadata service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Metadata             metadataConfig `json:"Metadata" yaml:"Metadata" toml:"Metadata"`
+}
+
+// metadataConfig controls device operating state tracking, e.g. METADATA_DEVICE_DOWN_AFTER=5m marks
+// devices DOWN after five minutes without contact. Devices are never marked DOWN while it is zero.
+type metadataConfig struct {
+	SweepInterval time.Duration `json:"SweepInterval" yaml:"SweepInterval" toml:"SweepInterval" env:"METADATA_SWEEP_INTERVAL"`
+	DownAfter     time.Duration `json:"DownAfter" yaml:"DownAfter" toml:"DownAfter" env:"METADATA_DEVICE_DOWN_AFTER"`
+}
+
+// newConfiguration returns the default Core Metadata configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59881),
+		Metadata: metadataConfig{
+			SweepInterval: metadata.DefaultSweepInterval,
+		},
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+const testSigningKey = "test-signing-key"
+
+// newTestRouter returns core-metadata's router with the middleware Bootstrap serves it with, and a hook
+// capturing what it logs
+func newTestRouter(t *testing.T, config configuration) (*mux.Router, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	serviceInfo := bootstrap.ServiceInfo{
+		ServiceName:    common.CoreMetaDataServiceKey,
+		ServiceVersion: common.ServiceVersion,
+		Config:         config,
+	}
+	var err error
+	serviceInfo.Middleware, err = authMiddleware(config, logger)
+	require.NoError(t, err)
+
+	router, _ := newRouter(config, serviceInfo, logger)
+	dic := bootstrap.NewDIContainer()
+	dic.Add(common.LoggingClientName, logger)
+	require.NoError(t, bootstrap.ConfigureRouter(router, dic, serviceInfo))
+	bootstrap.DefaultHealthRegistry().MarkStarted()
+	return router, hook
+}
+
+// signedToken signs a token granting roles with the test signing key
+func signedToken(t *testing.T, roles ...string) string {
+	claims := bootstrap.RoleClaims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "operator",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSigningKey))
+	require.NoError(t, err)
+	return token
+}
+
+// addDevice registers a device through the router and returns its id
+func addDevice(t *testing.T, router *mux.Router, name string) string {
+	body, err := json.Marshal(models.Device{Name: name})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, common.ApiDeviceRoute, bytes.NewReader(body))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response["id"].(string)
+}
+
+func TestNewRouter_JWTAuth(t *testing.T) {
+	config := newConfiguration()
+	config.Auth.JWT = true
+	config.Auth.SigningKey = testSigningKey
+
+	router, hook := newTestRouter(t, config)
+	id := addDevice(t, router, "Thermostat-1")
+
+	deleteDevice := func(token string) int {
+		req := httptest.NewRequest(http.MethodDelete, common.ApiDeviceRoute+"/id/"+id, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// A rejected token is answered after the standard middleware, so it is correlated and access logged
+	hook.Reset()
+	req := httptest.NewRequest(http.MethodGet, common.ApiDeviceRoute+"/id/"+id, nil)
+	req.Header.Set("Authorization", "Bearer not-a-jwt")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	correlationID := rr.Header().Get(common.CorrelationHeader)
+	assert.NotEmpty(t, correlationID)
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, http.StatusUnauthorized, hook.LastEntry().Data["status"])
+	assert.Equal(t, correlationID, hook.LastEntry().Data["correlationId"])
+
+	assert.Equal(t, http.StatusUnauthorized, deleteDevice(""))
+	assert.Equal(t, http.StatusForbidden, deleteDevice(signedToken(t, "operator")))
+	assert.Equal(t, http.StatusOK, deleteDevice(signedToken(t, bootstrap.RoleAdmin)))
+	assert.Equal(t, http.StatusNotFound, deleteDevice(signedToken(t, bootstrap.RoleAdmin)))
+}
+
+func TestNewRouter_JWTAuthWithoutSigningKey(t *testing.T) {
+	config := newConfiguration()
+	config.Auth.JWT = true
+
+	_, err := authMiddleware(config, logrus.New())
+	assert.Error(t, err)
+}
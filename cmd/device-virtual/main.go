@@ -1,11 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
 	"github.com/Hell0W0rID/edgex-go-clone/internal/device/virtual"
 )
 
@@ -13,21 +18,51 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.DeviceVirtualServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59900",
+		ServiceName:        common.DeviceVirtualServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
 	// Initialize device virtual service
 	deviceService := virtual.NewDeviceVirtualService(logger)
 
+	// Send readings to core-data, or publish them on the message bus when one is configured
+	deviceService.SetCoreDataURL(config.Device.CoreDataURL)
+	deviceService.SetDryRun(config.Device.DryRun)
+	deviceService.SetMaxBatchSize(config.Device.MaxBatchSize)
+	deviceService.SetHistorySize(config.Device.HistorySize)
+	if config.MessageBus.Host != "" && !config.Device.DryRun {
+		address := fmt.Sprintf("%s:%d", config.MessageBus.Host, config.MessageBus.Port)
+		messageClient := messaging.NewRedisMessageClient(address, "", 0, logger)
+		if err := messageClient.Connect(); err != nil {
+			logger.Fatalf("Failed to connect to message bus: %v", err)
+		}
+		defer messageClient.Disconnect()
+		bootstrap.RegisterHealthCheck("messagebus", messageClient.Ping)
+
+		deviceService.SetMessageClient(messageClient, config.Device.Topic)
+	}
+
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
 		deviceService,
@@ -40,4 +75,36 @@ func main() {
 
 	// Bootstrap the service
 	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+}
+
+// configuration is the Device Virtual service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Device               deviceConfig `json:"Device" yaml:"Device" toml:"Device"`
+}
+
+// deviceConfig says where generated readings go: POSTed to core-data at CoreDataURL, e.g.
+// CORE_DATA_URL=http://core-data:59880, published to Topic when the message bus is configured,
+// or, with DEVICE_VIRTUAL_DRY_RUN=true, only logged. MaxBatchSize caps the devices one batch or
+// clone request creates, e.g. DEVICE_VIRTUAL_MAX_BATCH_SIZE=5000, and HistorySize the readings kept per
+// device, e.g. DEVICE_VIRTUAL_HISTORY_SIZE=500
+type deviceConfig struct {
+	CoreDataURL  string `json:"CoreDataURL" yaml:"CoreDataURL" toml:"CoreDataURL" env:"CORE_DATA_URL"`
+	Topic        string `json:"Topic" yaml:"Topic" toml:"Topic" env:"DEVICE_VIRTUAL_TOPIC"`
+	DryRun       bool   `json:"DryRun" yaml:"DryRun" toml:"DryRun" env:"DEVICE_VIRTUAL_DRY_RUN"`
+	MaxBatchSize int    `json:"MaxBatchSize" yaml:"MaxBatchSize" toml:"MaxBatchSize" env:"DEVICE_VIRTUAL_MAX_BATCH_SIZE"`
+	HistorySize  int    `json:"HistorySize" yaml:"HistorySize" toml:"HistorySize" env:"DEVICE_VIRTUAL_HISTORY_SIZE"`
+}
+
+// newConfiguration returns the default Device Virtual configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59900),
+		Device: deviceConfig{
+			CoreDataURL:  "http://localhost:59880",
+			Topic:        messaging.MessageTopics.Events,
+			MaxBatchSize: virtual.DefaultMaxBatchSize,
+			HistorySize:  virtual.DefaultHistorySize,
+		},
+	}
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 	"github.com/Hell0W0rID/edgex-go-clone/internal/support/notifications"
 )
 
@@ -13,21 +20,57 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.SupportNotificationsServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59860",
+		ServiceName:        common.SupportNotificationsServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
 	// Initialize support notifications service
 	notificationService := notifications.NewSupportNotificationsService(logger)
 
+	notificationService.SetRetention(config.Notifications.CleanupInterval, config.Notifications.Retention)
+
+	// Deliver SMS channels through Twilio when its credentials are in the secret store
+	secretProvider := secrets.NewSecretProvider(secrets.NewInMemorySecretsClient(logger), logger)
+	if smsSender, err := notifications.NewTwilioSMSSender(secretProvider, logger); err != nil {
+		logger.Infof("SMS channel disabled: %v", err)
+	} else {
+		notificationService.SetSMSSender(smsSender)
+	}
+
+	// Also accept notifications published on the message bus when one is configured
+	if config.MessageBus.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.MessageBus.Host, config.MessageBus.Port)
+		messageClient := messaging.NewRedisMessageClient(address, "", 0, logger)
+		if err := messageClient.Connect(); err != nil {
+			logger.Fatalf("Failed to connect to message bus: %v", err)
+		}
+		defer messageClient.Disconnect()
+		bootstrap.RegisterHealthCheck("messagebus", messageClient.Ping)
+
+		notificationService.SetMessageClient(messageClient, config.Notifications.Topic)
+	}
+
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
 		notificationService,
@@ -40,4 +83,30 @@ func main() {
 
 	// Bootstrap the service
 	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+}
+
+// configuration is the Support Notifications service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Notifications        notificationsConfig `json:"Notifications" yaml:"Notifications" toml:"Notifications"`
+}
+
+// notificationsConfig controls notification retention, e.g. NOTIFICATIONS_RETENTION=72h,
+// and the message bus topic notifications are received on
+type notificationsConfig struct {
+	CleanupInterval time.Duration `json:"CleanupInterval" yaml:"CleanupInterval" toml:"CleanupInterval" env:"NOTIFICATIONS_CLEANUP_INTERVAL"`
+	Retention       time.Duration `json:"Retention" yaml:"Retention" toml:"Retention" env:"NOTIFICATIONS_RETENTION"`
+	Topic           string        `json:"Topic" yaml:"Topic" toml:"Topic" env:"NOTIFICATIONS_TOPIC"`
+}
+
+// newConfiguration returns the default Support Notifications configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59860),
+		Notifications: notificationsConfig{
+			CleanupInterval: notifications.DefaultCleanupInterval,
+			Retention:       notifications.DefaultRetention,
+			Topic:           messaging.MessageTopics.Notifications,
+		},
+	}
+}
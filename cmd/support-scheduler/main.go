@@ -1,6 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
@@ -13,21 +18,64 @@ func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 
+	// Load configuration from the -config file or EDGEX_CONFIG_FILE, overlaid with environment variables
+	config := newConfiguration()
+	configFile, err := bootstrap.ConfigFilePath(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("Invalid arguments: %v", err)
+	}
+	if err := bootstrap.LoadConfig(configFile, &config); err != nil {
+		logger.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	serviceInfo := bootstrap.ServiceInfo{
-		ServiceName:    common.SupportSchedulerServiceKey,
-		ServiceVersion: common.ServiceVersion,
-		Port:          "59861",
+		ServiceName:        common.SupportSchedulerServiceKey,
+		ServiceVersion:     common.ServiceVersion,
+		Port:               strconv.Itoa(config.Service.Port),
+		ShutdownTimeout:    config.Service.ShutdownTimeout,
+		MaxRequestBodySize: config.Service.MaxRequestBodySize,
+		Config:             config,
 	}
 
 	// Create router
 	router := mux.NewRouter()
 
 	// Add common EdgeX routes
-	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion)
+	bootstrap.AddCommonRoutes(router, serviceInfo.ServiceName, serviceInfo.ServiceVersion, config)
 
 	// Initialize support scheduler service
 	schedulerService := scheduler.NewSupportSchedulerService(logger)
 
+	schedulerService.SetRequestTimeout(config.Scheduler.RequestTimeout)
+	// Database credentials come from DATABASE_USERNAME and DATABASE_PASSWORD
+	secretProvider, err := bootstrap.NewSecretProvider(common.SupportSchedulerServiceKey, config.Database, logger)
+	if err != nil {
+		logger.Fatalf("Failed to set up secrets: %v", err)
+	}
+	schedulerService.SetSecretProvider(secretProvider)
+
+	// Keep events and actions across restarts in Redis when configured, otherwise in a file if one is set
+	if config.Database.Host != "" {
+		address := fmt.Sprintf("%s:%d", config.Database.Host, config.Database.Port)
+		store := scheduler.NewRedisScheduleStore(address, 0, secretProvider, logger)
+		if err := store.Connect(); err != nil {
+			logger.Fatalf("Failed to initialize schedule store: %v", err)
+		}
+		defer store.Close()
+		bootstrap.RegisterHealthCheck("database", store.Ping)
+
+		logger.Infof("Using Redis schedule store at %s", address)
+		schedulerService.SetStore(store)
+	} else if config.Scheduler.StoreFile != "" {
+		store, err := scheduler.NewFileScheduleStore(config.Scheduler.StoreFile)
+		if err != nil {
+			logger.Fatalf("Failed to initialize schedule store: %v", err)
+		}
+
+		logger.Infof("Using schedule store file %s", config.Scheduler.StoreFile)
+		schedulerService.SetStore(store)
+	}
+
 	// Create bootstrap handlers
 	handlers := []bootstrap.BootstrapHandler{
 		schedulerService,
@@ -40,4 +88,26 @@ func main() {
 
 	// Bootstrap the service
 	bootstrap.Bootstrap(serviceInfo, handlers, router)
-}
\ No newline at end of file
+}
+// configuration is the Support Scheduler service configuration
+type configuration struct {
+	bootstrap.BaseConfig `yaml:",inline"`
+	Scheduler            schedulerConfig `json:"Scheduler" yaml:"Scheduler" toml:"Scheduler"`
+}
+
+// schedulerConfig controls how scheduled jobs call their targets, e.g. SCHEDULER_REQUEST_TIMEOUT=5s, and
+// where events and actions are kept without a database, e.g. SCHEDULER_STORE_FILE=/var/lib/edgex/schedules.json
+type schedulerConfig struct {
+	RequestTimeout time.Duration `json:"RequestTimeout" yaml:"RequestTimeout" toml:"RequestTimeout" env:"SCHEDULER_REQUEST_TIMEOUT"`
+	StoreFile      string        `json:"StoreFile" yaml:"StoreFile" toml:"StoreFile" env:"SCHEDULER_STORE_FILE"`
+}
+
+// newConfiguration returns the default Support Scheduler configuration
+func newConfiguration() configuration {
+	return configuration{
+		BaseConfig: bootstrap.NewBaseConfig(59861),
+		Scheduler: schedulerConfig{
+			RequestTimeout: scheduler.DefaultRequestTimeout,
+		},
+	}
+}
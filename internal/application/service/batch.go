@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Values of the "batch" field of a pipeline result
+const (
+	BatchBuffered = "buffered" // the event is held until its batch flushes
+	BatchFlushed  = "flushed"  // the event went on to the target as part of a batch
+)
+
+// batcher buffers the events reaching one Batch transform of a pipeline. It releases them once
+// size events are held or timeout has passed since the first of them; either may be zero.
+type batcher struct {
+	pipeline Pipeline
+	index    int
+	size     int
+	timeout  time.Duration
+
+	mutex      sync.Mutex
+	events     []models.Event
+	timer      *time.Timer
+	generation int // counts released batches, so a late timer cannot release the next one
+}
+
+// add buffers event and returns the batch if it is now full, along with the number of events held.
+// onTimeout is scheduled with the batch's generation when event starts a new batch.
+func (b *batcher) add(event models.Event, onTimeout func(generation int)) ([]models.Event, int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.events = append(b.events, event)
+	if b.size > 0 && len(b.events) >= b.size {
+		return b.takeLocked(), 0
+	}
+	if len(b.events) == 1 && b.timeout > 0 {
+		generation := b.generation
+		b.timer = time.AfterFunc(b.timeout, func() { onTimeout(generation) })
+	}
+	return nil, len(b.events)
+}
+
+// take removes and returns the buffered events
+func (b *batcher) take() []models.Event {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.takeLocked()
+}
+
+// takeExpired is take for the timer of a batch, returning nothing if that batch was already released
+func (b *batcher) takeExpired(generation int) []models.Event {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if generation != b.generation {
+		return nil
+	}
+	return b.takeLocked()
+}
+
+func (b *batcher) takeLocked() []models.Event {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	events := b.events
+	b.events = nil
+	b.generation++
+	return events
+}
+
+// batchParameters reads the "batchSize" and "timeout" parameters of a Batch transform
+func batchParameters(transform Transform) (int, time.Duration, error) {
+	size := 0
+	switch value := transform.Parameters["batchSize"].(type) {
+	case nil:
+	case float64:
+		size = int(value)
+	case int:
+		size = value
+	case string:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid batchSize %q", value)
+		}
+		size = parsed
+	default:
+		return 0, 0, fmt.Errorf("invalid batchSize %v", value)
+	}
+	if size < 0 {
+		return 0, 0, fmt.Errorf("invalid batchSize %d", size)
+	}
+
+	var timeout time.Duration
+	if value, ok := transform.Parameters["timeout"].(string); ok && value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid timeout %q", value)
+		}
+		timeout = parsed
+	}
+
+	if size == 0 && timeout == 0 {
+		return 0, 0, errors.New("batch needs a batchSize or a timeout")
+	}
+	return size, timeout, nil
+}
+
+// batchTransform is the registered Batch transform. The pipeline runner buffers events itself, so this
+// only checks the parameters and passes the event on as a batch of one, as a dry run shows it.
+func batchTransform(ctx context.Context, data Payload, params map[string]interface{}) (Payload, string, error) {
+	if _, _, err := batchParameters(Transform{Parameters: params}); err != nil {
+		return data, "", err
+	}
+	if data.Serialized() {
+		return data, "", errors.New("cannot batch a serialized payload; batch before Compress or Encrypt")
+	}
+	if data.Batched {
+		return data, "Events already batched", nil
+	}
+	return batchData(data.Events, data.Format), "Event would be buffered; shown as a batch of 1", nil
+}
+
+// stepKey identifies the transform at index in a pipeline, keying the state of stateful transforms
+func stepKey(pipelineID string, index int) string {
+	return pipelineID + "/" + strconv.Itoa(index)
+}
+
+// addToBatch buffers event at the Batch transform at index of pipeline. It returns the batch to
+// process when this event completes one, otherwise the number of events now buffered.
+func (s *ApplicationService) addToBatch(pipeline Pipeline, index int, event models.Event) ([]models.Event, int, error) {
+	size, timeout, err := batchParameters(pipeline.Transforms[index])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Add under the map lock so a batcher being flushed by flushBatches cannot gain events
+	key := stepKey(pipeline.Id, index)
+	s.batchMutex.Lock()
+	defer s.batchMutex.Unlock()
+	b, ok := s.batchers[key]
+	if !ok {
+		b = &batcher{pipeline: pipeline, index: index, size: size, timeout: timeout}
+		s.batchers[key] = b
+	}
+	batch, buffered := b.add(event, func(generation int) {
+		s.flushBatch(b, func() []models.Event { return b.takeExpired(generation) }, "timeout")
+	})
+	return batch, buffered, nil
+}
+
+// flushBatch sends the events take removes from b through the rest of its pipeline and logs the
+// outcome, as no request is waiting for it. reason says what triggered the flush.
+func (s *ApplicationService) flushBatch(b *batcher, take func() []models.Event, reason string) {
+	// Shutdown takes the write lock to wait for flushes in progress
+	s.flushLock.RLock()
+	defer s.flushLock.RUnlock()
+
+	events := take()
+	if len(events) == 0 {
+		return
+	}
+
+	ctx := bootstrap.WithCorrelationID(context.Background(), models.GenerateUUID())
+	transformResults := []string{fmt.Sprintf("Batch of %d events flushed on %s", len(events), reason)}
+	result := s.runPipeline(ctx, b.pipeline, b.index+1, batchData(events, b.pipeline.Target.Format), transformResults)
+	if result["status"] == "error" {
+		s.logger.Errorf("Pipeline %s failed to flush batch of %d events on %s: %v", b.pipeline.Name, len(events), reason, result["error"])
+		return
+	}
+	s.logger.Infof("Pipeline %s flushed batch of %d events on %s", b.pipeline.Name, len(events), reason)
+}
+
+// flushBatches flushes and forgets the batchers of a pipeline, or of every pipeline when
+// pipelineID is empty, so buffered events are not lost when pipelines stop or change
+func (s *ApplicationService) flushBatches(pipelineID, reason string) {
+	s.batchMutex.Lock()
+	var flushing []*batcher
+	for key, b := range s.batchers {
+		if pipelineID == "" || strings.HasPrefix(key, pipelineID+"/") {
+			flushing = append(flushing, b)
+			delete(s.batchers, key)
+		}
+	}
+	s.batchMutex.Unlock()
+
+	for _, b := range flushing {
+		s.flushBatch(b, b.take, reason)
+	}
+}
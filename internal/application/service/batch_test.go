@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// batchReceiver is an HTTP target that records the event arrays posted to it
+type batchReceiver struct {
+	server  *httptest.Server
+	mutex   sync.Mutex
+	batches [][]models.Event
+}
+
+func newBatchReceiver(t *testing.T) *batchReceiver {
+	receiver := &batchReceiver{}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []models.Event
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		receiver.mutex.Lock()
+		receiver.batches = append(receiver.batches, batch)
+		receiver.mutex.Unlock()
+	}))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func (r *batchReceiver) received() [][]models.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([][]models.Event(nil), r.batches...)
+}
+
+// batchPipeline stores an unlocked pipeline that batches events on their way to receiver
+func batchPipeline(t *testing.T, service *ApplicationService, receiver *batchReceiver, parameters map[string]interface{}, after ...Transform) Pipeline {
+	id := models.GenerateUUID()
+	pipeline := Pipeline{
+		Id:         id,
+		Name:       "batch",
+		Transforms: append([]Transform{{Type: "Batch", Parameters: parameters}}, after...),
+		Target:     httpTarget(t, receiver.server, nil),
+		AdminState: common.Unlocked,
+	}
+	service.pipelines[id] = pipeline
+	return pipeline
+}
+
+func testEvent(i int) models.Event {
+	return models.Event{Id: "event-" + strconv.Itoa(i), DeviceName: "Device-" + strconv.Itoa(i%2)}
+}
+
+func TestBatchTransform_FlushOnSize(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newBatchReceiver(t)
+	pipeline := batchPipeline(t, service, receiver, map[string]interface{}{"batchSize": float64(3)},
+		Transform{Type: "FilterByDeviceName", Parameters: map[string]interface{}{"exclude": []interface{}{"Device-1"}}})
+
+	for i := 0; i < 2; i++ {
+		result := service.executePipeline(context.Background(), testEvent(i), pipeline)
+		assert.Equal(t, "success", result["status"], result["error"])
+		assert.Equal(t, BatchBuffered, result["batch"])
+		assert.Equal(t, i+1, result["batchCount"])
+		assert.NotContains(t, result, "targetResult")
+	}
+	assert.Empty(t, receiver.received())
+
+	result := service.executePipeline(context.Background(), testEvent(2), pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Equal(t, BatchFlushed, result["batch"])
+	assert.Equal(t, http.StatusOK, result["targetStatusCode"])
+
+	// The filter after the batch runs on every event, and the rest go out in one request
+	batches := receiver.received()
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 2)
+	assert.Equal(t, "event-0", batches[0][0].Id)
+	assert.Equal(t, "event-2", batches[0][1].Id)
+	assert.Equal(t, 2, result["batchCount"])
+}
+
+func TestBatchTransform_FlushOnTimeout(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newBatchReceiver(t)
+	pipeline := batchPipeline(t, service, receiver, map[string]interface{}{"batchSize": float64(100), "timeout": "50ms"})
+
+	for i := 0; i < 2; i++ {
+		result := service.executePipeline(context.Background(), testEvent(i), pipeline)
+		require.Equal(t, BatchBuffered, result["batch"])
+	}
+
+	require.Eventually(t, func() bool { return len(receiver.received()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, receiver.received()[0], 2)
+
+	// The next event starts a new batch with its own timer
+	service.executePipeline(context.Background(), testEvent(2), pipeline)
+	require.Eventually(t, func() bool { return len(receiver.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "event-2", receiver.received()[1][0].Id)
+}
+
+func TestBatchTransform_FlushOnStop(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newBatchReceiver(t)
+	pipeline := batchPipeline(t, service, receiver, map[string]interface{}{"batchSize": float64(10)})
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	service.executePipeline(context.Background(), testEvent(0), pipeline)
+	service.executePipeline(context.Background(), testEvent(1), pipeline)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v3/pipeline/id/"+pipeline.Id+"/stop", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	batches := receiver.received()
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+}
+
+func TestBatchTransform_FlushOnShutdown(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newBatchReceiver(t)
+	pipeline := batchPipeline(t, service, receiver, map[string]interface{}{"batchSize": float64(10), "timeout": "1h"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+
+	service.executePipeline(context.Background(), testEvent(0), pipeline)
+	cancel()
+	wg.Wait()
+
+	batches := receiver.received()
+	require.Len(t, batches, 1)
+	assert.Equal(t, "event-0", batches[0][0].Id)
+}
+
+func TestBatchTransform_Concurrent(t *testing.T) {
+	service, _ := newTestService()
+	receiver := newBatchReceiver(t)
+	pipeline := batchPipeline(t, service, receiver, map[string]interface{}{"batchSize": float64(10)})
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	flushed := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := service.executePipeline(context.Background(), testEvent(i), pipeline)
+			if result["batch"] == BatchFlushed {
+				mutex.Lock()
+				flushed++
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Every event arrives exactly once, in full batches
+	assert.Equal(t, 5, flushed)
+	seen := make(map[string]bool)
+	for _, batch := range receiver.received() {
+		assert.Len(t, batch, 10)
+		for _, event := range batch {
+			assert.False(t, seen[event.Id], "event %s sent twice", event.Id)
+			seen[event.Id] = true
+		}
+	}
+	assert.Len(t, seen, 50)
+}
+
+func TestBatcher_LateTimer(t *testing.T) {
+	b := &batcher{size: 2, timeout: time.Hour}
+	b.add(testEvent(0), func(int) {})
+	batch, _ := b.add(testEvent(1), func(int) {})
+	require.Len(t, batch, 2)
+
+	// A timer from the released batch must not release the next one early
+	b.add(testEvent(2), func(int) {})
+	assert.Empty(t, b.takeExpired(0))
+	assert.Len(t, b.takeExpired(1), 1)
+}
+
+func TestBatchTransform_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		parameters  map[string]interface{}
+		expectError string
+	}{
+		{"No size or timeout", map[string]interface{}{}, "batch needs a batchSize or a timeout"},
+		{"Negative size", map[string]interface{}{"batchSize": float64(-1)}, "invalid batchSize -1"},
+		{"Invalid size", map[string]interface{}{"batchSize": "ten"}, `invalid batchSize "ten"`},
+		{"Invalid timeout", map[string]interface{}{"batchSize": float64(5), "timeout": "soon"}, `invalid timeout "soon"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			receiver := newBatchReceiver(t)
+			pipeline := batchPipeline(t, service, receiver, tt.parameters)
+
+			result := service.executePipeline(context.Background(), testEvent(0), pipeline)
+			assert.Equal(t, "error", result["status"])
+			assert.Equal(t, "Batch transform failed: "+tt.expectError, result["error"])
+		})
+	}
+}
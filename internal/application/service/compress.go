@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+)
+
+// Algorithms of the Compress transform
+const (
+	CompressGzip = "gzip"
+	CompressZlib = "zlib"
+)
+
+// CompressionResult is what a Compress transform did: the algorithm and the payload size in bytes before and after
+type CompressionResult struct {
+	Algorithm      string `json:"algorithm"`
+	OriginalSize   int    `json:"originalSize"`
+	CompressedSize int    `json:"compressedSize"`
+}
+
+// compressData compresses the serialized data with the "algorithm" parameter, gzip by default.
+// Events not yet serialized are encoded as JSON first.
+func compressData(ctx context.Context, data Payload, parameters map[string]interface{}) (Payload, string, error) {
+	algorithm, err := compressAlgorithm(parameters)
+	if err != nil {
+		return data, "", err
+	}
+
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	var encoding string
+	if algorithm == CompressZlib {
+		writer, encoding = zlib.NewWriter(&buffer), "deflate"
+	} else {
+		writer, encoding = gzip.NewWriter(&buffer), "gzip"
+	}
+
+	data, err = data.Marshal()
+	if err != nil {
+		return data, "", err
+	}
+	if _, err := writer.Write(data.Body); err != nil {
+		return data, "", fmt.Errorf("failed to compress %s: %w", data.describe(), err)
+	}
+	if err := writer.Close(); err != nil {
+		return data, "", fmt.Errorf("failed to compress %s: %w", data.describe(), err)
+	}
+
+	compression := &CompressionResult{Algorithm: algorithm, OriginalSize: len(data.Body), CompressedSize: buffer.Len()}
+	message := fmt.Sprintf("Compressed %d bytes to %d with %s", compression.OriginalSize, compression.CompressedSize, algorithm)
+	data.Body = buffer.Bytes()
+	data.Compression = compression
+	data.ContentEncoding = append(append([]string(nil), data.ContentEncoding...), encoding)
+	return data, message, nil
+}
+
+// compressAlgorithm reads the "algorithm" parameter of a Compress transform
+func compressAlgorithm(parameters map[string]interface{}) (string, error) {
+	algorithm := CompressGzip
+	if value, ok := parameters["algorithm"]; ok {
+		name, isString := value.(string)
+		if !isString {
+			return "", fmt.Errorf("invalid algorithm %v", value)
+		}
+		if name != "" {
+			algorithm = name
+		}
+	}
+	if algorithm != CompressGzip && algorithm != CompressZlib {
+		return "", fmt.Errorf("unknown algorithm %q, must be %s or %s", algorithm, CompressGzip, CompressZlib)
+	}
+	return algorithm, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// capturedRequest is the body and headers an HTTP target received
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newCapturingServer starts an HTTP target that records the last request it received
+func newCapturingServer(t *testing.T) (*httptest.Server, *capturedRequest) {
+	captured := &capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		captured.header = r.Header
+		captured.body = body
+	}))
+	t.Cleanup(server.Close)
+	return server, captured
+}
+
+func TestCompressTransform(t *testing.T) {
+	tests := []struct {
+		name           string
+		algorithm      string
+		expectEncoding string
+		decompress     func(io.Reader) (io.Reader, error)
+	}{
+		{"Default gzip", "", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"Zlib", CompressZlib, "deflate", func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			server, captured := newCapturingServer(t)
+			parameters := map[string]interface{}{}
+			if tt.algorithm != "" {
+				parameters["algorithm"] = tt.algorithm
+			}
+			pipeline := Pipeline{
+				Name:       "compress",
+				Transforms: []Transform{{Type: "Compress", Parameters: parameters}},
+				Target:     httpTarget(t, server, nil),
+			}
+
+			result := service.executePipeline(context.Background(), testEvent(1), pipeline)
+			require.Equal(t, "success", result["status"], result["error"])
+
+			assert.Equal(t, common.ContentTypeJSON, captured.header.Get(common.ContentType))
+			assert.Equal(t, tt.expectEncoding, captured.header.Get("Content-Encoding"))
+			reader, err := tt.decompress(bytes.NewReader(captured.body))
+			require.NoError(t, err)
+			var event models.Event
+			require.NoError(t, json.NewDecoder(reader).Decode(&event))
+			assert.Equal(t, "event-1", event.Id)
+		})
+	}
+}
+
+func TestCompressTransform_ReportsSizes(t *testing.T) {
+	// A large event of similar readings compresses well
+	event := testEvent(1)
+	for i := 0; i < 200; i++ {
+		event.Readings = append(event.Readings, simpleReading("Temperature", common.ValueTypeFloat64, strconv.Itoa(20+i%10)))
+	}
+	uncompressed, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	for _, algorithm := range []string{CompressGzip, CompressZlib} {
+		t.Run(algorithm, func(t *testing.T) {
+			service, _ := newTestService()
+			server, captured := newCapturingServer(t)
+			pipeline := Pipeline{
+				Name:       "compress",
+				Transforms: []Transform{{Type: "Compress", Parameters: map[string]interface{}{"algorithm": algorithm}}},
+				Target:     httpTarget(t, server, nil),
+			}
+
+			result := service.executePipeline(context.Background(), event, pipeline)
+			require.Equal(t, "success", result["status"], result["error"])
+
+			compression, ok := result["compression"].(CompressionResult)
+			require.True(t, ok, "the result reports the sizes")
+			assert.Equal(t, algorithm, compression.Algorithm)
+			assert.Equal(t, len(uncompressed), compression.OriginalSize)
+			assert.Equal(t, len(captured.body), compression.CompressedSize, "the target is sent the compressed bytes")
+			assert.Less(t, compression.CompressedSize, compression.OriginalSize/4)
+		})
+	}
+}
+
+func TestCompressTransform_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		transforms  []Transform
+		expectError string
+	}{
+		{
+			"Unknown algorithm",
+			[]Transform{{Type: "Compress", Parameters: map[string]interface{}{"algorithm": "lz4"}}},
+			`Compress transform failed: unknown algorithm "lz4", must be gzip or zlib`,
+		},
+		{
+			"Filter after compression",
+			[]Transform{{Type: "Compress"}, {Type: "FilterByDeviceName", Parameters: map[string]interface{}{"include": []interface{}{"Device-1"}}}},
+			"FilterByDeviceName transform failed: FilterByDeviceName works on events, but an earlier step already serialized them",
+		},
+		{
+			"Batch after compression",
+			[]Transform{{Type: "Compress"}, {Type: "Batch", Parameters: map[string]interface{}{"batchSize": float64(2)}}},
+			"Batch transform failed: cannot batch a serialized payload; batch before Compress or Encrypt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			server, captured := newCapturingServer(t)
+			pipeline := Pipeline{Name: "compress", Transforms: tt.transforms, Target: httpTarget(t, server, nil)}
+
+			result := service.executePipeline(context.Background(), testEvent(1), pipeline)
+			assert.Equal(t, "error", result["status"])
+			assert.Equal(t, tt.expectError, result["error"])
+			assert.Nil(t, captured.body)
+		})
+	}
+}
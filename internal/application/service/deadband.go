@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// deadbandThreshold is how far a reading must move from the last value forwarded for its device and
+// resource to be forwarded again: by more than delta, or by more than delta percent of that value
+type deadbandThreshold struct {
+	delta   float64
+	percent bool
+}
+
+// deadbandParameters reads the minDelta parameter of a Deadband transform: a number for an absolute
+// change, or a string such as "5%" for a change relative to the last forwarded value
+func deadbandParameters(params map[string]interface{}) (deadbandThreshold, error) {
+	value, exists := params["minDelta"]
+	if !exists {
+		return deadbandThreshold{}, errors.New("deadband needs a minDelta")
+	}
+	var threshold deadbandThreshold
+	if text, isString := value.(string); isString && strings.HasSuffix(strings.TrimSpace(text), "%") {
+		threshold.percent = true
+		value = strings.TrimSuffix(strings.TrimSpace(text), "%")
+	}
+	delta, err := numberParameter(value)
+	if err != nil {
+		return deadbandThreshold{}, fmt.Errorf("invalid minDelta: %w", err)
+	}
+	if delta < 0 {
+		return deadbandThreshold{}, fmt.Errorf("invalid minDelta %v, must not be negative", params["minDelta"])
+	}
+	threshold.delta = delta
+	return threshold, nil
+}
+
+// exceeded reports whether value has moved beyond the threshold from last
+func (t deadbandThreshold) exceeded(last, value float64) bool {
+	limit := t.delta
+	if t.percent {
+		limit = math.Abs(last) * t.delta / 100
+	}
+	return math.Abs(value-last) > limit
+}
+
+// deadband remembers the last value forwarded by one Deadband transform of a pipeline, by device and
+// resource. An event is forwarded when any of its numeric readings is new or has moved beyond the
+// threshold, and then becomes the reference for all of them; events without numeric readings always pass.
+type deadband struct {
+	threshold deadbandThreshold
+
+	mutex sync.Mutex
+	last  map[string]float64 // by device and resource
+}
+
+// admit reports whether event passes the deadband, remembering its values if it does
+func (d *deadband) admit(event models.Event) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	values := make(map[string]float64, len(event.Readings))
+	changed := false
+	for _, reading := range event.Readings {
+		value, err := readingValue(reading)
+		if err != nil {
+			continue
+		}
+		key := event.DeviceName + "/" + reading.ResourceName
+		values[key] = value
+		if last, seen := d.last[key]; !seen || d.threshold.exceeded(last, value) {
+			changed = true
+		}
+	}
+	if len(values) > 0 && !changed {
+		return false
+	}
+	for key, value := range values {
+		d.last[key] = value
+	}
+	return true
+}
+
+// filter keeps the events of data that pass the deadband
+func (d *deadband) filter(data Payload) (Payload, string) {
+	kept := make([]models.Event, 0, len(data.Events))
+	for _, event := range data.Events {
+		if d.admit(event) {
+			kept = append(kept, event)
+		}
+	}
+	var message string
+	switch {
+	case data.Batched:
+		message = fmt.Sprintf("%d of %d events changed beyond the deadband", len(kept), len(data.Events))
+	case len(kept) == 0:
+		message = fmt.Sprintf("Event %s within the deadband of the last forwarded values", data.Events[0].Id)
+	default:
+		message = fmt.Sprintf("Event %s changed beyond the deadband", data.Events[0].Id)
+	}
+	data.Events = kept
+	return data, message
+}
+
+// deadbandFor returns the deadband of the Deadband transform at index of pipeline, creating it on
+// first use
+func (s *ApplicationService) deadbandFor(pipeline Pipeline, index int) (*deadband, error) {
+	threshold, err := deadbandParameters(pipeline.Transforms[index].Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	key := stepKey(pipeline.Id, index)
+	s.deadbandMutex.Lock()
+	defer s.deadbandMutex.Unlock()
+	d, ok := s.deadbands[key]
+	if !ok {
+		d = &deadband{threshold: threshold, last: make(map[string]float64)}
+		s.deadbands[key] = d
+	}
+	return d, nil
+}
+
+// forgetDeadbands drops the values remembered by the Deadband transforms of a pipeline, so a changed
+// definition starts afresh
+func (s *ApplicationService) forgetDeadbands(pipelineID string) {
+	s.deadbandMutex.Lock()
+	defer s.deadbandMutex.Unlock()
+	for key := range s.deadbands {
+		if strings.HasPrefix(key, pipelineID+"/") {
+			delete(s.deadbands, key)
+		}
+	}
+}
+
+// deadbandTransform is the registered Deadband transform. The pipeline runner keeps the last forwarded
+// values itself, so this only checks the parameters and passes the events on, as a dry run shows them.
+func deadbandTransform(ctx context.Context, data Payload, params map[string]interface{}) (Payload, string, error) {
+	if _, err := deadbandParameters(params); err != nil {
+		return data, "", err
+	}
+	if data.Serialized() {
+		return data, "", errNeedsEvents
+	}
+	return data, "Events would be compared with the last forwarded values; shown as passing", nil
+}
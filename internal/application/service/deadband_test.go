@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// deadbandPipeline adds a pipeline forwarding to a FILE target through a Deadband with minDelta
+func deadbandPipeline(service *ApplicationService, minDelta interface{}) Pipeline {
+	pipeline := Pipeline{
+		Id:         models.GenerateUUID(),
+		Name:       "deadband",
+		Transforms: []Transform{{Type: "Deadband", Parameters: map[string]interface{}{"minDelta": minDelta}}},
+		Target:     Target{Type: "FILE"},
+		AdminState: common.Unlocked,
+	}
+	service.pipelines[pipeline.Id] = pipeline
+	return pipeline
+}
+
+func temperatureReading(device, value string) models.Event {
+	return models.Event{
+		Id:         models.GenerateUUID(),
+		DeviceName: device,
+		Readings:   []models.Reading{simpleReading("Temperature", common.ValueTypeFloat64, value)},
+	}
+}
+
+// forwarded runs each value of device through pipeline and returns whether each one passed
+func forwarded(t *testing.T, service *ApplicationService, pipeline Pipeline, device string, values ...string) []bool {
+	passed := make([]bool, 0, len(values))
+	for _, value := range values {
+		result := service.executePipeline(context.Background(), temperatureReading(device, value), pipeline)
+		require.Equal(t, "success", result["status"], result["error"])
+		passed = append(passed, result["filteredOut"] == false)
+	}
+	return passed
+}
+
+func TestDeadbandTransform_Absolute(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := deadbandPipeline(service, float64(1))
+
+	// Changes are measured from the last forwarded value, so slow drift still gets through
+	assert.Equal(t, []bool{true, false, false, true, false, true, true},
+		forwarded(t, service, pipeline, "Thermostat-1", "20", "20.5", "19.2", "21.1", "20.2", "19.9", "21"))
+
+	// Each device has its own reference
+	assert.Equal(t, []bool{true, false}, forwarded(t, service, pipeline, "Thermostat-2", "20.5", "20.6"))
+
+	// Events without numeric readings always pass
+	result := service.executePipeline(context.Background(), models.Event{Id: "event-1", DeviceName: "Thermostat-1"}, pipeline)
+	assert.Equal(t, false, result["filteredOut"])
+}
+
+func TestDeadbandTransform_Percentage(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := deadbandPipeline(service, "10%")
+
+	assert.Equal(t, []bool{true, false, true, false, true},
+		forwarded(t, service, pipeline, "Thermostat-1", "100", "109", "111", "121", "80"))
+}
+
+func TestDeadbandTransform_ChangeOnly(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := deadbandPipeline(service, float64(0))
+
+	assert.Equal(t, []bool{true, false, true, true, false},
+		forwarded(t, service, pipeline, "Thermostat-1", "20", "20", "20.1", "20", "20"))
+}
+
+func TestDeadbandTransform_EventWithSeveralReadings(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := deadbandPipeline(service, float64(5))
+
+	event := temperatureEvent()
+	result := service.executePipeline(context.Background(), event, pipeline)
+	assert.Equal(t, false, result["filteredOut"])
+
+	// Humidity moving is enough to forward the event, which then becomes the reference for both readings
+	event.Readings[0].SimpleReading.Value = "37"
+	event.Readings[1].SimpleReading.Value = "50"
+	result = service.executePipeline(context.Background(), event, pipeline)
+	assert.Equal(t, false, result["filteredOut"])
+
+	event.Readings[0].SimpleReading.Value = "41"
+	result = service.executePipeline(context.Background(), event, pipeline)
+	assert.Equal(t, true, result["filteredOut"])
+	assert.Equal(t, []string{"Event event-1 within the deadband of the last forwarded values"}, result["transformResults"])
+}
+
+func TestDeadbandTransform_ForgottenOnUpdate(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	pipeline := deadbandPipeline(service, float64(1))
+	pipeline.Version = 1
+	service.pipelines[pipeline.Id] = pipeline
+
+	assert.Equal(t, []bool{true, false}, forwarded(t, service, pipeline, "Thermostat-1", "20", "20.5"))
+
+	pipeline.Transforms[0].Parameters["minDelta"] = 0.25
+	rr := pipelineRequest(t, router, "PUT", "/api/v3/pipeline/id/"+pipeline.Id, &pipeline)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	pipeline = service.pipelines[pipeline.Id]
+
+	// The first value after an update is forwarded again
+	assert.Equal(t, []bool{true, true}, forwarded(t, service, pipeline, "Thermostat-1", "20.5", "20.8"))
+}
+
+func TestDeadbandParameters(t *testing.T) {
+	threshold, err := deadbandParameters(map[string]interface{}{"minDelta": " 2.5% "})
+	require.NoError(t, err)
+	assert.Equal(t, deadbandThreshold{delta: 2.5, percent: true}, threshold)
+
+	threshold, err = deadbandParameters(map[string]interface{}{"minDelta": "3"})
+	require.NoError(t, err)
+	assert.Equal(t, deadbandThreshold{delta: 3}, threshold)
+
+	for name, params := range map[string]map[string]interface{}{
+		"Missing":    {},
+		"Negative":   {"minDelta": float64(-1)},
+		"Not number": {"minDelta": "a lot"},
+		"Bad":        {"minDelta": "%"},
+	} {
+		_, err := deadbandParameters(params)
+		assert.Error(t, err, name)
+	}
+
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Name:       "deadband",
+		Transforms: []Transform{{Type: "Deadband"}},
+		Target:     Target{Type: "FILE"},
+	}
+	assert.Equal(t, []string{"transforms[0] (Deadband): deadband needs a minDelta"}, service.validatePipeline(pipeline))
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// PipelineDocument is the portable definition of a pipeline, as exported and imported. It leaves out
+// the id, version, timestamps, admin state and runtime state, so it can be kept in version control and
+// imported into another instance.
+type PipelineDocument struct {
+	Name        string      `json:"name" yaml:"name"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Transforms  []Transform `json:"transforms" yaml:"transforms"`
+	Target      Target      `json:"target" yaml:"target"`
+	Trigger     string      `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+
+	Filter *PipelineFilter `json:"filter,omitempty" yaml:"filter,omitempty"`
+}
+
+// newPipelineDocument returns the portable definition of pipeline
+func newPipelineDocument(pipeline Pipeline) PipelineDocument {
+	return PipelineDocument{
+		Name:        pipeline.Name,
+		Description: pipeline.Description,
+		Transforms:  pipeline.Transforms,
+		Target:      pipeline.Target,
+		Trigger:     pipeline.Trigger,
+
+		Filter: pipeline.Filter,
+	}
+}
+
+// pipeline returns a pipeline with the definition in d and nothing else set
+func (d PipelineDocument) pipeline() Pipeline {
+	return Pipeline{
+		Name:        d.Name,
+		Description: d.Description,
+		Transforms:  d.Transforms,
+		Target:      d.Target,
+		Trigger:     d.Trigger,
+
+		Filter: d.Filter,
+	}
+}
+
+// wantsYAML reports whether a request asks for YAML, with ?format=yaml or an Accept header naming it
+func wantsYAML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, "yaml")
+	}
+	for _, accepted := range strings.Split(r.Header.Get(common.Accept), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == common.ContentTypeYAML {
+			return true
+		}
+	}
+	return false
+}
+
+// exportPipeline handles GET /api/v3/pipeline/id/{id}/export. It returns the pipeline's definition as
+// a JSON document, or YAML when asked for with ?format=yaml or Accept: application/yaml.
+func (s *ApplicationService) exportPipeline(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	s.mutex.RLock()
+	pipeline, exists := s.pipelines[id]
+	s.mutex.RUnlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	if format := r.URL.Query().Get("format"); format != "" && !strings.EqualFold(format, "yaml") && !strings.EqualFold(format, "json") {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid format %s, must be json or yaml", format))
+		return
+	}
+
+	document := newPipelineDocument(pipeline)
+	if wantsYAML(r) {
+		body, err := yaml.Marshal(document)
+		if err != nil {
+			s.logger.Errorf("Failed to export pipeline %s as YAML: %v", pipeline.Name, err)
+			common.WriteError(w, http.StatusInternalServerError, "Failed to export pipeline")
+			return
+		}
+		w.Header().Set(common.ContentType, common.ContentTypeYAML)
+		w.Write(body)
+		return
+	}
+
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(document)
+}
+
+// decodePipelineDocument reads a JSON or, with Content-Type application/yaml, YAML pipeline document.
+// YAML is converted to JSON first so parameters get the same types whichever format was sent.
+func decodePipelineDocument(r *http.Request) (PipelineDocument, error) {
+	var document PipelineDocument
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return document, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(common.ContentType))
+	if mediaType == common.ContentTypeYAML {
+		var generic interface{}
+		if err := yaml.Unmarshal(body, &generic); err != nil {
+			return document, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if body, err = json.Marshal(generic); err != nil {
+			return document, fmt.Errorf("invalid YAML: %w", err)
+		}
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return document, fmt.Errorf("invalid pipeline document: %w", err)
+	}
+	return document, nil
+}
+
+// importPipeline handles POST /api/v3/pipeline/import. The document in the body, JSON or YAML,
+// creates a pipeline or, if one already has its name, replaces that pipeline's definition and moves
+// its version on. Imports are validated like addPipeline but, being declarative, skip the version check.
+// New pipelines start unlocked and replaced ones keep their admin state.
+func (s *ApplicationService) importPipeline(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	document, err := decodePipelineDocument(r)
+	if err != nil {
+		s.logger.Errorf("Failed to decode pipeline document: %v", err)
+		common.WriteDecodeError(w, err, err.Error())
+		return
+	}
+
+	pipeline := document.pipeline()
+	if !validTrigger(pipeline.Trigger) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid trigger %s", pipeline.Trigger))
+		return
+	}
+	if problems := s.validatePipeline(pipeline); len(problems) > 0 {
+		common.WriteValidationErrors(w, "Invalid pipeline", problems)
+		return
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	s.mutex.Lock()
+	var matches []Pipeline
+	for _, existing := range s.pipelines {
+		if existing.Name == pipeline.Name {
+			matches = append(matches, existing)
+		}
+	}
+	if len(matches) > 1 {
+		s.mutex.Unlock()
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("%d pipelines are named %s", len(matches), pipeline.Name))
+		return
+	}
+	if len(matches) == 1 {
+		existing := matches[0]
+		pipeline.Id = existing.Id
+		pipeline.Version = existing.Version + 1
+		pipeline.Created = existing.Created
+		pipeline.AdminState = existing.AdminState
+	} else {
+		pipeline.Id = models.GenerateUUID()
+		pipeline.Version = 1
+		pipeline.Created = now
+		pipeline.AdminState = common.Unlocked
+	}
+	pipeline.Modified = now
+	err = s.putPipelineLocked(pipeline)
+	if err == nil && len(matches) == 1 {
+		s.recordVersionLocked(matches[0])
+	}
+	s.mutex.Unlock()
+
+	if err != nil {
+		s.logger.Errorf("Failed to import pipeline %s: %v", pipeline.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	statusCode := http.StatusCreated
+	if pipeline.Version > 1 {
+		// Events batched under the old definition are sent on with it
+		s.flushBatches(pipeline.Id, "import")
+		s.forgetDeadbands(pipeline.Id)
+		statusCode = http.StatusOK
+		s.logger.Infof("Pipeline %s updated from import to version %d", pipeline.Name, pipeline.Version)
+	} else {
+		s.logger.Infof("Pipeline created from import: %s", pipeline.Name)
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": statusCode,
+		"id":         pipeline.Id,
+		"version":    pipeline.Version,
+	}
+
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+const importYAML = `
+name: hot-export
+description: Exports hot readings
+transforms:
+  - type: Filter
+    parameters:
+      resource: Temperature
+      operator: ">"
+      threshold: 30
+  - type: Batch
+    parameters:
+      batchSize: 5
+target:
+  type: HTTP
+  host: localhost
+  port: 8080
+`
+
+func importRequest(t *testing.T, router *mux.Router, contentType, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/v3/pipeline/import", strings.NewReader(body))
+	req.Header.Set(common.ContentType, contentType)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr, response
+}
+
+func TestImportPipeline(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr, response := importRequest(t, router, common.ContentTypeYAML, importYAML)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	id := response["id"].(string)
+	pipeline := service.pipelines[id]
+	assert.Equal(t, "hot-export", pipeline.Name)
+	assert.Equal(t, int64(1), pipeline.Version)
+	assert.Equal(t, common.Unlocked, pipeline.AdminState)
+	assert.Equal(t, float64(30), pipeline.Transforms[0].Parameters["threshold"], "YAML numbers decode like JSON ones")
+
+	// Importing the same name again updates the pipeline in place
+	updated := strings.Replace(importYAML, "port: 8080", "port: 9090", 1)
+	rr, response = importRequest(t, router, common.ContentTypeYAML+"; charset=utf-8", updated)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, id, response["id"])
+	assert.Equal(t, float64(2), response["version"])
+	assert.Len(t, service.pipelines, 1)
+	assert.Equal(t, 9090, service.pipelines[id].Target.Port)
+	assert.Equal(t, pipeline.Created, service.pipelines[id].Created)
+
+	// JSON documents are accepted too
+	rr, _ = importRequest(t, router, common.ContentTypeJSON, `{"name":"file-export","target":{"type":"FILE"}}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestImportPipeline_Invalid(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr, response := importRequest(t, router, common.ContentTypeYAML, "name: p\ntransforms:\n  - type: Teleport\ntarget:\n  type: FILE\n")
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Equal(t, []interface{}{`transforms[0]: unknown type "Teleport"`}, response["errors"])
+
+	rr, _ = importRequest(t, router, common.ContentTypeYAML, "name: [unclosed")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = importRequest(t, router, common.ContentTypeJSON, `{"name":"p","trigger":"carrier-pigeon","target":{"type":"FILE"}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, service.pipelines)
+
+	// A name shared by several pipelines does not say which to update
+	service.pipelines["a"] = Pipeline{Id: "a", Name: "twin", Target: Target{Type: "FILE"}}
+	service.pipelines["b"] = Pipeline{Id: "b", Name: "twin", Target: Target{Type: "FILE"}}
+	rr, _ = importRequest(t, router, common.ContentTypeJSON, `{"name":"twin","target":{"type":"FILE"}}`)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestExportPipeline(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	_, response := importRequest(t, router, common.ContentTypeYAML, importYAML)
+	id := response["id"].(string)
+
+	export := func(path string, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set(common.Accept, accept)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := export("/api/v3/pipeline/id/"+id+"/export", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, common.ContentTypeJSON, rr.Header().Get(common.ContentType))
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &fields))
+	for _, generated := range []string{"id", "version", "created", "modified", "state"} {
+		assert.NotContains(t, fields, generated)
+	}
+	assert.Equal(t, "hot-export", fields["name"])
+
+	for _, rr := range []*httptest.ResponseRecorder{
+		export("/api/v3/pipeline/id/"+id+"/export?format=yaml", ""),
+		export("/api/v3/pipeline/id/"+id+"/export", "application/yaml"),
+	} {
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, common.ContentTypeYAML, rr.Header().Get(common.ContentType))
+		var document PipelineDocument
+		require.NoError(t, yaml.Unmarshal(rr.Body.Bytes(), &document))
+		assert.Equal(t, newPipelineDocument(service.pipelines[id]).Name, document.Name)
+		assert.NotContains(t, rr.Body.String(), id)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, export("/api/v3/pipeline/id/"+id+"/export?format=xml", "").Code)
+	assert.Equal(t, http.StatusNotFound, export("/api/v3/pipeline/id/missing/export", "").Code)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source, _ := newTestService()
+	source.initializeDefaultPipelines()
+	sourceRouter := mux.NewRouter()
+	source.AddRoutes(sourceRouter)
+	target, _ := newTestService()
+	targetRouter := mux.NewRouter()
+	target.AddRoutes(targetRouter)
+
+	for id, pipeline := range source.pipelines {
+		req := httptest.NewRequest("GET", "/api/v3/pipeline/id/"+id+"/export?format=yaml", nil)
+		rr := httptest.NewRecorder()
+		sourceRouter.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		rr, response := importRequest(t, targetRouter, common.ContentTypeYAML, rr.Body.String())
+		require.Equal(t, http.StatusCreated, rr.Code, pipeline.Name)
+		imported := target.pipelines[response["id"].(string)]
+		assert.Equal(t, newPipelineDocument(pipeline).Name, imported.Name)
+		assert.Equal(t, common.Unlocked, imported.AdminState, "admin state is not exported")
+		assert.Equal(t, len(pipeline.Transforms), len(imported.Transforms))
+		assert.Equal(t, pipeline.Target.Type, imported.Target.Type)
+	}
+}
+
+func TestGetPipelineVersion(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline", &Pipeline{Name: "v1", Target: Target{Type: "FILE"}})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	id := created["id"].(string)
+
+	// Every update keeps the definition it replaced
+	updates := MaxPipelineVersions + 2
+	for version := 1; version <= updates; version++ {
+		rr := pipelineRequest(t, router, "PUT", "/api/v3/pipeline/id/"+id, &Pipeline{Name: fmt.Sprintf("v%d", version+1), Target: Target{Type: "FILE"}, Version: int64(version)})
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+	current := int64(updates + 1)
+	assert.Len(t, service.pipelineVersions[id], MaxPipelineVersions)
+
+	getVersion := func(version string) (int, Pipeline) {
+		req := httptest.NewRequest("GET", "/api/v3/pipeline/id/"+id+"/version/"+version, bytes.NewReader(nil))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		var response struct {
+			Pipeline Pipeline `json:"pipeline"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &response)
+		return rr.Code, response.Pipeline
+	}
+
+	code, pipeline := getVersion(fmt.Sprint(current))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, fmt.Sprintf("v%d", current), pipeline.Name)
+
+	oldest := current - MaxPipelineVersions
+	code, pipeline = getVersion(fmt.Sprint(oldest))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, fmt.Sprintf("v%d", oldest), pipeline.Name)
+	assert.Equal(t, oldest, pipeline.Version)
+
+	code, _ = getVersion(fmt.Sprint(oldest - 1))
+	assert.Equal(t, http.StatusNotFound, code, "versions beyond the history are dropped")
+	code, _ = getVersion(fmt.Sprint(current + 1))
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = getVersion("zero")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Starting and stopping don't change the definition, so they keep the version and history
+	for _, action := range []string{"start", "stop", "start"} {
+		rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline/id/"+id+"/"+action, nil)
+		require.Equal(t, http.StatusOK, rr.Code, action)
+	}
+	assert.Equal(t, current, service.pipelines[id].Version)
+	assert.Equal(t, common.Unlocked, service.pipelines[id].AdminState)
+	assert.Len(t, service.pipelineVersions[id], MaxPipelineVersions)
+	code, pipeline = getVersion(fmt.Sprint(oldest))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, fmt.Sprintf("v%d", oldest), pipeline.Name)
+
+	// Updates keep the admin state, which only start and stop change
+	rr = pipelineRequest(t, router, "PUT", "/api/v3/pipeline/id/"+id, &Pipeline{Name: "renamed", Target: Target{Type: "FILE"}, Version: current})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, common.Unlocked, service.pipelines[id].AdminState)
+
+	// Deleting the pipeline drops its history
+	rr = pipelineRequest(t, router, "DELETE", "/api/v3/pipeline/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, service.pipelineVersions, id)
+}
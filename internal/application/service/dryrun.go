@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// DryRunStage is what one transform produced during a dry run
+type DryRunStage struct {
+	Index       int            `json:"index"`
+	Type        string         `json:"type"`
+	Result      string         `json:"result,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	FilteredOut bool           `json:"filteredOut,omitempty"`
+	Events      []models.Event `json:"events,omitempty"`  // the events passed on, until a step serializes them
+	Payload     *DryRunPayload `json:"payload,omitempty"` // the bytes passed on, once a step has serialized the events
+}
+
+// DryRunPayload describes serialized pipeline data. Body holds JSON as is, other text as a
+// string, and anything else base64 encoded, with Encoding set to "base64".
+type DryRunPayload struct {
+	ContentType     string      `json:"contentType"`
+	ContentEncoding []string    `json:"contentEncoding,omitempty"`
+	Size            int         `json:"size"`
+	Body            interface{} `json:"body"`
+	Encoding        string      `json:"encoding,omitempty"`
+}
+
+// DryRunTarget is what a dry run would have sent, and where
+type DryRunTarget struct {
+	Type        string         `json:"type"`
+	Destination string         `json:"destination,omitempty"`
+	Payload     *DryRunPayload `json:"payload,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// dryRunPipeline handles POST /api/v3/pipeline/id/{id}/dryrun. It runs the sample event in the
+// request body through the pipeline's transforms and reports the output of each, along with what
+// the target would receive, without exporting anything. A Batch transform passes the event on as a
+// batch of one instead of buffering it. The pipeline's admin state is ignored.
+func (s *ApplicationService) dryRunPipeline(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	id := mux.Vars(r)["id"]
+
+	var event models.Event
+	if err := common.DecodeJSON(w, r, &event); err != nil {
+		return
+	}
+
+	s.mutex.RLock()
+	pipeline, exists := s.pipelines[id]
+	s.mutex.RUnlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+
+	stages, target := s.dryRun(r.Context(), pipeline, event)
+	response := map[string]interface{}{
+		"apiVersion":   common.ServiceVersion,
+		"statusCode":   http.StatusOK,
+		"pipelineId":   pipeline.Id,
+		"pipelineName": pipeline.Name,
+		"stages":       stages,
+	}
+	if target != nil {
+		response["target"] = target
+	}
+	// The transforms run either way; selected says whether a real event would have reached them
+	matched, selected := matchSelectors(pipeline, event)
+	response["selected"] = selected
+	if matched != "" {
+		response["matchedSelector"] = matched
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// dryRun runs event through the transforms of pipeline, returning the stages it passed and, unless a
+// stage failed or dropped the event, what the target would have been sent
+func (s *ApplicationService) dryRun(ctx context.Context, pipeline Pipeline, event models.Event) ([]DryRunStage, *DryRunTarget) {
+	stages := []DryRunStage{}
+	data := eventData(event, pipeline.Target.Format)
+
+	for i, transform := range pipeline.Transforms {
+		stage := DryRunStage{Index: i, Type: transform.Type}
+		var err error
+		data, stage.Result, stage.FilteredOut, err = s.applyTransform(ctx, transform, data)
+		if err != nil {
+			stage.Result = ""
+			stage.Error = err.Error()
+			return append(stages, stage), nil
+		}
+		if stage.FilteredOut {
+			return append(stages, stage), nil
+		}
+		if data.Serialized() {
+			stage.Payload = describeDryRunPayload(data)
+		} else {
+			stage.Events = data.Events
+		}
+		stages = append(stages, stage)
+	}
+
+	target := &DryRunTarget{Type: pipeline.Target.Type}
+	destination, err := targetDestination(pipeline.Target)
+	if err != nil {
+		target.Error = err.Error()
+		return stages, target
+	}
+	target.Destination = destination
+	if data, err = data.Marshal(); err != nil {
+		target.Error = err.Error()
+		return stages, target
+	}
+	target.Payload = describeDryRunPayload(data)
+	return stages, target
+}
+
+// targetDestination names where a target sends its data
+func targetDestination(target Target) (string, error) {
+	switch target.Type {
+	case "HTTP":
+		return exportURL(target)
+	case "MQTT":
+		if target.Host == "" {
+			return "", errors.New("MQTT target needs a host")
+		}
+		if target.Topic == "" {
+			return "", errors.New("MQTT target needs a topic")
+		}
+		port := target.Port
+		if port == 0 {
+			port = 1883
+		}
+		return fmt.Sprintf("%s on %s", target.Topic, net.JoinHostPort(target.Host, strconv.Itoa(port))), nil
+	default:
+		return "", nil
+	}
+}
+
+// describeDryRunPayload renders serialized data for a dry run response
+func describeDryRunPayload(data Payload) *DryRunPayload {
+	payload := &DryRunPayload{
+		ContentType:     data.ContentType,
+		ContentEncoding: data.ContentEncoding,
+		Size:            len(data.Body),
+	}
+	switch {
+	case len(data.ContentEncoding) == 0 && isJSONContentType(data.ContentType) && json.Valid(data.Body):
+		payload.Body = json.RawMessage(data.Body)
+	case len(data.ContentEncoding) == 0 && utf8.Valid(data.Body):
+		payload.Body = string(data.Body)
+	default:
+		payload.Body = base64.StdEncoding.EncodeToString(data.Body)
+		payload.Encoding = "base64"
+	}
+	return payload
+}
+
+// isJSONContentType reports whether contentType is JSON, including JSON-based types such as CloudEvents
+func isJSONContentType(contentType string) bool {
+	return contentType == common.ContentTypeJSON || strings.HasSuffix(contentType, "+json")
+}
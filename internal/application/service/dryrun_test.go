@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// dryRunResponse is the body of a dry run reply
+type dryRunResponse struct {
+	Stages []DryRunStage `json:"stages"`
+	Target *struct {
+		Type        string `json:"type"`
+		Destination string `json:"destination"`
+		Error       string `json:"error"`
+		Payload     *struct {
+			ContentType     string          `json:"contentType"`
+			ContentEncoding []string        `json:"contentEncoding"`
+			Body            json.RawMessage `json:"body"`
+			Encoding        string          `json:"encoding"`
+		} `json:"payload"`
+	} `json:"target"`
+}
+
+func dryRun(t *testing.T, service *ApplicationService, pipeline Pipeline, event models.Event) dryRunResponse {
+	t.Helper()
+	service.pipelines[pipeline.Id] = pipeline
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/api/v3/pipeline/id/"+pipeline.Id+"/dryrun", bytes.NewReader(body))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var response dryRunResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response
+}
+
+func temperatureEvent() models.Event {
+	return models.Event{
+		Id:         "event-1",
+		DeviceName: "Thermostat-1",
+		Readings: []models.Reading{
+			{ResourceName: "Temperature", ValueType: common.ValueTypeFloat64, SimpleReading: models.SimpleReading{Value: "35"}},
+			{ResourceName: "Humidity", ValueType: common.ValueTypeFloat64, SimpleReading: models.SimpleReading{Value: "40"}},
+		},
+	}
+}
+
+func TestDryRunPipeline(t *testing.T) {
+	service, _ := newTestService()
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { received = true }))
+	defer server.Close()
+
+	pipeline := Pipeline{
+		Id:   "p-1",
+		Name: "dryrun",
+		Transforms: []Transform{
+			{Type: "FilterByResourceName", Parameters: map[string]interface{}{"include": []interface{}{"Temperature"}}},
+			{Type: "Batch", Parameters: map[string]interface{}{"batchSize": float64(10)}},
+		},
+		Target:     httpTarget(t, server, map[string]interface{}{"path": "/ingest"}),
+		AdminState: common.Locked,
+	}
+
+	response := dryRun(t, service, pipeline, temperatureEvent())
+
+	require.Len(t, response.Stages, 2)
+	assert.Equal(t, "Resource name filter kept 1 readings", response.Stages[0].Result)
+	require.Len(t, response.Stages[0].Events, 1)
+	assert.Len(t, response.Stages[0].Events[0].Readings, 1)
+	assert.Equal(t, "Batch", response.Stages[1].Type)
+	assert.Len(t, response.Stages[1].Events, 1)
+
+	require.NotNil(t, response.Target)
+	assert.Equal(t, server.URL+"/ingest", response.Target.Destination)
+	require.NotNil(t, response.Target.Payload)
+	assert.Equal(t, common.ContentTypeJSON, response.Target.Payload.ContentType)
+	var batch []models.Event
+	require.NoError(t, json.Unmarshal(response.Target.Payload.Body, &batch))
+	require.Len(t, batch, 1)
+	assert.Equal(t, "event-1", batch[0].Id)
+
+	// Nothing was exported or buffered
+	assert.False(t, received)
+	assert.Empty(t, service.batchers)
+}
+
+func TestDryRunPipeline_Compressed(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Id:         "p-1",
+		Name:       "dryrun",
+		Transforms: []Transform{{Type: "Compress"}},
+		Target:     Target{Type: "MQTT", Host: "broker", Topic: "edgex/export"},
+	}
+
+	response := dryRun(t, service, pipeline, temperatureEvent())
+
+	require.Len(t, response.Stages, 1)
+	assert.Empty(t, response.Stages[0].Events)
+	require.NotNil(t, response.Stages[0].Payload)
+	assert.Equal(t, "base64", response.Stages[0].Payload.Encoding)
+	assert.Equal(t, "edgex/export on broker:1883", response.Target.Destination)
+	assert.Equal(t, []string{"gzip"}, response.Target.Payload.ContentEncoding)
+	assert.Equal(t, "base64", response.Target.Payload.Encoding)
+}
+
+func TestDryRunPipeline_Stops(t *testing.T) {
+	tests := []struct {
+		name        string
+		transforms  []Transform
+		expectStage DryRunStage
+	}{
+		{
+			"Filtered out",
+			[]Transform{{Type: "FilterByDeviceName", Parameters: map[string]interface{}{"exclude": []interface{}{"Thermostat-1"}}}},
+			DryRunStage{Index: 0, Type: "FilterByDeviceName", Result: "device Thermostat-1: event filtered out", FilteredOut: true},
+		},
+		{
+			"Failed",
+			[]Transform{{Type: "Convert"}, {Type: "Encrypt", Parameters: map[string]interface{}{"secretPath": "aes"}}},
+			DryRunStage{Index: 1, Type: "Encrypt", Error: "no secrets client to resolve the encryption key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			pipeline := Pipeline{Id: "p-1", Name: "dryrun", Transforms: tt.transforms, Target: Target{Type: "FILE"}}
+
+			response := dryRun(t, service, pipeline, temperatureEvent())
+
+			require.NotEmpty(t, response.Stages)
+			assert.Equal(t, tt.expectStage, response.Stages[len(response.Stages)-1])
+			assert.Nil(t, response.Target)
+		})
+	}
+}
+
+func TestDryRunPipeline_NotFound(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	req := httptest.NewRequest("POST", "/api/v3/pipeline/id/missing/dryrun", bytes.NewReader([]byte(`{"id":"event-1"}`)))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// SecretKeyEncryptionKey is the default secret field holding the key of the Encrypt transform
+const SecretKeyEncryptionKey = "key"
+
+// encryptData encrypts the serialized data with AES-256-GCM. The key is read from the secret at
+// the "secretPath" parameter, in the field named by "secretKey", and must be 32 bytes encoded as
+// base64. The output is the base64 encoding of the nonce followed by the sealed data.
+func (s *ApplicationService) encryptData(ctx context.Context, data Payload, parameters map[string]interface{}) (Payload, string, error) {
+	key, err := s.encryptionKey(parameters)
+	if err != nil {
+		return data, "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return data, "", fmt.Errorf("invalid key: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return data, "", fmt.Errorf("invalid key: %w", err)
+	}
+
+	data, err = data.Marshal()
+	if err != nil {
+		return data, "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return data, "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, data.Body, nil)
+
+	message := fmt.Sprintf("Encrypted %d bytes with AES-256-GCM", len(data.Body))
+	data.Body = []byte(base64.StdEncoding.EncodeToString(sealed))
+	data.ContentType = "text/plain"
+	data.ContentEncoding = nil
+	return data, message, nil
+}
+
+// encryptionKey reads the AES-256 key of an Encrypt transform from the secrets client
+func (s *ApplicationService) encryptionKey(parameters map[string]interface{}) ([]byte, error) {
+	secretPath, _ := parameters["secretPath"].(string)
+	if secretPath == "" {
+		return nil, errors.New("encrypt needs a secretPath")
+	}
+	field := SecretKeyEncryptionKey
+	if value, ok := parameters["secretKey"].(string); ok && value != "" {
+		field = value
+	}
+	if s.secretsClient == nil {
+		return nil, errors.New("no secrets client to resolve the encryption key")
+	}
+	secret, err := s.secretsClient.GetSecret(secretPath, field)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key from %s: %w", secretPath, err)
+	}
+	encoded, ok := secret[field]
+	if !ok || encoded == "" {
+		return nil, fmt.Errorf("no %s in secret at %s", field, secretPath)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key at %s is not valid base64", secretPath)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key at %s must be 32 bytes, got %d", secretPath, len(key))
+	}
+	return key, nil
+}
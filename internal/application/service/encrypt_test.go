@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+var testEncryptionKey = bytes.Repeat([]byte{7}, 32)
+
+// newEncryptingService returns a test service whose secrets hold key at "aes"
+func newEncryptingService(t *testing.T, key string) *ApplicationService {
+	service, _ := newTestService()
+	client := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, client.StoreSecret("aes", map[string]string{SecretKeyEncryptionKey: key}))
+	service.SetSecretsClient(client)
+	return service
+}
+
+// decrypt reverses the Encrypt transform
+func decrypt(t *testing.T, body []byte) []byte {
+	sealed, err := base64.StdEncoding.DecodeString(string(body))
+	require.NoError(t, err)
+	block, err := aes.NewCipher(testEncryptionKey)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	require.NoError(t, err)
+	return plain
+}
+
+func TestEncryptTransform(t *testing.T) {
+	service := newEncryptingService(t, base64.StdEncoding.EncodeToString(testEncryptionKey))
+	server, captured := newCapturingServer(t)
+	pipeline := Pipeline{
+		Name:       "encrypt",
+		Transforms: []Transform{{Type: "Encrypt", Parameters: map[string]interface{}{"secretPath": "aes"}}},
+		Target:     httpTarget(t, server, nil),
+	}
+
+	result := service.executePipeline(context.Background(), testEvent(1), pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Equal(t, "text/plain", captured.header.Get(common.ContentType))
+
+	var event models.Event
+	require.NoError(t, json.Unmarshal(decrypt(t, captured.body), &event))
+	assert.Equal(t, "event-1", event.Id)
+}
+
+func TestEncryptTransform_AfterCompress(t *testing.T) {
+	service := newEncryptingService(t, base64.StdEncoding.EncodeToString(testEncryptionKey))
+	server, captured := newCapturingServer(t)
+	pipeline := Pipeline{
+		Name: "compress-encrypt",
+		Transforms: []Transform{
+			{Type: "Compress"},
+			{Type: "Encrypt", Parameters: map[string]interface{}{"secretPath": "aes"}},
+		},
+		Target: httpTarget(t, server, nil),
+	}
+
+	result := service.executePipeline(context.Background(), testEvent(1), pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Empty(t, captured.header.Get("Content-Encoding"))
+
+	// The encrypted bytes are the compressed event
+	reader, err := gzip.NewReader(bytes.NewReader(decrypt(t, captured.body)))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	var event models.Event
+	require.NoError(t, json.Unmarshal(plain, &event))
+	assert.Equal(t, "event-1", event.Id)
+}
+
+func TestEncryptTransform_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		parameters  map[string]interface{}
+		expectError string
+	}{
+		{"No secret path", base64.StdEncoding.EncodeToString(testEncryptionKey), map[string]interface{}{}, "encrypt needs a secretPath"},
+		{"Missing key", base64.StdEncoding.EncodeToString(testEncryptionKey), map[string]interface{}{"secretPath": "aes", "secretKey": "other"}, "no other in secret at aes"},
+		{"Invalid base64", "not base64!", map[string]interface{}{"secretPath": "aes"}, "encryption key at aes is not valid base64"},
+		{"Short key", base64.StdEncoding.EncodeToString([]byte("short")), map[string]interface{}{"secretPath": "aes"}, "encryption key at aes must be 32 bytes, got 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newEncryptingService(t, tt.key)
+			server, captured := newCapturingServer(t)
+			pipeline := Pipeline{
+				Name:       "encrypt",
+				Transforms: []Transform{{Type: "Encrypt", Parameters: tt.parameters}},
+				Target:     httpTarget(t, server, nil),
+			}
+
+			result := service.executePipeline(context.Background(), testEvent(1), pipeline)
+			assert.Equal(t, "error", result["status"])
+			assert.Contains(t, result["error"], "Encrypt transform failed: "+tt.expectError)
+			assert.Nil(t, captured.body)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// addTags merges the "tags" parameter, a map of tag names to values, into the event's tags.
+// Tags already on the event are overwritten by those of the same name.
+func addTags(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	tags, err := tagsParameter(params)
+	if err != nil {
+		return event, "", err
+	}
+
+	merged := make(map[string]interface{}, len(event.Tags)+len(tags))
+	for name, value := range event.Tags {
+		merged[name] = value
+	}
+	for name, value := range tags {
+		merged[name] = value
+	}
+	event.Tags = merged
+	return event, fmt.Sprintf("Added %d tags", len(tags)), nil
+}
+
+// tagsParameter reads the "tags" parameter of an AddTags transform
+func tagsParameter(params map[string]interface{}) (map[string]interface{}, error) {
+	var tags map[string]interface{}
+	switch value := params["tags"].(type) {
+	case map[string]interface{}:
+		tags = value
+	case map[string]string:
+		tags = make(map[string]interface{}, len(value))
+		for name, tag := range value {
+			tags[name] = tag
+		}
+	case nil:
+	default:
+		return nil, errors.New("tags must be a map of tag names to values")
+	}
+	if len(tags) == 0 {
+		return nil, errors.New("AddTags needs tags")
+	}
+	return tags, nil
+}
+
+// renameResource renames readings using the "mapping" parameter, a map of old resource names to
+// new ones. The event's source name is renamed too when it names a mapped resource.
+func renameResource(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	mapping, err := resourceMapping(params)
+	if err != nil {
+		return event, "", err
+	}
+
+	readings := make([]models.Reading, len(event.Readings))
+	renamed := 0
+	for i, reading := range event.Readings {
+		if name, ok := mapping[reading.ResourceName]; ok {
+			reading.ResourceName = name
+			renamed++
+		}
+		readings[i] = reading
+	}
+	if name, ok := mapping[event.SourceName]; ok {
+		event.SourceName = name
+	}
+	event.Readings = readings
+	return event, fmt.Sprintf("Renamed %d readings", renamed), nil
+}
+
+// resourceMapping reads the "mapping" parameter of a RenameResource transform
+func resourceMapping(params map[string]interface{}) (map[string]string, error) {
+	mapping := map[string]string{}
+	switch value := params["mapping"].(type) {
+	case map[string]string:
+		mapping = value
+	case map[string]interface{}:
+		for from, to := range value {
+			name, ok := to.(string)
+			if !ok {
+				return nil, fmt.Errorf("mapping of %s must be a resource name", from)
+			}
+			mapping[from] = name
+		}
+	case nil:
+	default:
+		return nil, errors.New("mapping must be a map of resource names to new names")
+	}
+	if len(mapping) == 0 {
+		return nil, errors.New("RenameResource needs a mapping")
+	}
+	for from, to := range mapping {
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid mapping %q to %q", from, to)
+		}
+	}
+	return mapping, nil
+}
+
+// unitConversion turns a value into value*factor + offset, expressed in units
+type unitConversion struct {
+	factor float64
+	offset float64
+	units  string
+}
+
+// convertUnits converts the readings of each resource in the "conversions" parameter, for example
+// {"Temperature": {"factor": 1.8, "offset": 32, "units": "Fahrenheit"}}. factor defaults to 1 and
+// offset to 0; units, if given, replaces the units of converted readings. Integer readings become
+// Float64. A reading of a converted resource whose value is not numeric fails the transform rather
+// than passing on mixed units.
+func convertUnits(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	conversions, err := unitConversions(params)
+	if err != nil {
+		return event, "", err
+	}
+
+	readings := make([]models.Reading, len(event.Readings))
+	converted := 0
+	for i, reading := range event.Readings {
+		readings[i] = reading
+		conversion, ok := conversions[reading.ResourceName]
+		if !ok {
+			continue
+		}
+		value, err := readingValue(reading)
+		if err != nil {
+			return event, "", fmt.Errorf("cannot convert %s reading %q: %w", reading.ResourceName, reading.SimpleReading.Value, err)
+		}
+		value = value*conversion.factor + conversion.offset
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return event, "", fmt.Errorf("converting %s reading %q overflows", reading.ResourceName, reading.SimpleReading.Value)
+		}
+
+		if reading.ValueType == common.ValueTypeFloat32 {
+			reading.SimpleReading.Value = strconv.FormatFloat(value, 'f', -1, 32)
+		} else {
+			reading.ValueType = common.ValueTypeFloat64
+			reading.SimpleReading.Value = formatConverted(value)
+		}
+		if conversion.units != "" {
+			reading.SimpleReading.Units = conversion.units
+		}
+		readings[i] = reading
+		converted++
+	}
+	event.Readings = readings
+	return event, fmt.Sprintf("Converted units of %d readings", converted), nil
+}
+
+// formatConverted formats a converted value, rounded to 15 significant digits so that
+// artifacts of the float arithmetic (21*1.8+32 = 69.80000000000001) do not show
+func formatConverted(value float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// unitConversions reads the "conversions" parameter of a ConvertUnits transform
+func unitConversions(params map[string]interface{}) (map[string]unitConversion, error) {
+	raw, ok := params["conversions"].(map[string]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, errors.New("ConvertUnits needs conversions, a map of resource names to {factor, offset, units}")
+	}
+
+	// Resources are checked in order so the same parameters always report the same problem
+	resources := make([]string, 0, len(raw))
+	for resource := range raw {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	conversions := make(map[string]unitConversion, len(raw))
+	for _, resource := range resources {
+		spec, ok := raw[resource].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("conversion of %s must be an object", resource)
+		}
+		conversion := unitConversion{factor: 1}
+		var err error
+		if value, exists := spec["factor"]; exists {
+			if conversion.factor, err = numberParameter(value); err != nil {
+				return nil, fmt.Errorf("conversion of %s: invalid factor: %w", resource, err)
+			}
+		}
+		if value, exists := spec["offset"]; exists {
+			if conversion.offset, err = numberParameter(value); err != nil {
+				return nil, fmt.Errorf("conversion of %s: invalid offset: %w", resource, err)
+			}
+		}
+		if value, exists := spec["units"]; exists {
+			units, isString := value.(string)
+			if !isString {
+				return nil, fmt.Errorf("conversion of %s: units must be a string", resource)
+			}
+			conversion.units = strings.TrimSpace(units)
+		}
+		conversions[resource] = conversion
+	}
+	return conversions, nil
+}
+
+// numberParameter reads a numeric parameter given as a JSON number, a Go number or a numeric string
+func numberParameter(value interface{}) (float64, error) {
+	var number float64
+	switch typed := value.(type) {
+	case float64:
+		number = typed
+	case int:
+		number = float64(typed)
+	case string:
+		parsed, err := strconv.ParseFloat(typed, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", typed)
+		}
+		number = parsed
+	default:
+		return 0, fmt.Errorf("%v is not a number", value)
+	}
+	if math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, fmt.Errorf("%v is not a finite number", value)
+	}
+	return number, nil
+}
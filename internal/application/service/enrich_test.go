@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestAddTags(t *testing.T) {
+	event := temperatureEvent()
+	event.Tags = map[string]interface{}{"site": "old", "line": "A"}
+
+	tagged, message, err := addTags(event, map[string]interface{}{
+		"tags": map[string]interface{}{"site": "plant-7", "floor": float64(2)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Added 2 tags", message)
+	assert.Equal(t, map[string]interface{}{"site": "plant-7", "line": "A", "floor": float64(2)}, tagged.Tags)
+	assert.Equal(t, "old", event.Tags["site"], "the input event is not modified")
+
+	// Events without tags get a fresh map
+	tagged, _, err = addTags(models.Event{}, map[string]interface{}{"tags": map[string]string{"site": "plant-7"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"site": "plant-7"}, tagged.Tags)
+
+	for name, params := range map[string]map[string]interface{}{
+		"Missing":   {},
+		"Empty":     {"tags": map[string]interface{}{}},
+		"Not a map": {"tags": []interface{}{"site"}},
+	} {
+		_, _, err := addTags(event, params)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRenameResource(t *testing.T) {
+	event := temperatureEvent()
+	event.SourceName = "Temperature"
+
+	renamed, message, err := renameResource(event, map[string]interface{}{
+		"mapping": map[string]interface{}{"Temperature": "AmbientTemperature", "Pressure": "BarometricPressure"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed 1 readings", message)
+	assert.Equal(t, "AmbientTemperature", renamed.Readings[0].ResourceName)
+	assert.Equal(t, "Humidity", renamed.Readings[1].ResourceName)
+	assert.Equal(t, "AmbientTemperature", renamed.SourceName)
+	assert.Equal(t, "Temperature", event.Readings[0].ResourceName, "the input event is not modified")
+
+	for name, params := range map[string]map[string]interface{}{
+		"Missing":        {},
+		"Not a map":      {"mapping": "Temperature"},
+		"Non-string":     {"mapping": map[string]interface{}{"Temperature": float64(1)}},
+		"Empty new name": {"mapping": map[string]interface{}{"Temperature": ""}},
+	} {
+		_, _, err := renameResource(event, params)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	celsiusToFahrenheit := map[string]interface{}{
+		"conversions": map[string]interface{}{
+			"Temperature": map[string]interface{}{"factor": 1.8, "offset": float64(32), "units": "Fahrenheit"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		reading     models.Reading
+		params      map[string]interface{}
+		expectValue string
+		expectType  string
+		expectUnits string
+		expectError string
+	}{
+		{"Float", simpleReading("Temperature", common.ValueTypeFloat64, "35"), celsiusToFahrenheit, "95", common.ValueTypeFloat64, "Fahrenheit", ""},
+		{"Float32 keeps its type", simpleReading("Temperature", common.ValueTypeFloat32, "-40"), celsiusToFahrenheit, "-40", common.ValueTypeFloat32, "Fahrenheit", ""},
+		{"Integer becomes float", simpleReading("Temperature", common.ValueTypeInt16, "21"), celsiusToFahrenheit, "69.8", common.ValueTypeFloat64, "Fahrenheit", ""},
+		{"Other resource untouched", simpleReading("Humidity", common.ValueTypeFloat64, "40"), celsiusToFahrenheit, "40", common.ValueTypeFloat64, "", ""},
+		{
+			"Factor only, units kept",
+			simpleReading("Temperature", common.ValueTypeFloat64, "1.5"),
+			map[string]interface{}{"conversions": map[string]interface{}{"Temperature": map[string]interface{}{"factor": "1000"}}},
+			"1500", common.ValueTypeFloat64, "Celsius", "",
+		},
+		{"Non-numeric type", simpleReading("Temperature", common.ValueTypeString, "warm"), celsiusToFahrenheit, "", "", "", `cannot convert Temperature reading "warm": value type String is not numeric`},
+		{"Unparseable value", simpleReading("Temperature", common.ValueTypeFloat64, "n/a"), celsiusToFahrenheit, "", "", "", `cannot convert Temperature reading "n/a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.reading.SimpleReading.Units == "" && tt.reading.ResourceName == "Temperature" {
+				tt.reading.SimpleReading.Units = "Celsius"
+			}
+			event := models.Event{Readings: []models.Reading{tt.reading}}
+			converted, _, err := convertUnits(event, tt.params)
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			reading := converted.Readings[0]
+			assert.Equal(t, tt.expectValue, reading.SimpleReading.Value)
+			assert.Equal(t, tt.expectType, reading.ValueType)
+			assert.Equal(t, tt.expectUnits, reading.SimpleReading.Units)
+		})
+	}
+}
+
+func TestConvertUnits_InvalidParameters(t *testing.T) {
+	for name, params := range map[string]map[string]interface{}{
+		"Missing":          {},
+		"Not an object":    {"conversions": map[string]interface{}{"Temperature": float64(1.8)}},
+		"Bad factor":       {"conversions": map[string]interface{}{"Temperature": map[string]interface{}{"factor": "x"}}},
+		"Bad offset":       {"conversions": map[string]interface{}{"Temperature": map[string]interface{}{"offset": true}}},
+		"Non-string units": {"conversions": map[string]interface{}{"Temperature": map[string]interface{}{"units": float64(1)}}},
+	} {
+		_, err := unitConversions(params)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestNormalizationPipeline(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Id:   "pipeline-1",
+		Name: "normalize",
+		Transforms: []Transform{
+			{Type: "AddTags", Parameters: map[string]interface{}{"tags": map[string]interface{}{"site": "plant-7"}}},
+			{Type: "RenameResource", Parameters: map[string]interface{}{"mapping": map[string]interface{}{"Temperature": "TemperatureF"}}},
+			{Type: "ConvertUnits", Parameters: map[string]interface{}{"conversions": map[string]interface{}{
+				"TemperatureF": map[string]interface{}{"factor": 1.8, "offset": float64(32), "units": "Fahrenheit"},
+			}}},
+		},
+		Target: Target{Type: "FILE"},
+	}
+	require.Empty(t, service.validatePipeline(pipeline))
+
+	stages, _ := service.dryRun(context.Background(), pipeline, temperatureEvent())
+	require.Len(t, stages, 3)
+	event := stages[2].Events[0]
+	assert.Equal(t, "plant-7", event.Tags["site"])
+	assert.Equal(t, "TemperatureF", event.Readings[0].ResourceName)
+	assert.Equal(t, "95", event.Readings[0].SimpleReading.Value)
+	assert.Equal(t, "Fahrenheit", event.Readings[0].SimpleReading.Units)
+	assert.Equal(t, "40", event.Readings[1].SimpleReading.Value)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// DefaultExportTimeout bounds an HTTP export when its target sets no "timeout" parameter
+const DefaultExportTimeout = 10 * time.Second
+
+// Values of the "authMode" parameter of an HTTP target
+const (
+	AuthModeBasic  = "basic"
+	AuthModeAPIKey = "apikey"
+)
+
+// Keys read from the secret at an HTTP target's "secretPath"
+const (
+	SecretKeyUsername = "username"
+	SecretKeyPassword = "password"
+	SecretKeyAPIKey   = "apiKey"
+)
+
+// DefaultAPIKeyHeader carries the key of an apikey target when it sets no "headerName" parameter
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// SetSecretsClient sets where HTTP targets resolve their credentials. Must be called before Initialize.
+func (s *ApplicationService) SetSecretsClient(client secrets.SecretsClient) {
+	s.secretsClient = client
+}
+
+// exportHTTP POSTs the data, in the target's Format unless an earlier step serialized it, to the target's Host, Port and "path" parameter. Optional
+// parameters: "scheme" (default http), "timeout" as a duration string, and "authMode" basic or
+// apikey with credentials read from "secretPath" ("headerName" names the API key header).
+// A non-2xx response is an error; the status code is returned either way.
+func (s *ApplicationService) exportHTTP(ctx context.Context, data Payload, target Target) (string, int, error) {
+	address, err := exportURL(target)
+	if err != nil {
+		return "", 0, err
+	}
+
+	timeout := DefaultExportTimeout
+	if value, ok := target.Parameters["timeout"].(string); ok && value != "" {
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			return "", 0, fmt.Errorf("invalid timeout %q", value)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	data, err = data.Marshal()
+	if err != nil {
+		return "", 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(data.Body))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(common.ContentType, data.ContentType)
+	if len(data.ContentEncoding) > 0 {
+		req.Header.Set("Content-Encoding", strings.Join(data.ContentEncoding, ", "))
+	}
+	correlationID := bootstrap.CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		correlationID = models.GenerateUUID()
+	}
+	req.Header.Set(common.CorrelationHeader, correlationID)
+	if err := s.authorizeExport(req, target); err != nil {
+		return "", 0, err
+	}
+
+	s.logger.Debugf("Sending %s to HTTP endpoint %s", data.describe(), address)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to send to %s: %w", address, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", resp.StatusCode, fmt.Errorf("%s responded with %s", address, resp.Status)
+	}
+	return fmt.Sprintf("Sent to %s", address), resp.StatusCode, nil
+}
+
+// exportURL builds the URL of an HTTP target
+func exportURL(target Target) (string, error) {
+	if target.Host == "" {
+		return "", errors.New("HTTP target needs a host")
+	}
+	scheme := "http"
+	if value, ok := target.Parameters["scheme"].(string); ok && value != "" {
+		scheme = value
+	}
+	host := target.Host
+	if target.Port != 0 {
+		host += ":" + strconv.Itoa(target.Port)
+	}
+	path, _ := target.Parameters["path"].(string)
+
+	address := url.URL{Scheme: scheme, Host: host, Path: path}
+	return address.String(), nil
+}
+
+// authorizeExport adds the credentials named by the target's "authMode" to req
+func (s *ApplicationService) authorizeExport(req *http.Request, target Target) error {
+	authMode, _ := target.Parameters["authMode"].(string)
+	if authMode == "" {
+		return nil
+	}
+	secretPath, _ := target.Parameters["secretPath"].(string)
+	if secretPath == "" {
+		return fmt.Errorf("authMode %s needs a secretPath", authMode)
+	}
+	if s.secretsClient == nil {
+		return errors.New("no secrets client to resolve credentials")
+	}
+
+	switch authMode {
+	case AuthModeBasic:
+		secret, err := s.secretsClient.GetSecret(secretPath, SecretKeyUsername, SecretKeyPassword)
+		if err != nil {
+			return fmt.Errorf("failed to read credentials from %s: %w", secretPath, err)
+		}
+		req.SetBasicAuth(secret[SecretKeyUsername], secret[SecretKeyPassword])
+	case AuthModeAPIKey:
+		secret, err := s.secretsClient.GetSecret(secretPath, SecretKeyAPIKey)
+		if err != nil {
+			return fmt.Errorf("failed to read API key from %s: %w", secretPath, err)
+		}
+		header, _ := target.Parameters["headerName"].(string)
+		if header == "" {
+			header = DefaultAPIKeyHeader
+		}
+		req.Header.Set(header, secret[SecretKeyAPIKey])
+	default:
+		return fmt.Errorf("invalid authMode %q", authMode)
+	}
+	return nil
+}
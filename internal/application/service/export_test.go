@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// httpTarget returns an HTTP target pointing at server with the given parameters
+func httpTarget(t *testing.T, server *httptest.Server, parameters map[string]interface{}) Target {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return Target{Type: "HTTP", Host: host, Port: portNumber, Parameters: parameters}
+}
+
+func TestExportHTTP(t *testing.T) {
+	var received *http.Request
+	var receivedEvent models.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &receivedEvent))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, secretsClient.StoreSecret("export/cloud", map[string]string{
+		SecretKeyUsername: "edge",
+		SecretKeyPassword: "s3cret",
+		SecretKeyAPIKey:   "key-123",
+	}))
+	service, _ := newTestService()
+	service.SetSecretsClient(secretsClient)
+
+	pipeline := Pipeline{
+		Name: "export",
+		Transforms: []Transform{
+			{Type: "FilterByResourceName", Parameters: map[string]interface{}{"include": []interface{}{"Temperature"}}},
+		},
+		Target: httpTarget(t, server, map[string]interface{}{"path": "/ingest/events", "authMode": AuthModeBasic, "secretPath": "export/cloud"}),
+	}
+	event := models.Event{Id: "event-1", DeviceName: "Boiler-1", Readings: []models.Reading{
+		simpleReading("Temperature", common.ValueTypeFloat64, "80"),
+		simpleReading("Humidity", common.ValueTypeFloat64, "40"),
+	}}
+	ctx := bootstrap.WithCorrelationID(context.Background(), "correlation-1")
+
+	result := service.executePipeline(ctx, event, pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Equal(t, http.StatusAccepted, result["targetStatusCode"])
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/ingest/events", received.URL.Path)
+	assert.Equal(t, common.ContentTypeJSON, received.Header.Get(common.ContentType))
+	assert.Equal(t, "correlation-1", received.Header.Get(common.CorrelationHeader))
+	username, password, ok := received.BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "edge", username)
+	assert.Equal(t, "s3cret", password)
+
+	// The transformed event is sent, not the original
+	assert.Equal(t, "event-1", receivedEvent.Id)
+	require.Len(t, receivedEvent.Readings, 1)
+	assert.Equal(t, "Temperature", receivedEvent.Readings[0].ResourceName)
+
+	// API keys go in the configured header
+	pipeline.Target.Parameters = map[string]interface{}{"authMode": AuthModeAPIKey, "secretPath": "export/cloud", "headerName": "X-Token"}
+	result = service.executePipeline(context.Background(), event, pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Equal(t, "key-123", received.Header.Get("X-Token"))
+	assert.NotEmpty(t, received.Header.Get(common.CorrelationHeader))
+}
+
+func TestExportHTTP_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	service, _ := newTestService()
+
+	tests := []struct {
+		name       string
+		parameters map[string]interface{}
+		statusCode interface{}
+		message    string
+	}{
+		{"Non-2xx", map[string]interface{}{"path": "/ingest"}, http.StatusServiceUnavailable, "503"},
+		{"Timeout", map[string]interface{}{"path": "/slow", "timeout": "20ms"}, nil, "deadline exceeded"},
+		{"Invalid timeout", map[string]interface{}{"timeout": "soon"}, nil, "invalid timeout"},
+		{"Missing secret path", map[string]interface{}{"authMode": AuthModeBasic}, nil, "needs a secretPath"},
+		{"No secrets client", map[string]interface{}{"authMode": AuthModeBasic, "secretPath": "export/cloud"}, nil, "no secrets client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := Pipeline{Name: "export", Target: httpTarget(t, server, tt.parameters)}
+			result := service.executePipeline(context.Background(), models.Event{Id: "event-1"}, pipeline)
+
+			assert.Equal(t, "error", result["status"])
+			assert.Contains(t, result["error"], tt.message)
+			assert.Equal(t, tt.statusCode, result["targetStatusCode"])
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// ErrFilteredOut is returned by a filter transform that drops the event, ending the pipeline for it
+var ErrFilteredOut = errors.New("event filtered out")
+
+// filterByDeviceName keeps the event only if its device name passes the include and exclude
+// lists in params. See newNameMatcher for the parameters.
+func filterByDeviceName(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	matcher, err := newNameMatcher(params)
+	if err != nil {
+		return event, "", err
+	}
+	if !matcher.matches(event.DeviceName) {
+		return event, "", fmt.Errorf("device %s: %w", event.DeviceName, ErrFilteredOut)
+	}
+	return event, "Device name filter passed", nil
+}
+
+// filterByResourceName keeps the readings whose resource name passes the include and exclude lists
+// in params, dropping the event if none are left. See newNameMatcher for the parameters.
+func filterByResourceName(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	matcher, err := newNameMatcher(params)
+	if err != nil {
+		return event, "", err
+	}
+
+	readings := []models.Reading{}
+	for _, reading := range event.Readings {
+		if matcher.matches(reading.ResourceName) {
+			readings = append(readings, reading)
+		}
+	}
+	if len(readings) == 0 {
+		return event, "", fmt.Errorf("no readings left: %w", ErrFilteredOut)
+	}
+
+	event.Readings = readings
+	return event, fmt.Sprintf("Resource name filter kept %d readings", len(readings)), nil
+}
+
+// filterByValue compares the readings of a resource against a threshold. Readings of the resource
+// that fail the comparison are dropped, and the event is dropped if none pass. Parameters:
+// "resource", "operator" (one of >, >=, <, <=, ==) and "threshold"; a "condition" such as
+// "temperature > 30" may stand in for the operator and threshold.
+func filterByValue(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	condition, err := newValueCondition(params)
+	if err != nil {
+		return event, "", err
+	}
+
+	readings := []models.Reading{}
+	passed := 0
+	for _, reading := range event.Readings {
+		if reading.ResourceName != condition.resource {
+			readings = append(readings, reading)
+			continue
+		}
+		value, err := readingValue(reading)
+		if err != nil || !condition.holds(value) {
+			continue
+		}
+		readings = append(readings, reading)
+		passed++
+	}
+	if passed == 0 {
+		return event, "", fmt.Errorf("no %s reading %s %v: %w", condition.resource, condition.operator, condition.threshold, ErrFilteredOut)
+	}
+
+	event.Readings = readings
+	return event, fmt.Sprintf("Value filter kept %d %s readings", passed, condition.resource), nil
+}
+
+// nameMatcher tests names against include and exclude lists
+type nameMatcher struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// newNameMatcher reads "include" and "exclude" lists of names from params. With "regex" true the
+// entries are regular expressions that must match the whole name. An empty include list includes everything.
+func newNameMatcher(params map[string]interface{}) (nameMatcher, error) {
+	useRegex, _ := params["regex"].(bool)
+
+	var matcher nameMatcher
+	var err error
+	if matcher.include, err = namePatterns(params, "include", useRegex); err != nil {
+		return matcher, err
+	}
+	if matcher.exclude, err = namePatterns(params, "exclude", useRegex); err != nil {
+		return matcher, err
+	}
+	if len(matcher.include) == 0 && len(matcher.exclude) == 0 {
+		return matcher, errors.New("filter needs an include or exclude list")
+	}
+	return matcher, nil
+}
+
+// namePatterns compiles the list parameter key into anchored patterns
+func namePatterns(params map[string]interface{}, key string, useRegex bool) ([]*regexp.Regexp, error) {
+	raw, exists := params[key]
+	if !exists {
+		return nil, nil
+	}
+
+	var names []string
+	switch list := raw.(type) {
+	case []string:
+		names = list
+	case []interface{}:
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", key)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings", key)
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(names))
+	for _, name := range names {
+		expression := regexp.QuoteMeta(name)
+		if useRegex {
+			expression = name
+		}
+		pattern, err := regexp.Compile("^(?:" + expression + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", key, name, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matches reports whether name is included and not excluded
+func (m nameMatcher) matches(name string) bool {
+	for _, pattern := range m.exclude {
+		if pattern.MatchString(name) {
+			return false
+		}
+	}
+	if len(m.include) == 0 {
+		return true
+	}
+	for _, pattern := range m.include {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// valueCondition compares reading values of a resource against a threshold
+type valueCondition struct {
+	resource  string
+	operator  string
+	threshold float64
+}
+
+// newValueCondition reads a value condition from params
+func newValueCondition(params map[string]interface{}) (valueCondition, error) {
+	condition := valueCondition{}
+	condition.resource, _ = params["resource"].(string)
+	if condition.resource == "" {
+		return condition, errors.New("filter needs a resource")
+	}
+
+	condition.operator, _ = params["operator"].(string)
+	threshold, hasThreshold := params["threshold"]
+	if expression, ok := params["condition"].(string); ok && condition.operator == "" && !hasThreshold {
+		fields := strings.Fields(expression)
+		if len(fields) != 3 {
+			return condition, fmt.Errorf("invalid condition %q, expected e.g. \"temperature > 30\"", expression)
+		}
+		condition.operator, threshold, hasThreshold = fields[1], fields[2], true
+	}
+
+	switch condition.operator {
+	case ">", ">=", "<", "<=", "==":
+	default:
+		return condition, fmt.Errorf("invalid operator %q", condition.operator)
+	}
+
+	if !hasThreshold {
+		return condition, errors.New("filter needs a threshold")
+	}
+	switch value := threshold.(type) {
+	case float64:
+		condition.threshold = value
+	case int:
+		condition.threshold = float64(value)
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return condition, fmt.Errorf("invalid threshold %q", value)
+		}
+		condition.threshold = parsed
+	default:
+		return condition, fmt.Errorf("invalid threshold %v", threshold)
+	}
+	return condition, nil
+}
+
+// holds reports whether value satisfies the condition
+func (c valueCondition) holds(value float64) bool {
+	switch c.operator {
+	case ">":
+		return value > c.threshold
+	case ">=":
+		return value >= c.threshold
+	case "<":
+		return value < c.threshold
+	case "<=":
+		return value <= c.threshold
+	default:
+		return value == c.threshold
+	}
+}
+
+// readingValue parses the value of a simple reading according to its value type
+func readingValue(reading models.Reading) (float64, error) {
+	value := reading.SimpleReading.Value
+	switch reading.ValueType {
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		return float64(parsed), err
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		return float64(parsed), err
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return 0, fmt.Errorf("value type %s is not numeric", reading.ValueType)
+	}
+}
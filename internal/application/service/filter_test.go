@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func simpleReading(resourceName, valueType, value string) models.Reading {
+	return models.Reading{ResourceName: resourceName, ValueType: valueType, SimpleReading: models.SimpleReading{Value: value}}
+}
+
+func TestFilterByValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		reading   models.Reading
+		kept      bool
+		malformed bool
+	}{
+		{"Float above", map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 30.0}, simpleReading("Temperature", common.ValueTypeFloat64, "30.5"), true, false},
+		{"Float equal is not above", map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 30.0}, simpleReading("Temperature", common.ValueTypeFloat32, "30"), false, false},
+		{"Float equal", map[string]interface{}{"resource": "Temperature", "operator": ">=", "threshold": 30}, simpleReading("Temperature", common.ValueTypeFloat32, "30"), true, false},
+		{"Int below", map[string]interface{}{"resource": "Count", "operator": "<", "threshold": "10"}, simpleReading("Count", common.ValueTypeInt16, "-3"), true, false},
+		{"Int not below", map[string]interface{}{"resource": "Count", "operator": "<=", "threshold": 10}, simpleReading("Count", common.ValueTypeInt64, "11"), false, false},
+		{"Uint equal", map[string]interface{}{"resource": "Level", "operator": "==", "threshold": 7}, simpleReading("Level", common.ValueTypeUint8, "7"), true, false},
+		{"Negative uint is rejected", map[string]interface{}{"resource": "Level", "operator": "<", "threshold": 7}, simpleReading("Level", common.ValueTypeUint8, "-1"), false, false},
+		{"Non-numeric type", map[string]interface{}{"resource": "State", "operator": ">", "threshold": 0}, simpleReading("State", common.ValueTypeString, "1"), false, false},
+		{"Unparseable value", map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 0}, simpleReading("Temperature", common.ValueTypeFloat64, "hot"), false, false},
+		{"Other resource only", map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 0}, simpleReading("Humidity", common.ValueTypeFloat64, "50"), false, false},
+		{"Condition shorthand", map[string]interface{}{"resource": "Temperature", "condition": "temperature > 30"}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), true, false},
+		{"Missing resource", map[string]interface{}{"operator": ">", "threshold": 30}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), false, true},
+		{"Unknown operator", map[string]interface{}{"resource": "Temperature", "operator": "!=", "threshold": 30}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), false, true},
+		{"Missing threshold", map[string]interface{}{"resource": "Temperature", "operator": ">"}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), false, true},
+		{"Bad threshold", map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": "thirty"}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), false, true},
+		{"Bad condition", map[string]interface{}{"resource": "Temperature", "condition": "temperature>30"}, simpleReading("Temperature", common.ValueTypeFloat64, "31"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := models.Event{Id: "event-1", Readings: []models.Reading{tt.reading}}
+			_, _, err := filterByValue(event, tt.params)
+			switch {
+			case tt.malformed:
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, ErrFilteredOut)
+			case tt.kept:
+				assert.NoError(t, err)
+			default:
+				assert.ErrorIs(t, err, ErrFilteredOut)
+			}
+		})
+	}
+}
+
+func TestFilterByValue_DropsFailingReadings(t *testing.T) {
+	event := models.Event{Readings: []models.Reading{
+		simpleReading("Temperature", common.ValueTypeFloat64, "25"),
+		simpleReading("Temperature", common.ValueTypeFloat64, "35"),
+		simpleReading("Humidity", common.ValueTypeFloat64, "80"),
+	}}
+
+	filtered, _, err := filterByValue(event, map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 30})
+	require.NoError(t, err)
+	require.Len(t, filtered.Readings, 2)
+	assert.Equal(t, "35", filtered.Readings[0].SimpleReading.Value)
+	assert.Equal(t, "Humidity", filtered.Readings[1].ResourceName)
+	assert.Len(t, event.Readings, 3, "the input event is not modified")
+}
+
+func TestFilterByName(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		device    bool
+		resources []string
+		malformed bool
+	}{
+		{"Include list", map[string]interface{}{"include": []interface{}{"Boiler-1", "Temperature"}}, true, []string{"Temperature"}, false},
+		{"Include regex", map[string]interface{}{"include": []interface{}{"Boiler-[0-9]+", "Temp.*"}, "regex": true}, true, []string{"Temperature"}, false},
+		{"Literal names do not match as regex", map[string]interface{}{"include": []interface{}{"Boiler-.*", "Temp.*"}}, false, nil, false},
+		{"Exclude list", map[string]interface{}{"exclude": []string{"Temperature"}}, true, []string{"Humidity"}, false},
+		{"Exclude wins over include", map[string]interface{}{"include": []interface{}{".*"}, "exclude": []interface{}{"Boiler-1", "Humidity"}, "regex": true}, false, []string{"Temperature"}, false},
+		{"Empty lists", map[string]interface{}{}, false, nil, true},
+		{"Not a list", map[string]interface{}{"include": "Boiler-1"}, false, nil, true},
+		{"Bad regex", map[string]interface{}{"include": []interface{}{"("}, "regex": true}, false, nil, true},
+	}
+
+	event := models.Event{DeviceName: "Boiler-1", Readings: []models.Reading{
+		simpleReading("Temperature", common.ValueTypeFloat64, "80"),
+		simpleReading("Humidity", common.ValueTypeFloat64, "40"),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := filterByDeviceName(event, tt.params)
+			switch {
+			case tt.malformed:
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, ErrFilteredOut)
+			case tt.device:
+				assert.NoError(t, err)
+			default:
+				assert.ErrorIs(t, err, ErrFilteredOut)
+			}
+
+			filtered, _, err := filterByResourceName(event, tt.params)
+			if tt.malformed {
+				assert.Error(t, err)
+				return
+			}
+			if tt.resources == nil {
+				assert.ErrorIs(t, err, ErrFilteredOut)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, reading := range filtered.Readings {
+				names = append(names, reading.ResourceName)
+			}
+			assert.Equal(t, tt.resources, names)
+		})
+	}
+}
+
+func TestExecutePipeline_FilteredOut(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Id:   "pipeline-1",
+		Name: "hot",
+		Transforms: []Transform{
+			{Type: "Filter", Parameters: map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 30}},
+			{Type: "Convert", Parameters: map[string]interface{}{"format": "json"}},
+		},
+		Target: Target{Type: "FILE"},
+	}
+
+	result := service.executePipeline(context.Background(), models.Event{Readings: []models.Reading{simpleReading("Temperature", common.ValueTypeFloat64, "20")}}, pipeline)
+	assert.Equal(t, true, result["filteredOut"])
+	assert.Equal(t, "success", result["status"])
+	assert.NotContains(t, result, "targetResult")
+	assert.Len(t, result["transformResults"], 1, "transforms after the filter do not run")
+
+	result = service.executePipeline(context.Background(), models.Event{Readings: []models.Reading{simpleReading("Temperature", common.ValueTypeFloat64, "40")}}, pipeline)
+	assert.Equal(t, false, result["filteredOut"])
+	assert.Equal(t, "Written to file", result["targetResult"])
+
+	pipeline.Transforms[0].Parameters["operator"] = "~"
+	result = service.executePipeline(context.Background(), models.Event{}, pipeline)
+	assert.Equal(t, "error", result["status"])
+	assert.Contains(t, result["error"], "invalid operator")
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Target formats, which say how events are serialized for a target
+const (
+	FormatJSON        = "json"
+	FormatXML         = "xml"
+	FormatCloudEvents = "cloudevents"
+)
+
+// CloudEvents content types for a single event and for a batch, in structured mode
+const (
+	ContentTypeCloudEvents      = "application/cloudevents+json"
+	ContentTypeCloudEventsBatch = "application/cloudevents-batch+json"
+)
+
+// CloudEventsSpecVersion is the CloudEvents version events are sent as
+const CloudEventsSpecVersion = "1.0"
+
+// CloudEventTypePrefix starts the type of every CloudEvent sent; the event's profile name follows it
+const CloudEventTypePrefix = "org.edgexfoundry.event"
+
+// validFormat reports whether format names a target format; empty means FormatJSON
+func validFormat(format string) bool {
+	switch strings.ToLower(format) {
+	case "", FormatJSON, FormatXML, FormatCloudEvents:
+		return true
+	}
+	return false
+}
+
+// marshalEvents serializes the event, or batch of events, of data in format and returns the body with
+// its content type
+func marshalEvents(data Payload, format string) ([]byte, string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		body, err := json.Marshal(data.payload())
+		return body, common.ContentTypeJSON, err
+	case FormatXML:
+		var document interface{}
+		if data.Batched {
+			batch := xmlEvents{Events: make([]xmlEvent, len(data.Events))}
+			for i, event := range data.Events {
+				batch.Events[i] = newXMLEvent(event)
+			}
+			document = batch
+		} else {
+			document = newXMLEvent(data.Events[0])
+		}
+		body, err := xml.Marshal(document)
+		if err != nil {
+			return nil, "", err
+		}
+		return append([]byte(xml.Header), body...), common.ContentTypeXML, nil
+	case FormatCloudEvents:
+		if data.Batched {
+			batch := make([]CloudEvent, len(data.Events))
+			for i, event := range data.Events {
+				batch[i] = NewCloudEvent(event)
+			}
+			body, err := json.Marshal(batch)
+			return body, ContentTypeCloudEventsBatch, err
+		}
+		body, err := json.Marshal(NewCloudEvent(data.Events[0]))
+		return body, ContentTypeCloudEvents, err
+	default:
+		return nil, "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// CloudEvent is an event as a CloudEvents 1.0 structured-mode JSON event, with the event in data
+type CloudEvent struct {
+	SpecVersion     string       `json:"specversion"`
+	Id              string       `json:"id"`
+	Source          string       `json:"source"`
+	Type            string       `json:"type"`
+	Subject         string       `json:"subject,omitempty"`
+	Time            string       `json:"time,omitempty"`
+	DataContentType string       `json:"datacontenttype"`
+	Data            models.Event `json:"data"`
+}
+
+// NewCloudEvent wraps event as a CloudEvent. The id is the event's id, the source names the device,
+// the type is CloudEventTypePrefix followed by the profile name, the subject is the source name and
+// the time is the event's origin, taken to be in milliseconds.
+func NewCloudEvent(event models.Event) CloudEvent {
+	cloudEvent := CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		Id:              event.Id,
+		Source:          "/" + common.AppServiceConfigurableKey + "/device/" + url.PathEscape(event.DeviceName),
+		Type:            CloudEventTypePrefix,
+		Subject:         event.SourceName,
+		DataContentType: common.ContentTypeJSON,
+		Data:            event,
+	}
+	// CloudEvents requires an id, and it must differ between events from the same source
+	if cloudEvent.Id == "" {
+		cloudEvent.Id = models.GenerateUUID()
+	}
+	if event.ProfileName != "" {
+		cloudEvent.Type += "." + event.ProfileName
+	}
+	if event.Origin > 0 {
+		cloudEvent.Time = time.UnixMilli(event.Origin).UTC().Format(time.RFC3339Nano)
+	}
+	return cloudEvent
+}
+
+// xmlEvents is a batch of events in the XML format
+type xmlEvents struct {
+	XMLName xml.Name   `xml:"events"`
+	Events  []xmlEvent `xml:"event"`
+}
+
+// xmlEvent is an event in the XML format. Tag values that are not strings are written as JSON.
+type xmlEvent struct {
+	XMLName     xml.Name     `xml:"event"`
+	Id          string       `xml:"id,attr"`
+	DeviceName  string       `xml:"deviceName"`
+	ProfileName string       `xml:"profileName"`
+	SourceName  string       `xml:"sourceName"`
+	Origin      int64        `xml:"origin"`
+	Tags        []xmlTag     `xml:"tags>tag,omitempty"`
+	Readings    []xmlReading `xml:"readings>reading"`
+}
+
+// xmlReading is a reading in the XML format. Binary values are base64 and object values JSON.
+type xmlReading struct {
+	Id           string   `xml:"id,attr"`
+	Origin       int64    `xml:"origin"`
+	ResourceName string   `xml:"resourceName"`
+	ValueType    string   `xml:"valueType"`
+	Value        string   `xml:"value,omitempty"`
+	Units        string   `xml:"units,omitempty"`
+	BinaryValue  string   `xml:"binaryValue,omitempty"`
+	MediaType    string   `xml:"mediaType,omitempty"`
+	ObjectValue  string   `xml:"objectValue,omitempty"`
+	Tags         []xmlTag `xml:"tags>tag,omitempty"`
+}
+
+// xmlTag is one tag of an event or reading
+type xmlTag struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// newXMLEvent converts event to the XML format
+func newXMLEvent(event models.Event) xmlEvent {
+	document := xmlEvent{
+		Id:          event.Id,
+		DeviceName:  event.DeviceName,
+		ProfileName: event.ProfileName,
+		SourceName:  event.SourceName,
+		Origin:      event.Origin,
+		Tags:        newXMLTags(event.Tags),
+		Readings:    make([]xmlReading, len(event.Readings)),
+	}
+	for i, reading := range event.Readings {
+		document.Readings[i] = xmlReading{
+			Id:           reading.Id,
+			Origin:       reading.Origin,
+			ResourceName: reading.ResourceName,
+			ValueType:    reading.ValueType,
+			Value:        reading.SimpleReading.Value,
+			Units:        reading.SimpleReading.Units,
+			MediaType:    reading.BinaryReading.MediaType,
+			Tags:         newXMLTags(reading.Tags),
+		}
+		if len(reading.BinaryReading.BinaryValue) > 0 {
+			document.Readings[i].BinaryValue = base64.StdEncoding.EncodeToString(reading.BinaryReading.BinaryValue)
+		}
+		if reading.ObjectReading.ObjectValue != nil {
+			document.Readings[i].ObjectValue = xmlValue(reading.ObjectReading.ObjectValue)
+		}
+	}
+	return document
+}
+
+// newXMLTags converts tags to the XML format, sorted by name so the output is stable
+func newXMLTags(tags map[string]interface{}) []xmlTag {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	converted := make([]xmlTag, len(names))
+	for i, name := range names {
+		converted[i] = xmlTag{Name: name, Value: xmlValue(tags[name])}
+	}
+	return converted
+}
+
+// xmlValue writes a string as it is and any other value as JSON
+func xmlValue(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
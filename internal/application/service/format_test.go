@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// formatEvent returns an event with a reading of each kind and tags of several types
+func formatEvent() models.Event {
+	return models.Event{
+		Id:          "event-1",
+		DeviceName:  "Boiler 1",
+		ProfileName: "BoilerProfile",
+		SourceName:  "Status",
+		Origin:      1700000000123,
+		Tags:        map[string]interface{}{"site": "plant-7", "floor": float64(2)},
+		Readings: []models.Reading{
+			{Id: "reading-1", Origin: 1700000000123, ResourceName: "Temperature", ValueType: common.ValueTypeFloat64, SimpleReading: models.SimpleReading{Value: "80.5", Units: "Celsius"}},
+			{Id: "reading-2", ResourceName: "Snapshot", ValueType: common.ValueTypeBinary, BinaryReading: models.BinaryReading{BinaryValue: []byte{0, 1, 0xfe}, MediaType: "image/png"}},
+			{Id: "reading-3", ResourceName: "Config", ValueType: "Object", ObjectReading: models.ObjectReading{ObjectValue: map[string]interface{}{"mode": "eco"}}},
+		},
+	}
+}
+
+func TestMarshalEvents_JSON(t *testing.T) {
+	for _, format := range []string{"", FormatJSON, "JSON"} {
+		data, err := eventData(formatEvent(), format).Marshal()
+		require.NoError(t, err)
+		assert.Equal(t, common.ContentTypeJSON, data.ContentType)
+		var event models.Event
+		require.NoError(t, json.Unmarshal(data.Body, &event))
+		assert.Equal(t, "event-1", event.Id)
+	}
+}
+
+func TestMarshalEvents_XML(t *testing.T) {
+	data, err := eventData(formatEvent(), FormatXML).Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, common.ContentTypeXML, data.ContentType)
+	assert.True(t, strings.HasPrefix(string(data.Body), xml.Header))
+	assert.Contains(t, string(data.Body), `<event id="event-1"><deviceName>Boiler 1</deviceName>`)
+
+	var event xmlEvent
+	require.NoError(t, xml.Unmarshal(data.Body, &event))
+	assert.Equal(t, "event-1", event.Id)
+	assert.Equal(t, "Boiler 1", event.DeviceName)
+	assert.Equal(t, "BoilerProfile", event.ProfileName)
+	assert.Equal(t, "Status", event.SourceName)
+	assert.Equal(t, int64(1700000000123), event.Origin)
+	assert.Equal(t, []xmlTag{{Name: "floor", Value: "2"}, {Name: "site", Value: "plant-7"}}, event.Tags)
+	require.Len(t, event.Readings, 3)
+	assert.Equal(t, xmlReading{Id: "reading-1", Origin: 1700000000123, ResourceName: "Temperature", ValueType: common.ValueTypeFloat64, Value: "80.5", Units: "Celsius"}, event.Readings[0])
+	assert.Equal(t, "AAH+", event.Readings[1].BinaryValue)
+	assert.Equal(t, "image/png", event.Readings[1].MediaType)
+	assert.Equal(t, `{"mode":"eco"}`, event.Readings[2].ObjectValue)
+
+	// A batch is an events document
+	data, err = batchData([]models.Event{formatEvent(), {Id: "event-2"}}, FormatXML).Marshal()
+	require.NoError(t, err)
+	var batch xmlEvents
+	require.NoError(t, xml.Unmarshal(data.Body, &batch))
+	require.Len(t, batch.Events, 2)
+	assert.Equal(t, "event-2", batch.Events[1].Id)
+	assert.Empty(t, batch.Events[1].Readings)
+}
+
+func TestMarshalEvents_CloudEvents(t *testing.T) {
+	data, err := eventData(formatEvent(), FormatCloudEvents).Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeCloudEvents, data.ContentType)
+
+	var attributes map[string]interface{}
+	require.NoError(t, json.Unmarshal(data.Body, &attributes))
+	assert.Equal(t, "1.0", attributes["specversion"])
+	assert.Equal(t, "event-1", attributes["id"])
+	assert.Equal(t, "/app-service-configurable/device/Boiler%201", attributes["source"])
+	assert.Equal(t, "org.edgexfoundry.event.BoilerProfile", attributes["type"])
+	assert.Equal(t, "Status", attributes["subject"])
+	assert.Equal(t, "2023-11-14T22:13:20.123Z", attributes["time"])
+	assert.Equal(t, common.ContentTypeJSON, attributes["datacontenttype"])
+
+	// The event round-trips through data
+	var cloudEvent CloudEvent
+	require.NoError(t, json.Unmarshal(data.Body, &cloudEvent))
+	expected, err := json.Marshal(formatEvent())
+	require.NoError(t, err)
+	actual, err := json.Marshal(cloudEvent.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+
+	// A batch is a JSON array of CloudEvents
+	data, err = batchData([]models.Event{formatEvent(), {DeviceName: "Pump"}}, FormatCloudEvents).Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeCloudEventsBatch, data.ContentType)
+	var batch []CloudEvent
+	require.NoError(t, json.Unmarshal(data.Body, &batch))
+	require.Len(t, batch, 2)
+	assert.NotEmpty(t, batch[1].Id, "events without an id get one")
+	assert.Equal(t, CloudEventTypePrefix, batch[1].Type)
+	assert.Empty(t, batch[1].Time)
+}
+
+func TestValidatePipeline_Format(t *testing.T) {
+	service, _ := newTestService()
+	for _, format := range []string{"", FormatJSON, FormatXML, FormatCloudEvents, "XML"} {
+		pipeline := Pipeline{Name: "formatted", Target: Target{Type: "FILE", Format: format}}
+		assert.Empty(t, service.validatePipeline(pipeline), format)
+	}
+	pipeline := Pipeline{Name: "formatted", Target: Target{Type: "FILE", Format: "protobuf"}}
+	assert.Equal(t, []string{`target: invalid format "protobuf", must be json, xml or cloudevents`}, service.validatePipeline(pipeline))
+}
+
+func TestExportFormats(t *testing.T) {
+	var contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get(common.ContentType)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	service, _ := newTestService()
+	broker := installFakeMQTT(service)
+
+	for format, expectedType := range map[string]string{
+		FormatJSON:        common.ContentTypeJSON,
+		FormatXML:         common.ContentTypeXML,
+		FormatCloudEvents: ContentTypeCloudEvents,
+	} {
+		target := httpTarget(t, server, nil)
+		target.Format = format
+		result := service.executePipeline(context.Background(), formatEvent(), Pipeline{Name: format, Target: target})
+		require.Equal(t, "success", result["status"], result["error"])
+		assert.Equal(t, expectedType, contentType, format)
+
+		mqttTarget := Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export", Format: format}
+		result = service.executePipeline(context.Background(), formatEvent(), Pipeline{Name: format, Target: mqttTarget})
+		require.Equal(t, "success", result["status"], result["error"])
+		published := broker.sender.published[len(broker.sender.published)-1]
+		assert.Equal(t, body, published.payload, "MQTT publishes what HTTP sends for %s", format)
+	}
+
+	// Serializing transforms use the target's format too
+	target := httpTarget(t, server, nil)
+	target.Format = FormatXML
+	pipeline := Pipeline{Name: "compressed", Transforms: []Transform{{Type: "Compress"}}, Target: target}
+	result := service.executePipeline(context.Background(), formatEvent(), pipeline)
+	require.Equal(t, "success", result["status"], result["error"])
+	assert.Equal(t, common.ContentTypeXML, contentType)
+}
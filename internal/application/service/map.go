@@ -0,0 +1,215 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// eventMapping reshapes events into flat JSON objects for a Map transform. Its "fields" parameter maps
+// output fields to JSONPaths into the event, such as $.deviceName, $.tags.site or $.readings[0].value;
+// paths that lead nowhere leave their field out. With "readings" true, each reading's value is added
+// under its resource name, as a number or bool when its value type is one. Fields win over readings.
+type eventMapping struct {
+	fields   map[string][]pathStep
+	readings bool
+}
+
+// pathStep is one step of a JSONPath: a member name, or an index when name is empty
+type pathStep struct {
+	name  string
+	index int
+}
+
+// newEventMapping reads the parameters of a Map transform
+func newEventMapping(parameters map[string]interface{}) (eventMapping, error) {
+	mapping := eventMapping{fields: make(map[string][]pathStep)}
+	if value, ok := parameters["readings"]; ok {
+		readings, isBool := value.(bool)
+		if !isBool {
+			return mapping, fmt.Errorf("readings must be true or false, got %v", value)
+		}
+		mapping.readings = readings
+	}
+	if value, ok := parameters["fields"]; ok {
+		fields, isObject := value.(map[string]interface{})
+		if !isObject {
+			return mapping, fmt.Errorf("fields must map output fields to JSONPaths, got %v", value)
+		}
+		for field, value := range fields {
+			path, isString := value.(string)
+			if !isString {
+				return mapping, fmt.Errorf("fields.%s must be a JSONPath, got %v", field, value)
+			}
+			steps, err := parseJSONPath(path)
+			if err != nil {
+				return mapping, fmt.Errorf("fields.%s: %w", field, err)
+			}
+			mapping.fields[field] = steps
+		}
+	}
+	if len(mapping.fields) == 0 && !mapping.readings {
+		return mapping, errors.New("map needs fields, readings or both")
+	}
+	return mapping, nil
+}
+
+// parseJSONPath parses the subset of JSONPath a Map transform understands: $ followed by .member
+// and [index] steps
+func parseJSONPath(path string) ([]pathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+	var steps []pathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			if end == 1 {
+				return nil, fmt.Errorf("path %q has an empty member name", path)
+			}
+			steps = append(steps, pathStep{name: rest[1:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed [", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has an invalid index %q", path, rest[1:end])
+			}
+			steps = append(steps, pathStep{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q: expected . or [ at %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// lookup follows steps through a decoded JSON document
+func lookup(document interface{}, steps []pathStep) (interface{}, bool) {
+	current := document
+	for _, step := range steps {
+		if step.name != "" {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[step.name]; !ok {
+				return nil, false
+			}
+			continue
+		}
+		array, ok := current.([]interface{})
+		if !ok || step.index >= len(array) {
+			return nil, false
+		}
+		current = array[step.index]
+	}
+	return current, true
+}
+
+// apply maps one event to its flat object
+func (m eventMapping) apply(event models.Event) (map[string]interface{}, error) {
+	object := make(map[string]interface{})
+	if m.readings {
+		for _, reading := range event.Readings {
+			object[reading.ResourceName] = typedReadingValue(reading)
+		}
+	}
+	if len(m.fields) == 0 {
+		return object, nil
+	}
+
+	// Paths are followed through the event as it is serialized, so they use its JSON names
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.Id, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", event.Id, err)
+	}
+	for field, steps := range m.fields {
+		if value, found := lookup(document, steps); found {
+			object[field] = value
+		}
+	}
+	return object, nil
+}
+
+// typedReadingValue returns a reading's value as the JSON type its value type calls for
+func typedReadingValue(reading models.Reading) interface{} {
+	switch reading.ValueType {
+	case common.ValueTypeBinary:
+		return reading.BinaryReading.BinaryValue
+	case common.ValueTypeBool:
+		if value, err := strconv.ParseBool(reading.SimpleReading.Value); err == nil {
+			return value
+		}
+	default:
+		if reading.ObjectReading.ObjectValue != nil {
+			return reading.ObjectReading.ObjectValue
+		}
+		// Kept as written, so large integers do not lose precision
+		if _, err := readingValue(reading); err == nil {
+			return json.Number(reading.SimpleReading.Value)
+		}
+	}
+	return reading.SimpleReading.Value
+}
+
+// mapEvents is the Map transform. It replaces the events with their mapped objects, serialized as
+// JSON: one object for an event, an array of them for a batch.
+func mapEvents(ctx context.Context, data Payload, parameters map[string]interface{}) (Payload, string, error) {
+	if data.Serialized() {
+		return data, "", errNeedsEvents
+	}
+	mapping, err := newEventMapping(parameters)
+	if err != nil {
+		return data, "", err
+	}
+
+	objects := make([]map[string]interface{}, 0, len(data.Events))
+	for _, event := range data.Events {
+		object, err := mapping.apply(event)
+		if err != nil {
+			return data, "", err
+		}
+		objects = append(objects, object)
+	}
+	var document interface{} = objects
+	if !data.Batched {
+		document = objects[0]
+	}
+	body, err := json.Marshal(document)
+	if err != nil {
+		return data, "", fmt.Errorf("failed to marshal mapped %s: %w", data.describe(), err)
+	}
+
+	fields := make([]string, 0, len(objects[0]))
+	for field := range objects[0] {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	message := fmt.Sprintf("Mapped %s to fields %s", data.describe(), strings.Join(fields, ", "))
+	data.Body = body
+	data.ContentType = common.ContentTypeJSON
+	return data, message, nil
+}
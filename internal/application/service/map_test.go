@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestMapTransform_Readings(t *testing.T) {
+	event := models.Event{
+		Id:         "event-1",
+		DeviceName: "Thermostat-1",
+		Readings: []models.Reading{
+			simpleReading("Temperature", common.ValueTypeFloat64, "22.5"),
+			simpleReading("Humidity", common.ValueTypeInt64, "60"),
+			simpleReading("Heating", common.ValueTypeBool, "true"),
+			simpleReading("Mode", common.ValueTypeString, "eco"),
+		},
+	}
+
+	mapped, message, err := mapEvents(context.Background(), Payload{Events: []models.Event{event}},
+		map[string]interface{}{"readings": true})
+	require.NoError(t, err)
+	assert.Equal(t, "Mapped event event-1 to fields Heating, Humidity, Mode, Temperature", message)
+	assert.Equal(t, common.ContentTypeJSON, mapped.ContentType)
+	assert.JSONEq(t, `{"Temperature":22.5,"Humidity":60,"Heating":true,"Mode":"eco"}`, string(mapped.Body))
+}
+
+func TestMapTransform_Fields(t *testing.T) {
+	event := temperatureEvent()
+	event.Tags = map[string]interface{}{"site": "plant-7"}
+
+	mapped, _, err := mapEvents(context.Background(), Payload{Events: []models.Event{event}}, map[string]interface{}{
+		"readings": true,
+		"fields": map[string]interface{}{
+			"device":      "$.deviceName",
+			"site":        "$.tags.site",
+			"first":       "$.readings[0].resourceName",
+			"Humidity":    "$.readings[1].resourceName",
+			"missing":     "$.tags.line",
+			"outOfBounds": "$.readings[5].value",
+		},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"device": "Thermostat-1",
+		"site": "plant-7",
+		"first": "Temperature",
+		"Temperature": 35,
+		"Humidity": "Humidity"
+	}`, string(mapped.Body), "fields win over readings, and paths that lead nowhere are left out")
+}
+
+func TestMapTransform_Batch(t *testing.T) {
+	data := Payload{Events: []models.Event{testEvent(0), testEvent(1)}, Batched: true}
+
+	mapped, message, err := mapEvents(context.Background(), data, map[string]interface{}{
+		"fields": map[string]interface{}{"id": "$.id"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Mapped batch of 2 events to fields id", message)
+	assert.JSONEq(t, `[{"id":"event-0"},{"id":"event-1"}]`, string(mapped.Body))
+
+	_, _, err = mapEvents(context.Background(), mapped, map[string]interface{}{"readings": true})
+	assert.ErrorIs(t, err, errNeedsEvents)
+}
+
+func TestMapTransform_Validation(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Name: "map",
+		Transforms: []Transform{
+			{Type: "Map", Parameters: map[string]interface{}{"fields": map[string]interface{}{"site": "$.tags.site"}}},
+			{Type: "Compress"},
+		},
+		Target: Target{Type: "FILE"},
+	}
+	assert.Empty(t, service.validatePipeline(pipeline))
+
+	pipeline.Transforms = append(pipeline.Transforms, Transform{Type: "FilterByDeviceName",
+		Parameters: map[string]interface{}{"include": []interface{}{"Thermostat-1"}}})
+	assert.Equal(t, []string{"transforms[2] (FilterByDeviceName): must come before Map, which serializes the events"},
+		service.validatePipeline(pipeline))
+
+	for name, params := range map[string]map[string]interface{}{
+		"Nothing to map":     {},
+		"Readings not bool":  {"readings": "yes"},
+		"Fields not object":  {"fields": []interface{}{"$.id"}},
+		"Path not string":    {"fields": map[string]interface{}{"id": float64(1)}},
+		"No $":               {"fields": map[string]interface{}{"id": "id"}},
+		"Empty member":       {"fields": map[string]interface{}{"id": "$..id"}},
+		"Unclosed index":     {"fields": map[string]interface{}{"id": "$.readings[0"}},
+		"Invalid index":      {"fields": map[string]interface{}{"id": "$.readings[-1]"}},
+		"Unexpected content": {"fields": map[string]interface{}{"id": "$id"}},
+	} {
+		_, err := newEventMapping(params)
+		assert.Error(t, err, name)
+	}
+}
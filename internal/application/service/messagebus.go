@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// Pipeline triggers: what feeds a pipeline its events
+const (
+	// TriggerHTTP pipelines run on events posted to /api/v3/process
+	TriggerHTTP = "http"
+	// TriggerMessageBus pipelines run on events published to the message bus
+	TriggerMessageBus = "edgex-messagebus"
+)
+
+// validTrigger reports whether trigger is a known pipeline trigger; empty means TriggerHTTP
+func validTrigger(trigger string) bool {
+	return trigger == "" || trigger == TriggerHTTP || trigger == TriggerMessageBus
+}
+
+// pipelineTrigger returns the trigger of a pipeline, defaulting to TriggerHTTP
+func pipelineTrigger(pipeline Pipeline) string {
+	if pipeline.Trigger == "" {
+		return TriggerHTTP
+	}
+	return pipeline.Trigger
+}
+
+// SetMessageClient makes the service run its message bus pipelines on events published to topic.
+// A nil client leaves HTTP as the only trigger. Must be called before Initialize.
+func (s *ApplicationService) SetMessageClient(client messaging.MessageClient, topic string) {
+	if topic == "" {
+		topic = messaging.MessageTopics.Events
+	}
+	s.messageClient = client
+	s.topic = topic
+}
+
+// handleEventMessage decodes an event received from the message bus and runs it through every
+// unlocked message bus pipeline, as processData does for HTTP pipelines. Malformed payloads are
+// returned as errors so the message client does not acknowledge them.
+func (s *ApplicationService) handleEventMessage(topic string, data []byte) error {
+	var event models.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		s.logger.Warnf("Discarding malformed event from topic %s: %v", topic, err)
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	// Bus events carry no request, so each gets its own correlation id for the targets it reaches
+	ctx := bootstrap.WithCorrelationID(context.Background(), models.GenerateUUID())
+	for _, result := range s.processEventThroughPipelines(ctx, event, TriggerMessageBus) {
+		if result["status"] != "success" {
+			s.logger.Errorf("Pipeline %v failed for event %s from topic %s: %v", result["pipelineName"], event.Id, topic, result["error"])
+			continue
+		}
+		s.logger.Debugf("Pipeline %v processed event %s from topic %s", result["pipelineName"], event.Id, topic)
+	}
+	return nil
+}
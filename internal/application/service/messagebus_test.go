@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// fakeMessageClient is an in-process MessageClient that lets tests deliver messages directly
+type fakeMessageClient struct {
+	handlers map[string]messaging.MessageHandler
+	mutex    sync.Mutex
+}
+
+func newFakeMessageClient() *fakeMessageClient {
+	return &fakeMessageClient{handlers: make(map[string]messaging.MessageHandler)}
+}
+
+func (f *fakeMessageClient) Connect() error    { return nil }
+func (f *fakeMessageClient) Disconnect() error { return nil }
+
+func (f *fakeMessageClient) Publish(topic string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return f.deliver(topic, payload)
+}
+
+func (f *fakeMessageClient) Subscribe(topic string, handler messaging.MessageHandler) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.handlers[topic] = handler
+	return nil
+}
+
+func (f *fakeMessageClient) Unsubscribe(topic string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.handlers, topic)
+	return nil
+}
+
+func (f *fakeMessageClient) subscribed(topic string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, ok := f.handlers[topic]
+	return ok
+}
+
+// deliver hands a raw payload to the topic's handler, returning its error as the NACK
+func (f *fakeMessageClient) deliver(topic string, data []byte) error {
+	f.mutex.Lock()
+	handler, ok := f.handlers[topic]
+	f.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("no subscriber for topic %s", topic)
+	}
+	return handler(topic, data)
+}
+
+// newTestService returns a service without the default pipelines, logging to a test hook
+func newTestService() (*ApplicationService, *test.Hook) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+	service := NewApplicationService(logger)
+	service.pipelines = make(map[string]Pipeline)
+	return service, hook
+}
+
+// addTestPipeline stores an unlocked pipeline with the given trigger
+func addTestPipeline(service *ApplicationService, name, trigger string) {
+	id := models.GenerateUUID()
+	service.pipelines[id] = Pipeline{Id: id, Name: name, Trigger: trigger, AdminState: common.Unlocked, Target: Target{Type: "FILE"}}
+}
+
+// processedBy returns the names of the pipelines the hook saw process a bus event
+func processedBy(hook *test.Hook) []string {
+	var names []string
+	for _, entry := range hook.AllEntries() {
+		if name, found := strings.CutPrefix(entry.Message, "Pipeline "); found && strings.Contains(name, " processed event ") {
+			names = append(names, strings.SplitN(name, " ", 2)[0])
+		}
+	}
+	return names
+}
+
+func TestApplicationService_MessageBusTrigger(t *testing.T) {
+	service, hook := newTestService()
+	addTestPipeline(service, "bus", TriggerMessageBus)
+	addTestPipeline(service, "rest", "")
+	addTestPipeline(service, "locked-bus", TriggerMessageBus)
+	for id, pipeline := range service.pipelines {
+		if pipeline.Name == "locked-bus" {
+			pipeline.AdminState = common.Locked
+			service.pipelines[id] = pipeline
+		}
+	}
+
+	client := newFakeMessageClient()
+	service.SetMessageClient(client, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	topic := messaging.MessageTopics.Events
+	require.True(t, client.subscribed(topic))
+
+	require.NoError(t, client.Publish(topic, models.Event{Id: "event-1", DeviceName: "Device1"}))
+	assert.Equal(t, []string{"bus"}, processedBy(hook))
+
+	assert.Error(t, client.deliver(topic, []byte(`{"id":`)))
+
+	// HTTP processing only runs HTTP pipelines
+	results := service.processEventThroughPipelines(context.Background(), models.Event{Id: "event-2"}, TriggerHTTP)
+	require.Len(t, results, 1)
+	assert.Equal(t, "rest", results[0]["pipelineName"])
+
+	// Shutdown stops the subscription
+	cancel()
+	wg.Wait()
+	assert.False(t, client.subscribed(topic))
+}
+
+func TestApplicationService_InvalidTrigger(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	body, err := json.Marshal(Pipeline{Name: "p", Trigger: "carrier-pigeon"})
+	require.NoError(t, err)
+	req, err := http.NewRequest("POST", "/api/v3/pipeline", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Empty(t, service.pipelines)
+}
+
+// pingingMessageClient is a fakeMessageClient whose health check returns err
+type pingingMessageClient struct {
+	*fakeMessageClient
+	err error
+}
+
+func (p pingingMessageClient) Ping(ctx context.Context) error { return p.err }
+
+func TestApplicationService_MessageBusHealthCheck(t *testing.T) {
+	service, _ := newTestService()
+	service.SetMessageClient(pingingMessageClient{newFakeMessageClient(), fmt.Errorf("connection refused")}, "")
+	registry := bootstrap.NewHealthRegistry()
+	dic := bootstrap.NewDIContainer()
+	dic.Add(common.HealthRegistryName, registry)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, dic))
+
+	assert.Equal(t, []string{"messagebus"}, registry.Names())
+	report := registry.Run(context.Background())
+	assert.Equal(t, bootstrap.HealthStatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Checks["messagebus"].Message)
+
+	cancel()
+	wg.Wait()
+}
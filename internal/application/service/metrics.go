@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// PipelineMetrics is the running tally of what a pipeline has done since it was created or the service started
+type PipelineMetrics struct {
+	EventsProcessed   int64  `json:"eventsProcessed"`   // Events that entered the pipeline
+	EventsFiltered    int64  `json:"eventsFiltered"`    // Events a filter dropped
+	TransformFailures int64  `json:"transformFailures"` // Runs stopped by a failing transform
+	TargetSuccesses   int64  `json:"targetSuccesses"`   // Payloads the target accepted, a flushed batch counting once
+	TargetFailures    int64  `json:"targetFailures"`
+	AverageLatency    string `json:"averageLatency"` // Mean time to run an event through the pipeline, target included
+}
+
+// pipelineCounters accumulates the metrics of one pipeline
+type pipelineCounters struct {
+	metrics      PipelineMetrics
+	totalLatency time.Duration
+}
+
+// countersLocked returns the counters of a pipeline, creating them if needed. Caller must hold metricsMutex.
+func (s *ApplicationService) countersLocked(pipelineID string) *pipelineCounters {
+	counters, ok := s.pipelineMetrics[pipelineID]
+	if !ok {
+		counters = &pipelineCounters{}
+		s.pipelineMetrics[pipelineID] = counters
+	}
+	return counters
+}
+
+// recordPipelineRun counts an event run through a pipeline by executePipeline, with its result and how long it took
+func (s *ApplicationService) recordPipelineRun(pipelineID string, result map[string]interface{}, latency time.Duration) {
+	s.metricsMutex.Lock()
+	defer s.metricsMutex.Unlock()
+
+	counters := s.countersLocked(pipelineID)
+	counters.metrics.EventsProcessed++
+	counters.totalLatency += latency
+	if result["filteredOut"] == true {
+		counters.metrics.EventsFiltered++
+	}
+	if _, reachedTarget := result["targetResult"]; !reachedTarget && result["status"] == "error" {
+		counters.metrics.TransformFailures++
+	}
+}
+
+// recordTargetResult counts a payload sent to a pipeline's target
+func (s *ApplicationService) recordTargetResult(pipelineID string, err error) {
+	s.metricsMutex.Lock()
+	defer s.metricsMutex.Unlock()
+
+	counters := s.countersLocked(pipelineID)
+	if err != nil {
+		counters.metrics.TargetFailures++
+	} else {
+		counters.metrics.TargetSuccesses++
+	}
+}
+
+// pipelineMetricsSnapshot returns the current metrics of a pipeline
+func (s *ApplicationService) pipelineMetricsSnapshot(pipelineID string) PipelineMetrics {
+	s.metricsMutex.Lock()
+	defer s.metricsMutex.Unlock()
+
+	counters, ok := s.pipelineMetrics[pipelineID]
+	if !ok {
+		return PipelineMetrics{AverageLatency: time.Duration(0).String()}
+	}
+	metrics := counters.metrics
+	var average time.Duration
+	if metrics.EventsProcessed > 0 {
+		average = counters.totalLatency / time.Duration(metrics.EventsProcessed)
+	}
+	metrics.AverageLatency = average.String()
+	return metrics
+}
+
+// forgetPipelineMetrics drops the metrics of a deleted pipeline
+func (s *ApplicationService) forgetPipelineMetrics(pipelineID string) {
+	s.metricsMutex.Lock()
+	defer s.metricsMutex.Unlock()
+	delete(s.pipelineMetrics, pipelineID)
+}
+
+// getPipelineMetrics handles GET /api/v3/pipeline/id/{id}/metrics
+func (s *ApplicationService) getPipelineMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	s.mutex.RLock()
+	_, exists := s.pipelines[id]
+	s.mutex.RUnlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"metrics":    s.pipelineMetricsSnapshot(id),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
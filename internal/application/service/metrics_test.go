@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// getMetrics reads a pipeline's metrics through the API
+func getMetrics(t *testing.T, router *mux.Router, id string) PipelineMetrics {
+	rr := pipelineRequest(t, router, "GET", "/api/v3/pipeline/id/"+id+"/metrics", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response struct {
+		Metrics PipelineMetrics `json:"metrics"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.Metrics
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	pipeline := Pipeline{
+		Name: "hot",
+		Transforms: []Transform{
+			{Type: "Filter", Parameters: map[string]interface{}{"resource": "Temperature", "operator": ">", "threshold": 30}},
+		},
+		Target: httpTarget(t, server, map[string]interface{}{"path": "/ingest"}),
+	}
+	rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline", &pipeline)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	id := created["id"].(string)
+	pipeline = service.pipelines[id]
+
+	metrics := getMetrics(t, router, id)
+	assert.Equal(t, PipelineMetrics{AverageLatency: "0s"}, metrics)
+
+	event := func(value string) models.Event {
+		return models.Event{Id: models.GenerateUUID(), Readings: []models.Reading{simpleReading("Temperature", common.ValueTypeFloat64, value)}}
+	}
+	for _, value := range []string{"40", "20", "35", "10", "50"} {
+		service.executePipeline(context.Background(), event(value), pipeline)
+	}
+	failing.Store(true)
+	result := service.executePipeline(context.Background(), event("45"), pipeline)
+	require.Equal(t, "error", result["status"])
+
+	// A transform that fails stops the run before the target
+	broken := pipeline
+	broken.Transforms = []Transform{{Type: "Filter", Parameters: map[string]interface{}{"resource": "Temperature", "operator": "~", "threshold": 30}}}
+	service.executePipeline(context.Background(), event("45"), broken)
+
+	metrics = getMetrics(t, router, id)
+	assert.Equal(t, int64(7), metrics.EventsProcessed)
+	assert.Equal(t, int64(2), metrics.EventsFiltered)
+	assert.Equal(t, int64(1), metrics.TransformFailures)
+	assert.Equal(t, int64(3), metrics.TargetSuccesses)
+	assert.Equal(t, int64(1), metrics.TargetFailures)
+	average, err := time.ParseDuration(metrics.AverageLatency)
+	require.NoError(t, err)
+	assert.Greater(t, average, time.Duration(0))
+
+	rr = pipelineRequest(t, router, "GET", "/api/v3/pipeline/id/missing/metrics", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Deleting the pipeline forgets its metrics
+	rr = pipelineRequest(t, router, "DELETE", "/api/v3/pipeline/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, service.pipelineMetrics, id)
+}
+
+func TestPipelineMetrics_Batch(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Id:         "pipeline-1",
+		Name:       "batched",
+		Transforms: []Transform{{Type: "Batch", Parameters: map[string]interface{}{"batchSize": 3}}},
+		Target:     Target{Type: "FILE"},
+	}
+
+	for i := 0; i < 6; i++ {
+		service.executePipeline(context.Background(), models.Event{Id: models.GenerateUUID()}, pipeline)
+	}
+
+	metrics := service.pipelineMetricsSnapshot("pipeline-1")
+	assert.Equal(t, int64(6), metrics.EventsProcessed)
+	assert.Equal(t, int64(2), metrics.TargetSuccesses, "each full batch goes to the target once")
+	assert.Zero(t, metrics.TargetFailures)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// MQTTSender publishes payloads to one MQTT broker connection
+type MQTTSender interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+	Close() error
+}
+
+// mqttSenderFactory connects a sender; tests replace it to avoid a real broker
+type mqttSenderFactory func(options messaging.MQTTOptions) (MQTTSender, error)
+
+// connectMQTT is the default mqttSenderFactory, backed by messaging.MQTTClient
+func (s *ApplicationService) connectMQTT(options messaging.MQTTOptions) (MQTTSender, error) {
+	client := messaging.NewMQTTClient(options, s.logger)
+	if err := client.Connect(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// exportMQTT publishes the data, in the target's Format unless an earlier step serialized it, to the target's Topic. Optional parameters: "clientId",
+// "qos" 0 or 1, "retain", and "secretPath" holding the broker username and password.
+func (s *ApplicationService) exportMQTT(data Payload, target Target) (string, int, error) {
+	if target.Topic == "" {
+		return "", 0, errors.New("MQTT target needs a topic")
+	}
+	qos, retained, err := mqttPublishOptions(target)
+	if err != nil {
+		return "", 0, err
+	}
+	sender, err := s.mqttSender(target)
+	if err != nil {
+		return "", 0, err
+	}
+
+	data, err = data.Marshal()
+	if err != nil {
+		return "", 0, err
+	}
+	s.logger.Debugf("Publishing %s to MQTT topic %s", data.describe(), target.Topic)
+	if err := sender.Publish(target.Topic, qos, retained, data.Body); err != nil {
+		return "", 0, fmt.Errorf("failed to publish to %s: %w", target.Topic, err)
+	}
+	return fmt.Sprintf("Published to MQTT topic %s", target.Topic), 0, nil
+}
+
+// mqttSender returns the sender for a target, connecting it on first use. Targets with the same
+// broker, client id and credentials share a sender.
+func (s *ApplicationService) mqttSender(target Target) (MQTTSender, error) {
+	options, err := s.mqttOptions(target)
+	if err != nil {
+		return nil, err
+	}
+	key := options.Address + "|" + options.ClientID + "|" + options.Username
+
+	s.mqttMutex.Lock()
+	defer s.mqttMutex.Unlock()
+	if sender, ok := s.mqttSenders[key]; ok {
+		return sender, nil
+	}
+	sender, err := s.newMQTTSender(options)
+	if err != nil {
+		return nil, err
+	}
+	s.mqttSenders[key] = sender
+	return sender, nil
+}
+
+// mqttOptions builds the broker connection settings of a target
+func (s *ApplicationService) mqttOptions(target Target) (messaging.MQTTOptions, error) {
+	if target.Host == "" {
+		return messaging.MQTTOptions{}, errors.New("MQTT target needs a host")
+	}
+	port := target.Port
+	if port == 0 {
+		port = 1883
+	}
+	options := messaging.MQTTOptions{
+		Address:  net.JoinHostPort(target.Host, strconv.Itoa(port)),
+		ClientID: "app-service-" + target.Topic,
+	}
+	if clientID, ok := target.Parameters["clientId"].(string); ok && clientID != "" {
+		options.ClientID = clientID
+	}
+
+	if secretPath, ok := target.Parameters["secretPath"].(string); ok && secretPath != "" {
+		if s.secretsClient == nil {
+			return options, errors.New("no secrets client to resolve credentials")
+		}
+		secret, err := s.secretsClient.GetSecret(secretPath, SecretKeyUsername, SecretKeyPassword)
+		if err != nil {
+			return options, fmt.Errorf("failed to read credentials from %s: %w", secretPath, err)
+		}
+		options.Username = secret[SecretKeyUsername]
+		options.Password = secret[SecretKeyPassword]
+	}
+	return options, nil
+}
+
+// mqttPublishOptions reads the "qos" and "retain" parameters of a target
+func mqttPublishOptions(target Target) (byte, bool, error) {
+	var qos byte
+	switch value := target.Parameters["qos"].(type) {
+	case nil:
+	case float64:
+		if value != 0 && value != 1 {
+			return 0, false, fmt.Errorf("invalid qos %v, must be 0 or 1", value)
+		}
+		qos = byte(value)
+	default:
+		return 0, false, fmt.Errorf("invalid qos %v, must be 0 or 1", value)
+	}
+
+	retained := false
+	switch value := target.Parameters["retain"].(type) {
+	case nil:
+	case bool:
+		retained = value
+	default:
+		return 0, false, fmt.Errorf("invalid retain %v, must be true or false", value)
+	}
+	return qos, retained, nil
+}
+
+// closeMQTTSenders disconnects every broker connection
+func (s *ApplicationService) closeMQTTSenders() {
+	s.mqttMutex.Lock()
+	defer s.mqttMutex.Unlock()
+	for key, sender := range s.mqttSenders {
+		if err := sender.Close(); err != nil {
+			s.logger.Warnf("Failed to close MQTT connection: %v", err)
+		}
+		delete(s.mqttSenders, key)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// mqttPublish is a message handed to a fakeMQTTSender
+type mqttPublish struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  []byte
+}
+
+// fakeMQTTSender records publishes, failing them with err when set
+type fakeMQTTSender struct {
+	mutex     sync.Mutex
+	published []mqttPublish
+	err       error
+	closed    bool
+}
+
+func (f *fakeMQTTSender) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.published = append(f.published, mqttPublish{topic, qos, retained, payload})
+	return nil
+}
+
+func (f *fakeMQTTSender) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.closed = true
+	return nil
+}
+
+// fakeMQTTBroker stands in for the broker connections of a service
+type fakeMQTTBroker struct {
+	sender     *fakeMQTTSender
+	connectErr error
+	options    []messaging.MQTTOptions
+}
+
+func installFakeMQTT(service *ApplicationService) *fakeMQTTBroker {
+	broker := &fakeMQTTBroker{sender: &fakeMQTTSender{}}
+	service.newMQTTSender = func(options messaging.MQTTOptions) (MQTTSender, error) {
+		broker.options = append(broker.options, options)
+		if broker.connectErr != nil {
+			return nil, broker.connectErr
+		}
+		return broker.sender, nil
+	}
+	return broker
+}
+
+func TestExportMQTT(t *testing.T) {
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, secretsClient.StoreSecret("mqtt/broker", map[string]string{
+		SecretKeyUsername: "edge",
+		SecretKeyPassword: "s3cret",
+	}))
+	service, _ := newTestService()
+	service.SetSecretsClient(secretsClient)
+	broker := installFakeMQTT(service)
+
+	target := Target{
+		Type:  "MQTT",
+		Host:  "broker.local",
+		Port:  8883,
+		Topic: "edgex/export",
+		Parameters: map[string]interface{}{
+			"clientId":   "exporter-1",
+			"qos":        float64(1),
+			"retain":     true,
+			"secretPath": "mqtt/broker",
+		},
+	}
+	pipeline := Pipeline{Name: "mqtt", Target: target}
+	event := models.Event{Id: "event-1", DeviceName: "Boiler-1"}
+
+	for i := 0; i < 2; i++ {
+		result := service.executePipeline(context.Background(), event, pipeline)
+		require.Equal(t, "success", result["status"], result["error"])
+		assert.Equal(t, "Published to MQTT topic edgex/export", result["targetResult"])
+	}
+
+	// One connection serves every event of the target
+	require.Len(t, broker.options, 1)
+	assert.Equal(t, messaging.MQTTOptions{
+		Address:  "broker.local:8883",
+		ClientID: "exporter-1",
+		Username: "edge",
+		Password: "s3cret",
+	}, broker.options[0])
+
+	require.Len(t, broker.sender.published, 2)
+	published := broker.sender.published[0]
+	assert.Equal(t, "edgex/export", published.topic)
+	assert.Equal(t, byte(1), published.qos)
+	assert.True(t, published.retained)
+	var received models.Event
+	require.NoError(t, json.Unmarshal(published.payload, &received))
+	assert.Equal(t, "event-1", received.Id)
+
+	// Senders are closed on shutdown
+	service.closeMQTTSenders()
+	assert.True(t, broker.sender.closed)
+}
+
+func TestExportMQTTErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      Target
+		connectErr  error
+		publishErr  error
+		expectError string
+	}{
+		{
+			name:        "Broker error",
+			target:      Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export"},
+			publishErr:  errors.New("connection reset"),
+			expectError: "MQTT target failed: failed to publish to edgex/export: connection reset",
+		},
+		{
+			name:        "Broker unreachable",
+			target:      Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export"},
+			connectErr:  errors.New("connection refused"),
+			expectError: "MQTT target failed: connection refused",
+		},
+		{
+			name:        "Missing topic",
+			target:      Target{Type: "MQTT", Host: "broker.local"},
+			expectError: "MQTT target failed: MQTT target needs a topic",
+		},
+		{
+			name:        "Invalid QoS",
+			target:      Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export", Parameters: map[string]interface{}{"qos": float64(2)}},
+			expectError: "MQTT target failed: invalid qos 2, must be 0 or 1",
+		},
+		{
+			name:        "Credentials without secrets client",
+			target:      Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export", Parameters: map[string]interface{}{"secretPath": "mqtt/broker"}},
+			expectError: "MQTT target failed: no secrets client to resolve credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestService()
+			broker := installFakeMQTT(service)
+			broker.connectErr = tt.connectErr
+			broker.sender.err = tt.publishErr
+
+			result := service.executePipeline(context.Background(), models.Event{Id: "event-1"}, Pipeline{Name: "mqtt", Target: tt.target})
+			assert.Equal(t, "error", result["status"])
+			assert.Equal(t, tt.expectError, result["error"])
+		})
+	}
+}
+
+func TestApplicationService_StartPipelineConnectsMQTT(t *testing.T) {
+	service, _ := newTestService()
+	broker := installFakeMQTT(service)
+	broker.connectErr = errors.New("connection refused")
+
+	id := models.GenerateUUID()
+	service.pipelines[id] = Pipeline{
+		Id:         id,
+		Name:       "mqtt",
+		AdminState: common.Locked,
+		Target:     Target{Type: "MQTT", Host: "broker.local", Topic: "edgex/export"},
+	}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	start := func() map[string]interface{} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("POST", "/api/v3/pipeline/id/"+id+"/start", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+	get := func() Pipeline {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v3/pipeline/id/"+id, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Pipeline Pipeline `json:"pipeline"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Pipeline
+	}
+
+	// A broker that cannot be reached leaves the pipeline started but in the error state
+	response := start()
+	assert.Equal(t, PipelineStateError, response["state"])
+	assert.Equal(t, "connection refused", response["error"])
+	pipeline := get()
+	assert.Equal(t, common.Unlocked, pipeline.AdminState)
+	assert.Equal(t, PipelineStateError, pipeline.State)
+	assert.Equal(t, "connection refused", pipeline.Error)
+
+	// Starting again once the broker is back clears the error
+	broker.connectErr = nil
+	response = start()
+	assert.Equal(t, PipelineStateReady, response["state"])
+	assert.NotContains(t, response, "error")
+	pipeline = get()
+	assert.Equal(t, PipelineStateReady, pipeline.State)
+	assert.Empty(t, pipeline.Error)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Payload is what one step of a pipeline hands to the next. It starts as the event being
+// processed, becomes a batch of events after a Batch transform, and becomes bytes once a step
+// such as Compress serializes it. Later steps and the target work on whatever the last step produced.
+type Payload struct {
+	Events  []models.Event
+	Batched bool
+	Format  string // the target's format, which the events are serialized in
+
+	// Body is the serialized payload; nil until a step serializes the events
+	Body            []byte
+	ContentType     string
+	ContentEncoding []string // content codings applied to Body, in order
+
+	// Compression is set by a Compress transform, and reported as the pipeline result's "compression"
+	Compression *CompressionResult
+}
+
+// eventData wraps a single event as the input of a pipeline whose target takes format
+func eventData(event models.Event, format string) Payload {
+	return Payload{Events: []models.Event{event}, Format: format}
+}
+
+// batchData wraps a released batch as the input of the rest of a pipeline whose target takes format
+func batchData(events []models.Event, format string) Payload {
+	return Payload{Events: events, Batched: true, Format: format}
+}
+
+// Serialized reports whether the events have been turned into bytes
+func (d Payload) Serialized() bool {
+	return d.Body != nil
+}
+
+// payload returns the event, or the batch of events, to serialize
+func (d Payload) payload() interface{} {
+	if d.Batched {
+		return d.Events
+	}
+	return d.Events[0]
+}
+
+// Marshal returns d serialized, encoding the events in d.Format (JSON by default) if no step has
+// serialized them yet
+func (d Payload) Marshal() (Payload, error) {
+	if d.Serialized() {
+		return d, nil
+	}
+	body, contentType, err := marshalEvents(d, d.Format)
+	if err != nil {
+		return d, fmt.Errorf("failed to marshal %s: %w", d.describe(), err)
+	}
+	d.Body = body
+	d.ContentType = contentType
+	return d, nil
+}
+
+// describe names the data for logs and errors
+func (d Payload) describe() string {
+	var what string
+	if d.Batched {
+		what = fmt.Sprintf("batch of %d events", len(d.Events))
+	} else {
+		what = "event " + d.Events[0].Id
+	}
+	if d.Serialized() {
+		what += fmt.Sprintf(" (%d bytes", len(d.Body))
+		if len(d.ContentEncoding) > 0 {
+			what += ", " + strings.Join(d.ContentEncoding, ", ")
+		}
+		what += ")"
+	}
+	return what
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// TransformFunc applies a transform to the data passing through a pipeline, given the parameters
+// of the transform in the pipeline definition. It returns the data to hand to the next step and a
+// short description of what it did, reported in the pipeline results. Returning data with no events
+// drops it, ending the pipeline for it.
+type TransformFunc func(ctx context.Context, payload Payload, params map[string]interface{}) (Payload, string, error)
+
+// EventTransformFunc transforms a single event. Returning an error wrapping ErrFilteredOut drops the event.
+type EventTransformFunc func(event models.Event, params map[string]interface{}) (models.Event, string, error)
+
+// errNeedsEvents is returned by an event transform given a payload that is already serialized
+var errNeedsEvents = errors.New("works on events, but an earlier step already serialized them")
+
+// RegisterTransform makes fn available to pipelines as the transform type name, replacing any
+// transform already registered under it. Pipelines naming a type that is not registered are
+// rejected when saved, so site-specific transforms should be registered before routes are added.
+// Batch and Deadband always keep their state in the pipeline runner; their registered functions are only
+// used by dry runs.
+func (s *ApplicationService) RegisterTransform(name string, fn TransformFunc) {
+	s.transformMutex.Lock()
+	defer s.transformMutex.Unlock()
+	s.transforms[name] = fn
+}
+
+// lookupTransform returns the transform registered under name
+func (s *ApplicationService) lookupTransform(name string) (TransformFunc, bool) {
+	s.transformMutex.RLock()
+	defer s.transformMutex.RUnlock()
+	fn, exists := s.transforms[name]
+	return fn, exists
+}
+
+// registerBuiltinTransforms registers the transforms every pipeline can use
+func (s *ApplicationService) registerBuiltinTransforms() {
+	s.RegisterTransform("Batch", batchTransform)
+	s.RegisterTransform("Compress", compressData)
+	s.RegisterTransform("Deadband", deadbandTransform)
+	s.RegisterTransform("Encrypt", s.encryptData)
+	s.RegisterTransform("Map", mapEvents)
+	s.RegisterTransform("Filter", EachEvent(filterByValue))
+	s.RegisterTransform("FilterByDeviceName", EachEvent(filterByDeviceName))
+	s.RegisterTransform("FilterByResourceName", EachEvent(filterByResourceName))
+	s.RegisterTransform("Convert", EachEvent(s.convertEvent))
+	s.RegisterTransform("AddTags", EachEvent(addTags))
+	s.RegisterTransform("RenameResource", EachEvent(renameResource))
+	s.RegisterTransform("ConvertUnits", EachEvent(convertUnits))
+}
+
+// EachEvent adapts fn into a TransformFunc that applies it to every event of the payload, keeping the
+// events it does not drop. The payload must not have been serialized yet.
+func EachEvent(fn EventTransformFunc) TransformFunc {
+	return func(ctx context.Context, payload Payload, params map[string]interface{}) (Payload, string, error) {
+		if payload.Serialized() {
+			return payload, "", errNeedsEvents
+		}
+		kept := make([]models.Event, 0, len(payload.Events))
+		var message string
+		for _, event := range payload.Events {
+			processedEvent, eventMessage, err := fn(event, params)
+			if errors.Is(err, ErrFilteredOut) {
+				message = err.Error()
+				continue
+			}
+			if err != nil {
+				return payload, "", err
+			}
+			message = eventMessage
+			kept = append(kept, processedEvent)
+		}
+		if payload.Batched && len(kept) > 0 {
+			message = fmt.Sprintf("%d of %d events kept", len(kept), len(payload.Events))
+		}
+		payload.Events = kept
+		return payload, message, nil
+	}
+}
+
+// applyTransform runs transform on data through the registry. filteredOut is set when the transform
+// dropped every event, in which case data is returned unchanged.
+func (s *ApplicationService) applyTransform(ctx context.Context, transform Transform, data Payload) (Payload, string, bool, error) {
+	fn, exists := s.lookupTransform(transform.Type)
+	if !exists {
+		return data, "", false, fmt.Errorf("unknown transform type %q", transform.Type)
+	}
+	output, message, err := fn(ctx, data, transform.Parameters)
+	if errors.Is(err, errNeedsEvents) {
+		err = fmt.Errorf("%s %w", transform.Type, err)
+	}
+	if err != nil {
+		return data, "", false, err
+	}
+	if !output.Serialized() && len(output.Events) == 0 {
+		return data, message, true, nil
+	}
+	return output, message, false, nil
+}
+
+// convertEvent simulates data conversion
+func (s *ApplicationService) convertEvent(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	s.logger.Debugf("Converting to format: %v", params["format"])
+	return event, "Data converted successfully", nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// tagSite is a site-specific transform tagging every event with the "site" parameter
+func tagSite(event models.Event, params map[string]interface{}) (models.Event, string, error) {
+	site, _ := params["site"].(string)
+	if site == "" {
+		return event, "", errors.New("site is required")
+	}
+	event.Tags = map[string]interface{}{"site": site}
+	return event, "Tagged with site " + site, nil
+}
+
+func TestRegisterTransform(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Id:         "pipeline-1",
+		Name:       "tagged",
+		Transforms: []Transform{{Type: "TagSite", Parameters: map[string]interface{}{"site": "plant-7"}}},
+		Target:     Target{Type: "FILE"},
+	}
+	assert.Equal(t, []string{`transforms[0]: unknown type "TagSite"`}, service.validatePipeline(pipeline))
+
+	service.RegisterTransform("TagSite", EachEvent(tagSite))
+	assert.Empty(t, service.validatePipeline(pipeline))
+
+	result := service.executePipeline(context.Background(), temperatureEvent(), pipeline)
+	assert.Equal(t, "success", result["status"])
+	assert.Equal(t, []string{"Tagged with site plant-7"}, result["transformResults"])
+
+	pipeline.Transforms[0].Parameters = nil
+	result = service.executePipeline(context.Background(), temperatureEvent(), pipeline)
+	assert.Equal(t, "error", result["status"])
+	assert.Equal(t, "TagSite transform failed: site is required", result["error"])
+}
+
+func TestRegisterTransform_SeesPayload(t *testing.T) {
+	service, _ := newTestService()
+	var seen Payload
+	service.RegisterTransform("Inspect", func(ctx context.Context, payload Payload, params map[string]interface{}) (Payload, string, error) {
+		seen = payload
+		return payload, fmt.Sprintf("%d bytes", len(payload.Body)), nil
+	})
+	pipeline := Pipeline{
+		Id:         "pipeline-1",
+		Name:       "inspected",
+		Transforms: []Transform{{Type: "Compress"}, {Type: "Inspect"}},
+		Target:     Target{Type: "FILE"},
+	}
+
+	result := service.executePipeline(context.Background(), temperatureEvent(), pipeline)
+	require.Equal(t, "success", result["status"])
+	assert.True(t, seen.Serialized())
+	assert.Equal(t, []string{"gzip"}, seen.ContentEncoding)
+	assert.Equal(t, fmt.Sprintf("%d bytes", len(seen.Body)), result["transformResults"].([]string)[1])
+}
+
+func TestRegisterTransform_DropsEvents(t *testing.T) {
+	service, _ := newTestService()
+	service.RegisterTransform("DropAll", func(ctx context.Context, payload Payload, params map[string]interface{}) (Payload, string, error) {
+		payload.Events = nil
+		return payload, "Dropped", nil
+	})
+	pipeline := Pipeline{
+		Id:         "pipeline-1",
+		Name:       "dropping",
+		Transforms: []Transform{{Type: "DropAll"}, {Type: "Convert"}},
+		Target:     Target{Type: "FILE"},
+	}
+
+	result := service.executePipeline(context.Background(), temperatureEvent(), pipeline)
+	assert.Equal(t, true, result["filteredOut"])
+	assert.Equal(t, []string{"Dropped"}, result["transformResults"])
+	assert.NotContains(t, result, "targetResult")
+}
+
+func TestEachEvent(t *testing.T) {
+	transform := EachEvent(filterByDeviceName)
+	params := map[string]interface{}{"include": []interface{}{"Device-1"}}
+	batch := batchData([]models.Event{{DeviceName: "Device-1"}, {DeviceName: "Device-2"}, {DeviceName: "Device-1"}}, "")
+
+	output, message, err := transform(context.Background(), batch, params)
+	require.NoError(t, err)
+	assert.Len(t, output.Events, 2)
+	assert.Equal(t, "2 of 3 events kept", message)
+
+	serialized, err := batch.Marshal()
+	require.NoError(t, err)
+	_, _, err = transform(context.Background(), serialized, params)
+	assert.ErrorIs(t, err, errNeedsEvents)
+}
+
+func TestAddPipeline_RegisteredTransform(t *testing.T) {
+	service, _ := newTestService()
+	service.RegisterTransform("TagSite", EachEvent(tagSite))
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline", &Pipeline{Name: "p", Transforms: []Transform{{Type: "TagSite"}}, Target: Target{Type: "FILE"}})
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
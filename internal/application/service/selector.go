@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// pipelineSelector is one of a pipeline's match lists and the event field it is matched against
+type pipelineSelector struct {
+	name     string
+	patterns []string
+	value    func(event models.Event) string
+}
+
+// PipelineFilter limits the events a pipeline runs for to those whose device, profile or source name
+// matches one of the glob patterns, such as Virtual-*. An empty filter admits every event.
+type PipelineFilter struct {
+	DeviceNames  []string `json:"deviceNames,omitempty" yaml:"deviceNames,omitempty"`
+	ProfileNames []string `json:"profileNames,omitempty" yaml:"profileNames,omitempty"`
+	SourceNames  []string `json:"sourceNames,omitempty" yaml:"sourceNames,omitempty"`
+}
+
+// selectors returns the match lists of the pipeline's filter in order of precedence: device names,
+// then profile names, then source names. A pipeline without a filter has none.
+func (p Pipeline) selectors() []pipelineSelector {
+	if p.Filter == nil {
+		return nil
+	}
+	return []pipelineSelector{
+		{"deviceName", p.Filter.DeviceNames, func(event models.Event) string { return event.DeviceName }},
+		{"profileName", p.Filter.ProfileNames, func(event models.Event) string { return event.ProfileName }},
+		{"sourceName", p.Filter.SourceNames, func(event models.Event) string { return event.SourceName }},
+	}
+}
+
+// matchSelectors reports whether pipeline should run for event and, when the pipeline has
+// selectors, which pattern matched, for example "deviceName=Virtual-*". A pipeline without
+// selectors runs for every event. Otherwise one matching pattern in any list is enough; lists
+// are checked in the order of selectors and patterns in the order given, and the first match wins.
+func matchSelectors(pipeline Pipeline, event models.Event) (string, bool) {
+	selected := false
+	for _, selector := range pipeline.selectors() {
+		value := selector.value(event)
+		for _, pattern := range selector.patterns {
+			selected = true
+			if matched, _ := path.Match(pattern, value); matched {
+				return selector.name + "=" + pattern, true
+			}
+		}
+	}
+	return "", !selected
+}
+
+// validateSelectors checks that every filter entry is a valid glob pattern
+func validateSelectors(pipeline Pipeline) []string {
+	var problems []string
+	for _, selector := range pipeline.selectors() {
+		for i, pattern := range selector.patterns {
+			if pattern == "" {
+				problems = append(problems, fmt.Sprintf("filter.%ss[%d]: empty pattern", selector.name, i))
+				continue
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("filter.%ss[%d]: invalid pattern %q", selector.name, i, pattern))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestMatchSelectors(t *testing.T) {
+	event := models.Event{DeviceName: "Virtual-Temperature-Sensor-01", ProfileName: "TemperatureSensorProfile", SourceName: "Temperature"}
+
+	tests := []struct {
+		name          string
+		pipeline      Pipeline
+		expectMatch   bool
+		expectMatched string
+	}{
+		{"No filter matches everything", Pipeline{}, true, ""},
+		{"Empty filter matches everything", Pipeline{Filter: &PipelineFilter{}}, true, ""},
+		{"Exact device name", Pipeline{Filter: &PipelineFilter{DeviceNames: []string{"Virtual-Temperature-Sensor-01"}}}, true, "deviceName=Virtual-Temperature-Sensor-01"},
+		{"Device glob", Pipeline{Filter: &PipelineFilter{DeviceNames: []string{"Virtual-*"}}}, true, "deviceName=Virtual-*"},
+		{"Single character glob", Pipeline{Filter: &PipelineFilter{SourceNames: []string{"Temperatur?"}}}, true, "sourceName=Temperatur?"},
+		{"No pattern matches", Pipeline{Filter: &PipelineFilter{DeviceNames: []string{"Modbus-*"}, SourceNames: []string{"Humidity"}}}, false, ""},
+		{"First matching pattern of a list wins", Pipeline{Filter: &PipelineFilter{DeviceNames: []string{"Modbus-*", "*-01", "Virtual-*"}}}, true, "deviceName=*-01"},
+		{"Any list may match", Pipeline{Filter: &PipelineFilter{DeviceNames: []string{"Modbus-*"}, ProfileNames: []string{"Temperature*"}}}, true, "profileName=Temperature*"},
+		{
+			"Device names take precedence over profile and source names",
+			Pipeline{Filter: &PipelineFilter{SourceNames: []string{"Temperature"}, ProfileNames: []string{"*Profile"}, DeviceNames: []string{"Virtual-*"}}},
+			true, "deviceName=Virtual-*",
+		},
+		{
+			"Profile names take precedence over source names",
+			Pipeline{Filter: &PipelineFilter{SourceNames: []string{"Temperature"}, ProfileNames: []string{"*Profile"}}},
+			true, "profileName=*Profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, ok := matchSelectors(tt.pipeline, event)
+			assert.Equal(t, tt.expectMatch, ok)
+			assert.Equal(t, tt.expectMatched, matched)
+		})
+	}
+}
+
+func TestValidatePipeline_Selectors(t *testing.T) {
+	service, _ := newTestService()
+	pipeline := Pipeline{
+		Name:   "routed",
+		Target: Target{Type: "FILE"},
+		Filter: &PipelineFilter{
+			DeviceNames:  []string{"Virtual-*", "[unclosed"},
+			ProfileNames: []string{""},
+			SourceNames:  []string{"Temperature"},
+		},
+	}
+	assert.Equal(t, []string{
+		`filter.deviceNames[1]: invalid pattern "[unclosed"`,
+		"filter.profileNames[0]: empty pattern",
+	}, service.validatePipeline(pipeline))
+}
+
+func TestProcessEventThroughPipelines_Selectors(t *testing.T) {
+	service, _ := newTestService()
+	service.pipelines = map[string]Pipeline{
+		"all":      {Id: "all", Name: "all", AdminState: common.Unlocked, Target: Target{Type: "FILE"}},
+		"virtual":  {Id: "virtual", Name: "virtual", AdminState: common.Unlocked, Target: Target{Type: "FILE"}, Filter: &PipelineFilter{DeviceNames: []string{"Virtual-*"}}},
+		"humidity": {Id: "humidity", Name: "humidity", AdminState: common.Unlocked, Target: Target{Type: "FILE"}, Filter: &PipelineFilter{SourceNames: []string{"Humidity"}}},
+		"locked":   {Id: "locked", Name: "locked", AdminState: common.Locked, Target: Target{Type: "FILE"}, Filter: &PipelineFilter{DeviceNames: []string{"*"}}},
+	}
+
+	run := func(event models.Event) map[string]interface{} {
+		selectors := map[string]interface{}{}
+		for _, result := range service.processEventThroughPipelines(context.Background(), event, TriggerHTTP) {
+			selectors[result["pipelineName"].(string)] = result["matchedSelector"]
+		}
+		return selectors
+	}
+	names := func(selectors map[string]interface{}) []string {
+		var names []string
+		for name := range selectors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	results := run(models.Event{DeviceName: "Virtual-Temperature-Sensor-01", SourceName: "Temperature"})
+	assert.Equal(t, []string{"all", "virtual"}, names(results))
+	assert.Nil(t, results["all"], "pipelines without selectors report none")
+	assert.Equal(t, "deviceName=Virtual-*", results["virtual"])
+
+	results = run(models.Event{DeviceName: "Modbus-Meter", SourceName: "Humidity"})
+	assert.Equal(t, []string{"all", "humidity"}, names(results))
+	assert.Equal(t, "sourceName=Humidity", results["humidity"])
+}
+
+func TestProcessEventThroughPipelines_Filter(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	send := func(method, path, body string) map[string]interface{} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+		router.ServeHTTP(rr, req)
+		require.Less(t, rr.Code, 300, rr.Body.String())
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+	send("POST", "/api/v3/pipeline", `{"name":"virtual","target":{"type":"FILE"},"filter":{"deviceNames":["Virtual-*"]}}`)
+	send("POST", "/api/v3/pipeline", `{"name":"modbus","target":{"type":"FILE"},"filter":{"profileNames":["Modbus*"]}}`)
+
+	ran := func(event string) []string {
+		var names []string
+		results, _ := send("POST", "/api/v3/process", event)["pipelineResults"].([]interface{})
+		for _, result := range results {
+			names = append(names, result.(map[string]interface{})["pipelineName"].(string))
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Each event enters only the pipeline whose filter it matches
+	assert.Equal(t, []string{"virtual"}, ran(`{"deviceName":"Virtual-Sensor-01","profileName":"Sensor","sourceName":"Temperature"}`))
+	assert.Equal(t, []string{"modbus"}, ran(`{"deviceName":"Meter-7","profileName":"ModbusMeter","sourceName":"Power"}`))
+	assert.Empty(t, ran(`{"deviceName":"Meter-7","profileName":"Sensor","sourceName":"Power"}`))
+
+	// The filter survives a round trip through the pipeline's definition
+	for _, pipeline := range service.pipelines {
+		require.NotNil(t, pipeline.Filter)
+		assert.Equal(t, pipeline.Filter, newPipelineDocument(pipeline).pipeline().Filter)
+	}
+}
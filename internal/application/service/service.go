@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,6 +16,8 @@ import (
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 )
 
 // Pipeline represents a data processing pipeline
@@ -22,40 +27,79 @@ type Pipeline struct {
 	Description string      `json:"description"`
 	Transforms  []Transform `json:"transforms"`
 	Target      Target      `json:"target"`
+	Trigger     string      `json:"trigger,omitempty"` // TriggerHTTP (the default) or TriggerMessageBus
 	AdminState  string      `json:"adminState"`
-	Created     int64       `json:"created"`
-	Modified    int64       `json:"modified"`
+	// Filter limits the events the pipeline runs for; without one it runs for every event
+	Filter   *PipelineFilter `json:"filter,omitempty"`
+	State    string          `json:"state,omitempty"` // PipelineStateReady or PipelineStateError once started
+	Error    string          `json:"error,omitempty"` // why the pipeline is in PipelineStateError
+	Version  int64           `json:"version"`         // incremented on every change; updates must send the version they read
+	Created  int64           `json:"created"`
+	Modified int64           `json:"modified"`
 }
 
+// States of a started pipeline, set when its target is connected
+const (
+	PipelineStateReady = "ready"
+	PipelineStateError = "error"
+)
+
 // Transform represents a data transformation step
 type Transform struct {
-	Type       string                 `json:"type"`
-	Parameters map[string]interface{} `json:"parameters"`
+	Type       string                 `json:"type" yaml:"type"`
+	Parameters map[string]interface{} `json:"parameters" yaml:"parameters,omitempty"`
 }
 
 // Target represents the output destination
 type Target struct {
-	Type       string                 `json:"type"`
-	Host       string                 `json:"host,omitempty"`
-	Port       int                    `json:"port,omitempty"`
-	Topic      string                 `json:"topic,omitempty"`
-	Format     string                 `json:"format,omitempty"`
-	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Type       string                 `json:"type" yaml:"type"`
+	Host       string                 `json:"host,omitempty" yaml:"host,omitempty"`
+	Port       int                    `json:"port,omitempty" yaml:"port,omitempty"`
+	Topic      string                 `json:"topic,omitempty" yaml:"topic,omitempty"`
+	Format     string                 `json:"format,omitempty" yaml:"format,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 }
 
 // ApplicationService handles data processing pipelines
 type ApplicationService struct {
-	logger    *logrus.Logger
-	pipelines map[string]Pipeline
-	mutex     sync.RWMutex
+	logger           *logrus.Logger
+	pipelines        map[string]Pipeline
+	pipelineVersions map[string][]Pipeline // Previous definitions by pipeline id, oldest first
+	mutex            sync.RWMutex
+	messageClient    messaging.MessageClient
+	topic            string
+	secretsClient    secrets.SecretsClient
+	httpClient       *http.Client
+	mqttSenders      map[string]MQTTSender
+	mqttMutex        sync.Mutex
+	newMQTTSender    mqttSenderFactory
+	batchers         map[string]*batcher
+	batchMutex       sync.Mutex
+	flushLock        sync.RWMutex
+	deadbands        map[string]*deadband // By pipeline id and transform index
+	deadbandMutex    sync.Mutex
+	transforms       map[string]TransformFunc
+	transformMutex   sync.RWMutex
+	pipelineMetrics  map[string]*pipelineCounters // By pipeline id
+	metricsMutex     sync.Mutex
+	store            PipelineStore // Where pipelines are saved, if anywhere
 }
 
 // NewApplicationService creates a new application service
 func NewApplicationService(logger *logrus.Logger) *ApplicationService {
 	service := &ApplicationService{
-		logger:    logger,
-		pipelines: make(map[string]Pipeline),
-	}
+		logger:           logger,
+		pipelines:        make(map[string]Pipeline),
+		pipelineVersions: make(map[string][]Pipeline),
+		httpClient:       &http.Client{},
+		mqttSenders:      make(map[string]MQTTSender),
+		batchers:         make(map[string]*batcher),
+		deadbands:        make(map[string]*deadband),
+		transforms:       make(map[string]TransformFunc),
+		pipelineMetrics:  make(map[string]*pipelineCounters),
+	}
+	service.newMQTTSender = service.connectMQTT
+	service.registerBuiltinTransforms()
 	
 	// Initialize with default pipelines
 	service.initializeDefaultPipelines()
@@ -70,6 +114,46 @@ func (s *ApplicationService) Initialize(ctx context.Context, wg *sync.WaitGroup,
 	// Add service to DI container
 	dic.Add("ApplicationService", s)
 	
+	// Pick up the pipelines saved before a restart
+	if err := s.loadStore(); err != nil {
+		s.logger.Errorf("Failed to load pipelines: %v", err)
+		return false
+	}
+	
+	// Hold off readiness while the message bus is unreachable
+	if registry, err := bootstrap.GetHealthRegistry(dic); err == nil {
+		if client, ok := s.messageClient.(bootstrap.HealthChecker); ok {
+			registry.Register("messagebus", client.Ping, true)
+		}
+	}
+	
+	// Run message bus pipelines on events published to the bus until shutdown
+	if s.messageClient != nil {
+		if err := s.messageClient.Subscribe(s.topic, s.handleEventMessage); err != nil {
+			s.logger.Errorf("Failed to subscribe to event topic %s: %v", s.topic, err)
+			return false
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			if err := s.messageClient.Unsubscribe(s.topic); err != nil {
+				s.logger.Warnf("Failed to unsubscribe from event topic %s: %v", s.topic, err)
+			}
+		}()
+	}
+	
+	// Flush buffered batches, then disconnect from MQTT brokers on shutdown
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		s.flushBatches("", "shutdown")
+		s.flushLock.Lock()
+		s.flushLock.Unlock()
+		s.closeMQTTSenders()
+	}()
+	
 	s.logger.Info("Application Service initialization completed")
 	return true
 }
@@ -85,6 +169,11 @@ func (s *ApplicationService) AddRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v3/pipeline/name/{name}", s.getPipelineByName).Methods("GET")
 	router.HandleFunc("/api/v3/pipeline/id/{id}/start", s.startPipeline).Methods("POST")
 	router.HandleFunc("/api/v3/pipeline/id/{id}/stop", s.stopPipeline).Methods("POST")
+	router.HandleFunc("/api/v3/pipeline/id/{id}/dryrun", s.dryRunPipeline).Methods("POST")
+	router.HandleFunc("/api/v3/pipeline/id/{id}/export", s.exportPipeline).Methods("GET")
+	router.HandleFunc("/api/v3/pipeline/id/{id}/version/{version}", s.getPipelineVersion).Methods("GET")
+	router.HandleFunc("/api/v3/pipeline/id/{id}/metrics", s.getPipelineMetrics).Methods("GET")
+	router.HandleFunc("/api/v3/pipeline/import", s.importPipeline).Methods("POST")
 	
 	// Data processing routes
 	router.HandleFunc("/api/v3/process", s.processData).Methods("POST")
@@ -104,8 +193,9 @@ func (s *ApplicationService) initializeDefaultPipelines() {
 				{
 					Type: "Filter",
 					Parameters: map[string]interface{}{
-						"condition": "temperature > 30",
 						"resource":  "Temperature",
+						"operator":  ">",
+						"threshold": 30,
 					},
 				},
 				{
@@ -122,6 +212,7 @@ func (s *ApplicationService) initializeDefaultPipelines() {
 				Format: "json",
 			},
 			AdminState: common.Unlocked,
+			Version:    1,
 			Created:    time.Now().UnixNano() / int64(time.Millisecond),
 			Modified:   time.Now().UnixNano() / int64(time.Millisecond),
 		},
@@ -152,6 +243,44 @@ func (s *ApplicationService) initializeDefaultPipelines() {
 				Format: "json",
 			},
 			AdminState: common.Unlocked,
+			Version:    1,
+			Created:    time.Now().UnixNano() / int64(time.Millisecond),
+			Modified:   time.Now().UnixNano() / int64(time.Millisecond),
+		},
+		{
+			Id:          models.GenerateUUID(),
+			Name:        "NormalizationPipeline",
+			Description: "Example pipeline that tags events with their site and reports temperatures in Fahrenheit; locked until started",
+			Transforms: []Transform{
+				{
+					Type: "AddTags",
+					Parameters: map[string]interface{}{
+						"tags": map[string]interface{}{"site": "default"},
+					},
+				},
+				{
+					Type: "RenameResource",
+					Parameters: map[string]interface{}{
+						"mapping": map[string]interface{}{"Temperature": "TemperatureF"},
+					},
+				},
+				{
+					Type: "ConvertUnits",
+					Parameters: map[string]interface{}{
+						"conversions": map[string]interface{}{
+							"TemperatureF": map[string]interface{}{"factor": 1.8, "offset": 32, "units": "Fahrenheit"},
+						},
+					},
+				},
+			},
+			Target: Target{
+				Type:   "HTTP",
+				Host:   "localhost",
+				Port:   8080,
+				Format: "json",
+			},
+			AdminState: common.Locked,
+			Version:    1,
 			Created:    time.Now().UnixNano() / int64(time.Millisecond),
 			Modified:   time.Now().UnixNano() / int64(time.Millisecond),
 		},
@@ -171,9 +300,8 @@ func (s *ApplicationService) addPipeline(w http.ResponseWriter, r *http.Request)
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var pipeline Pipeline
-	if err := json.NewDecoder(r.Body).Decode(&pipeline); err != nil {
+	if err := common.DecodeJSON(w, r, &pipeline); err != nil {
 		s.logger.Errorf("Failed to decode pipeline: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
@@ -181,16 +309,35 @@ func (s *ApplicationService) addPipeline(w http.ResponseWriter, r *http.Request)
 	pipeline.Id = models.GenerateUUID()
 	pipeline.Created = time.Now().UnixNano() / int64(time.Millisecond)
 	pipeline.Modified = pipeline.Created
+	pipeline.Version = 1
+	
+	// State is only set by starting the pipeline
+	pipeline.State = ""
+	pipeline.Error = ""
 	
 	// Set defaults
 	if pipeline.AdminState == "" {
 		pipeline.AdminState = common.Unlocked
 	}
+	if !validTrigger(pipeline.Trigger) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid trigger %s", pipeline.Trigger))
+		return
+	}
+	if problems := s.validatePipeline(pipeline); len(problems) > 0 {
+		common.WriteValidationErrors(w, "Invalid pipeline", problems)
+		return
+	}
 	
 	s.mutex.Lock()
-	s.pipelines[pipeline.Id] = pipeline
+	err := s.putPipelineLocked(pipeline)
 	s.mutex.Unlock()
 	
+	if err != nil {
+		s.logger.Errorf("Failed to create pipeline %s: %v", pipeline.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
 	s.logger.Infof("Pipeline created: %s", pipeline.Name)
 	
 	response := map[string]interface{}{
@@ -207,23 +354,38 @@ func (s *ApplicationService) addPipeline(w http.ResponseWriter, r *http.Request)
 func (s *ApplicationService) getAllPipelines(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	pipelines := make([]Pipeline, 0, len(s.pipelines))
 	for _, pipeline := range s.pipelines {
 		pipelines = append(pipelines, pipeline)
 	}
 	s.mutex.RUnlock()
+	sortPipelinesByCreated(pipelines)
+	page, totalCount := common.Paginate(pipelines, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":  common.ServiceVersion,
 		"statusCode":  http.StatusOK,
-		"totalCount":  len(pipelines),
-		"pipelines":   pipelines,
+		"totalCount":  totalCount,
+		"pipelines":   page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
 }
 
+// sortPipelinesByCreated orders pipelines newest first, tie-broken by id
+func sortPipelinesByCreated(pipelines []Pipeline) {
+	sort.Slice(pipelines, func(i, j int) bool {
+		return common.NewestFirst(pipelines[i].Created, pipelines[i].Id, pipelines[j].Created, pipelines[j].Id)
+	})
+}
+
 // getPipelineById handles GET /api/v3/pipeline/id/{id}
 func (s *ApplicationService) getPipelineById(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
@@ -236,7 +398,7 @@ func (s *ApplicationService) getPipelineById(w http.ResponseWriter, r *http.Requ
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
 		return
 	}
 	
@@ -254,14 +416,13 @@ func (s *ApplicationService) processData(w http.ResponseWriter, r *http.Request)
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var event models.Event
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := common.DecodeJSON(w, r, &event); err != nil {
 		s.logger.Errorf("Failed to decode event: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
-	// Process through all active pipelines
-	results := s.processEventThroughPipelines(event)
+	// Process through all active HTTP-triggered pipelines
+	results := s.processEventThroughPipelines(r.Context(), event, TriggerHTTP)
 	
 	response := map[string]interface{}{
 		"apiVersion":       common.ServiceVersion,
@@ -274,108 +435,170 @@ func (s *ApplicationService) processData(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
-// processEventThroughPipelines processes an event through all active pipelines
-func (s *ApplicationService) processEventThroughPipelines(event models.Event) []map[string]interface{} {
+// processEventThroughPipelines processes an event through all active pipelines with the given trigger
+// whose selectors match it. Pipelines run outside the lock since their targets may block on the network.
+func (s *ApplicationService) processEventThroughPipelines(ctx context.Context, event models.Event, trigger string) []map[string]interface{} {
 	var results []map[string]interface{}
 	
 	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-	
+	var active []Pipeline
+	var matchedSelectors []string
 	for _, pipeline := range s.pipelines {
-		if pipeline.AdminState == common.Unlocked {
-			result := s.executePipeline(event, pipeline)
-			results = append(results, result)
+		if pipeline.AdminState != common.Unlocked || pipelineTrigger(pipeline) != trigger {
+			continue
+		}
+		if matched, ok := matchSelectors(pipeline, event); ok {
+			active = append(active, pipeline)
+			matchedSelectors = append(matchedSelectors, matched)
 		}
 	}
+	s.mutex.RUnlock()
+	
+	for i, pipeline := range active {
+		result := s.executePipeline(ctx, event, pipeline)
+		if matchedSelectors[i] != "" {
+			result["matchedSelector"] = matchedSelectors[i]
+		}
+		results = append(results, result)
+	}
 	
 	return results
 }
 
-// executePipeline executes a single pipeline on an event
-func (s *ApplicationService) executePipeline(event models.Event, pipeline Pipeline) map[string]interface{} {
+// executePipeline executes a single pipeline on an event. A filter that drops the event stops the
+// pipeline and is reported as filteredOut; a failing transform stops it with status "error".
+// A Batch transform buffers the event and only a full batch continues, as one payload.
+// ctx carries the correlation id forwarded to targets and bounds their requests. Every run counts
+// towards the pipeline's metrics.
+func (s *ApplicationService) executePipeline(ctx context.Context, event models.Event, pipeline Pipeline) map[string]interface{} {
 	s.logger.Debugf("Executing pipeline: %s for event: %s", pipeline.Name, event.Id)
 	
-	processedEvent := event
-	transformResults := []string{}
+	start := time.Now()
+	result := s.runPipeline(ctx, pipeline, 0, eventData(event, pipeline.Target.Format), []string{})
+	s.recordPipelineRun(pipeline.Id, result, time.Since(start))
+	return result
+}
+
+// runPipeline executes the transforms of pipeline from index from on, then its target. Each step
+// works on the output of the one before it, so for example Encrypt after Compress encrypts the
+// compressed bytes.
+func (s *ApplicationService) runPipeline(ctx context.Context, pipeline Pipeline, from int, data Payload, transformResults []string) map[string]interface{} {
+	result := map[string]interface{}{
+		"pipelineId":   pipeline.Id,
+		"pipelineName": pipeline.Name,
+		"filteredOut":  false,
+		"status":       "success",
+	}
+	if data.Batched {
+		result["batch"] = BatchFlushed
+		result["batchCount"] = len(data.Events)
+	}
+	finish := func() map[string]interface{} {
+		result["transformResults"] = transformResults
+		if data.Compression != nil {
+			result["compression"] = *data.Compression
+		}
+		result["timestamp"] = time.Now().UnixNano() / int64(time.Millisecond)
+		return result
+	}
+	fail := func(transform Transform, err error) map[string]interface{} {
+		transformResults = append(transformResults, err.Error())
+		result["status"] = "error"
+		result["error"] = fmt.Sprintf("%s transform failed: %v", transform.Type, err)
+		return finish()
+	}
 	
 	// Execute transforms
-	for _, transform := range pipeline.Transforms {
-		result := s.executeTransform(processedEvent, transform)
-		transformResults = append(transformResults, result)
+	for i := from; i < len(pipeline.Transforms); i++ {
+		transform := pipeline.Transforms[i]
+		switch transform.Type {
+		case "Batch":
+			if data.Serialized() {
+				return fail(transform, errors.New("cannot batch a serialized payload; batch before Compress or Encrypt"))
+			}
+			if data.Batched {
+				transformResults = append(transformResults, "Events already batched")
+				continue
+			}
+			batch, buffered, err := s.addToBatch(pipeline, i, data.Events[0])
+			if err != nil {
+				return fail(transform, err)
+			}
+			if batch == nil {
+				transformResults = append(transformResults, fmt.Sprintf("Event buffered, %d in batch", buffered))
+				result["batch"] = BatchBuffered
+				result["batchCount"] = buffered
+				return finish()
+			}
+			transformResults = append(transformResults, fmt.Sprintf("Batch of %d events flushed", len(batch)))
+			data = batchData(batch, data.Format)
+			result["batch"] = BatchFlushed
+			result["batchCount"] = len(batch)
+			
+		case "Deadband":
+			if data.Serialized() {
+				return fail(transform, errNeedsEvents)
+			}
+			deadband, err := s.deadbandFor(pipeline, i)
+			if err != nil {
+				return fail(transform, err)
+			}
+			var message string
+			data, message = deadband.filter(data)
+			transformResults = append(transformResults, message)
+			if len(data.Events) == 0 {
+				result["filteredOut"] = true
+				return finish()
+			}
+			if data.Batched {
+				result["batchCount"] = len(data.Events)
+			}
+			
+		default:
+			var message string
+			var filteredOut bool
+			var err error
+			if data, message, filteredOut, err = s.applyTransform(ctx, transform, data); err != nil {
+				return fail(transform, err)
+			}
+			transformResults = append(transformResults, message)
+			if filteredOut {
+				result["filteredOut"] = true
+				return finish()
+			}
+			if data.Batched {
+				result["batchCount"] = len(data.Events)
+			}
+		}
 	}
 	
-	// Execute target (output)
-	targetResult := s.executeTarget(processedEvent, pipeline.Target)
-	
-	return map[string]interface{}{
-		"pipelineId":       pipeline.Id,
-		"pipelineName":     pipeline.Name,
-		"transformResults": transformResults,
-		"targetResult":     targetResult,
-		"status":           "success",
-		"timestamp":        time.Now().UnixNano() / int64(time.Millisecond),
+	// Execute target (output); a batch goes out as a single document in the target's format
+	targetResult, statusCode, err := s.executeTarget(ctx, data, pipeline.Target)
+	s.recordTargetResult(pipeline.Id, err)
+	result["targetResult"] = targetResult
+	if statusCode != 0 {
+		result["targetStatusCode"] = statusCode
 	}
-}
-
-// executeTransform executes a single transform
-func (s *ApplicationService) executeTransform(event models.Event, transform Transform) string {
-	switch transform.Type {
-	case "Filter":
-		return s.executeFilterTransform(event, transform)
-	case "Convert":
-		return s.executeConvertTransform(event, transform)
-	case "Batch":
-		return s.executeBatchTransform(event, transform)
-	case "Compress":
-		return s.executeCompressTransform(event, transform)
-	default:
-		return "Unknown transform type"
+	if err != nil {
+		result["status"] = "error"
+		result["error"] = fmt.Sprintf("%s target failed: %v", pipeline.Target.Type, err)
 	}
+	return finish()
 }
 
-// executeFilterTransform simulates filtering data
-func (s *ApplicationService) executeFilterTransform(event models.Event, transform Transform) string {
-	// Simulate filter logic
-	condition := transform.Parameters["condition"]
-	s.logger.Debugf("Applying filter: %v", condition)
-	return "Filter applied successfully"
-}
-
-// executeConvertTransform simulates data conversion
-func (s *ApplicationService) executeConvertTransform(event models.Event, transform Transform) string {
-	format := transform.Parameters["format"]
-	s.logger.Debugf("Converting to format: %v", format)
-	return "Data converted successfully"
-}
-
-// executeBatchTransform simulates batching data
-func (s *ApplicationService) executeBatchTransform(event models.Event, transform Transform) string {
-	batchSize := transform.Parameters["batchSize"]
-	s.logger.Debugf("Batching with size: %v", batchSize)
-	return "Data batched successfully"
-}
-
-// executeCompressTransform simulates data compression
-func (s *ApplicationService) executeCompressTransform(event models.Event, transform Transform) string {
-	algorithm := transform.Parameters["algorithm"]
-	s.logger.Debugf("Compressing with algorithm: %v", algorithm)
-	return "Data compressed successfully"
-}
-
-// executeTarget simulates sending data to target
-func (s *ApplicationService) executeTarget(event models.Event, target Target) string {
+// executeTarget sends the output of the last step to the pipeline's target. It returns a description
+// of the outcome and, for HTTP targets, the response status code.
+func (s *ApplicationService) executeTarget(ctx context.Context, data Payload, target Target) (string, int, error) {
 	switch target.Type {
 	case "HTTP":
-		s.logger.Debugf("Sending to HTTP endpoint: %s:%d", target.Host, target.Port)
-		return "Sent to HTTP endpoint"
+		return s.exportHTTP(ctx, data, target)
 	case "MQTT":
-		s.logger.Debugf("Publishing to MQTT topic: %s", target.Topic)
-		return "Published to MQTT"
+		return s.exportMQTT(data, target)
 	case "FILE":
 		s.logger.Debugf("Writing to file")
-		return "Written to file"
+		return "Written to file", 0, nil
 	default:
-		return "Unknown target type"
+		return "Unknown target type", 0, nil
 	}
 }
 
@@ -389,30 +612,59 @@ func (s *ApplicationService) updatePipeline(w http.ResponseWriter, r *http.Reque
 	id := vars["id"]
 	
 	var updatedPipeline Pipeline
-	if err := json.NewDecoder(r.Body).Decode(&updatedPipeline); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &updatedPipeline); err != nil {
+		return
+	}
+	if !validTrigger(updatedPipeline.Trigger) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid trigger %s", updatedPipeline.Trigger))
+		return
+	}
+	if problems := s.validatePipeline(updatedPipeline); len(problems) > 0 {
+		common.WriteValidationErrors(w, "Invalid pipeline", problems)
 		return
 	}
 	
 	s.mutex.Lock()
 	existingPipeline, exists := s.pipelines[id]
-	if exists {
+	stale := exists && updatedPipeline.Version != existingPipeline.Version
+	var err error
+	if exists && !stale {
 		updatedPipeline.Id = id
+		updatedPipeline.Version = existingPipeline.Version + 1
+		updatedPipeline.AdminState = existingPipeline.AdminState
+		updatedPipeline.State = ""
+		updatedPipeline.Error = ""
 		updatedPipeline.Created = existingPipeline.Created
 		updatedPipeline.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.pipelines[id] = updatedPipeline
+		if err = s.putPipelineLocked(updatedPipeline); err == nil {
+			s.recordVersionLocked(existingPipeline)
+		}
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	if stale {
+		common.WriteVersionConflict(w, "Pipeline", updatedPipeline.Version, existingPipeline.Version)
 		return
 	}
+	if err != nil {
+		s.logger.Errorf("Failed to update pipeline %s: %v", existingPipeline.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	// Events batched under the old definition are sent on with it
+	s.flushBatches(id, "update")
+	s.forgetDeadbands(id)
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
 		"message":    "Pipeline updated successfully",
+		"version":    updatedPipeline.Version,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -427,15 +679,26 @@ func (s *ApplicationService) deletePipeline(w http.ResponseWriter, r *http.Reque
 	
 	s.mutex.Lock()
 	_, exists := s.pipelines[id]
+	var err error
 	if exists {
-		delete(s.pipelines, id)
+		err = s.removePipelineLocked(id)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
 		return
 	}
+	if err != nil {
+		s.logger.Errorf("Failed to delete pipeline %s: %v", id, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	// Send on the events the pipeline still buffers
+	s.flushBatches(id, "delete")
+	s.forgetDeadbands(id)
+	s.forgetPipelineMetrics(id)
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
@@ -464,7 +727,7 @@ func (s *ApplicationService) getPipelineByName(w http.ResponseWriter, r *http.Re
 	s.mutex.RUnlock()
 	
 	if foundPipeline == nil {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
 		return
 	}
 	
@@ -477,7 +740,9 @@ func (s *ApplicationService) getPipelineByName(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(response)
 }
 
-// startPipeline handles POST /api/v3/pipeline/id/{id}/start
+// startPipeline handles POST /api/v3/pipeline/id/{id}/start. The pipeline's target is connected
+// up front; if that fails the pipeline still starts, in PipelineStateError, and connecting is
+// retried on each event.
 func (s *ApplicationService) startPipeline(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
@@ -486,29 +751,67 @@ func (s *ApplicationService) startPipeline(w http.ResponseWriter, r *http.Reques
 	
 	s.mutex.Lock()
 	pipeline, exists := s.pipelines[id]
+	var err error
 	if exists {
+		// The admin state is not part of the definition, so the version and history are left alone
 		pipeline.AdminState = common.Unlocked
 		pipeline.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.pipelines[id] = pipeline
+		err = s.putPipelineLocked(pipeline)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to start pipeline %s: %v", pipeline.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	
+	// Connect outside the lock, since a broker may be slow to answer
+	state, stateError := PipelineStateReady, ""
+	if err := s.connectTarget(pipeline.Target); err != nil {
+		s.logger.Errorf("Pipeline %s started but its target is unavailable: %v", pipeline.Name, err)
+		state, stateError = PipelineStateError, err.Error()
+	}
+	s.mutex.Lock()
+	if current, ok := s.pipelines[id]; ok {
+		current.State = state
+		current.Error = stateError
+		s.pipelines[id] = current
+	}
+	s.mutex.Unlock()
+	
 	s.logger.Infof("Started pipeline: %s", pipeline.Name)
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
 		"message":    "Pipeline started successfully",
+		"state":      state,
+	}
+	if stateError != "" {
+		response["error"] = stateError
 	}
 	
 	json.NewEncoder(w).Encode(response)
 }
 
+// connectTarget sets up the connection a target needs before its first event. Only MQTT targets
+// hold a connection; other targets connect per event.
+func (s *ApplicationService) connectTarget(target Target) error {
+	if target.Type != "MQTT" {
+		return nil
+	}
+	if _, _, err := mqttPublishOptions(target); err != nil {
+		return err
+	}
+	_, err := s.mqttSender(target)
+	return err
+}
+
 // stopPipeline handles POST /api/v3/pipeline/id/{id}/stop
 func (s *ApplicationService) stopPipeline(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
@@ -518,17 +821,27 @@ func (s *ApplicationService) stopPipeline(w http.ResponseWriter, r *http.Request
 	
 	s.mutex.Lock()
 	pipeline, exists := s.pipelines[id]
+	var err error
 	if exists {
+		// The admin state is not part of the definition, so the version and history are left alone
 		pipeline.AdminState = common.Locked
 		pipeline.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.pipelines[id] = pipeline
+		err = s.putPipelineLocked(pipeline)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
 		return
 	}
+	if err != nil {
+		s.logger.Errorf("Failed to stop pipeline %s: %v", pipeline.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	// Send on the events the pipeline still buffers
+	s.flushBatches(id, "stop")
 	
 	s.logger.Infof("Stopped pipeline: %s", pipeline.Name)
 	
@@ -549,9 +862,8 @@ func (s *ApplicationService) triggerPipeline(w http.ResponseWriter, r *http.Requ
 	pipelineId := vars["pipelineId"]
 	
 	var event models.Event
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := common.DecodeJSON(w, r, &event); err != nil {
 		s.logger.Errorf("Failed to decode event: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
@@ -560,16 +872,16 @@ func (s *ApplicationService) triggerPipeline(w http.ResponseWriter, r *http.Requ
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
 		return
 	}
 	
 	if pipeline.AdminState != common.Unlocked {
-		http.Error(w, "Pipeline is not active", http.StatusConflict)
+		common.WriteError(w, http.StatusConflict, "Pipeline is not active")
 		return
 	}
 	
-	result := s.executePipeline(event, pipeline)
+	result := s.executePipeline(r.Context(), event, pipeline)
 	
 	response := map[string]interface{}{
 		"apiVersion":     common.ServiceVersion,
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// PipelineStore persists pipelines so they survive a restart. The service keeps working copies in
+// memory; the store only has to save changes and hand everything back at startup.
+type PipelineStore interface {
+	// LoadPipelines returns every saved pipeline, in no particular order
+	LoadPipelines() ([]Pipeline, error)
+	// SavePipeline stores a new or updated pipeline, replacing any with the same id
+	SavePipeline(pipeline Pipeline) error
+	// DeletePipeline removes a pipeline; removing one that is not there is not an error
+	DeletePipeline(id string) error
+}
+
+// SetStore saves pipelines in store, and has Initialize load them from it. Must be called before Initialize.
+func (s *ApplicationService) SetStore(store PipelineStore) {
+	s.store = store
+}
+
+// loadStore replaces the default pipelines with those saved in the service's store, if it has one. An
+// empty store is seeded with the defaults instead, so they are only created once.
+func (s *ApplicationService) loadStore() error {
+	if s.store == nil {
+		return nil
+	}
+	pipelines, err := s.store.LoadPipelines()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(pipelines) == 0 {
+		for _, pipeline := range s.pipelines {
+			if err := s.store.SavePipeline(pipeline); err != nil {
+				return fmt.Errorf("failed to save default pipeline %s: %w", pipeline.Name, err)
+			}
+		}
+		s.logger.Infof("Saved %d default pipeline(s) to the pipeline store", len(s.pipelines))
+		return nil
+	}
+
+	// Each pipeline comes back in its saved admin state; whether its target is reachable is found
+	// out afresh
+	s.pipelines = make(map[string]Pipeline, len(pipelines))
+	for _, pipeline := range pipelines {
+		pipeline.State = ""
+		pipeline.Error = ""
+		s.pipelines[pipeline.Id] = pipeline
+	}
+	s.logger.Infof("Loaded %d pipeline(s)", len(pipelines))
+	return nil
+}
+
+// putPipelineLocked saves a pipeline to the store, if any, then keeps it in memory. Nothing changes if
+// the save fails. Caller must hold the write lock.
+func (s *ApplicationService) putPipelineLocked(pipeline Pipeline) error {
+	if s.store != nil {
+		if err := s.store.SavePipeline(pipeline); err != nil {
+			return fmt.Errorf("failed to save pipeline %s: %w", pipeline.Name, err)
+		}
+	}
+	s.pipelines[pipeline.Id] = pipeline
+	return nil
+}
+
+// removePipelineLocked deletes a pipeline from the store, if any, then from memory with its previous
+// versions. Nothing changes if the delete fails. Caller must hold the write lock.
+func (s *ApplicationService) removePipelineLocked(id string) error {
+	if s.store != nil {
+		if err := s.store.DeletePipeline(id); err != nil {
+			return fmt.Errorf("failed to delete pipeline %s: %w", s.pipelines[id].Name, err)
+		}
+	}
+	delete(s.pipelines, id)
+	delete(s.pipelineVersions, id)
+	return nil
+}
+
+// filePipelineDocument is the layout of a FilePipelineStore's file
+type filePipelineDocument struct {
+	Pipelines []Pipeline `json:"pipelines"`
+}
+
+// FilePipelineStore implements PipelineStore with a JSON file, rewritten in full on each change. The new
+// contents are written beside the file and renamed over it, so a crash never leaves half a file behind.
+type FilePipelineStore struct {
+	path      string
+	pipelines map[string]Pipeline
+	mutex     sync.Mutex
+}
+
+// NewFilePipelineStore creates a store kept in the file at path, reading what it already holds. A missing
+// file is an empty store; its directory must exist.
+func NewFilePipelineStore(path string) (*FilePipelineStore, error) {
+	store := &FilePipelineStore{
+		path:      path,
+		pipelines: make(map[string]Pipeline),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline store %s: %w", path, err)
+	}
+	var document filePipelineDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline store %s: %w", path, err)
+	}
+	for _, pipeline := range document.Pipelines {
+		store.pipelines[pipeline.Id] = pipeline
+	}
+	return store, nil
+}
+
+// LoadPipelines returns every saved pipeline
+func (f *FilePipelineStore) LoadPipelines() ([]Pipeline, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	pipelines := make([]Pipeline, 0, len(f.pipelines))
+	for _, pipeline := range f.pipelines {
+		pipelines = append(pipelines, pipeline)
+	}
+	return pipelines, nil
+}
+
+// SavePipeline stores a pipeline and rewrites the file
+func (f *FilePipelineStore) SavePipeline(pipeline Pipeline) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.pipelines[pipeline.Id]
+	f.pipelines[pipeline.Id] = pipeline
+	if err := f.writeLocked(); err != nil {
+		if existed {
+			f.pipelines[pipeline.Id] = previous
+		} else {
+			delete(f.pipelines, pipeline.Id)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeletePipeline removes a pipeline and rewrites the file
+func (f *FilePipelineStore) DeletePipeline(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.pipelines[id]
+	if !existed {
+		return nil
+	}
+	delete(f.pipelines, id)
+	if err := f.writeLocked(); err != nil {
+		f.pipelines[id] = previous
+		return err
+	}
+	return nil
+}
+
+// writeLocked replaces the file with the store's contents, in id order so the file diffs cleanly.
+// Caller must hold the lock.
+func (f *FilePipelineStore) writeLocked() error {
+	document := filePipelineDocument{Pipelines: make([]Pipeline, 0, len(f.pipelines))}
+	for _, pipeline := range f.pipelines {
+		document.Pipelines = append(document.Pipelines, pipeline)
+	}
+	sort.Slice(document.Pipelines, func(i, j int) bool { return document.Pipelines[i].Id < document.Pipelines[j].Id })
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pipeline store: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write pipeline store %s: %w", f.path, err)
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write pipeline store %s: %w", f.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// pipelineNames returns the sorted names of the pipelines in store
+func pipelineNames(t *testing.T, store PipelineStore) []string {
+	pipelines, err := store.LoadPipelines()
+	require.NoError(t, err)
+	var names []string
+	for _, pipeline := range pipelines {
+		names = append(names, pipeline.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFilePipelineStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipelines.json")
+	store, err := NewFilePipelineStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, pipelineNames(t, store))
+
+	require.NoError(t, store.SavePipeline(Pipeline{Id: "p-1", Name: "hot", AdminState: common.Unlocked}))
+	require.NoError(t, store.SavePipeline(Pipeline{Id: "p-2", Name: "cold", AdminState: common.Locked}))
+	require.NoError(t, store.SavePipeline(Pipeline{Id: "p-1", Name: "warm", AdminState: common.Unlocked}))
+	assert.Equal(t, []string{"cold", "warm"}, pipelineNames(t, store))
+
+	require.NoError(t, store.DeletePipeline("p-2"))
+	require.NoError(t, store.DeletePipeline("p-2"), "deleting a missing pipeline is not an error")
+
+	// A new store over the same file reads back what was saved
+	reopened, err := NewFilePipelineStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"warm"}, pipelineNames(t, reopened))
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	_, err = NewFilePipelineStore(path)
+	assert.Error(t, err)
+}
+
+func TestFilePipelineStore_WriteFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gone")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	store, err := NewFilePipelineStore(filepath.Join(dir, "pipelines.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(dir))
+
+	assert.Error(t, store.SavePipeline(Pipeline{Id: "p-1", Name: "hot"}))
+	assert.Empty(t, pipelineNames(t, store), "a failed save leaves the store as it was")
+}
+
+// failingPipelineStore is a PipelineStore whose writes all fail
+type failingPipelineStore struct{}
+
+var errStoreDown = errors.New("store down")
+
+func (failingPipelineStore) LoadPipelines() ([]Pipeline, error) { return nil, nil }
+func (failingPipelineStore) SavePipeline(Pipeline) error        { return errStoreDown }
+func (failingPipelineStore) DeletePipeline(string) error        { return errStoreDown }
+
+func TestApplicationService_StoreFailure(t *testing.T) {
+	service, _ := newTestService()
+	service.pipelines["p-1"] = Pipeline{Id: "p-1", Name: "hot", AdminState: common.Unlocked, Target: Target{Type: "FILE"}, Version: 1}
+	service.SetStore(failingPipelineStore{})
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	requests := []struct {
+		method, path string
+		pipeline     *Pipeline
+	}{
+		{"POST", "/api/v3/pipeline", &Pipeline{Name: "new", Target: Target{Type: "FILE"}}},
+		{"PUT", "/api/v3/pipeline/id/p-1", &Pipeline{Name: "renamed", Target: Target{Type: "FILE"}, Version: 1}},
+		{"POST", "/api/v3/pipeline/id/p-1/stop", nil},
+		{"POST", "/api/v3/pipeline/id/p-1/start", nil},
+		{"DELETE", "/api/v3/pipeline/id/p-1", nil},
+	}
+	for _, request := range requests {
+		rr := pipelineRequest(t, router, request.method, request.path, request.pipeline)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code, "%s %s: %s", request.method, request.path, rr.Body.String())
+	}
+
+	// Nothing changed in memory either
+	assert.Equal(t, map[string]Pipeline{
+		"p-1": {Id: "p-1", Name: "hot", AdminState: common.Unlocked, Target: Target{Type: "FILE"}, Version: 1},
+	}, service.pipelines)
+	assert.Empty(t, service.pipelineVersions)
+}
+
+func TestApplicationService_Restart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipelines.json")
+
+	// start runs a service with its default pipelines on a fresh store over the file, as a restart would
+	start := func(t *testing.T) (*ApplicationService, *mux.Router, func()) {
+		store, err := NewFilePipelineStore(path)
+		require.NoError(t, err)
+		logger, _ := test.NewNullLogger()
+		service := NewApplicationService(logger)
+		service.SetStore(store)
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+		router := mux.NewRouter()
+		service.AddRoutes(router)
+		return service, router, func() {
+			cancel()
+			wg.Wait()
+		}
+	}
+	snapshot := func(service *ApplicationService) map[string]Pipeline {
+		service.mutex.RLock()
+		defer service.mutex.RUnlock()
+		pipelines := make(map[string]Pipeline, len(service.pipelines))
+		for id, pipeline := range service.pipelines {
+			pipelines[id] = pipeline
+		}
+		return pipelines
+	}
+
+	service, router, stop := start(t)
+	defaults := snapshot(service)
+	require.NotEmpty(t, defaults)
+
+	rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline", &Pipeline{Name: "hot", Target: Target{Type: "FILE"}})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	id := created["id"].(string)
+	rr = pipelineRequest(t, router, "POST", "/api/v3/pipeline/id/"+id+"/stop", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	saved := snapshot(service)[id]
+	stop()
+
+	// The created pipeline comes back as it was left, stopped, beside the same defaults
+	service, router, stop = start(t)
+	restored := snapshot(service)
+	assert.Len(t, restored, len(defaults)+1)
+	assert.Equal(t, saved, restored[id])
+	assert.Equal(t, common.Locked, restored[id].AdminState)
+	for defaultId, pipeline := range defaults {
+		assert.Equal(t, pipeline.Name, restored[defaultId].Name, "defaults are not created again")
+	}
+
+	// Deletes are saved too
+	rr = pipelineRequest(t, router, "DELETE", "/api/v3/pipeline/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	stop()
+
+	service, _, stop = start(t)
+	defer stop()
+	assert.Len(t, snapshot(service), len(defaults))
+	assert.NotContains(t, snapshot(service), id)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// transformValidators check the parameters of the built-in transforms. Other registered
+// transforms check their parameters when they run.
+var transformValidators = map[string]func(parameters map[string]interface{}) error{
+	"Batch": func(parameters map[string]interface{}) error {
+		_, _, err := batchParameters(Transform{Parameters: parameters})
+		return err
+	},
+	"Compress": func(parameters map[string]interface{}) error {
+		_, err := compressAlgorithm(parameters)
+		return err
+	},
+	"Encrypt": func(parameters map[string]interface{}) error {
+		if secretPath, _ := parameters["secretPath"].(string); secretPath == "" {
+			return errors.New("encrypt needs a secretPath")
+		}
+		return nil
+	},
+	"Filter": func(parameters map[string]interface{}) error {
+		_, err := newValueCondition(parameters)
+		return err
+	},
+	"FilterByDeviceName": func(parameters map[string]interface{}) error {
+		_, err := newNameMatcher(parameters)
+		return err
+	},
+	"FilterByResourceName": func(parameters map[string]interface{}) error {
+		_, err := newNameMatcher(parameters)
+		return err
+	},
+	"AddTags": func(parameters map[string]interface{}) error {
+		_, err := tagsParameter(parameters)
+		return err
+	},
+	"RenameResource": func(parameters map[string]interface{}) error {
+		_, err := resourceMapping(parameters)
+		return err
+	},
+	"ConvertUnits": func(parameters map[string]interface{}) error {
+		_, err := unitConversions(parameters)
+		return err
+	},
+	"Deadband": func(parameters map[string]interface{}) error {
+		_, err := deadbandParameters(parameters)
+		return err
+	},
+	"Map": func(parameters map[string]interface{}) error {
+		_, err := newEventMapping(parameters)
+		return err
+	},
+}
+
+// serializingTransforms turn the events into bytes; only other serializing transforms may follow them
+var serializingTransforms = map[string]bool{
+	"Compress": true,
+	"Encrypt":  true,
+	"Map":      true,
+}
+
+// validatePipeline checks the transforms, selectors and target of a pipeline and returns every problem
+// found, so a broken pipeline is rejected when it is saved rather than when data flows through it
+func (s *ApplicationService) validatePipeline(pipeline Pipeline) []string {
+	var problems []string
+	if pipeline.Name == "" {
+		problems = append(problems, "name is required")
+	}
+
+	serializedBy := ""
+	for i, transform := range pipeline.Transforms {
+		if _, known := s.lookupTransform(transform.Type); !known {
+			problems = append(problems, fmt.Sprintf("transforms[%d]: unknown type %q", i, transform.Type))
+			continue
+		}
+		if validator, ok := transformValidators[transform.Type]; ok {
+			if err := validator(transform.Parameters); err != nil {
+				problems = append(problems, fmt.Sprintf("transforms[%d] (%s): %v", i, transform.Type, err))
+			}
+		}
+		if serializedBy != "" && !serializingTransforms[transform.Type] {
+			problems = append(problems, fmt.Sprintf("transforms[%d] (%s): must come before %s, which serializes the events", i, transform.Type, serializedBy))
+		}
+		if serializingTransforms[transform.Type] && serializedBy == "" {
+			serializedBy = transform.Type
+		}
+	}
+
+	problems = append(problems, validateSelectors(pipeline)...)
+	for _, problem := range validateTarget(pipeline.Target) {
+		problems = append(problems, "target: "+problem)
+	}
+	return problems
+}
+
+// validateTarget checks that a target's type is known and that it has the fields that type needs
+func validateTarget(target Target) []string {
+	var problems []string
+	if target.Port < 0 || target.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("invalid port %d", target.Port))
+	}
+	if !validFormat(target.Format) {
+		problems = append(problems, fmt.Sprintf("invalid format %q, must be %s, %s or %s", target.Format, FormatJSON, FormatXML, FormatCloudEvents))
+	}
+
+	switch target.Type {
+	case "HTTP":
+		if target.Host == "" {
+			problems = append(problems, "host is required")
+		}
+		if value, ok := target.Parameters["timeout"].(string); ok && value != "" {
+			if timeout, err := time.ParseDuration(value); err != nil || timeout <= 0 {
+				problems = append(problems, fmt.Sprintf("invalid timeout %q", value))
+			}
+		}
+		if authMode, _ := target.Parameters["authMode"].(string); authMode != "" {
+			if authMode != AuthModeBasic && authMode != AuthModeAPIKey {
+				problems = append(problems, fmt.Sprintf("invalid authMode %q", authMode))
+			}
+			if secretPath, _ := target.Parameters["secretPath"].(string); secretPath == "" {
+				problems = append(problems, fmt.Sprintf("authMode %s needs a secretPath", authMode))
+			}
+		}
+	case "MQTT":
+		if target.Host == "" {
+			problems = append(problems, "host is required")
+		}
+		if target.Topic == "" {
+			problems = append(problems, "topic is required")
+		}
+		if _, _, err := mqttPublishOptions(target); err != nil {
+			problems = append(problems, err.Error())
+		}
+	case "FILE":
+	case "":
+		problems = append(problems, "type is required")
+	default:
+		problems = append(problems, fmt.Sprintf("unknown type %q", target.Type))
+	}
+	return problems
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func TestValidatePipeline(t *testing.T) {
+	httpTarget := Target{Type: "HTTP", Host: "localhost", Port: 8080}
+
+	tests := []struct {
+		name           string
+		pipeline       Pipeline
+		expectProblems []string
+	}{
+		{
+			"Valid",
+			Pipeline{
+				Name: "export",
+				Transforms: []Transform{
+					{Type: "FilterByDeviceName", Parameters: map[string]interface{}{"include": []interface{}{"Device-1"}}},
+					{Type: "Batch", Parameters: map[string]interface{}{"batchSize": float64(10)}},
+					{Type: "Compress"},
+					{Type: "Encrypt", Parameters: map[string]interface{}{"secretPath": "aes"}},
+				},
+				Target: httpTarget,
+			},
+			nil,
+		},
+		{
+			"Missing name and target",
+			Pipeline{},
+			[]string{"name is required", "target: type is required"},
+		},
+		{
+			"Unknown transform",
+			Pipeline{Name: "p", Transforms: []Transform{{Type: "Teleport"}}, Target: httpTarget},
+			[]string{`transforms[0]: unknown type "Teleport"`},
+		},
+		{
+			"Invalid parameters",
+			Pipeline{
+				Name: "p",
+				Transforms: []Transform{
+					{Type: "Filter", Parameters: map[string]interface{}{"resource": "Temperature", "operator": "~", "threshold": float64(1)}},
+					{Type: "Batch", Parameters: map[string]interface{}{}},
+					{Type: "Compress", Parameters: map[string]interface{}{"algorithm": "lz4"}},
+					{Type: "Encrypt"},
+				},
+				Target: httpTarget,
+			},
+			[]string{
+				`transforms[0] (Filter): invalid operator "~"`,
+				"transforms[1] (Batch): batch needs a batchSize or a timeout",
+				`transforms[2] (Compress): unknown algorithm "lz4", must be gzip or zlib`,
+				"transforms[3] (Encrypt): encrypt needs a secretPath",
+			},
+		},
+		{
+			"Event transform after serializing",
+			Pipeline{Name: "p", Transforms: []Transform{{Type: "Compress"}, {Type: "Convert"}}, Target: httpTarget},
+			[]string{"transforms[1] (Convert): must come before Compress, which serializes the events"},
+		},
+		{
+			"HTTP target without host",
+			Pipeline{Name: "p", Target: Target{Type: "HTTP", Parameters: map[string]interface{}{"timeout": "soon", "authMode": "basic"}}},
+			[]string{"target: host is required", `target: invalid timeout "soon"`, "target: authMode basic needs a secretPath"},
+		},
+		{
+			"MQTT target without topic",
+			Pipeline{Name: "p", Target: Target{Type: "MQTT", Host: "broker", Parameters: map[string]interface{}{"qos": float64(2)}}},
+			[]string{"target: topic is required", "target: invalid qos 2, must be 0 or 1"},
+		},
+		{
+			"Unknown target",
+			Pipeline{Name: "p", Target: Target{Type: "FTP", Port: 70000}},
+			[]string{"target: invalid port 70000", `target: unknown type "FTP"`},
+		},
+	}
+
+	service, _ := newTestService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectProblems, service.validatePipeline(tt.pipeline))
+		})
+	}
+}
+
+func TestDefaultPipelinesAreValid(t *testing.T) {
+	service, _ := newTestService()
+	service.initializeDefaultPipelines()
+	for _, pipeline := range service.pipelines {
+		assert.Empty(t, service.validatePipeline(pipeline), pipeline.Name)
+	}
+}
+
+func TestAddPipeline_Invalid(t *testing.T) {
+	service, _ := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := pipelineRequest(t, router, "POST", "/api/v3/pipeline", &Pipeline{Name: "p", Transforms: []Transform{{Type: "Teleport"}}, Target: Target{Type: "FILE"}})
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	var response common.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "Invalid pipeline", response.Message)
+	assert.Equal(t, []string{`transforms[0]: unknown type "Teleport"`}, response.Errors)
+	assert.Empty(t, service.pipelines)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// MaxPipelineVersions is how many previous definitions of a pipeline are kept
+const MaxPipelineVersions = 10
+
+// recordVersionLocked keeps previous, the definition a change is about to replace, so it can still be
+// read by version. Only the newest MaxPipelineVersions are kept. Caller must hold the lock.
+func (s *ApplicationService) recordVersionLocked(previous Pipeline) {
+	versions := append(s.pipelineVersions[previous.Id], previous)
+	if len(versions) > MaxPipelineVersions {
+		versions = append([]Pipeline(nil), versions[len(versions)-MaxPipelineVersions:]...)
+	}
+	s.pipelineVersions[previous.Id] = versions
+}
+
+// getPipelineVersion handles GET /api/v3/pipeline/id/{id}/version/{version}. The current version
+// and the last MaxPipelineVersions before it can be read; older ones get 404.
+func (s *ApplicationService) getPipelineVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+	version, err := strconv.ParseInt(vars["version"], 10, 64)
+	if err != nil || version < 1 {
+		common.WriteError(w, http.StatusBadRequest, "Invalid version "+vars["version"])
+		return
+	}
+
+	s.mutex.RLock()
+	current, exists := s.pipelines[id]
+	var pipeline Pipeline
+	found := false
+	if exists && current.Version == version {
+		pipeline, found = current, true
+	}
+	for _, previous := range s.pipelineVersions[id] {
+		if !found && previous.Version == version {
+			pipeline, found = previous, true
+		}
+	}
+	s.mutex.RUnlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Pipeline not found")
+		return
+	}
+	if !found {
+		common.WriteError(w, http.StatusNotFound, "Pipeline version "+vars["version"]+" not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"pipeline":   pipeline,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
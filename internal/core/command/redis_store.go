@@ -0,0 +1,106 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// redisCommandResponsesKey is the hash holding every command response, each field an id holding the
+// response as JSON
+const redisCommandResponsesKey = "edgex:core-command:responses"
+
+// RedisCommandResponseStore implements CommandResponseStore using Redis, so responses survive a restart
+type RedisCommandResponseStore struct {
+	client *redis.Client
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+// NewRedisCommandResponseStore creates a new Redis command response store, using database credentials from the secret provider when present
+func NewRedisCommandResponseStore(addr string, db int, secretProvider *secrets.SecretProvider, logger *logrus.Logger) *RedisCommandResponseStore {
+	options := &redis.Options{
+		Addr: addr,
+		DB:   db,
+	}
+
+	if secretProvider != nil {
+		username, password, err := secretProvider.GetDatabaseCredentials(common.CoreCommandServiceKey)
+		if err != nil {
+			logger.Infof("No database credentials found, connecting to Redis without authentication: %v", err)
+		} else {
+			options.Username = username
+			options.Password = password
+		}
+	}
+
+	return &RedisCommandResponseStore{
+		client: redis.NewClient(options),
+		logger: logger,
+		ctx:    context.Background(),
+	}
+}
+
+// Connect verifies the Redis connection
+func (r *RedisCommandResponseStore) Connect() error {
+	if err := r.client.Ping(r.ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that Redis is reachable; suitable as a health check
+func (r *RedisCommandResponseStore) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis unreachable: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection
+func (r *RedisCommandResponseStore) Close() error {
+	return r.client.Close()
+}
+
+// Add stores a response
+func (r *RedisCommandResponseStore) Add(response CommandResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command response %s: %w", response.Id, err)
+	}
+	if err := r.client.HSet(r.ctx, redisCommandResponsesKey, response.Id, data).Err(); err != nil {
+		return fmt.Errorf("failed to save command response %s: %w", response.Id, err)
+	}
+	return nil
+}
+
+// GetById returns the response with the given id
+func (r *RedisCommandResponseStore) GetById(id string) (CommandResponse, error) {
+	data, err := r.client.HGet(r.ctx, redisCommandResponsesKey, id).Bytes()
+	if err == redis.Nil {
+		return CommandResponse{}, ErrCommandResponseNotFound
+	}
+	if err != nil {
+		return CommandResponse{}, fmt.Errorf("failed to read command response %s: %w", id, err)
+	}
+	var response CommandResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return CommandResponse{}, fmt.Errorf("failed to unmarshal command response %s: %w", id, err)
+	}
+	return response, nil
+}
+
+// Count returns the number of stored responses
+func (r *RedisCommandResponseStore) Count() (int, error) {
+	count, err := r.client.HLen(r.ctx, redisCommandResponsesKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count command responses: %w", err)
+	}
+	return int(count), nil
+}
@@ -0,0 +1,32 @@
+//go:build integration
+
+package command
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedisCommandResponseStore_Conformance(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	runCommandResponseStoreConformance(t, func(t *testing.T) CommandResponseStore {
+		store := NewRedisCommandResponseStore(addr, 15, nil, logger)
+		if err := store.Connect(); err != nil {
+			t.Skipf("Redis not available at %s: %v", addr, err)
+		}
+		if err := store.client.FlushDB(store.ctx).Err(); err != nil {
+			t.Fatalf("failed to flush Redis test database: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
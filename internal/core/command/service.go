@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -30,16 +31,20 @@ type CommandResponse struct {
 
 // CoreCommandService handles device command execution
 type CoreCommandService struct {
-	logger           *logrus.Logger
-	commandResponses map[string]CommandResponse
-	mutex            sync.RWMutex
+	logger *logrus.Logger
+	store  CommandResponseStore
 }
 
-// NewCoreCommandService creates a new core command service
+// NewCoreCommandService creates a new core command service keeping command responses in memory
 func NewCoreCommandService(logger *logrus.Logger) *CoreCommandService {
+	return NewCoreCommandServiceWithStore(logger, NewMemoryCommandResponseStore())
+}
+
+// NewCoreCommandServiceWithStore creates a new core command service keeping command responses in the given store
+func NewCoreCommandServiceWithStore(logger *logrus.Logger, store CommandResponseStore) *CoreCommandService {
 	return &CoreCommandService{
-		logger:           logger,
-		commandResponses: make(map[string]CommandResponse),
+		logger: logger,
+		store:  store,
 	}
 }
 
@@ -60,6 +65,7 @@ func (s *CoreCommandService) AddRoutes(router *mux.Router) {
 	router.HandleFunc(common.ApiDeviceByNameCommandRoute, s.getDeviceCommands).Methods("GET")
 	router.HandleFunc(common.ApiDeviceByNameCommandRoute+"/{command}", s.issueGetCommand).Methods("GET")
 	router.HandleFunc(common.ApiDeviceByNameCommandRoute+"/{command}", s.issueSetCommand).Methods("PUT")
+	router.HandleFunc(common.ApiCommandResponseByIdRoute, s.getCommandResponse).Methods("GET")
 	
 	s.logger.Info("Core Command routes registered")
 }
@@ -140,7 +146,7 @@ func (s *CoreCommandService) issueGetCommand(w http.ResponseWriter, r *http.Requ
 			"units": "Celsius",
 		}
 	default:
-		http.Error(w, "Command not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Command not found")
 		return
 	}
 	
@@ -154,9 +160,11 @@ func (s *CoreCommandService) issueGetCommand(w http.ResponseWriter, r *http.Requ
 	}
 	
 	// Store command response
-	s.mutex.Lock()
-	s.commandResponses[responseId] = cmdResponse
-	s.mutex.Unlock()
+	if err := s.store.Add(cmdResponse); err != nil {
+		s.logger.Errorf("Failed to store response of command %s: %v", commandName, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to store command response")
+		return
+	}
 	
 	s.logger.Infof("Executed GET command %s on device %s", commandName, deviceName)
 	
@@ -196,15 +204,14 @@ func (s *CoreCommandService) issueSetCommand(w http.ResponseWriter, r *http.Requ
 	
 	// Parse command parameters from request body
 	var commandRequest map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&commandRequest); err != nil {
+	if err := common.DecodeJSON(w, r, &commandRequest); err != nil {
 		s.logger.Errorf("Failed to decode command request: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
 	// Validate command exists and supports SET
 	if commandName != "SetPoint" {
-		http.Error(w, "Command does not support SET operation", http.StatusMethodNotAllowed)
+		common.WriteError(w, http.StatusMethodNotAllowed, "Command does not support SET operation")
 		return
 	}
 	
@@ -228,9 +235,11 @@ func (s *CoreCommandService) issueSetCommand(w http.ResponseWriter, r *http.Requ
 	}
 	
 	// Store command response
-	s.mutex.Lock()
-	s.commandResponses[responseId] = cmdResponse
-	s.mutex.Unlock()
+	if err := s.store.Add(cmdResponse); err != nil {
+		s.logger.Errorf("Failed to store response of command %s: %v", commandName, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to store command response")
+		return
+	}
 	
 	s.logger.Infof("Executed SET command %s on device %s with parameters: %v", commandName, deviceName, commandRequest)
 	
@@ -241,5 +250,32 @@ func (s *CoreCommandService) issueSetCommand(w http.ResponseWriter, r *http.Requ
 		"commandId":  responseId,
 	}
 	
+	json.NewEncoder(w).Encode(response)
+}
+
+// getCommandResponse handles GET /api/v3/command/response/id/{id}
+func (s *CoreCommandService) getCommandResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	cmdResponse, err := s.store.GetById(id)
+	if errors.Is(err, ErrCommandResponseNotFound) {
+		common.WriteError(w, http.StatusNotFound, "Command response not found")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to get command response %s: %v", id, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to get command response")
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion":      common.ServiceVersion,
+		"statusCode":      http.StatusOK,
+		"commandResponse": cmdResponse,
+	}
+	
 	json.NewEncoder(w).Encode(response)
 }
\ No newline at end of file
@@ -23,8 +23,10 @@ func TestNewCoreCommandService(t *testing.T) {
 	
 	assert.NotNil(t, service)
 	assert.NotNil(t, service.logger)
-	assert.NotNil(t, service.commandResponses)
-	assert.Equal(t, 0, len(service.commandResponses))
+	assert.NotNil(t, service.store)
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
 }
 
 func TestCoreCommandService_Initialize(t *testing.T) {
@@ -240,6 +242,46 @@ func TestCoreCommandService_IssueSetCommand(t *testing.T) {
 	}
 }
 
+func TestCoreCommandService_GetCommandResponse(t *testing.T) {
+	service := NewCoreCommandService(logrus.New())
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	body, err := json.Marshal(map[string]interface{}{"value": "25.0"})
+	require.NoError(t, err)
+	req, err := http.NewRequest("PUT", "/api/v3/device/name/TestDevice/command/SetPoint", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var issued struct {
+		CommandId string `json:"commandId"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &issued))
+
+	req, err = http.NewRequest("GET", "/api/v3/command/response/id/"+issued.CommandId, nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var polled struct {
+		CommandResponse CommandResponse `json:"commandResponse"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &polled))
+	assert.Equal(t, issued.CommandId, polled.CommandResponse.Id)
+	assert.Equal(t, "TestDevice", polled.CommandResponse.DeviceName)
+	assert.Equal(t, map[string]string{"value": "25.0"}, polled.CommandResponse.Parameters)
+
+	req, err = http.NewRequest("GET", "/api/v3/command/response/id/missing", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 func TestCoreCommandService_InvalidJSON(t *testing.T) {
 	logger := logrus.New()
 	service := NewCoreCommandService(logger)
@@ -327,7 +369,9 @@ func TestCoreCommandService_ConcurrentCommandExecution(t *testing.T) {
 	wg.Wait()
 	
 	// Verify command responses were stored
-	assert.Equal(t, numGoroutines, len(service.commandResponses))
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, numGoroutines, count)
 }
 
 func TestCoreCommandService_ConcurrentSetCommands(t *testing.T) {
@@ -365,5 +409,7 @@ func TestCoreCommandService_ConcurrentSetCommands(t *testing.T) {
 	wg.Wait()
 	
 	// Verify command responses were stored
-	assert.Equal(t, numGoroutines, len(service.commandResponses))
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, numGoroutines, count)
 }
\ No newline at end of file
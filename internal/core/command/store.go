@@ -0,0 +1,56 @@
+package command
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrCommandResponseNotFound is returned by a CommandResponseStore when no response has the requested id
+var ErrCommandResponseNotFound = errors.New("command response not found")
+
+// CommandResponseStore keeps the responses of issued commands so callers can fetch them by id later,
+// such as when polling for the outcome of a command
+type CommandResponseStore interface {
+	// Add stores a response, replacing any with the same id
+	Add(response CommandResponse) error
+	GetById(id string) (CommandResponse, error)
+	Count() (int, error)
+}
+
+// MemoryCommandResponseStore implements CommandResponseStore with an in-memory map. Its responses are
+// lost on restart.
+type MemoryCommandResponseStore struct {
+	responses map[string]CommandResponse
+	mutex     sync.RWMutex
+}
+
+// NewMemoryCommandResponseStore creates an empty in-memory command response store
+func NewMemoryCommandResponseStore() *MemoryCommandResponseStore {
+	return &MemoryCommandResponseStore{responses: make(map[string]CommandResponse)}
+}
+
+// Add stores a response
+func (m *MemoryCommandResponseStore) Add(response CommandResponse) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.responses[response.Id] = response
+	return nil
+}
+
+// GetById returns the response with the given id
+func (m *MemoryCommandResponseStore) GetById(id string) (CommandResponse, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	response, exists := m.responses[id]
+	if !exists {
+		return CommandResponse{}, ErrCommandResponseNotFound
+	}
+	return response, nil
+}
+
+// Count returns the number of stored responses
+func (m *MemoryCommandResponseStore) Count() (int, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.responses), nil
+}
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommandResponseStoreConformance exercises the CommandResponseStore contract against any implementation.
+// newStore must return an empty store for every call.
+func runCommandResponseStoreConformance(t *testing.T, newStore func(t *testing.T) CommandResponseStore) {
+	t.Run("Empty", func(t *testing.T) {
+		store := newStore(t)
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Add and GetById", func(t *testing.T) {
+		store := newStore(t)
+		response := CommandResponse{
+			Id:          "response-1",
+			DeviceName:  "Thermostat-1",
+			CommandName: "SetPoint",
+			Parameters:  map[string]string{"value": "25.0"},
+			Response:    map[string]interface{}{"value": 25.0, "units": "Celsius"},
+			Timestamp:   1000,
+			StatusCode:  http.StatusOK,
+		}
+		require.NoError(t, store.Add(response))
+		require.NoError(t, store.Add(CommandResponse{Id: "response-2", CommandName: "Temperature"}))
+
+		stored, err := store.GetById("response-1")
+		require.NoError(t, err)
+		assert.Equal(t, response, stored)
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("Add replaces", func(t *testing.T) {
+		store := newStore(t)
+		require.NoError(t, store.Add(CommandResponse{Id: "response-1", StatusCode: http.StatusAccepted}))
+		require.NoError(t, store.Add(CommandResponse{Id: "response-1", StatusCode: http.StatusOK}))
+
+		stored, err := store.GetById("response-1")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, stored.StatusCode)
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("GetById missing", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.GetById("missing")
+		assert.ErrorIs(t, err, ErrCommandResponseNotFound)
+	})
+}
+
+func TestMemoryCommandResponseStore_Conformance(t *testing.T) {
+	runCommandResponseStoreConformance(t, func(t *testing.T) CommandResponseStore {
+		return NewMemoryCommandResponseStore()
+	})
+}
+
+// failingResponseStore is a CommandResponseStore whose every operation fails
+type failingResponseStore struct{}
+
+func (failingResponseStore) Add(CommandResponse) error { return errors.New("store unavailable") }
+func (failingResponseStore) GetById(string) (CommandResponse, error) {
+	return CommandResponse{}, errors.New("store unavailable")
+}
+func (failingResponseStore) Count() (int, error) { return 0, errors.New("store unavailable") }
+
+func TestCoreCommandService_StoreFailure(t *testing.T) {
+	service := NewCoreCommandServiceWithStore(logrus.New(), failingResponseStore{})
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	for _, path := range []string{"/api/v3/device/name/TestDevice/command/Temperature", "/api/v3/command/response/id/response-1"} {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code, path)
+	}
+}
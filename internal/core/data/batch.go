@@ -0,0 +1,116 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// BatchResult reports the outcome of one event of a batch. Index is the event's position in the request.
+type BatchResult struct {
+	Index  int    `json:"index"`
+	Id     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// validateEvent checks the fields an event must carry before it is stored
+func validateEvent(event models.Event) error {
+	if event.DeviceName == "" {
+		return errors.New("deviceName is required")
+	}
+	for i, reading := range event.Readings {
+		if reading.ResourceName == "" {
+			return fmt.Errorf("readings[%d]: resourceName is required", i)
+		}
+	}
+	return nil
+}
+
+// acceptEvent fills in ids and timestamps of a validated event, stores it and hands it to open
+// streams and the message bus. It is shared by the single and batch endpoints so both behave identically.
+func (s *CoreDataService) acceptEvent(event *models.Event) error {
+	// Generate ID and timestamps if not provided
+	if event.Id == "" {
+		event.Id = models.GenerateUUID()
+	}
+	if event.Created == 0 {
+		event.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	event.Modified = time.Now().UnixNano() / int64(time.Millisecond)
+
+	// Generate IDs for readings
+	for i := range event.Readings {
+		if event.Readings[i].Id == "" {
+			event.Readings[i].Id = models.GenerateUUID()
+		}
+		if event.Readings[i].Created == 0 {
+			event.Readings[i].Created = event.Created
+		}
+		event.Readings[i].Modified = event.Modified
+	}
+
+	if err := s.store.Add(*event); err != nil {
+		s.logger.Errorf("Failed to store event %s: %v", event.Id, err)
+		return err
+	}
+
+	// Push the event to any open streams and subscribers on the bus, and tell Core Metadata the device reported
+	s.broadcaster.publish(*event)
+	s.publishEvent(*event)
+	if s.lastReported != nil {
+		s.lastReported.queue(event.DeviceName)
+	}
+	return nil
+}
+
+// addEventBatch handles POST /api/v3/event/batch. Every event is validated and stored on its own,
+// so a bad event fails alone; the response is always 207 with one result per event.
+func (s *CoreDataService) addEventBatch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	var events []models.Event
+	if err := decodeRequest(r, &events); err != nil {
+		s.logger.Errorf("Failed to decode event batch: %v", err)
+		common.WriteDecodeError(w, err, "Invalid event batch payload")
+		return
+	}
+
+	results := make([]BatchResult, len(events))
+	created := 0
+	for i := range events {
+		event := &events[i]
+		results[i] = BatchResult{Index: i, Id: event.Id}
+
+		if err := validateEvent(*event); err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		if err := s.acceptEvent(event); err != nil {
+			results[i].Status = http.StatusInternalServerError
+			results[i].Error = "Failed to store event"
+			continue
+		}
+
+		results[i].Id = event.Id
+		results[i].Status = http.StatusCreated
+		created++
+	}
+
+	s.logger.Infof("Event batch processed: %d of %d events created", created, len(events))
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusMultiStatus,
+		"results":    results,
+	}
+
+	w.WriteHeader(http.StatusMultiStatus)
+	json.NewEncoder(w).Encode(response)
+}
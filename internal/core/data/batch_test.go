@@ -0,0 +1,174 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// recordingMessageClient is an in-process MessageClient that records what is published
+type recordingMessageClient struct {
+	published map[string][]interface{}
+	mutex     sync.Mutex
+}
+
+func newRecordingMessageClient() *recordingMessageClient {
+	return &recordingMessageClient{published: make(map[string][]interface{})}
+}
+
+func (c *recordingMessageClient) Connect() error    { return nil }
+func (c *recordingMessageClient) Disconnect() error { return nil }
+
+func (c *recordingMessageClient) Publish(topic string, data interface{}) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.published[topic] = append(c.published[topic], data)
+	return nil
+}
+
+func (c *recordingMessageClient) Subscribe(topic string, handler messaging.MessageHandler) error {
+	return nil
+}
+
+func (c *recordingMessageClient) Unsubscribe(topic string) error { return nil }
+
+func (c *recordingMessageClient) publishedTo(topic string) []interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]interface{}(nil), c.published[topic]...)
+}
+
+// failingStore rejects events of one device to simulate a storage failure
+type failingStore struct {
+	EventStore
+	device string
+}
+
+func (f *failingStore) Add(event models.Event) error {
+	if event.DeviceName == f.device {
+		return errors.New("disk full")
+	}
+	return f.EventStore.Add(event)
+}
+
+func postBatch(t *testing.T, service *CoreDataService, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	req := httptest.NewRequest("POST", "/api/v3/event/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCoreDataService_AddEventBatch(t *testing.T) {
+	logger := logrus.New()
+	store := &failingStore{EventStore: NewMemoryEventStore(), device: "BrokenDevice"}
+	service := NewCoreDataServiceWithStore(logger, store)
+	client := newRecordingMessageClient()
+	service.SetMessageClient(client, "")
+
+	reading := models.Reading{ResourceName: "Temperature", ValueType: "Float64", SimpleReading: models.SimpleReading{Value: "21.5"}}
+	batch := []models.Event{
+		{DeviceName: "Device1", Readings: []models.Reading{reading}},
+		{ProfileName: "NoDevice", Readings: []models.Reading{reading}},
+		{DeviceName: "Device2", Id: "chosen-id", Readings: []models.Reading{reading}},
+		{DeviceName: "Device3", Readings: []models.Reading{{SimpleReading: models.SimpleReading{Value: "1"}}}},
+		{DeviceName: "BrokenDevice", Readings: []models.Reading{reading}},
+	}
+	body, err := json.Marshal(batch)
+	require.NoError(t, err)
+
+	rr := postBatch(t, service, body)
+	require.Equal(t, http.StatusMultiStatus, rr.Code)
+
+	var response struct {
+		StatusCode int           `json:"statusCode"`
+		Results    []BatchResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+	require.Len(t, response.Results, len(batch))
+
+	expected := []struct {
+		status int
+		error  string
+	}{
+		{http.StatusCreated, ""},
+		{http.StatusBadRequest, "deviceName is required"},
+		{http.StatusCreated, ""},
+		{http.StatusBadRequest, "readings[0]: resourceName is required"},
+		{http.StatusInternalServerError, "Failed to store event"},
+	}
+	for i, want := range expected {
+		result := response.Results[i]
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, want.status, result.Status, "event %d", i)
+		assert.Equal(t, want.error, result.Error, "event %d", i)
+		if want.status == http.StatusCreated {
+			assert.NotEmpty(t, result.Id, "event %d", i)
+		}
+	}
+	assert.Equal(t, "chosen-id", response.Results[2].Id)
+
+	// Only the accepted events are stored and published
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	published := client.publishedTo(messaging.MessageTopics.Events)
+	require.Len(t, published, 2)
+	assert.Equal(t, response.Results[0].Id, published[0].(models.Event).Id)
+	assert.Equal(t, "chosen-id", published[1].(models.Event).Id)
+}
+
+func TestCoreDataService_AddEventBatchInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"Malformed JSON", `[{"deviceName":`, http.StatusBadRequest},
+		{"Not an array", `{"deviceName":"Device1"}`, http.StatusBadRequest},
+		{"Empty batch", `[]`, http.StatusMultiStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCoreDataService(logrus.New())
+			rr := postBatch(t, service, []byte(tt.body))
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestCoreDataService_AddEventPublishes(t *testing.T) {
+	service := NewCoreDataService(logrus.New())
+	client := newRecordingMessageClient()
+	service.SetMessageClient(client, "site.events")
+
+	body, err := json.Marshal(models.Event{DeviceName: "Device1"})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/api/v3/event", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(service.addEvent).ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	assert.Len(t, client.publishedTo("site.events"), 1)
+	assert.Empty(t, client.publishedTo(messaging.MessageTopics.Events))
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// includeBinaryParam is the query parameter that controls whether binary reading values are returned
+const includeBinaryParam = "includeBinary"
+
+// parseIncludeBinary reads ?includeBinary=, which defaults to true so existing clients still get the bytes
+func parseIncludeBinary(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get(includeBinaryParam)
+	if value == "" {
+		return true, nil
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("includeBinary must be true or false")
+	}
+	return include, nil
+}
+
+// eventResponse is an event returned without the bytes of its binary readings. It is only ever
+// encoded, never stored.
+type eventResponse struct {
+	models.Event
+	Readings []readingResponse `json:"readings"`
+}
+
+// readingResponse is a reading whose binaryReading carries the size of the bytes in their place
+type readingResponse struct {
+	models.Reading
+	BinaryReading binaryReadingResponse `json:"binaryReading"`
+}
+
+// binaryReadingResponse replaces models.BinaryReading in a readingResponse
+type binaryReadingResponse struct {
+	MediaType  string `json:"mediaType"`
+	BinarySize int    `json:"binarySize,omitempty"`
+}
+
+// withoutBinaryValues returns events as responses whose binary readings carry their size in place
+// of the bytes. The stored events are left untouched.
+func withoutBinaryValues(events []models.Event) []eventResponse {
+	elided := make([]eventResponse, len(events))
+	for i, event := range events {
+		elided[i] = withoutBinaryValue(event)
+	}
+	return elided
+}
+
+// withoutBinaryValue is withoutBinaryValues for a single event
+func withoutBinaryValue(event models.Event) eventResponse {
+	readings := make([]readingResponse, len(event.Readings))
+	for i, reading := range event.Readings {
+		readings[i] = readingResponse{
+			Reading: reading,
+			BinaryReading: binaryReadingResponse{
+				MediaType:  reading.BinaryReading.MediaType,
+				BinarySize: len(reading.BinaryReading.BinaryValue),
+			},
+		}
+	}
+	return eventResponse{Event: event, Readings: readings}
+}
@@ -0,0 +1,129 @@
+package data
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestCoreDataService_IncludeBinary(t *testing.T) {
+	router := newEncodingRouter()
+	event := binaryEvent(t, 1024)
+	event.Readings = append(event.Readings, models.Reading{
+		DeviceName:    "camera-1",
+		ResourceName:  "exposure",
+		ProfileName:   "camera",
+		ValueType:     common.ValueTypeFloat64,
+		SimpleReading: models.SimpleReading{Value: "0.25"},
+	})
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	rr := serve(t, router, "POST", common.ApiEventRoute, body, map[string]string{common.ContentType: common.ContentTypeJSON})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	byId := common.ApiEventRoute + "/id/" + created["id"].(string)
+	all := common.ApiEventRoute + "/all"
+	payload := event.Readings[0].BinaryReading.BinaryValue
+
+	// elidedEvent is an event as returned without its binary values
+	type elidedEvent struct {
+		DeviceName string `json:"deviceName"`
+		Readings   []struct {
+			ResourceName  string `json:"resourceName"`
+			BinaryReading struct {
+				BinaryValue []byte `json:"binaryValue"`
+				MediaType   string `json:"mediaType"`
+				BinarySize  int    `json:"binarySize"`
+			} `json:"binaryReading"`
+			SimpleReading models.SimpleReading `json:"simpleReading"`
+		} `json:"readings"`
+	}
+	get := func(path string, headers map[string]string, response interface{}) {
+		rr := serve(t, router, "GET", path, nil, headers)
+		require.Equal(t, http.StatusOK, rr.Code, path)
+		if headers[common.Accept] == common.ContentTypeCBOR {
+			require.NoError(t, cborDecMode.Unmarshal(rr.Body.Bytes(), response))
+			return
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), response))
+	}
+	getEvent := func(path string) models.Event {
+		var response struct {
+			Event models.Event `json:"event"`
+		}
+		get(path, nil, &response)
+		return response.Event
+	}
+	getEvents := func(path string) []models.Event {
+		var response struct {
+			Events []models.Event `json:"events"`
+		}
+		get(path, nil, &response)
+		require.Len(t, response.Events, 1)
+		return response.Events
+	}
+
+	// The bytes are returned by default and with the flag set
+	for _, path := range []string{byId, byId + "?includeBinary=true"} {
+		assert.Equal(t, payload, getEvent(path).Readings[0].BinaryReading.BinaryValue, path)
+	}
+	for _, path := range []string{all, all + "?includeBinary=true"} {
+		assert.Equal(t, payload, getEvents(path)[0].Readings[0].BinaryReading.BinaryValue, path)
+	}
+	rr = serve(t, router, "GET", byId, nil, nil)
+	assert.NotContains(t, rr.Body.String(), "binarySize")
+
+	// Without them, only the media type and size remain, in JSON and CBOR alike
+	var elided []elidedEvent
+	for _, headers := range []map[string]string{nil, {common.Accept: common.ContentTypeCBOR}} {
+		var one struct {
+			Event elidedEvent `json:"event"`
+		}
+		get(byId+"?includeBinary=false", headers, &one)
+		var all struct {
+			Events []elidedEvent `json:"events"`
+		}
+		get(common.ApiEventRoute+"/all?includeBinary=false", headers, &all)
+		require.Len(t, all.Events, 1)
+		elided = append(elided, one.Event, all.Events[0])
+	}
+	for _, event := range elided {
+		assert.Equal(t, "camera-1", event.DeviceName)
+		require.Len(t, event.Readings, 2)
+		binary := event.Readings[0].BinaryReading
+		assert.Nil(t, binary.BinaryValue)
+		assert.Equal(t, "image/jpeg", binary.MediaType)
+		assert.Equal(t, len(payload), binary.BinarySize)
+		assert.Equal(t, "snapshot", event.Readings[0].ResourceName)
+		assert.Zero(t, event.Readings[1].BinaryReading.BinarySize)
+		assert.Equal(t, "0.25", event.Readings[1].SimpleReading.Value)
+	}
+	rr = serve(t, router, "GET", byId+"?includeBinary=false", nil, nil)
+	assert.NotContains(t, rr.Body.String(), "binaryValue")
+
+	// A client can't store a size of its own; the field isn't part of an event
+	body = []byte(strings.Replace(string(body), `"mediaType":"image/jpeg"`, `"mediaType":"image/jpeg","binarySize":7`, 1))
+	rr = serve(t, router, "POST", common.ApiEventRoute, body, map[string]string{common.ContentType: common.ContentTypeJSON})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var added map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &added))
+	rr = serve(t, router, "GET", common.ApiEventRoute+"/id/"+added["id"].(string), nil, nil)
+	assert.NotContains(t, rr.Body.String(), "binarySize")
+
+	// Eliding a response doesn't touch the stored event
+	assert.Equal(t, payload, getEvent(byId).Readings[0].BinaryReading.BinaryValue)
+
+	for _, path := range []string{byId + "?includeBinary=maybe", all + "?includeBinary=maybe"} {
+		rr := serve(t, router, "GET", path, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, path)
+	}
+}
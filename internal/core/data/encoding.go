@@ -0,0 +1,80 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// cborDecMode decodes nested maps (tags, object readings) as map[string]interface{} so they
+// re-encode as JSON, rather than the library default of map[interface{}]interface{}
+var cborDecMode = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// requestIsCBOR reports whether the request body is declared as CBOR
+func requestIsCBOR(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(common.ContentType))
+	return err == nil && mediaType == common.ContentTypeCBOR
+}
+
+// acceptsCBOR reports whether the client prefers CBOR, i.e. lists application/cbor in Accept
+// ahead of application/json. JSON remains the default.
+func acceptsCBOR(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get(common.Accept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case common.ContentTypeCBOR:
+			return true
+		case common.ContentTypeJSON:
+			return false
+		}
+	}
+	return false
+}
+
+// decodeRequest decodes the request body as CBOR or JSON according to its Content-Type
+func decodeRequest(r *http.Request, v interface{}) error {
+	if requestIsCBOR(r) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		return cborDecMode.Unmarshal(body, v)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// respond writes payload with a 200 status, encoded as CBOR when the client asks for it and JSON otherwise
+func respond(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	if !acceptsCBOR(r) {
+		w.Header().Set(common.ContentType, common.ContentTypeJSON)
+		json.NewEncoder(w).Encode(payload)
+		return
+	}
+
+	body, err := cbor.Marshal(payload)
+	if err != nil {
+		common.WriteError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to encode response: %v", err))
+		return
+	}
+	w.Header().Set(common.ContentType, common.ContentTypeCBOR)
+	w.Write(body)
+}
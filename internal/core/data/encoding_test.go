@@ -0,0 +1,155 @@
+package data
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func newEncodingRouter() *mux.Router {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	router := mux.NewRouter()
+	NewCoreDataService(logger).AddRoutes(router)
+	return router
+}
+
+// binaryEvent returns an event carrying a single binary reading of the given size
+func binaryEvent(t *testing.T, size int) models.Event {
+	payload := make([]byte, size)
+	_, err := rand.Read(payload)
+	require.NoError(t, err)
+
+	return models.Event{
+		DeviceName:  "camera-1",
+		ProfileName: "camera",
+		SourceName:  "snapshot",
+		Tags:        map[string]interface{}{"site": "plant-a"},
+		Readings: []models.Reading{{
+			DeviceName:    "camera-1",
+			ResourceName:  "snapshot",
+			ProfileName:   "camera",
+			ValueType:     common.ValueTypeBinary,
+			BinaryReading: models.BinaryReading{BinaryValue: payload, MediaType: "image/jpeg"},
+		}},
+	}
+}
+
+// serve sends a request with the given headers and returns the recorder
+func serve(t *testing.T, router *mux.Router, method, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+	require.NoError(t, err)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCoreDataService_CBORRoundTrip(t *testing.T) {
+	router := newEncodingRouter()
+	event := binaryEvent(t, 4096)
+
+	body, err := cbor.Marshal(event)
+	require.NoError(t, err)
+	rr := serve(t, router, "POST", common.ApiEventRoute, body, map[string]string{common.ContentType: common.ContentTypeCBOR})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	path := common.ApiEventRoute + "/id/" + created["id"].(string)
+
+	// CBOR response
+	cborResponse := serve(t, router, "GET", path, nil, map[string]string{common.Accept: common.ContentTypeCBOR})
+	require.Equal(t, http.StatusOK, cborResponse.Code)
+	assert.Equal(t, common.ContentTypeCBOR, cborResponse.Header().Get(common.ContentType))
+
+	var decoded struct {
+		Event models.Event `json:"event"`
+	}
+	require.NoError(t, cborDecMode.Unmarshal(cborResponse.Body.Bytes(), &decoded))
+	require.Len(t, decoded.Event.Readings, 1)
+	assert.Equal(t, event.Readings[0].BinaryReading.BinaryValue, decoded.Event.Readings[0].BinaryReading.BinaryValue)
+	assert.Equal(t, "image/jpeg", decoded.Event.Readings[0].BinaryReading.MediaType)
+	assert.Equal(t, "plant-a", decoded.Event.Tags["site"])
+
+	// JSON stays the default and carries the same event
+	jsonResponse := serve(t, router, "GET", path, nil, nil)
+	require.Equal(t, http.StatusOK, jsonResponse.Code)
+	assert.Equal(t, common.ContentTypeJSON, jsonResponse.Header().Get(common.ContentType))
+
+	var fromJSON struct {
+		Event models.Event `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(jsonResponse.Body.Bytes(), &fromJSON))
+	assert.Equal(t, decoded.Event, fromJSON.Event)
+
+	// Binary data isn't base64-inflated in CBOR
+	assert.Less(t, cborResponse.Body.Len(), jsonResponse.Body.Len())
+}
+
+func TestCoreDataService_GetAllEventsCBOR(t *testing.T) {
+	router := newEncodingRouter()
+	for i := 0; i < 3; i++ {
+		body, err := json.Marshal(binaryEvent(t, 256))
+		require.NoError(t, err)
+		rr := serve(t, router, "POST", common.ApiEventRoute, body, map[string]string{common.ContentType: common.ContentTypeJSON})
+		require.Equal(t, http.StatusCreated, rr.Code)
+	}
+
+	rr := serve(t, router, "GET", common.ApiEventRoute+"/all", nil, map[string]string{common.Accept: "application/cbor, application/json;q=0.5"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, common.ContentTypeCBOR, rr.Header().Get(common.ContentType))
+
+	var response struct {
+		TotalCount int            `json:"totalCount"`
+		Events     []models.Event `json:"events"`
+	}
+	require.NoError(t, cborDecMode.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.TotalCount)
+	assert.Len(t, response.Events, 3)
+}
+
+func TestCoreDataService_AddEventInvalidCBOR(t *testing.T) {
+	router := newEncodingRouter()
+
+	rr := serve(t, router, "POST", common.ApiEventRoute, []byte{0xff, 0x00}, map[string]string{common.ContentType: common.ContentTypeCBOR})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAcceptsCBOR(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/cbor", true},
+		{"application/cbor; q=0.9", true},
+		{"application/json, application/cbor", false},
+		{"text/html, application/cbor, application/json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(common.Accept, tt.accept)
+			assert.Equal(t, tt.expected, acceptsCBOR(req))
+		})
+	}
+}
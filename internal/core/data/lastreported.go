@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/clients/httpclient"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// DefaultLastReportedTimeout bounds each attempt to tell Core Metadata a device reported
+const DefaultLastReportedTimeout = 5 * time.Second
+
+// lastReportedReporter tells Core Metadata which devices have sent events. Devices are queued by name,
+// so a burst of events from one device costs one request.
+type lastReportedReporter struct {
+	baseURL string
+	client  *httpclient.Client
+
+	mutex   sync.Mutex
+	pending map[string]struct{}
+	wake    chan struct{}
+}
+
+// SetMetadataURL makes the service report each device whose event it stores to Core Metadata at
+// baseURL, e.g. http://localhost:59881, keeping the device's lastReported current. An empty URL
+// turns reporting off. Must be called before Initialize.
+func (s *CoreDataService) SetMetadataURL(baseURL string) {
+	if baseURL == "" {
+		s.lastReported = nil
+		return
+	}
+	config := httpclient.DefaultConfig()
+	config.Timeout = DefaultLastReportedTimeout
+	s.lastReported = &lastReportedReporter{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  httpclient.NewClient(config),
+		pending: make(map[string]struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// queue marks deviceName as having reported; it never blocks the event being stored
+func (r *lastReportedReporter) queue(deviceName string) {
+	r.mutex.Lock()
+	r.pending[deviceName] = struct{}{}
+	r.mutex.Unlock()
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runLastReported sends the queued reports until ctx is done
+func (s *CoreDataService) runLastReported(ctx context.Context) {
+	r := s.lastReported
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		}
+
+		r.mutex.Lock()
+		names := r.pending
+		r.pending = make(map[string]struct{})
+		r.mutex.Unlock()
+
+		for name := range names {
+			s.reportLastReported(ctx, name)
+		}
+	}
+}
+
+// reportLastReported sends PUT /api/v3/device/name/{name}/lastreported to Core Metadata. The event is
+// already stored, so a failure is logged rather than reported to the client.
+func (s *CoreDataService) reportLastReported(ctx context.Context, deviceName string) {
+	r := s.lastReported
+	path := strings.Replace(common.ApiDeviceLastReportedRoute, "{name}", url.PathEscape(deviceName), 1)
+	req, err := http.NewRequest(http.MethodPut, r.baseURL+path, nil)
+	if err != nil {
+		s.logger.Warnf("Failed to report device %s to Core Metadata: %v", deviceName, err)
+		return
+	}
+
+	resp, err := r.client.Do(ctx, req)
+	if err != nil {
+		var clientErr *httpclient.Error
+		if errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound {
+			s.logger.Debugf("Device %s is not known to Core Metadata", deviceName)
+			return
+		}
+		s.logger.Warnf("Failed to report device %s to Core Metadata: %v", deviceName, err)
+		return
+	}
+	resp.Body.Close()
+}
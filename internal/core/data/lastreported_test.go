@@ -0,0 +1,129 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestCoreDataService_ReportsLastReported(t *testing.T) {
+	var mutex sync.Mutex
+	var reported []string
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		reported = append(reported, r.Method+" "+r.URL.EscapedPath())
+		mutex.Unlock()
+		if r.URL.Path != "/api/v3/device/name/Thermostat 1/lastreported" {
+			common.WriteError(w, http.StatusNotFound, "Device not found")
+		}
+	}))
+	defer metadata.Close()
+	reports := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), reported...)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewCoreDataService(logger)
+	service.SetMetadataURL(metadata.URL + "/")
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	addEvent := func(deviceName string) {
+		event := models.NewEvent("thermostat", deviceName, "temperature")
+		event.Readings = []models.Reading{{DeviceName: deviceName, ResourceName: "temperature", ProfileName: "thermostat",
+			ValueType: common.ValueTypeFloat64, SimpleReading: models.SimpleReading{Value: "21.5"}}}
+		body, err := json.Marshal(event)
+		require.NoError(t, err)
+		rr := serve(t, router, "POST", common.ApiEventRoute, body, map[string]string{common.ContentType: common.ContentTypeJSON})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	// Storing an event reports its device, with the name escaped in the path
+	addEvent("Thermostat 1")
+	require.Eventually(t, func() bool { return len(reports()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "PUT /api/v3/device/name/Thermostat%201/lastreported", reports()[0])
+
+	// A device Core Metadata doesn't know is skipped without retrying or holding up others
+	addEvent("unknown")
+	require.Eventually(t, func() bool { return len(reports()) == 2 }, time.Second, 10*time.Millisecond)
+	addEvent("Thermostat 1")
+	require.Eventually(t, func() bool { return len(reports()) == 3 }, time.Second, 10*time.Millisecond)
+}
+
+func TestCoreDataService_LastReportedCoalesced(t *testing.T) {
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	calls := 0
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		calls++
+		first := calls == 1
+		mutex.Unlock()
+		if first {
+			<-release
+		}
+	}))
+	defer metadata.Close()
+	callCount := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return calls
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	service := NewCoreDataService(logger)
+	service.SetMetadataURL(metadata.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// While the first report is in flight, a burst from one device queues a single further report
+	service.lastReported.queue("sensor-1")
+	require.Eventually(t, func() bool { return callCount() == 1 }, time.Second, 10*time.Millisecond)
+	for i := 0; i < 50; i++ {
+		service.lastReported.queue("sensor-1")
+	}
+	close(release)
+
+	require.Eventually(t, func() bool { return callCount() == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, callCount())
+}
+
+func TestCoreDataService_LastReportedOff(t *testing.T) {
+	service := NewCoreDataService(logrus.New())
+	assert.Nil(t, service.lastReported)
+
+	service.SetMetadataURL("http://localhost:59881")
+	assert.NotNil(t, service.lastReported)
+	service.SetMetadataURL("")
+	assert.Nil(t, service.lastReported)
+}
@@ -0,0 +1,27 @@
+package data
+
+import (
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// SetMessageClient makes the service publish every stored event to topic on the message bus.
+// A nil client keeps events local to Core Data. Must be called before Initialize.
+func (s *CoreDataService) SetMessageClient(client messaging.MessageClient, topic string) {
+	if topic == "" {
+		topic = messaging.MessageTopics.Events
+	}
+	s.messageClient = client
+	s.topic = topic
+}
+
+// publishEvent sends a stored event to the message bus. The event is already persisted, so a
+// failure is logged rather than reported to the client.
+func (s *CoreDataService) publishEvent(event models.Event) {
+	if s.messageClient == nil {
+		return
+	}
+	if err := s.messageClient.Publish(s.topic, event); err != nil {
+		s.logger.Warnf("Failed to publish event %s to topic %s: %v", event.Id, s.topic, err)
+	}
+}
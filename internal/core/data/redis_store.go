@@ -0,0 +1,320 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// Redis key layout for events
+const (
+	redisEventKeyPrefix       = "edgex:core-data:event:"
+	redisEventsByCreatedKey   = "edgex:core-data:events:created"
+	redisEventsByDevicePrefix = "edgex:core-data:events:device:"
+	// Set of the ids of events holding at least one reading of a resource
+	redisEventsByResourcePrefix = "edgex:core-data:events:resource:"
+)
+
+// RedisEventStore implements EventStore using Redis. Each event is a hash keyed by id,
+// indexed by a sorted set on Created, a per-device sorted set and a per-resource set.
+type RedisEventStore struct {
+	client *redis.Client
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+// NewRedisEventStore creates a new Redis event store, using database credentials from the secret provider when present
+func NewRedisEventStore(addr string, db int, secretProvider *secrets.SecretProvider, logger *logrus.Logger) *RedisEventStore {
+	options := &redis.Options{
+		Addr: addr,
+		DB:   db,
+	}
+
+	if secretProvider != nil {
+		username, password, err := secretProvider.GetDatabaseCredentials(common.CoreDataServiceKey)
+		if err != nil {
+			logger.Infof("No database credentials found, connecting to Redis without authentication: %v", err)
+		} else {
+			options.Username = username
+			options.Password = password
+		}
+	}
+
+	return &RedisEventStore{
+		client: redis.NewClient(options),
+		logger: logger,
+		ctx:    context.Background(),
+	}
+}
+
+// Connect verifies the Redis connection
+func (r *RedisEventStore) Connect() error {
+	if err := r.client.Ping(r.ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that Redis is reachable; suitable as a health check
+func (r *RedisEventStore) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis unreachable: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection
+func (r *RedisEventStore) Close() error {
+	return r.client.Close()
+}
+
+// Add stores an event, replacing any existing event with the same id
+func (r *RedisEventStore) Add(event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	key := redisEventKeyPrefix + event.Id
+
+	// A replaced event may have moved device or changed resources, so drop it from the old indexes
+	previous, err := r.client.HMGet(r.ctx, key, "deviceName", "resourceNames").Result()
+	if err != nil {
+		return fmt.Errorf("failed to read event %s: %w", event.Id, err)
+	}
+	previousDevice, _ := previous[0].(string)
+	previousResources, _ := previous[1].(string)
+
+	resourceNames := eventResourceNames(event)
+	_, err = r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		if previousDevice != "" && previousDevice != event.DeviceName {
+			pipe.ZRem(r.ctx, redisEventsByDevicePrefix+previousDevice, event.Id)
+		}
+		for _, resourceName := range splitResourceNames(previousResources) {
+			pipe.SRem(r.ctx, redisEventsByResourcePrefix+resourceName, event.Id)
+		}
+		pipe.HSet(r.ctx, key,
+			"data", string(data),
+			"deviceName", event.DeviceName,
+			"created", strconv.FormatInt(event.Created, 10),
+			"resourceNames", strings.Join(resourceNames, "\n"),
+		)
+		member := &redis.Z{Score: float64(event.Created), Member: event.Id}
+		pipe.ZAdd(r.ctx, redisEventsByCreatedKey, member)
+		pipe.ZAdd(r.ctx, redisEventsByDevicePrefix+event.DeviceName, member)
+		for _, resourceName := range resourceNames {
+			pipe.SAdd(r.ctx, redisEventsByResourcePrefix+resourceName, event.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store event %s: %w", event.Id, err)
+	}
+	return nil
+}
+
+// GetById retrieves a single event
+func (r *RedisEventStore) GetById(id string) (models.Event, error) {
+	data, err := r.client.HGet(r.ctx, redisEventKeyPrefix+id, "data").Result()
+	if err == redis.Nil {
+		return models.Event{}, ErrEventNotFound
+	}
+	if err != nil {
+		return models.Event{}, fmt.Errorf("failed to read event %s: %w", id, err)
+	}
+
+	var event models.Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return models.Event{}, fmt.Errorf("failed to unmarshal event %s: %w", id, err)
+	}
+	return event, nil
+}
+
+// All returns a page of events ordered newest first
+func (r *RedisEventStore) All(offset, limit int) ([]models.Event, error) {
+	return r.rangeByScore(redisEventsByCreatedKey, "-inf", "+inf", offset, limit)
+}
+
+// DeleteById removes a single event and its index entries
+func (r *RedisEventStore) DeleteById(id string) error {
+	key := redisEventKeyPrefix + id
+
+	fields, err := r.client.HMGet(r.ctx, key, "deviceName", "resourceNames").Result()
+	if err != nil {
+		return fmt.Errorf("failed to read event %s: %w", id, err)
+	}
+	deviceName, exists := fields[0].(string)
+	if !exists {
+		return ErrEventNotFound
+	}
+	resourceNames, _ := fields[1].(string)
+
+	_, err = r.client.TxPipelined(r.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(r.ctx, key)
+		pipe.ZRem(r.ctx, redisEventsByCreatedKey, id)
+		pipe.ZRem(r.ctx, redisEventsByDevicePrefix+deviceName, id)
+		for _, resourceName := range splitResourceNames(resourceNames) {
+			pipe.SRem(r.ctx, redisEventsByResourcePrefix+resourceName, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete event %s: %w", id, err)
+	}
+	return nil
+}
+
+// ByDeviceName returns all events originating from the given device, newest first
+func (r *RedisEventStore) ByDeviceName(deviceName string) ([]models.Event, error) {
+	return r.rangeByScore(redisEventsByDevicePrefix+deviceName, "-inf", "+inf", 0, -1)
+}
+
+// ByTimeRange returns a page of events created within [start, end], newest first, and the number of matches
+func (r *RedisEventStore) ByTimeRange(start, end int64, offset, limit int) ([]models.Event, int, error) {
+	min, max := strconv.FormatInt(start, 10), strconv.FormatInt(end, 10)
+	total, err := r.client.ZCount(r.ctx, redisEventsByCreatedKey, min, max).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	events, err := r.rangeByScore(redisEventsByCreatedKey, min, max, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, int(total), nil
+}
+
+// Count returns the number of stored events
+func (r *RedisEventStore) Count() (int, error) {
+	count, err := r.client.ZCard(r.ctx, redisEventsByCreatedKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	return int(count), nil
+}
+
+// ReadingsByResourceName returns a page of the readings with the given resource name, newest first.
+// Readings are ordered by Origin rather than by their event's Created, so the matching events are
+// loaded in full and paginated here.
+func (r *RedisEventStore) ReadingsByResourceName(resourceName string, offset, limit int) ([]models.Reading, int, error) {
+	ids, err := r.client.SMembers(r.ctx, redisEventsByResourcePrefix+resourceName).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query index for resource %s: %w", resourceName, err)
+	}
+	events, err := r.loadEvents(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	readings, total := readingsByResourceName(events, resourceName, offset, limit)
+	return readings, total, nil
+}
+
+// ReadingStats summarizes the numeric readings of a resource, loading the events that hold any
+func (r *RedisEventStore) ReadingStats(resourceName string, start, end int64) (ReadingStats, error) {
+	ids, err := r.client.SMembers(r.ctx, redisEventsByResourcePrefix+resourceName).Result()
+	if err != nil {
+		return ReadingStats{}, fmt.Errorf("failed to query index for resource %s: %w", resourceName, err)
+	}
+	events, err := r.loadEvents(ids)
+	if err != nil {
+		return ReadingStats{}, err
+	}
+
+	accumulator := newStatsAccumulator(resourceName, start, end)
+	for _, event := range events {
+		accumulator.add(event)
+	}
+	return accumulator.stats(), nil
+}
+
+// eventResourceNames returns the distinct resource names of an event's readings, sorted
+func eventResourceNames(event models.Event) []string {
+	seen := make(map[string]bool, len(event.Readings))
+	names := []string{}
+	for _, reading := range event.Readings {
+		if !seen[reading.ResourceName] {
+			seen[reading.ResourceName] = true
+			names = append(names, reading.ResourceName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitResourceNames parses the resourceNames field of an event hash
+func splitResourceNames(field string) []string {
+	if field == "" {
+		return nil
+	}
+	return strings.Split(field, "\n")
+}
+
+// rangeByScore loads the events referenced by a sorted-set index between min and max, newest first.
+// A negative limit returns every match.
+func (r *RedisEventStore) rangeByScore(indexKey, min, max string, offset, limit int) ([]models.Event, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit == 0 {
+		return []models.Event{}, nil
+	}
+
+	ids, err := r.client.ZRevRangeByScore(r.ctx, indexKey, &redis.ZRangeBy{
+		Min:    min,
+		Max:    max,
+		Offset: int64(offset),
+		Count:  int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query index %s: %w", indexKey, err)
+	}
+
+	return r.loadEvents(ids)
+}
+
+// loadEvents fetches the given events in a single round trip, preserving order
+func (r *RedisEventStore) loadEvents(ids []string) ([]models.Event, error) {
+	events := make([]models.Event, 0, len(ids))
+	if len(ids) == 0 {
+		return events, nil
+	}
+
+	pipe := r.client.Pipeline()
+	commands := make([]*redis.StringCmd, len(ids))
+	for i, id := range ids {
+		commands[i] = pipe.HGet(r.ctx, redisEventKeyPrefix+id, "data")
+	}
+	if _, err := pipe.Exec(r.ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	for i, cmd := range commands {
+		data, err := cmd.Result()
+		if err == redis.Nil {
+			// Index entry outlived its hash; skip it rather than fail the whole page
+			r.logger.Warnf("Event %s is indexed but missing from Redis", ids[i])
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load event %s: %w", ids[i], err)
+		}
+
+		var event models.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %s: %w", ids[i], err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"strconv"
 	"sync"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -14,20 +13,34 @@ import (
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/metrics"
 )
 
 // CoreDataService handles event and reading management
 type CoreDataService struct {
-	logger *logrus.Logger
-	events map[string]models.Event
-	mutex  sync.RWMutex
+	logger      *logrus.Logger
+	store       EventStore
+	broadcaster *eventBroadcaster
+	ctx         context.Context
+	
+	messageClient messaging.MessageClient
+	topic         string
+	lastReported  *lastReportedReporter
 }
 
-// NewCoreDataService creates a new core data service
+// NewCoreDataService creates a new core data service backed by an in-memory store of DefaultMaxEvents events
 func NewCoreDataService(logger *logrus.Logger) *CoreDataService {
+	return NewCoreDataServiceWithStore(logger, NewMemoryEventStoreWithCapacity(DefaultMaxEvents, logger))
+}
+
+// NewCoreDataServiceWithStore creates a new core data service backed by the given store
+func NewCoreDataServiceWithStore(logger *logrus.Logger, store EventStore) *CoreDataService {
 	return &CoreDataService{
-		logger: logger,
-		events: make(map[string]models.Event),
+		logger:      logger,
+		store:       store,
+		broadcaster: newEventBroadcaster(logger),
+		ctx:         context.Background(),
 	}
 }
 
@@ -38,6 +51,50 @@ func (s *CoreDataService) Initialize(ctx context.Context, wg *sync.WaitGroup, di
 	// Add service to DI container
 	dic.Add("CoreDataService", s)
 	
+	// Open event streams close when the service shuts down
+	s.ctx = ctx
+	
+	// Expose the stored event count as a domain gauge
+	err := metrics.RegisterGaugeFunc("core_data_events", "Number of events stored by Core Data.", func() float64 {
+		count, err := s.store.Count()
+		if err != nil {
+			return 0
+		}
+		return float64(count)
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to register event count metric: %v", err)
+	}
+	
+	// Count events dropped to keep an in-memory store within capacity
+	if store, ok := s.store.(*MemoryEventStore); ok {
+		err = metrics.RegisterCounterFunc("core_data_events_evicted_total", "Number of events evicted from the Core Data in-memory store to stay within capacity.", func() float64 {
+			return float64(store.Evictions())
+		})
+		if err != nil {
+			s.logger.Warnf("Failed to register event eviction metric: %v", err)
+		}
+	}
+	
+	// Keep Core Metadata's lastReported current for the devices sending events
+	if s.lastReported != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runLastReported(ctx)
+		}()
+	}
+	
+	// Hold off readiness while the event store or message bus is unreachable
+	if registry, err := bootstrap.GetHealthRegistry(dic); err == nil {
+		if store, ok := s.store.(bootstrap.HealthChecker); ok {
+			registry.Register("database", store.Ping, true)
+		}
+		if client, ok := s.messageClient.(bootstrap.HealthChecker); ok {
+			registry.Register("messagebus", client.Ping, true)
+		}
+	}
+	
 	s.logger.Info("Core Data Service initialization completed")
 	return true
 }
@@ -46,10 +103,29 @@ func (s *CoreDataService) Initialize(ctx context.Context, wg *sync.WaitGroup, di
 func (s *CoreDataService) AddRoutes(router *mux.Router) {
 	// Event routes
 	router.HandleFunc(common.ApiEventRoute, s.addEvent).Methods("POST")
+	router.HandleFunc(common.ApiEventBatchRoute, s.addEventBatch).Methods("POST")
 	router.HandleFunc(common.ApiEventRoute+"/all", s.getAllEvents).Methods("GET")
 	router.HandleFunc(common.ApiEventByIdRoute, s.getEventById).Methods("GET")
 	router.HandleFunc(common.ApiEventByIdRoute, s.deleteEventById).Methods("DELETE")
 	router.HandleFunc(common.ApiEventByDeviceNameRoute, s.getEventsByDeviceName).Methods("GET")
+	router.HandleFunc(common.ApiEventByTimeRangeRoute, s.getEventsByTimeRange).Methods("GET")
+	router.HandleFunc(common.ApiEventStreamRoute, s.streamEvents).Methods("GET")
+	
+	// Reading routes
+	router.HandleFunc(common.ApiReadingByResourceNameRoute, s.getReadingsByResourceName).Methods("GET")
+	router.HandleFunc(common.ApiReadingStatsByResourceNameRoute, s.getReadingStats).Methods("GET")
+	
+	// Summaries for the OpenAPI description
+	bootstrap.DescribeOperation("POST", common.ApiEventRoute, "Add an event")
+	bootstrap.DescribeOperation("POST", common.ApiEventBatchRoute, "Add a batch of events, reporting the outcome of each")
+	bootstrap.DescribeOperation("GET", common.ApiEventRoute+"/all", "List events")
+	bootstrap.DescribeOperation("GET", common.ApiEventByIdRoute, "Get an event by id")
+	bootstrap.DescribeOperation("DELETE", common.ApiEventByIdRoute, "Delete an event by id")
+	bootstrap.DescribeOperation("GET", common.ApiEventByDeviceNameRoute, "List events of a device")
+	bootstrap.DescribeOperation("GET", common.ApiEventByTimeRangeRoute, "List events created between two timestamps")
+	bootstrap.DescribeOperation("GET", common.ApiEventStreamRoute, "Stream new events as server-sent events")
+	bootstrap.DescribeOperation("GET", common.ApiReadingByResourceNameRoute, "List readings of a resource across all events")
+	bootstrap.DescribeOperation("GET", common.ApiReadingStatsByResourceNameRoute, "Summarize the numeric readings of a resource")
 	
 	s.logger.Info("Core Data routes registered")
 }
@@ -59,37 +135,22 @@ func (s *CoreDataService) addEvent(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var event models.Event
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := decodeRequest(r, &event); err != nil {
 		s.logger.Errorf("Failed to decode event: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		common.WriteDecodeError(w, err, "Invalid event payload")
 		return
 	}
 	
-	// Generate ID and timestamps if not provided
-	if event.Id == "" {
-		event.Id = models.GenerateUUID()
-	}
-	if event.Created == 0 {
-		event.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	if err := validateEvent(event); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	event.Modified = time.Now().UnixNano() / int64(time.Millisecond)
 	
-	// Generate IDs for readings
-	for i := range event.Readings {
-		if event.Readings[i].Id == "" {
-			event.Readings[i].Id = models.GenerateUUID()
-		}
-		if event.Readings[i].Created == 0 {
-			event.Readings[i].Created = event.Created
-		}
-		event.Readings[i].Modified = event.Modified
+	if err := s.acceptEvent(&event); err != nil {
+		common.WriteError(w, http.StatusInternalServerError, "Failed to store event")
+		return
 	}
 	
-	// Store event
-	s.mutex.Lock()
-	s.events[event.Id] = event
-	s.mutex.Unlock()
-	
 	s.logger.Infof("Event created with ID: %s", event.Id)
 	
 	response := map[string]interface{}{
@@ -106,55 +167,43 @@ func (s *CoreDataService) addEvent(w http.ResponseWriter, r *http.Request) {
 func (s *CoreDataService) getAllEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
-	// Parse query parameters
-	offsetStr := r.URL.Query().Get("offset")
-	limitStr := r.URL.Query().Get("limit")
-	
-	offset := 0
-	limit := 20
-	
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l <= 1000 {
-			limit = l
-		}
+	includeBinary, err := parseIncludeBinary(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 	
-	s.mutex.RLock()
-	events := make([]models.Event, 0, len(s.events))
-	for _, event := range s.events {
-		events = append(events, event)
+	totalCount, err := s.store.Count()
+	if err != nil {
+		s.logger.Errorf("Failed to count events: %v", err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve events")
+		return
 	}
-	s.mutex.RUnlock()
 	
-	totalCount := len(events)
-	
-	// Apply pagination
-	start := offset
-	if start >= len(events) {
-		start = len(events)
+	paginatedEvents, err := s.store.All(offset, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to retrieve events: %v", err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve events")
+		return
 	}
-	
-	end := start + limit
-	if end > len(events) {
-		end = len(events)
+	var events interface{} = paginatedEvents
+	if !includeBinary {
+		events = withoutBinaryValues(paginatedEvents)
 	}
 	
-	paginatedEvents := events[start:end]
-	
 	response := map[string]interface{}{
 		"apiVersion":  common.ServiceVersion,
 		"statusCode":  http.StatusOK,
 		"totalCount":  totalCount,
-		"events":      paginatedEvents,
+		"events":      events,
 	}
 	
-	json.NewEncoder(w).Encode(response)
+	respond(w, r, response)
 }
 
 // getEventById handles GET /api/v3/event/id/{id}
@@ -164,22 +213,34 @@ func (s *CoreDataService) getEventById(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 	
-	s.mutex.RLock()
-	event, exists := s.events[id]
-	s.mutex.RUnlock()
+	includeBinary, err := parseIncludeBinary(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	
-	if !exists {
-		http.Error(w, "Event not found", http.StatusNotFound)
+	event, err := s.store.GetById(id)
+	if err == ErrEventNotFound {
+		common.WriteError(w, http.StatusNotFound, "Event not found")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to retrieve event %s: %v", id, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve event")
 		return
 	}
+	var body interface{} = event
+	if !includeBinary {
+		body = withoutBinaryValue(event)
+	}
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
-		"event":      event,
+		"event":      body,
 	}
 	
-	json.NewEncoder(w).Encode(response)
+	respond(w, r, response)
 }
 
 // deleteEventById handles DELETE /api/v3/event/id/{id}
@@ -189,15 +250,14 @@ func (s *CoreDataService) deleteEventById(w http.ResponseWriter, r *http.Request
 	vars := mux.Vars(r)
 	id := vars["id"]
 	
-	s.mutex.Lock()
-	_, exists := s.events[id]
-	if exists {
-		delete(s.events, id)
+	err := s.store.DeleteById(id)
+	if err == ErrEventNotFound {
+		common.WriteError(w, http.StatusNotFound, "Event not found")
+		return
 	}
-	s.mutex.Unlock()
-	
-	if !exists {
-		http.Error(w, "Event not found", http.StatusNotFound)
+	if err != nil {
+		s.logger.Errorf("Failed to delete event %s: %v", id, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to delete event")
 		return
 	}
 	
@@ -219,21 +279,138 @@ func (s *CoreDataService) getEventsByDeviceName(w http.ResponseWriter, r *http.R
 	vars := mux.Vars(r)
 	deviceName := vars["name"]
 	
-	s.mutex.RLock()
-	var deviceEvents []models.Event
-	for _, event := range s.events {
-		if event.DeviceName == deviceName {
-			deviceEvents = append(deviceEvents, event)
-		}
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
-	s.mutex.RUnlock()
+	
+	deviceEvents, err := s.store.ByDeviceName(deviceName)
+	if err != nil {
+		s.logger.Errorf("Failed to retrieve events for device %s: %v", deviceName, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve events")
+		return
+	}
+	events, totalCount := common.Paginate(deviceEvents, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":  common.ServiceVersion,
 		"statusCode":  http.StatusOK,
-		"totalCount":  len(deviceEvents),
-		"events":      deviceEvents,
+		"totalCount":  totalCount,
+		"events":      events,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+// getEventsByTimeRange handles GET /api/v3/event/start/{start}/end/{end}
+func (s *CoreDataService) getEventsByTimeRange(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	start, err := strconv.ParseInt(vars["start"], 10, 64)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "Invalid start timestamp")
+		return
+	}
+	end, err := strconv.ParseInt(vars["end"], 10, 64)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "Invalid end timestamp")
+		return
+	}
+	if end < start {
+		common.WriteError(w, http.StatusBadRequest, "End timestamp must not be before start timestamp")
+		return
+	}
+	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	events, totalCount, err := s.store.ByTimeRange(start, end, offset, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to retrieve events between %d and %d: %v", start, end, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve events")
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"totalCount": totalCount,
+		"events":     events,
 	}
 	
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// getReadingsByResourceName handles GET /api/v3/reading/resourceName/{name}
+func (s *CoreDataService) getReadingsByResourceName(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	resourceName := mux.Vars(r)["name"]
+	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	readings, totalCount, err := s.store.ReadingsByResourceName(resourceName, offset, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to retrieve readings for resource %s: %v", resourceName, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to retrieve readings")
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"totalCount": totalCount,
+		"readings":   readings,
+	}
+	
+	respond(w, r, response)
+}
+
+// getReadingStats handles GET /api/v3/reading/resourceName/{name}/stats?start=&end=
+func (s *CoreDataService) getReadingStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	resourceName := mux.Vars(r)["name"]
+	
+	var start, end int64
+	var err error
+	if value := r.URL.Query().Get("start"); value != "" {
+		if start, err = strconv.ParseInt(value, 10, 64); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "Invalid start timestamp")
+			return
+		}
+	}
+	if value := r.URL.Query().Get("end"); value != "" {
+		if end, err = strconv.ParseInt(value, 10, 64); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "Invalid end timestamp")
+			return
+		}
+	}
+	if end != 0 && end < start {
+		common.WriteError(w, http.StatusBadRequest, "End timestamp must not be before start timestamp")
+		return
+	}
+	
+	stats, err := s.store.ReadingStats(resourceName, start, end)
+	if err != nil {
+		s.logger.Errorf("Failed to compute statistics for resource %s: %v", resourceName, err)
+		common.WriteError(w, http.StatusInternalServerError, "Failed to compute reading statistics")
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"stats":      stats,
+	}
+	
+	respond(w, r, response)
+}
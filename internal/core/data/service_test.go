@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -16,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
 )
 
@@ -25,8 +28,10 @@ func TestNewCoreDataService(t *testing.T) {
 	
 	assert.NotNil(t, service)
 	assert.NotNil(t, service.logger)
-	assert.NotNil(t, service.events)
-	assert.Equal(t, 0, len(service.events))
+	assert.NotNil(t, service.store)
+	count, err := service.store.Count()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
 }
 
 func TestCoreDataService_Initialize(t *testing.T) {
@@ -41,6 +46,39 @@ func TestCoreDataService_Initialize(t *testing.T) {
 	assert.NotNil(t, dic.Get("CoreDataService"))
 }
 
+// pingingStore is an EventStore that reports err from its health check
+type pingingStore struct {
+	EventStore
+	err error
+}
+
+func (p pingingStore) Ping(ctx context.Context) error { return p.err }
+
+// pingingMessageClient is a MessageClient with a passing health check
+type pingingMessageClient struct {
+	*recordingMessageClient
+}
+
+func (pingingMessageClient) Ping(ctx context.Context) error { return nil }
+
+func TestCoreDataService_InitializeRegistersHealthChecks(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreDataServiceWithStore(logger, pingingStore{NewMemoryEventStore(), errors.New("connection refused")})
+	service.SetMessageClient(pingingMessageClient{newRecordingMessageClient()}, "events")
+	registry := bootstrap.NewHealthRegistry()
+	dic := bootstrap.NewDIContainer()
+	dic.Add(common.HealthRegistryName, registry)
+	var wg sync.WaitGroup
+
+	require.True(t, service.Initialize(context.Background(), &wg, dic))
+
+	assert.Equal(t, []string{"database", "messagebus"}, registry.Names())
+	report := registry.Run(context.Background())
+	assert.Equal(t, bootstrap.HealthStatusDown, report.Status)
+	assert.Equal(t, bootstrap.HealthStatusDown, report.Checks["database"].Status)
+	assert.Equal(t, bootstrap.HealthStatusUp, report.Checks["messagebus"].Status)
+}
+
 func TestCoreDataService_AddEvent(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -70,6 +108,12 @@ func TestCoreDataService_AddEvent(t *testing.T) {
 			expectedCode: http.StatusCreated,
 			expectError:  false,
 		},
+		{
+			name:         "Missing device name",
+			event:        models.Event{ProfileName: "TestProfile"},
+			expectedCode: http.StatusBadRequest,
+			expectError:  true,
+		},
 		{
 			name:         "Invalid JSON",
 			event:        models.Event{}, // Will be sent as invalid JSON
@@ -113,12 +157,69 @@ func TestCoreDataService_AddEvent(t *testing.T) {
 				assert.NotEmpty(t, response["id"])
 				
 				// Verify event was stored
-				assert.Equal(t, 1, len(service.events))
+				count, err := service.store.Count()
+				require.NoError(t, err)
+				assert.Equal(t, 1, count)
 			}
 		})
 	}
 }
 
+func TestCoreDataService_AddEventTooLarge(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreDataService(logger)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/event", service.addEvent).Methods("POST")
+	router.Use(bootstrap.LimitRequestBody(1024))
+	
+	event := models.Event{DeviceName: "TestDevice", Readings: []models.Reading{}}
+	for i := 0; i < 50; i++ {
+		event.Readings = append(event.Readings, models.Reading{DeviceName: "TestDevice", ResourceName: "Temperature", ValueType: "Float32"})
+	}
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	
+	req, err := http.NewRequest("POST", "/api/v3/event", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestCoreDataService_OpenAPI(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreDataService(logger)
+	router := mux.NewRouter()
+	bootstrap.AddCommonRoutes(router, common.CoreDataServiceKey, common.ServiceVersion, nil)
+	service.AddRoutes(router)
+	
+	req, err := http.NewRequest("GET", common.ApiOpenAPIRoute, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	
+	var document bootstrap.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Equal(t, common.CoreDataServiceKey, document.Info.Title)
+	
+	event := document.Paths[common.ApiEventRoute]
+	require.Contains(t, event, "post")
+	assert.Equal(t, "Add an event", event["post"].Summary)
+	assert.NotContains(t, event, "get")
+	
+	byId := document.Paths[common.ApiEventByIdRoute]
+	assert.Contains(t, byId, "get")
+	assert.Contains(t, byId, "delete")
+	require.Len(t, byId["get"].Parameters, 1)
+	assert.Equal(t, "id", byId["get"].Parameters[0].Name)
+}
+
 func TestCoreDataService_GetAllEvents(t *testing.T) {
 	logger := logrus.New()
 	service := NewCoreDataService(logger)
@@ -142,7 +243,7 @@ func TestCoreDataService_GetAllEvents(t *testing.T) {
 	}
 	
 	for _, event := range testEvents {
-		service.events[event.Id] = event
+		service.store.Add(event)
 	}
 	
 	tests := []struct {
@@ -229,7 +330,7 @@ func TestCoreDataService_GetEventById(t *testing.T) {
 		SourceName:  "TestSource",
 		Created:     time.Now().UnixNano() / int64(time.Millisecond),
 	}
-	service.events[testEvent.Id] = testEvent
+	service.store.Add(testEvent)
 	
 	tests := []struct {
 		name         string
@@ -274,6 +375,13 @@ func TestCoreDataService_GetEventById(t *testing.T) {
 				event := response["event"].(map[string]interface{})
 				assert.Equal(t, testEvent.Id, event["id"])
 				assert.Equal(t, testEvent.DeviceName, event["deviceName"])
+			} else {
+				// Errors use the same JSON envelope as successful responses
+				assert.Equal(t, common.ContentTypeJSON, rr.Header().Get(common.ContentType))
+				var response common.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response.StatusCode)
+				assert.NotEmpty(t, response.Message)
 			}
 		})
 	}
@@ -290,7 +398,7 @@ func TestCoreDataService_DeleteEventById(t *testing.T) {
 		SourceName:  "TestSource",
 		Created:     time.Now().UnixNano() / int64(time.Millisecond),
 	}
-	service.events[testEvent.Id] = testEvent
+	service.store.Add(testEvent)
 	
 	tests := []struct {
 		name         string
@@ -326,8 +434,8 @@ func TestCoreDataService_DeleteEventById(t *testing.T) {
 			
 			if tt.expectedCode == http.StatusOK {
 				// Verify event was deleted
-				_, exists := service.events[tt.eventId]
-				assert.False(t, exists)
+				_, err := service.store.GetById(tt.eventId)
+				assert.Equal(t, ErrEventNotFound, err)
 			}
 		})
 	}
@@ -363,7 +471,7 @@ func TestCoreDataService_GetEventsByDeviceName(t *testing.T) {
 	}
 	
 	for _, event := range testEvents {
-		service.events[event.Id] = event
+		service.store.Add(event)
 	}
 	
 	tests := []struct {
@@ -476,7 +584,7 @@ func BenchmarkCoreDataService_GetAllEvents(b *testing.B) {
 			SourceName:  "BenchmarkSource",
 			Created:     time.Now().UnixNano() / int64(time.Millisecond),
 		}
-		service.events[event.Id] = event
+		service.store.Add(event)
 	}
 	
 	b.ResetTimer()
@@ -535,5 +643,118 @@ func TestCoreDataService_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 	
 	// Verify all events were added
-	assert.Equal(t, numGoroutines, len(service.events))
-}
\ No newline at end of file
+	count, err := service.store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, numGoroutines, count)
+}
+
+func TestCoreDataService_GetReadingsByResourceName(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreDataService(logger)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	
+	for i, resources := range [][]string{{"Temperature", "Humidity"}, {"Pressure"}, {"Temperature", "Temperature", "Humidity"}} {
+		event := models.Event{Id: fmt.Sprintf("event-%d", i), DeviceName: "TestDevice", Created: int64(1000 + i)}
+		for j, resourceName := range resources {
+			event.Readings = append(event.Readings, models.Reading{
+				Id:           fmt.Sprintf("reading-%d-%d", i, j),
+				Origin:       int64(100*i + j),
+				DeviceName:   "TestDevice",
+				ResourceName: resourceName,
+				ValueType:    "Float32",
+			})
+		}
+		require.NoError(t, service.store.Add(event))
+	}
+	
+	get := func(path string) (int, []models.Reading) {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		
+		var response struct {
+			TotalCount int              `json:"totalCount"`
+			Readings   []models.Reading `json:"readings"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.TotalCount, response.Readings
+	}
+	
+	total, readings := get("/api/v3/reading/resourceName/Temperature")
+	assert.Equal(t, 3, total)
+	require.Len(t, readings, 3)
+	for _, reading := range readings {
+		assert.Equal(t, "Temperature", reading.ResourceName)
+	}
+	assert.Equal(t, []int64{201, 200, 0}, []int64{readings[0].Origin, readings[1].Origin, readings[2].Origin})
+	
+	total, readings = get("/api/v3/reading/resourceName/Temperature?offset=1&limit=1")
+	assert.Equal(t, 3, total)
+	require.Len(t, readings, 1)
+	assert.Equal(t, "reading-2-0", readings[0].Id)
+	
+	total, readings = get("/api/v3/reading/resourceName/Voltage")
+	assert.Zero(t, total)
+	assert.Empty(t, readings)
+}
+
+func TestCoreDataService_GetReadingStats(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreDataService(logger)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	
+	event := models.Event{Id: "event-1", DeviceName: "TestDevice", Created: 1000}
+	for i, value := range []string{"10", "20", "60"} {
+		event.Readings = append(event.Readings, models.Reading{
+			Id:            fmt.Sprintf("reading-%d", i),
+			Origin:        int64(100 * (i + 1)),
+			ResourceName:  "Temperature",
+			ValueType:     common.ValueTypeFloat64,
+			SimpleReading: models.SimpleReading{Value: value},
+		})
+	}
+	require.NoError(t, service.store.Add(event))
+	
+	get := func(path string) (int, map[string]interface{}) {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return rr.Code, response
+	}
+	
+	code, response := get("/api/v3/reading/resourceName/Temperature/stats")
+	require.Equal(t, http.StatusOK, code)
+	stats := response["stats"].(map[string]interface{})
+	assert.Equal(t, 3.0, stats["count"])
+	assert.Equal(t, 10.0, stats["min"])
+	assert.Equal(t, 60.0, stats["max"])
+	assert.Equal(t, 30.0, stats["mean"])
+	assert.Equal(t, 60.0, stats["latest"])
+	
+	code, response = get("/api/v3/reading/resourceName/Temperature/stats?start=150&end=250")
+	require.Equal(t, http.StatusOK, code)
+	stats = response["stats"].(map[string]interface{})
+	assert.Equal(t, 1.0, stats["count"])
+	assert.Equal(t, 20.0, stats["mean"])
+	
+	// An empty range reports no values rather than zeros
+	code, response = get("/api/v3/reading/resourceName/Temperature/stats?start=500&end=900")
+	require.Equal(t, http.StatusOK, code)
+	stats = response["stats"].(map[string]interface{})
+	assert.Equal(t, 0.0, stats["count"])
+	assert.NotContains(t, stats, "min")
+	assert.NotContains(t, stats, "mean")
+	
+	code, _ = get("/api/v3/reading/resourceName/Temperature/stats?start=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/api/v3/reading/resourceName/Temperature/stats?start=300&end=100")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"strconv"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// numericValueTypes are the reading value types included in statistics
+var numericValueTypes = map[string]bool{
+	common.ValueTypeUint8:   true,
+	common.ValueTypeUint16:  true,
+	common.ValueTypeUint32:  true,
+	common.ValueTypeUint64:  true,
+	common.ValueTypeInt8:    true,
+	common.ValueTypeInt16:   true,
+	common.ValueTypeInt32:   true,
+	common.ValueTypeInt64:   true,
+	common.ValueTypeFloat32: true,
+	common.ValueTypeFloat64: true,
+}
+
+// ReadingStats summarizes the numeric readings of a resource. Min, Max, Mean and Latest are omitted
+// when no numeric reading matched; Skipped counts matching readings whose value is not numeric.
+type ReadingStats struct {
+	ResourceName string   `json:"resourceName"`
+	Count        int      `json:"count"`
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	Mean         *float64 `json:"mean,omitempty"`
+	Latest       *float64 `json:"latest,omitempty"`
+	LatestOrigin int64    `json:"latestOrigin,omitempty"`
+	Skipped      int      `json:"skipped"`
+}
+
+// statsAccumulator computes ReadingStats in a single pass over events
+type statsAccumulator struct {
+	resourceName string
+	start, end   int64 // Origin window; zero bounds are open
+	count        int
+	skipped      int
+	min, max     float64
+	sum          float64
+	latest       float64
+	latestOrigin int64
+}
+
+// newStatsAccumulator accumulates readings of resourceName whose Origin is within [start, end]
+func newStatsAccumulator(resourceName string, start, end int64) *statsAccumulator {
+	return &statsAccumulator{resourceName: resourceName, start: start, end: end}
+}
+
+// add folds the matching readings of an event into the statistics
+func (a *statsAccumulator) add(event models.Event) {
+	for _, reading := range event.Readings {
+		if reading.ResourceName != a.resourceName {
+			continue
+		}
+		if (a.start != 0 && reading.Origin < a.start) || (a.end != 0 && reading.Origin > a.end) {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(reading.SimpleReading.Value, 64)
+		if !numericValueTypes[reading.ValueType] || err != nil {
+			a.skipped++
+			continue
+		}
+
+		if a.count == 0 || value < a.min {
+			a.min = value
+		}
+		if a.count == 0 || value > a.max {
+			a.max = value
+		}
+		if a.count == 0 || reading.Origin >= a.latestOrigin {
+			a.latest = value
+			a.latestOrigin = reading.Origin
+		}
+		a.sum += value
+		a.count++
+	}
+}
+
+// stats returns the statistics accumulated so far
+func (a *statsAccumulator) stats() ReadingStats {
+	stats := ReadingStats{ResourceName: a.resourceName, Count: a.count, Skipped: a.skipped}
+	if a.count == 0 {
+		return stats
+	}
+
+	min, max, mean, latest := a.min, a.max, a.sum/float64(a.count), a.latest
+	stats.Min = &min
+	stats.Max = &max
+	stats.Mean = &mean
+	stats.Latest = &latest
+	stats.LatestOrigin = a.latestOrigin
+	return stats
+}
@@ -0,0 +1,273 @@
+package data
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// DefaultMaxEvents is the capacity of a MemoryEventStore when none is configured
+const DefaultMaxEvents = 100000
+
+// ErrEventNotFound is returned by an EventStore when no event has the requested id
+var ErrEventNotFound = errors.New("event not found")
+
+// EventStore defines the persistence operations Core Data needs for events
+type EventStore interface {
+	Add(event models.Event) error
+	GetById(id string) (models.Event, error)
+	All(offset, limit int) ([]models.Event, error)
+	DeleteById(id string) error
+	ByDeviceName(deviceName string) ([]models.Event, error)
+	// ByTimeRange returns a page of the events created within [start, end], newest first, and
+	// the total number of matching events
+	ByTimeRange(start, end int64, offset, limit int) ([]models.Event, int, error)
+	Count() (int, error)
+	// ReadingsByResourceName returns a page of the readings of every event with the given
+	// resource name, newest Origin first, and the total number of matching readings
+	ReadingsByResourceName(resourceName string, offset, limit int) ([]models.Reading, int, error)
+	// ReadingStats summarizes the numeric readings with the given resource name whose Origin is
+	// within [start, end]; zero bounds are open
+	ReadingStats(resourceName string, start, end int64) (ReadingStats, error)
+}
+
+// MemoryEventStore implements EventStore using an in-memory map. Once it holds maxEvents events,
+// adding another evicts the oldest by Created, found through a min-heap kept beside the map.
+type MemoryEventStore struct {
+	events    map[string]models.Event
+	byCreated eventHeap
+	entries   map[string]*eventHeapEntry
+	maxEvents int
+	evictions uint64
+	logger    *logrus.Logger
+	mutex     sync.RWMutex
+}
+
+// NewMemoryEventStore creates a new in-memory event store holding up to DefaultMaxEvents events
+func NewMemoryEventStore() *MemoryEventStore {
+	return NewMemoryEventStoreWithCapacity(DefaultMaxEvents, logrus.StandardLogger())
+}
+
+// NewMemoryEventStoreWithCapacity creates a new in-memory event store holding up to maxEvents events.
+// A maxEvents of zero or less leaves the store unbounded.
+func NewMemoryEventStoreWithCapacity(maxEvents int, logger *logrus.Logger) *MemoryEventStore {
+	return &MemoryEventStore{
+		events:    make(map[string]models.Event),
+		entries:   make(map[string]*eventHeapEntry),
+		maxEvents: maxEvents,
+		logger:    logger,
+	}
+}
+
+// Add stores an event, replacing any existing event with the same id, and evicts the oldest
+// events if the store is over capacity
+func (m *MemoryEventStore) Add(event models.Event) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.events[event.Id] = event
+	if entry, exists := m.entries[event.Id]; exists {
+		entry.created = event.Created
+		heap.Fix(&m.byCreated, entry.index)
+	} else {
+		entry := &eventHeapEntry{id: event.Id, created: event.Created}
+		heap.Push(&m.byCreated, entry)
+		m.entries[event.Id] = entry
+	}
+
+	for m.maxEvents > 0 && len(m.events) > m.maxEvents {
+		oldest := heap.Pop(&m.byCreated).(*eventHeapEntry)
+		delete(m.events, oldest.id)
+		delete(m.entries, oldest.id)
+		if m.evictions == 0 {
+			m.logger.Warnf("Event store reached its capacity of %d events, evicting the oldest events", m.maxEvents)
+		}
+		m.evictions++
+		m.logger.Debugf("Evicted event %s created at %d", oldest.id, oldest.created)
+	}
+	return nil
+}
+
+// Evictions returns the number of events evicted to stay within capacity
+func (m *MemoryEventStore) Evictions() uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.evictions
+}
+
+// GetById retrieves a single event
+func (m *MemoryEventStore) GetById(id string) (models.Event, error) {
+	m.mutex.RLock()
+	event, exists := m.events[id]
+	m.mutex.RUnlock()
+
+	if !exists {
+		return models.Event{}, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// All returns a page of events ordered newest first
+func (m *MemoryEventStore) All(offset, limit int) ([]models.Event, error) {
+	m.mutex.RLock()
+	events := make([]models.Event, 0, len(m.events))
+	for _, event := range m.events {
+		events = append(events, event)
+	}
+	m.mutex.RUnlock()
+
+	sortEventsByCreated(events)
+	page, _ := common.Paginate(events, offset, limit)
+	return page, nil
+}
+
+// DeleteById removes a single event
+func (m *MemoryEventStore) DeleteById(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.events[id]; !exists {
+		return ErrEventNotFound
+	}
+	delete(m.events, id)
+	heap.Remove(&m.byCreated, m.entries[id].index)
+	delete(m.entries, id)
+	return nil
+}
+
+// ByDeviceName returns all events originating from the given device, newest first
+func (m *MemoryEventStore) ByDeviceName(deviceName string) ([]models.Event, error) {
+	m.mutex.RLock()
+	events := []models.Event{}
+	for _, event := range m.events {
+		if event.DeviceName == deviceName {
+			events = append(events, event)
+		}
+	}
+	m.mutex.RUnlock()
+
+	sortEventsByCreated(events)
+	return events, nil
+}
+
+// ByTimeRange returns a page of events created within [start, end], newest first, and the number of matches
+func (m *MemoryEventStore) ByTimeRange(start, end int64, offset, limit int) ([]models.Event, int, error) {
+	m.mutex.RLock()
+	events := []models.Event{}
+	for _, event := range m.events {
+		if event.Created >= start && event.Created <= end {
+			events = append(events, event)
+		}
+	}
+	m.mutex.RUnlock()
+
+	sortEventsByCreated(events)
+	page, total := common.Paginate(events, offset, limit)
+	return page, total, nil
+}
+
+// Count returns the number of stored events
+func (m *MemoryEventStore) Count() (int, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.events), nil
+}
+
+// ReadingsByResourceName returns a page of the readings with the given resource name, newest first
+func (m *MemoryEventStore) ReadingsByResourceName(resourceName string, offset, limit int) ([]models.Reading, int, error) {
+	m.mutex.RLock()
+	events := make([]models.Event, 0, len(m.events))
+	for _, event := range m.events {
+		events = append(events, event)
+	}
+	m.mutex.RUnlock()
+
+	readings, total := readingsByResourceName(events, resourceName, offset, limit)
+	return readings, total, nil
+}
+
+// ReadingStats summarizes the numeric readings of a resource in a single pass under the read lock
+func (m *MemoryEventStore) ReadingStats(resourceName string, start, end int64) (ReadingStats, error) {
+	accumulator := newStatsAccumulator(resourceName, start, end)
+
+	m.mutex.RLock()
+	for _, event := range m.events {
+		accumulator.add(event)
+	}
+	m.mutex.RUnlock()
+
+	return accumulator.stats(), nil
+}
+
+// readingsByResourceName flattens the readings of events with the given resource name, orders them
+// newest Origin first, tie-broken by id, and returns the requested page with the number of matches
+func readingsByResourceName(events []models.Event, resourceName string, offset, limit int) ([]models.Reading, int) {
+	readings := []models.Reading{}
+	for _, event := range events {
+		for _, reading := range event.Readings {
+			if reading.ResourceName == resourceName {
+				readings = append(readings, reading)
+			}
+		}
+	}
+
+	sort.Slice(readings, func(i, j int) bool {
+		if readings[i].Origin != readings[j].Origin {
+			return readings[i].Origin > readings[j].Origin
+		}
+		return readings[i].Id < readings[j].Id
+	})
+	return common.Paginate(readings, offset, limit)
+}
+
+// sortEventsByCreated orders events newest first, tie-broken by id for stable pagination
+func sortEventsByCreated(events []models.Event) {
+	sort.Slice(events, func(i, j int) bool {
+		return common.NewestFirst(events[i].Created, events[i].Id, events[j].Created, events[j].Id)
+	})
+}
+
+// eventHeapEntry is the position of a stored event in the eviction heap
+type eventHeapEntry struct {
+	id      string
+	created int64
+	index   int
+}
+
+// eventHeap orders events oldest first, the reverse of sortEventsByCreated; it implements heap.Interface
+type eventHeap []*eventHeapEntry
+
+func (h eventHeap) Len() int { return len(h) }
+
+func (h eventHeap) Less(i, j int) bool {
+	if h[i].created != h[j].created {
+		return h[i].created < h[j].created
+	}
+	return h[i].id > h[j].id
+}
+
+func (h eventHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *eventHeap) Push(x interface{}) {
+	entry := x.(*eventHeapEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
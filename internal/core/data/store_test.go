@@ -0,0 +1,307 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// runEventStoreConformance exercises the EventStore contract against any implementation.
+// newStore must return an empty store for every call.
+func runEventStoreConformance(t *testing.T, newStore func(t *testing.T) EventStore) {
+	seed := func(t *testing.T, store EventStore) []models.Event {
+		events := []models.Event{
+			{Id: "event-1", DeviceName: "Device1", ProfileName: "Profile1", SourceName: "Source1", Created: 1000},
+			{Id: "event-2", DeviceName: "Device2", ProfileName: "Profile2", SourceName: "Source2", Created: 2000},
+			{Id: "event-3", DeviceName: "Device1", ProfileName: "Profile1", SourceName: "Source1", Created: 3000},
+		}
+		for _, event := range events {
+			require.NoError(t, store.Add(event))
+		}
+		return events
+	}
+
+	t.Run("Add and GetById", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		event, err := store.GetById("event-2")
+		require.NoError(t, err)
+		assert.Equal(t, "Device2", event.DeviceName)
+		assert.Equal(t, int64(2000), event.Created)
+	})
+
+	t.Run("GetById missing", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetById("missing")
+		assert.Equal(t, ErrEventNotFound, err)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("All newest first with pagination", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		events, err := store.All(0, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, "event-3", events[0].Id)
+		assert.Equal(t, "event-1", events[2].Id)
+
+		events, err = store.All(1, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "event-2", events[0].Id)
+
+		events, err = store.All(5, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("ByDeviceName", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		events, err := store.ByDeviceName("Device1")
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		for _, event := range events {
+			assert.Equal(t, "Device1", event.DeviceName)
+		}
+
+		events, err = store.ByDeviceName("NoSuchDevice")
+		require.NoError(t, err)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("ByTimeRange", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		events, total, err := store.ByTimeRange(1500, 3000, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, "event-3", events[0].Id)
+		assert.Equal(t, "event-2", events[1].Id)
+
+		events, total, err = store.ByTimeRange(1500, 3000, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, "event-2", events[0].Id)
+
+		events, total, err = store.ByTimeRange(4000, 5000, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 0)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("DeleteById", func(t *testing.T) {
+		store := newStore(t)
+		seed(t, store)
+
+		require.NoError(t, store.DeleteById("event-1"))
+		assert.Equal(t, ErrEventNotFound, store.DeleteById("event-1"))
+
+		_, err := store.GetById("event-1")
+		assert.Equal(t, ErrEventNotFound, err)
+
+		events, err := store.ByDeviceName("Device1")
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("ReadingsByResourceName", func(t *testing.T) {
+		store := newStore(t)
+		reading := func(id, resourceName string, origin int64) models.Reading {
+			return models.Reading{Id: id, DeviceName: "Device1", ResourceName: resourceName, Origin: origin}
+		}
+		require.NoError(t, store.Add(models.Event{Id: "event-1", DeviceName: "Device1", Created: 1000, Readings: []models.Reading{
+			reading("t-1", "Temperature", 100), reading("h-1", "Humidity", 150), reading("t-2", "Temperature", 300),
+		}}))
+		require.NoError(t, store.Add(models.Event{Id: "event-2", DeviceName: "Device2", Created: 2000, Readings: []models.Reading{
+			reading("t-3", "Temperature", 200),
+		}}))
+
+		readings, total, err := store.ReadingsByResourceName("Temperature", 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, readings, 3)
+		assert.Equal(t, "t-2", readings[0].Id)
+		assert.Equal(t, "t-3", readings[1].Id)
+		assert.Equal(t, "t-1", readings[2].Id)
+
+		readings, total, err = store.ReadingsByResourceName("Temperature", 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, readings, 1)
+		assert.Equal(t, "t-3", readings[0].Id)
+
+		// Replacing or deleting an event drops its readings
+		require.NoError(t, store.Add(models.Event{Id: "event-1", DeviceName: "Device1", Created: 1000, Readings: []models.Reading{
+			reading("h-1", "Humidity", 150),
+		}}))
+		require.NoError(t, store.DeleteById("event-2"))
+		readings, total, err = store.ReadingsByResourceName("Temperature", 0, 10)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, readings)
+
+		readings, total, err = store.ReadingsByResourceName("Humidity", 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, readings, 1)
+		assert.Equal(t, "h-1", readings[0].Id)
+	})
+
+	t.Run("ReadingStats", func(t *testing.T) {
+		store := newStore(t)
+		reading := func(resourceName, valueType, value string, origin int64) models.Reading {
+			return models.Reading{Id: models.GenerateUUID(), ResourceName: resourceName, ValueType: valueType, Origin: origin, SimpleReading: models.SimpleReading{Value: value}}
+		}
+		require.NoError(t, store.Add(models.Event{Id: "event-1", DeviceName: "Device1", Created: 1000, Readings: []models.Reading{
+			reading("Temperature", "Float64", "20.5", 100),
+			reading("Temperature", "Int32", "30", 300),
+			reading("Humidity", "Float64", "99", 150),
+		}}))
+		require.NoError(t, store.Add(models.Event{Id: "event-2", DeviceName: "Device2", Created: 2000, Readings: []models.Reading{
+			reading("Temperature", "Float32", "-4.5", 200),
+			reading("Temperature", "String", "warm", 250),
+			reading("Temperature", "Float64", "not-a-number", 260),
+		}}))
+
+		stats, err := store.ReadingStats("Temperature", 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Temperature", stats.ResourceName)
+		assert.Equal(t, 3, stats.Count)
+		assert.Equal(t, 2, stats.Skipped)
+		require.NotNil(t, stats.Min)
+		assert.Equal(t, -4.5, *stats.Min)
+		assert.Equal(t, 30.0, *stats.Max)
+		assert.InDelta(t, 46.0/3, *stats.Mean, 1e-9)
+		assert.Equal(t, 30.0, *stats.Latest)
+		assert.Equal(t, int64(300), stats.LatestOrigin)
+
+		stats, err = store.ReadingStats("Temperature", 100, 250)
+		require.NoError(t, err)
+		assert.Equal(t, 2, stats.Count)
+		assert.Equal(t, 1, stats.Skipped)
+		assert.Equal(t, 8.0, *stats.Mean)
+		assert.Equal(t, -4.5, *stats.Latest)
+
+		stats, err = store.ReadingStats("Temperature", 1000, 2000)
+		require.NoError(t, err)
+		assert.Zero(t, stats.Count)
+		assert.Zero(t, stats.Skipped)
+		assert.Nil(t, stats.Min)
+		assert.Nil(t, stats.Mean)
+		assert.Nil(t, stats.Latest)
+	})
+
+	t.Run("Readings round trip", func(t *testing.T) {
+		store := newStore(t)
+		event := models.Event{Id: "event-r", DeviceName: "Device1", Created: 1000}
+		for i := 0; i < 3; i++ {
+			reading := models.NewSimpleReading("Profile1", "Device1", "Temperature", "Float64", fmt.Sprintf("%d", 20+i))
+			event.Readings = append(event.Readings, reading)
+		}
+		require.NoError(t, store.Add(event))
+
+		stored, err := store.GetById("event-r")
+		require.NoError(t, err)
+		require.Len(t, stored.Readings, 3)
+		assert.Equal(t, "22", stored.Readings[2].SimpleReading.Value)
+	})
+}
+
+func TestMemoryEventStore_Conformance(t *testing.T) {
+	runEventStoreConformance(t, func(t *testing.T) EventStore {
+		return NewMemoryEventStore()
+	})
+}
+
+func TestMemoryEventStore_Capacity(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	store := NewMemoryEventStoreWithCapacity(3, logger)
+
+	// Insert out of order so eviction can't rely on insertion order
+	for _, created := range []int64{5000, 1000, 4000, 2000, 6000, 3000} {
+		require.NoError(t, store.Add(models.Event{Id: fmt.Sprintf("event-%d", created), Created: created}))
+		count, err := store.Count()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, count, 3)
+	}
+
+	events, err := store.All(0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "event-6000", events[0].Id)
+	assert.Equal(t, "event-5000", events[1].Id)
+	assert.Equal(t, "event-4000", events[2].Id)
+	assert.Equal(t, uint64(3), store.Evictions())
+
+	// Replacing an event moves it in the eviction order, and deleted events free their slot
+	require.NoError(t, store.Add(models.Event{Id: "event-4000", Created: 9000}))
+	require.NoError(t, store.DeleteById("event-6000"))
+	require.NoError(t, store.Add(models.Event{Id: "event-7000", Created: 7000}))
+	require.NoError(t, store.Add(models.Event{Id: "event-8000", Created: 8000}))
+
+	events, err = store.All(0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "event-4000", events[0].Id)
+	assert.Equal(t, "event-8000", events[1].Id)
+	assert.Equal(t, "event-7000", events[2].Id)
+	assert.Equal(t, uint64(4), store.Evictions())
+}
+
+func TestMemoryEventStore_Unbounded(t *testing.T) {
+	store := NewMemoryEventStoreWithCapacity(0, logrus.New())
+	for i := 0; i < 100; i++ {
+		require.NoError(t, store.Add(models.Event{Id: fmt.Sprintf("event-%d", i), Created: int64(i)}))
+	}
+
+	count, err := store.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 100, count)
+	assert.Zero(t, store.Evictions())
+}
+
+func TestNewRedisEventStore_Credentials(t *testing.T) {
+	logger := logrus.New()
+	secretProvider, err := bootstrap.NewSecretProvider(common.CoreDataServiceKey,
+		bootstrap.DatabaseConfig{Host: "localhost", Port: 6379, Username: "edgex", Password: "hunter2"}, logger)
+	require.NoError(t, err)
+
+	options := NewRedisEventStore("localhost:6379", 0, secretProvider, logger).client.Options()
+	assert.Equal(t, "edgex", options.Username)
+	assert.Equal(t, "hunter2", options.Password)
+
+	secretProvider, err = bootstrap.NewSecretProvider(common.CoreDataServiceKey, bootstrap.DatabaseConfig{}, logger)
+	require.NoError(t, err)
+	options = NewRedisEventStore("localhost:6379", 0, secretProvider, logger).client.Options()
+	assert.Empty(t, options.Username)
+	assert.Empty(t, options.Password)
+}
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Stream tuning
+const (
+	// streamBufferSize is how many events a slow stream client may fall behind before events are dropped for it
+	streamBufferSize = 64
+	// streamKeepAliveInterval is how often an idle stream sends a comment so proxies keep the connection open
+	streamKeepAliveInterval = 15 * time.Second
+)
+
+// eventBroadcaster fans newly added events out to stream subscribers
+type eventBroadcaster struct {
+	subscribers map[chan models.Event]string
+	logger      *logrus.Logger
+	mutex       sync.RWMutex
+}
+
+// newEventBroadcaster creates a broadcaster with no subscribers
+func newEventBroadcaster(logger *logrus.Logger) *eventBroadcaster {
+	return &eventBroadcaster{
+		subscribers: make(map[chan models.Event]string),
+		logger:      logger,
+	}
+}
+
+// subscribe returns a channel receiving every new event, or only those from deviceName when it is set
+func (b *eventBroadcaster) subscribe(deviceName string) chan models.Event {
+	ch := make(chan models.Event, streamBufferSize)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscribers[ch] = deviceName
+	return ch
+}
+
+// unsubscribe stops delivery to ch
+func (b *eventBroadcaster) unsubscribe(ch chan models.Event) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.subscribers, ch)
+}
+
+// publish delivers event to every matching subscriber without blocking on slow ones
+func (b *eventBroadcaster) publish(event models.Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for ch, deviceName := range b.subscribers {
+		if deviceName != "" && deviceName != event.DeviceName {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			b.logger.Warnf("Event stream subscriber is falling behind, dropping event %s", event.Id)
+		}
+	}
+}
+
+// count returns the number of active subscribers
+func (b *eventBroadcaster) count() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.subscribers)
+}
+
+// streamEvents handles GET /api/v3/event/stream, pushing each new event as a Server-Sent Event.
+// An optional ?deviceName= restricts the stream to one device.
+func (s *CoreDataService) streamEvents(w http.ResponseWriter, r *http.Request) {
+	controller := http.NewResponseController(w)
+
+	w.Header().Set(common.ContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// The stream outlives the server's write timeout, so lift it; writers that cannot take a deadline have none
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	deviceName := r.URL.Query().Get("deviceName")
+	events := s.broadcaster.subscribe(deviceName)
+	defer s.broadcaster.unsubscribe(events)
+
+	// Confirm the subscription so clients know events added from now on will arrive
+	fmt.Fprint(w, ": connected\n\n")
+	if err := controller.Flush(); err != nil {
+		s.logger.Errorf("Event stream unsupported by response writer: %v", err)
+		return
+	}
+
+	s.logger.Infof("Event stream opened (deviceName=%q)", deviceName)
+	defer s.logger.Infof("Event stream closed (deviceName=%q)", deviceName)
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				s.logger.Errorf("Failed to marshal event %s for stream: %v", event.Id, err)
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: event\ndata: %s\n\n", event.Id, data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			// Let graceful shutdown drain instead of waiting on a stream that never ends
+			return
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// newStreamServer serves the core data routes behind the standard middleware
+func newStreamServer(t *testing.T) (*CoreDataService, *httptest.Server) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	service := NewCoreDataService(logger)
+
+	router := mux.NewRouter()
+	bootstrap.ApplyMiddleware(router, bootstrap.NewDIContainer())
+	service.AddRoutes(router)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return service, server
+}
+
+// openStream connects to the event stream and waits until the subscription is confirmed
+func openStream(t *testing.T, ctx context.Context, url string) (*http.Response, *bufio.Reader) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get(common.ContentType))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, ": connected\n", line)
+	return resp, reader
+}
+
+// readStreamEvent reads lines until the next event's data line and decodes it
+func readStreamEvent(t *testing.T, reader *bufio.Reader) models.Event {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var event models.Event
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			return event
+		}
+	}
+}
+
+// postEvent adds an event through the API
+func postEvent(t *testing.T, serverURL string, event models.Event) {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	resp, err := http.Post(serverURL+common.ApiEventRoute, common.ContentTypeJSON, bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+}
+
+func TestCoreDataService_StreamEvents(t *testing.T) {
+	_, server := newStreamServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, reader := openStream(t, ctx, server.URL+common.ApiEventStreamRoute)
+
+	postEvent(t, server.URL, models.Event{DeviceName: "thermostat-1", ProfileName: "thermostat", SourceName: "temperature"})
+
+	event := readStreamEvent(t, reader)
+	assert.Equal(t, "thermostat-1", event.DeviceName)
+	assert.NotEmpty(t, event.Id)
+}
+
+func TestCoreDataService_StreamEventsDeviceFilter(t *testing.T) {
+	_, server := newStreamServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, reader := openStream(t, ctx, server.URL+common.ApiEventStreamRoute+"?deviceName=thermostat-2")
+
+	postEvent(t, server.URL, models.Event{DeviceName: "thermostat-1", ProfileName: "thermostat", SourceName: "temperature"})
+	postEvent(t, server.URL, models.Event{DeviceName: "thermostat-2", ProfileName: "thermostat", SourceName: "temperature"})
+
+	event := readStreamEvent(t, reader)
+	assert.Equal(t, "thermostat-2", event.DeviceName)
+}
+
+func TestCoreDataService_StreamEventsUnsubscribesOnDisconnect(t *testing.T) {
+	service, server := newStreamServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	openStream(t, ctx, server.URL+common.ApiEventStreamRoute)
+	require.Equal(t, 1, service.broadcaster.count())
+
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return service.broadcaster.count() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestEventBroadcaster_DropsForSlowSubscribers(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	broadcaster := newEventBroadcaster(logger)
+	ch := broadcaster.subscribe("")
+	defer broadcaster.unsubscribe(ch)
+
+	// Publishing never blocks, even once the subscriber's buffer is full
+	for i := 0; i < streamBufferSize+10; i++ {
+		broadcaster.publish(models.Event{Id: "e"})
+	}
+
+	assert.Len(t, ch, streamBufferSize)
+}
@@ -0,0 +1,180 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// DeviceImportResult reports the outcome of one device of an import. Index is the device's position in the request.
+type DeviceImportResult struct {
+	Index  int    `json:"index"`
+	Id     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// validateImportedDevice checks the fields a device must carry before it is imported
+func validateImportedDevice(device models.Device) error {
+	if device.Name == "" {
+		return errors.New("name is required")
+	}
+	if device.AdminState != "" && device.AdminState != common.Locked && device.AdminState != common.Unlocked {
+		return fmt.Errorf("adminState %q must be %s or %s", device.AdminState, common.Locked, common.Unlocked)
+	}
+	if device.OperatingState != "" && device.OperatingState != common.Up && device.OperatingState != common.Down {
+		return fmt.Errorf("operatingState %q must be %s or %s", device.OperatingState, common.Up, common.Down)
+	}
+	return nil
+}
+
+// deviceImportFileField is the form field carrying the devices of a multipart/form-data import
+const deviceImportFileField = "file"
+
+// deviceImportBody returns the JSON array of devices of an import: the request body, or the file
+// uploaded as the "file" field of a multipart/form-data request. The file is streamed, not buffered.
+func deviceImportBody(r *http.Request) (io.Reader, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(common.ContentType))
+	if mediaType != common.ContentTypeMultipartForm {
+		return r.Body, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("invalid multipart form: %w", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("multipart form has no %q field", deviceImportFileField)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart form: %w", err)
+		}
+		if part.FormName() == deviceImportFileField {
+			return part, nil
+		}
+	}
+}
+
+// importDevices handles POST /api/v3/device/import, taking a JSON array of devices such as an export,
+// either as the body or uploaded as the "file" field of a multipart/form-data request.
+// Every device is validated and added on its own, so a bad one fails alone; the response is always
+// 207 with one result per device. Devices get new ids unless ?preserveIds=true, and a device whose
+// name, or preserved id, is already taken is rejected with 409 so an import can safely be repeated.
+func (s *CoreMetadataService) importDevices(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	preserveIds := false
+	if value := r.URL.Query().Get("preserveIds"); value != "" {
+		var err error
+		if preserveIds, err = strconv.ParseBool(value); err != nil {
+			common.WriteError(w, http.StatusBadRequest, "preserveIds must be true or false")
+			return
+		}
+	}
+
+	body, err := deviceImportBody(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var devices []models.Device
+	if err := json.NewDecoder(body).Decode(&devices); err != nil {
+		s.logger.Errorf("Failed to decode device import: %v", err)
+		common.WriteDecodeError(w, err, "Invalid device import payload, expected a JSON array of devices")
+		return
+	}
+
+	created := s.clock.Now().UnixNano() / int64(time.Millisecond)
+	results := make([]DeviceImportResult, len(devices))
+	var imported []models.Device
+
+	s.mutex.Lock()
+	names := make(map[string]bool, len(s.devices))
+	for _, device := range s.devices {
+		names[device.Name] = true
+	}
+	for i, device := range devices {
+		results[i] = DeviceImportResult{Index: i, Name: device.Name}
+
+		if err := validateImportedDevice(device); err != nil {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = err.Error()
+			continue
+		}
+		if names[device.Name] {
+			results[i].Status = http.StatusConflict
+			results[i].Error = fmt.Sprintf("device %s already exists", device.Name)
+			continue
+		}
+		if !preserveIds || device.Id == "" {
+			device.Id = models.GenerateUUID()
+		} else if _, exists := s.devices[device.Id]; exists {
+			results[i].Status = http.StatusConflict
+			results[i].Error = fmt.Sprintf("device id %s already exists", device.Id)
+			continue
+		}
+
+		initializeDevice(&device, created)
+		s.devices[device.Id] = device
+		names[device.Name] = true
+		imported = append(imported, device)
+
+		results[i].Id = device.Id
+		results[i].Status = http.StatusCreated
+	}
+	s.mutex.Unlock()
+
+	s.logger.Infof("Device import processed: %d of %d devices created", len(imported), len(devices))
+	for _, device := range imported {
+		s.notifyDeviceChange(r.Context(), device, DeviceActionAdd)
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusMultiStatus,
+		"results":    results,
+	}
+
+	w.WriteHeader(http.StatusMultiStatus)
+	json.NewEncoder(w).Encode(response)
+}
+
+// exportDevices handles GET /api/v3/device/export, streaming every device, newest first, as a JSON
+// array that importDevices accepts back
+func (s *CoreMetadataService) exportDevices(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	devices := make([]models.Device, 0, len(s.devices))
+	for _, device := range s.devices {
+		devices = append(devices, device)
+	}
+	s.mutex.RUnlock()
+	sortDevicesByCreated(devices)
+
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	w.Header().Set("Content-Disposition", `attachment; filename="devices.json"`)
+
+	// Encode one device at a time so a large registry is never held as a single document
+	encoder := json.NewEncoder(w)
+	w.Write([]byte("["))
+	for i, device := range devices {
+		if i > 0 {
+			w.Write([]byte(","))
+		}
+		if err := encoder.Encode(device); err != nil {
+			s.logger.Errorf("Failed to export device %s: %v", device.Name, err)
+			return
+		}
+	}
+	w.Write([]byte("]\n"))
+}
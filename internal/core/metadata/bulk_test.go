@@ -0,0 +1,205 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// importRequest posts body to the import route and decodes the per-device results
+func importRequest(t *testing.T, router *mux.Router, query string, body []byte) (int, []DeviceImportResult) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/device/import"+query, bytes.NewReader(body))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	var response struct {
+		Results []DeviceImportResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr.Code, response.Results
+}
+
+// exportRequest returns the body of the export route
+func exportRequest(t *testing.T, router *mux.Router) []byte {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v3/device/export", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, common.ContentTypeJSON, rr.Header().Get(common.ContentType))
+	return rr.Body.Bytes()
+}
+
+func newBulkTestService() (*CoreMetadataService, *mux.Router) {
+	service := NewCoreMetadataService(logrus.New())
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	return service, router
+}
+
+func TestCoreMetadataService_ImportDevicesMixed(t *testing.T) {
+	service, router := newBulkTestService()
+	seedDevice(service, "Existing", service.clock.Now())
+
+	body, err := json.Marshal([]models.Device{
+		{Id: "ignored-id", Name: "Thermostat-1", ProfileName: "Thermostat", ServiceName: "device-virtual"},
+		{Description: "no name"},
+		{Name: "Thermostat-2", AdminState: "PAUSED"},
+		{Name: "Existing"},
+		{Name: "Thermostat-1"},
+		{Name: "Pump-1", AdminState: common.Locked, OperatingState: common.Down},
+	})
+	require.NoError(t, err)
+
+	code, results := importRequest(t, router, "", body)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	require.Len(t, results, 6)
+
+	expected := []struct {
+		status int
+		error  string
+	}{
+		{http.StatusCreated, ""},
+		{http.StatusBadRequest, "name is required"},
+		{http.StatusBadRequest, `adminState "PAUSED" must be LOCKED or UNLOCKED`},
+		{http.StatusConflict, "device Existing already exists"},
+		{http.StatusConflict, "device Thermostat-1 already exists"},
+		{http.StatusCreated, ""},
+	}
+	for i, want := range expected {
+		assert.Equal(t, i, results[i].Index)
+		assert.Equal(t, want.status, results[i].Status, i)
+		assert.Equal(t, want.error, results[i].Error, i)
+	}
+
+	// New ids, with the same defaults as a single add
+	assert.NotEqual(t, "ignored-id", results[0].Id)
+	thermostat := deviceByName(t, service, "Thermostat-1")
+	assert.Equal(t, results[0].Id, thermostat.Id)
+	assert.Equal(t, int64(1), thermostat.Version)
+	assert.Equal(t, common.Unlocked, thermostat.AdminState)
+	assert.Equal(t, common.Up, thermostat.OperatingState)
+	assert.Equal(t, common.Down, deviceByName(t, service, "Pump-1").OperatingState)
+	assert.Len(t, service.devices, 3)
+
+	for _, body := range []string{`{"name":"not an array"}`, `[{"name":`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v3/device/import", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/device/import?preserveIds=maybe", bytes.NewBufferString("[]"))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCoreMetadataService_ExportImportRoundTrip(t *testing.T) {
+	source, sourceRouter := newBulkTestService()
+	body, err := json.Marshal([]models.Device{
+		{Name: "Thermostat-1", ProfileName: "Thermostat", Labels: []string{"hvac"}, Location: map[string]string{"floor": "2"}},
+		{Name: "Pump-1", ProfileName: "Pump", AdminState: common.Locked},
+		{Name: "Meter-1", ProfileName: "Meter", Protocols: map[string]models.ProtocolProperties{"modbus": {Address: "10.0.0.5", Port: "502"}}},
+	})
+	require.NoError(t, err)
+	_, results := importRequest(t, sourceRouter, "", body)
+	for _, result := range results {
+		require.Equal(t, http.StatusCreated, result.Status)
+	}
+
+	exported := exportRequest(t, sourceRouter)
+	var devices []models.Device
+	require.NoError(t, json.Unmarshal(exported, &devices))
+	assert.Len(t, devices, 3)
+
+	// Restoring with preserved ids gives back the same devices
+	target, targetRouter := newBulkTestService()
+	code, results := importRequest(t, targetRouter, "?preserveIds=true", exported)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	for _, result := range results {
+		assert.Equal(t, http.StatusCreated, result.Status, result.Name)
+	}
+	for id, device := range source.devices {
+		restored, exists := target.devices[id]
+		require.True(t, exists, device.Name)
+		assert.Equal(t, device.Name, restored.Name)
+		assert.Equal(t, device.ProfileName, restored.ProfileName)
+		assert.Equal(t, device.AdminState, restored.AdminState)
+		assert.Equal(t, device.Labels, restored.Labels)
+		assert.Equal(t, device.Location, restored.Location)
+		assert.Equal(t, device.Protocols, restored.Protocols)
+	}
+
+	var reexported []models.Device
+	require.NoError(t, json.Unmarshal(exportRequest(t, targetRouter), &reexported))
+	assert.Len(t, reexported, 3)
+
+	// Importing the same export again changes nothing
+	_, results = importRequest(t, targetRouter, "?preserveIds=true", exported)
+	for _, result := range results {
+		assert.Equal(t, http.StatusConflict, result.Status, result.Name)
+	}
+	assert.Len(t, target.devices, 3)
+}
+
+func TestCoreMetadataService_ExportDevicesEmpty(t *testing.T) {
+	_, router := newBulkTestService()
+	assert.JSONEq(t, "[]", string(exportRequest(t, router)))
+}
+
+func TestCoreMetadataService_ImportDevicesUpload(t *testing.T) {
+	service, router := newBulkTestService()
+	devices, err := json.Marshal([]models.Device{{Name: "Thermostat-1"}, {Name: "Thermostat-2"}})
+	require.NoError(t, err)
+
+	upload := func(field string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		require.NoError(t, form.WriteField("comment", "nightly"))
+		file, err := form.CreateFormFile(field, "devices.json")
+		require.NoError(t, err)
+		file.Write(devices)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v3/device/import", &body)
+		req.Header.Set(common.ContentType, form.FormDataContentType())
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := upload("file")
+	require.Equal(t, http.StatusMultiStatus, rr.Code, rr.Body.String())
+	var response struct {
+		Results []DeviceImportResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Results, 2)
+	for _, result := range response.Results {
+		assert.Equal(t, http.StatusCreated, result.Status)
+	}
+	assert.Len(t, service.devices, 2)
+
+	// The devices must come in the file field
+	rr = upload("devices")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), `no \"file\" field`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v3/device/import", bytes.NewReader(devices))
+	req.Header.Set(common.ContentType, common.ContentTypeMultipartForm)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,114 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/clients/httpclient"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// DefaultCallbackTimeout bounds each attempt of a device change callback to a device service
+const DefaultCallbackTimeout = 5 * time.Second
+
+// newCallbackClient returns the client device change callbacks are sent with. A device service that
+// keeps failing them is left alone for a while rather than called for every change.
+func newCallbackClient() *httpclient.Client {
+	config := httpclient.DefaultConfig()
+	config.Timeout = DefaultCallbackTimeout
+	return httpclient.NewClient(config)
+}
+
+// Actions reported by device change callbacks
+const (
+	DeviceActionAdd    = "add"
+	DeviceActionUpdate = "update"
+	DeviceActionDelete = "delete"
+)
+
+// DeviceChange is posted to a device service when one of its devices with Notify set changes
+type DeviceChange struct {
+	Action     string `json:"action"`
+	DeviceName string `json:"deviceName"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// notifyDeviceChange tells the device's owning service about a change if the device asked for it.
+// The callback is sent in the background and failures are only logged, so a slow or missing
+// device service never holds up the metadata API. ctx supplies the correlation id to forward.
+// Callbacks run under the service's context, so shutdown cancels them and waits for them to return.
+func (s *CoreMetadataService) notifyDeviceChange(ctx context.Context, device models.Device, action string) {
+	if !device.Notify {
+		return
+	}
+
+	s.mutex.RLock()
+	baseAddress := ""
+	for _, deviceService := range s.deviceServices {
+		if deviceService.Name == device.ServiceName {
+			baseAddress = deviceService.BaseAddress
+			break
+		}
+	}
+	serviceCtx := s.ctx
+	started := baseAddress != "" && serviceCtx.Err() == nil
+	if started {
+		s.callbacks.Add(1)
+	}
+	s.mutex.RUnlock()
+
+	if baseAddress == "" {
+		s.logger.Warnf("Cannot notify device service %s of %s of device %s: service has no base address", device.ServiceName, action, device.Name)
+		return
+	}
+	if !started {
+		s.logger.Warnf("Cannot notify device service %s of %s of device %s: service is shutting down", device.ServiceName, action, device.Name)
+		return
+	}
+
+	change := DeviceChange{
+		Action:     action,
+		DeviceName: device.Name,
+		Timestamp:  s.clock.Now().UnixNano() / int64(time.Millisecond),
+	}
+	address := strings.TrimSuffix(baseAddress, "/") + common.ApiDeviceCallbackRoute
+	correlationID := bootstrap.CorrelationIDFromContext(ctx)
+
+	go func() {
+		defer s.callbacks.Done()
+
+		body, err := json.Marshal(change)
+		if err != nil {
+			s.logger.Errorf("Failed to marshal %s callback for device %s: %v", action, device.Name, err)
+			return
+		}
+		req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader(body))
+		if err != nil {
+			s.logger.Errorf("Failed to create %s callback for device %s: %v", action, device.Name, err)
+			return
+		}
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+		if correlationID != "" {
+			req.Header.Set(common.CorrelationHeader, correlationID)
+		}
+
+		// The request that made the change may be over by now, so the callback runs under the service's context
+		resp, err := s.callbackClient.Do(serviceCtx, req)
+		if httpclient.KindOf(err) == httpclient.KindStatus {
+			s.logger.Warnf("Device service %s rejected %s callback for device %s: %v", device.ServiceName, action, device.Name, err)
+			return
+		}
+		if err != nil {
+			s.logger.Warnf("Failed to notify device service %s of %s of device %s: %v", device.ServiceName, action, device.Name, err)
+			return
+		}
+		resp.Body.Close()
+		s.logger.Debugf("Notified device service %s of %s of device %s", device.ServiceName, action, device.Name)
+	}()
+}
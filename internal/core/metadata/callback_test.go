@@ -0,0 +1,233 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// callbackRequest is a device change callback as received by a fake device service
+type callbackRequest struct {
+	path          string
+	correlationID string
+	change        DeviceChange
+}
+
+// newFakeDeviceService starts a device service that forwards every callback it receives
+func newFakeDeviceService(t *testing.T) (*httptest.Server, chan callbackRequest) {
+	received := make(chan callbackRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var change DeviceChange
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&change))
+		received <- callbackRequest{path: r.URL.Path, correlationID: r.Header.Get(common.CorrelationHeader), change: change}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+// awaitCallback returns the next callback, failing the test if none arrives in time
+func awaitCallback(t *testing.T, received chan callbackRequest) callbackRequest {
+	t.Helper()
+	select {
+	case request := <-received:
+		return request
+	case <-time.After(5 * time.Second):
+		t.Fatal("device service received no callback")
+		return callbackRequest{}
+	}
+}
+
+func deviceRequest(t *testing.T, router *mux.Router, method, path string, device models.Device) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(device)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	req.Header.Set(common.CorrelationHeader, "correlation-1")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCoreMetadataService_DeviceCallbacks(t *testing.T) {
+	server, received := newFakeDeviceService(t)
+	clock := common.NewFakeClock(time.Now())
+	service := NewCoreMetadataService(logrus.New())
+	service.SetClock(clock)
+	service.deviceServices["ds-1"] = models.DeviceService{Id: "ds-1", Name: "device-virtual", BaseAddress: server.URL + "/"}
+	router := mux.NewRouter()
+	router.Use(bootstrap.CorrelationIDMiddleware)
+	service.AddRoutes(router)
+
+	device := models.Device{Name: "Thermostat-1", ServiceName: "device-virtual", ProfileName: "Thermostat", Notify: true}
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, device)
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	request := awaitCallback(t, received)
+	assert.Equal(t, common.ApiDeviceCallbackRoute, request.path)
+	assert.Equal(t, "correlation-1", request.correlationID)
+	assert.Equal(t, DeviceActionAdd, request.change.Action)
+	assert.Equal(t, "Thermostat-1", request.change.DeviceName)
+	assert.Equal(t, clock.Now().UnixNano()/int64(time.Millisecond), request.change.Timestamp)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	id := created["id"].(string)
+
+	device.Version = 1
+	rr = deviceRequest(t, router, "PUT", "/api/v3/device/id/"+id, device)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, DeviceActionUpdate, awaitCallback(t, received).change.Action)
+
+	rr = deviceRequest(t, router, "DELETE", "/api/v3/device/id/"+id, models.Device{})
+	require.Equal(t, http.StatusOK, rr.Code)
+	request = awaitCallback(t, received)
+	assert.Equal(t, DeviceActionDelete, request.change.Action)
+	assert.Equal(t, "Thermostat-1", request.change.DeviceName)
+}
+
+func TestCoreMetadataService_DeviceCallbackSkipped(t *testing.T) {
+	server, received := newFakeDeviceService(t)
+
+	tests := []struct {
+		name   string
+		device models.Device
+	}{
+		{"Notify not set", models.Device{Name: "Quiet-1", ServiceName: "device-virtual"}},
+		{"Unknown device service", models.Device{Name: "Orphan-1", ServiceName: "device-missing", Notify: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCoreMetadataService(logrus.New())
+			service.deviceServices["ds-1"] = models.DeviceService{Id: "ds-1", Name: "device-virtual", BaseAddress: server.URL}
+			router := mux.NewRouter()
+			service.AddRoutes(router)
+
+			rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, tt.device)
+			require.Equal(t, http.StatusCreated, rr.Code)
+
+			select {
+			case request := <-received:
+				t.Fatalf("unexpected callback %+v", request.change)
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestCoreMetadataService_DeviceCallbackUnreachable(t *testing.T) {
+	server, _ := newFakeDeviceService(t)
+	address := server.URL
+	server.Close()
+
+	service := NewCoreMetadataService(logrus.New())
+	service.deviceServices["ds-1"] = models.DeviceService{Id: "ds-1", Name: "device-virtual", BaseAddress: address}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	// The device is stored even though its service cannot be told
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1", ServiceName: "device-virtual", Notify: true})
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Len(t, service.devices, 1)
+}
+
+func TestCoreMetadataService_DeviceCallbackRetried(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan DeviceChange, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var change DeviceChange
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&change))
+		received <- change
+	}))
+	defer server.Close()
+
+	service := NewCoreMetadataService(logrus.New())
+	service.deviceServices["ds-1"] = models.DeviceService{Id: "ds-1", Name: "device-virtual", BaseAddress: server.URL}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1", ServiceName: "device-virtual", Notify: true})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	// The device service was briefly unavailable, so the callback is sent again
+	select {
+	case change := <-received:
+		assert.Equal(t, DeviceActionAdd, change.Action)
+		assert.Equal(t, "Thermostat-1", change.DeviceName)
+	case <-time.After(5 * time.Second):
+		t.Fatal("device service received no callback")
+	}
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestCoreMetadataService_DeviceCallbackShutdown(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the callback until the caller gives up on it. The body is read first, since only then
+		// does the server notice the connection closing.
+		io.Copy(io.Discard, r.Body)
+		arrived <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	service := NewCoreMetadataService(logrus.New())
+	service.deviceServices["ds-1"] = models.DeviceService{Id: "ds-1", Name: "device-virtual", BaseAddress: server.URL}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1", ServiceName: "device-virtual", Notify: true})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	select {
+	case <-arrived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("device service received no callback")
+	}
+
+	// Shutdown cancels the callback in flight and waits for it to return
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not wait for the callback to return")
+	}
+
+	// No callback is started once the service is shutting down
+	rr = deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-2", ServiceName: "device-virtual", Notify: true})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	select {
+	case <-arrived:
+		t.Fatal("callback sent after shutdown")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
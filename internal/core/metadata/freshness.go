@@ -0,0 +1,84 @@
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// reportDeviceReading records that the device just reported readings, as Core Data does for the devices
+// whose events it stores when CORE_DATA_METADATA_URL is set. Like contact, this only moves LastReported
+// and leaves the device's version alone.
+func (s *CoreMetadataService) reportDeviceReading(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	name := mux.Vars(r)["name"]
+	timestamp := s.clock.Now().UnixNano() / int64(time.Millisecond)
+
+	s.mutex.Lock()
+	id, exists := s.deviceIdByNameLocked(name)
+	if exists {
+		device := s.devices[id]
+		device.LastReported = timestamp
+		s.devices[id] = device
+	}
+	s.mutex.Unlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"apiVersion":   common.ServiceVersion,
+		"statusCode":   http.StatusOK,
+		"lastReported": timestamp,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// getStaleDevices handles GET /api/v3/device/stale?threshold={ms}, listing the devices that have not
+// reported for longer than threshold, or ever, stalest first
+func (s *CoreMetadataService) getStaleDevices(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	threshold, err := strconv.ParseInt(r.URL.Query().Get("threshold"), 10, 64)
+	if err != nil || threshold < 0 {
+		common.WriteError(w, http.StatusBadRequest, "Threshold must be a non-negative number of milliseconds")
+		return
+	}
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cutoff := s.clock.Now().UnixNano()/int64(time.Millisecond) - threshold
+
+	s.mutex.RLock()
+	devices := make([]models.Device, 0)
+	for _, device := range s.devices {
+		if device.LastReported == 0 || device.LastReported < cutoff {
+			devices = append(devices, device)
+		}
+	}
+	s.mutex.RUnlock()
+	sortDevicesByLastReported(devices)
+	page, totalCount := common.Paginate(devices, offset, limit)
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"totalCount": totalCount,
+		"devices":    page,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,82 @@
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+func TestCoreMetadataService_StaleDevices(t *testing.T) {
+	clock := common.NewFakeClock(time.Now())
+	service := NewCoreMetadataService(logrus.New())
+	service.SetClock(clock)
+	for _, name := range []string{"Never", "Old", "Older", "Borderline", "Fresh"} {
+		seedDevice(service, name, clock.Now())
+	}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	report := func(name string) {
+		code, _ := putRequest(t, router, "/api/v3/device/name/"+name+"/lastreported")
+		require.Equal(t, http.StatusOK, code, name)
+	}
+	report("Older")
+	clock.Advance(time.Minute)
+	report("Old")
+	clock.Advance(4 * time.Minute)
+	report("Borderline")
+	clock.Advance(time.Minute)
+	report("Fresh")
+	assert.Equal(t, clock.Now().UnixNano()/int64(time.Millisecond), deviceByName(t, service, "Fresh").LastReported)
+
+	staleNames := func(query string) (int, []string) {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v3/device/stale"+query, nil))
+		if rr.Code != http.StatusOK {
+			return rr.Code, nil
+		}
+		var response struct {
+			TotalCount int             `json:"totalCount"`
+			Devices    []models.Device `json:"devices"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		names := make([]string, 0, len(response.Devices))
+		for _, device := range response.Devices {
+			names = append(names, device.Name)
+		}
+		return rr.Code, names
+	}
+
+	// Borderline reported exactly a minute ago, which is not older than the threshold
+	code, names := staleNames("?threshold=60000")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"Never", "Older", "Old"}, names)
+
+	_, names = staleNames("?threshold=0")
+	assert.Equal(t, []string{"Never", "Older", "Old", "Borderline"}, names)
+
+	_, names = staleNames("?threshold=3600000")
+	assert.Equal(t, []string{"Never"}, names)
+
+	_, names = staleNames("?threshold=60000&offset=1&limit=1")
+	assert.Equal(t, []string{"Older"}, names)
+
+	for _, query := range []string{"", "?threshold=soon", "?threshold=-1"} {
+		code, _ := staleNames(query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	code, _ = putRequest(t, router, "/api/v3/device/name/Missing/lastreported")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, int64(1), deviceByName(t, service, "Fresh").Version)
+}
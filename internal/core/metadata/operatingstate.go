@@ -0,0 +1,173 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// DefaultSweepInterval is how often devices are checked for lost contact once a down threshold is set
+const DefaultSweepInterval = time.Minute
+
+// SetClock sets the clock used for contact times and the sweeper. Must be called before Initialize.
+func (s *CoreMetadataService) SetClock(clock common.Clock) {
+	s.clock = clock
+}
+
+// SetOperatingStateSweep configures how often the sweeper runs and how long a device may go without
+// contact before it is marked DOWN. A zero interval or threshold disables the sweeper. Must be called
+// before Initialize.
+func (s *CoreMetadataService) SetOperatingStateSweep(interval, downAfter time.Duration) {
+	s.sweepInterval = interval
+	s.downAfter = downAfter
+}
+
+// runSweeper periodically marks UP devices DOWN once their last contact is older than the threshold
+func (s *CoreMetadataService) runSweeper(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.sweepInterval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Operating state sweeper started: interval %v, down after %v", s.sweepInterval, s.downAfter)
+
+	for {
+		select {
+		case <-ticker.C():
+			for _, device := range s.sweepDevices(s.clock.Now()) {
+				s.logger.Warnf("Device %s has not made contact for %v, marked %s", device.Name, s.downAfter, common.Down)
+				s.notifyDeviceChange(ctx, device, DeviceActionUpdate)
+			}
+		case <-ctx.Done():
+			s.logger.Info("Operating state sweeper stopped")
+			return
+		}
+	}
+}
+
+// sweepDevices marks DOWN every UP device whose last contact, or creation if it never made contact,
+// is older than the threshold at now. Returns the devices that changed.
+func (s *CoreMetadataService) sweepDevices(now time.Time) []models.Device {
+	cutoff := now.Add(-s.downAfter).UnixNano() / int64(time.Millisecond)
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var changed []models.Device
+	for id, device := range s.devices {
+		lastContact := device.LastConnected
+		if lastContact == 0 {
+			lastContact = device.Created
+		}
+		if device.OperatingState != common.Up || lastContact >= cutoff {
+			continue
+		}
+		setOperatingState(&device, common.Down, timestamp)
+		s.devices[id] = device
+		changed = append(changed, device)
+	}
+	return changed
+}
+
+// setOperatingState changes device's operating state as an update, so its version moves on
+func setOperatingState(device *models.Device, state string, timestamp int64) {
+	device.OperatingState = state
+	device.Version++
+	device.Modified = timestamp
+}
+
+// deviceIdByNameLocked returns the id of the device called name. Caller must hold the lock.
+func (s *CoreMetadataService) deviceIdByNameLocked(name string) (string, bool) {
+	for id, device := range s.devices {
+		if device.Name == name {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// reportDeviceContact records that a service just reached the device, bringing it back UP if it was
+// DOWN. Contact alone only moves LastConnected; the device's version changes with its state.
+func (s *CoreMetadataService) reportDeviceContact(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	name := mux.Vars(r)["name"]
+	timestamp := s.clock.Now().UnixNano() / int64(time.Millisecond)
+
+	s.mutex.Lock()
+	id, exists := s.deviceIdByNameLocked(name)
+	device := s.devices[id]
+	recovered := exists && device.OperatingState == common.Down
+	if exists {
+		device.LastConnected = timestamp
+		if recovered {
+			setOperatingState(&device, common.Up, timestamp)
+		}
+		s.devices[id] = device
+	}
+	s.mutex.Unlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+	if recovered {
+		s.logger.Infof("Device %s made contact again, marked %s", device.Name, common.Up)
+		s.notifyDeviceChange(r.Context(), device, DeviceActionUpdate)
+	}
+
+	response := map[string]interface{}{
+		"apiVersion":     common.ServiceVersion,
+		"statusCode":     http.StatusOK,
+		"operatingState": device.OperatingState,
+		"lastConnected":  device.LastConnected,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// setDeviceOperatingState overrides a device's operating state by hand
+func (s *CoreMetadataService) setDeviceOperatingState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	state := strings.ToUpper(vars["state"])
+	if state != common.Up && state != common.Down {
+		common.WriteError(w, http.StatusBadRequest, "Operating state must be "+common.Up+" or "+common.Down)
+		return
+	}
+
+	s.mutex.Lock()
+	id, exists := s.deviceIdByNameLocked(vars["name"])
+	device := s.devices[id]
+	changed := exists && device.OperatingState != state
+	if changed {
+		setOperatingState(&device, state, s.clock.Now().UnixNano()/int64(time.Millisecond))
+		s.devices[id] = device
+	}
+	s.mutex.Unlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+	if changed {
+		s.logger.Infof("Device %s marked %s", device.Name, state)
+		s.notifyDeviceChange(r.Context(), device, DeviceActionUpdate)
+	}
+
+	response := map[string]interface{}{
+		"apiVersion":     common.ServiceVersion,
+		"statusCode":     http.StatusOK,
+		"operatingState": device.OperatingState,
+		"version":        device.Version,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,158 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// seedDevice stores an UP device created at created
+func seedDevice(service *CoreMetadataService, name string, created time.Time) {
+	id := models.GenerateUUID()
+	service.devices[id] = models.Device{
+		Id:             id,
+		Name:           name,
+		AdminState:     common.Unlocked,
+		OperatingState: common.Up,
+		Version:        1,
+		Created:        created.UnixNano() / int64(time.Millisecond),
+	}
+}
+
+// deviceByName returns the stored device called name
+func deviceByName(t *testing.T, service *CoreMetadataService, name string) models.Device {
+	t.Helper()
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	id, exists := service.deviceIdByNameLocked(name)
+	require.True(t, exists, name)
+	return service.devices[id]
+}
+
+// putRequest sends a PUT without a body and decodes the JSON response
+func putRequest(t *testing.T, router *mux.Router, path string) (int, map[string]interface{}) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, path, nil))
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr.Code, response
+}
+
+func TestCoreMetadataService_OperatingStateSweeper(t *testing.T) {
+	clock := common.NewFakeClock(time.Now())
+	service := NewCoreMetadataService(logrus.New())
+	service.SetClock(clock)
+	service.SetOperatingStateSweep(time.Minute, 5*time.Minute)
+	seedDevice(service, "Quiet", clock.Now())
+	seedDevice(service, "Chatty", clock.Now())
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	state := func(name string) func() string {
+		return func() string { return deviceByName(t, service, name).OperatingState }
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(3 * time.Minute)
+	code, _ := putRequest(t, router, "/api/v3/device/name/Chatty/lastconnected")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, common.Up, state("Chatty")())
+
+	// Quiet never made contact and is now past the threshold since it was created
+	clock.Advance(3 * time.Minute)
+	assert.Eventually(t, func() bool { return state("Quiet")() == common.Down }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, common.Up, state("Chatty")())
+	assert.Equal(t, int64(2), deviceByName(t, service, "Quiet").Version)
+	assert.Equal(t, int64(1), deviceByName(t, service, "Chatty").Version)
+
+	// New contact brings it back up
+	code, response := putRequest(t, router, "/api/v3/device/name/Quiet/lastconnected")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, common.Up, response["operatingState"])
+	quiet := deviceByName(t, service, "Quiet")
+	assert.Equal(t, common.Up, quiet.OperatingState)
+	assert.Equal(t, int64(3), quiet.Version)
+	assert.Equal(t, clock.Now().UnixNano()/int64(time.Millisecond), quiet.LastConnected)
+
+	// Chatty's contact is now too old, while Quiet's is recent
+	clock.Advance(3 * time.Minute)
+	assert.Eventually(t, func() bool { return state("Chatty")() == common.Down }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, common.Up, state("Quiet")())
+
+	code, _ = putRequest(t, router, "/api/v3/device/name/Missing/lastconnected")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	cancel()
+	wg.Wait()
+}
+
+func TestCoreMetadataService_OperatingStateSweeperDisabled(t *testing.T) {
+	clock := common.NewFakeClock(time.Now())
+	service := NewCoreMetadataService(logrus.New())
+	service.SetClock(clock)
+	seedDevice(service, "Quiet", clock.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	assert.Equal(t, 0, clock.Waiters())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, common.Up, deviceByName(t, service, "Quiet").OperatingState)
+
+	cancel()
+	wg.Wait()
+}
+
+func TestCoreMetadataService_SetDeviceOperatingState(t *testing.T) {
+	service := NewCoreMetadataService(logrus.New())
+	seedDevice(service, "TestDevice", time.Now())
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedCode    int
+		expectedState   string
+		expectedVersion int64
+	}{
+		{"Mark down", "/api/v3/device/name/TestDevice/operatingstate/DOWN", http.StatusOK, common.Down, 2},
+		{"Unchanged", "/api/v3/device/name/TestDevice/operatingstate/DOWN", http.StatusOK, common.Down, 2},
+		{"Lower case", "/api/v3/device/name/TestDevice/operatingstate/up", http.StatusOK, common.Up, 3},
+		{"Invalid state", "/api/v3/device/name/TestDevice/operatingstate/SIDEWAYS", http.StatusBadRequest, common.Up, 3},
+		{"Unknown device", "/api/v3/device/name/Missing/operatingstate/DOWN", http.StatusNotFound, common.Up, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, response := putRequest(t, router, tt.path)
+			assert.Equal(t, tt.expectedCode, code)
+			if code == http.StatusOK {
+				assert.Equal(t, tt.expectedState, response["operatingState"])
+				assert.Equal(t, float64(tt.expectedVersion), response["version"])
+			}
+
+			device := deviceByName(t, service, "TestDevice")
+			assert.Equal(t, tt.expectedState, device.OperatingState)
+			assert.Equal(t, tt.expectedVersion, device.Version)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"sort"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// sortDevicesByCreated orders devices newest first, tie-broken by id
+func sortDevicesByCreated(devices []models.Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		return common.NewestFirst(devices[i].Created, devices[i].Id, devices[j].Created, devices[j].Id)
+	})
+}
+
+// sortDevicesByLastReported orders devices stalest first, those that never reported leading, tie-broken by id
+func sortDevicesByLastReported(devices []models.Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].LastReported != devices[j].LastReported {
+			return devices[i].LastReported < devices[j].LastReported
+		}
+		return devices[i].Id < devices[j].Id
+	})
+}
+
+// sortDeviceProfilesByCreated orders device profiles newest first, tie-broken by id
+func sortDeviceProfilesByCreated(profiles []models.DeviceProfile) {
+	sort.Slice(profiles, func(i, j int) bool {
+		return common.NewestFirst(profiles[i].Created, profiles[i].Id, profiles[j].Created, profiles[j].Id)
+	})
+}
+
+// sortDeviceServicesByCreated orders device services newest first, tie-broken by id
+func sortDeviceServicesByCreated(services []models.DeviceService) {
+	sort.Slice(services, func(i, j int) bool {
+		return common.NewestFirst(services[i].Created, services[i].Id, services[j].Created, services[j].Id)
+	})
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/clients/httpclient"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
 )
@@ -21,6 +23,13 @@ type CoreMetadataService struct {
 	devices        map[string]models.Device
 	deviceProfiles map[string]models.DeviceProfile
 	deviceServices map[string]models.DeviceService
+	adminOnly      mux.MiddlewareFunc
+	callbackClient *httpclient.Client
+	sweepInterval  time.Duration
+	downAfter      time.Duration
+	clock          common.Clock
+	ctx            context.Context // Device change callbacks run under it, the service's once Initialize has run
+	callbacks      sync.WaitGroup  // Device change callbacks in flight
 	mutex          sync.RWMutex
 }
 
@@ -31,9 +40,20 @@ func NewCoreMetadataService(logger *logrus.Logger) *CoreMetadataService {
 		devices:        make(map[string]models.Device),
 		deviceProfiles: make(map[string]models.DeviceProfile),
 		deviceServices: make(map[string]models.DeviceService),
+		adminOnly:      func(next http.Handler) http.Handler { return next },
+		callbackClient: newCallbackClient(),
+		sweepInterval:  DefaultSweepInterval,
+		clock:          common.RealClock{},
+		ctx:            context.Background(),
 	}
 }
 
+// RequireAdminForDeletes restricts deleting devices to callers with the admin role.
+// The router must authenticate requests with bootstrap.JWTAuth. Must be called before AddRoutes.
+func (s *CoreMetadataService) RequireAdminForDeletes() {
+	s.adminOnly = bootstrap.RequireRole(bootstrap.RoleAdmin)
+}
+
 // Initialize implements the BootstrapHandler interface
 func (s *CoreMetadataService) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *bootstrap.DIContainer) bool {
 	s.logger.Info("Initializing Core Metadata Service")
@@ -41,6 +61,29 @@ func (s *CoreMetadataService) Initialize(ctx context.Context, wg *sync.WaitGroup
 	// Add service to DI container
 	dic.Add("CoreMetadataService", s)
 	
+	// Send device change callbacks under the service's context, and have shutdown wait for those in flight
+	s.mutex.Lock()
+	s.ctx = ctx
+	s.mutex.Unlock()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		// Callbacks are counted under the lock, so none starts after this
+		s.mutex.Lock()
+		s.mutex.Unlock()
+		s.callbacks.Wait()
+	}()
+	
+	// Mark devices that stop making contact as down until shutdown
+	if s.sweepInterval > 0 && s.downAfter > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runSweeper(ctx)
+		}()
+	}
+	
 	s.logger.Info("Core Metadata Service initialization completed")
 	return true
 }
@@ -53,7 +96,13 @@ func (s *CoreMetadataService) AddRoutes(router *mux.Router) {
 	router.HandleFunc(common.ApiDeviceByIdRoute, s.getDeviceById).Methods("GET")
 	router.HandleFunc(common.ApiDeviceByNameRoute, s.getDeviceByName).Methods("GET")
 	router.HandleFunc(common.ApiDeviceByIdRoute, s.updateDevice).Methods("PUT")
-	router.HandleFunc(common.ApiDeviceByIdRoute, s.deleteDevice).Methods("DELETE")
+	router.Handle(common.ApiDeviceByIdRoute, s.adminOnly(http.HandlerFunc(s.deleteDevice))).Methods("DELETE")
+	router.HandleFunc(common.ApiDeviceLastConnectedRoute, s.reportDeviceContact).Methods("PUT")
+	router.HandleFunc(common.ApiDeviceOperatingStateRoute, s.setDeviceOperatingState).Methods("PUT")
+	router.HandleFunc(common.ApiDeviceLastReportedRoute, s.reportDeviceReading).Methods("PUT")
+	router.HandleFunc(common.ApiDeviceStaleRoute, s.getStaleDevices).Methods("GET")
+	router.HandleFunc(common.ApiDeviceImportRoute, s.importDevices).Methods("POST")
+	router.HandleFunc(common.ApiDeviceExportRoute, s.exportDevices).Methods("GET")
 
 	// Device Profile routes
 	router.HandleFunc(common.ApiDeviceProfileRoute, s.addDeviceProfile).Methods("POST")
@@ -70,35 +119,45 @@ func (s *CoreMetadataService) AddRoutes(router *mux.Router) {
 	s.logger.Info("Core Metadata routes registered")
 }
 
+// initializeDevice sets the timestamps and version of a new device, and defaults its states
+func initializeDevice(device *models.Device, created int64) {
+	device.Created = created
+	device.Modified = created
+	device.Version = 1
+	if device.AdminState == "" {
+		device.AdminState = common.Unlocked
+	}
+	if device.OperatingState == "" {
+		device.OperatingState = common.Up
+	}
+}
+
 // Device handlers
 func (s *CoreMetadataService) addDevice(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var device models.Device
-	if err := json.NewDecoder(r.Body).Decode(&device); err != nil {
+	if err := common.DecodeJSON(w, r, &device); err != nil {
 		s.logger.Errorf("Failed to decode device: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
-	// Generate ID and timestamps
+	// Generate ID and timestamps, and set defaults
 	device.Id = models.GenerateUUID()
-	device.Created = time.Now().UnixNano() / int64(time.Millisecond)
-	device.Modified = device.Created
-	
-	// Set defaults
-	if device.AdminState == "" {
-		device.AdminState = common.Unlocked
-	}
-	if device.OperatingState == "" {
-		device.OperatingState = common.Up
-	}
+	initializeDevice(&device, s.clock.Now().UnixNano()/int64(time.Millisecond))
 	
+	// Names are unique, as on import
 	s.mutex.Lock()
+	if _, exists := s.deviceIdByNameLocked(device.Name); exists {
+		s.mutex.Unlock()
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("device %s already exists", device.Name))
+		return
+	}
 	s.devices[device.Id] = device
 	s.mutex.Unlock()
 	
 	s.logger.Infof("Device created: %s", device.Name)
+	s.notifyDeviceChange(r.Context(), device, DeviceActionAdd)
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
@@ -113,18 +172,26 @@ func (s *CoreMetadataService) addDevice(w http.ResponseWriter, r *http.Request)
 func (s *CoreMetadataService) getAllDevices(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	devices := make([]models.Device, 0, len(s.devices))
 	for _, device := range s.devices {
 		devices = append(devices, device)
 	}
 	s.mutex.RUnlock()
+	sortDevicesByCreated(devices)
+	page, totalCount := common.Paginate(devices, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":  common.ServiceVersion,
 		"statusCode":  http.StatusOK,
-		"totalCount":  len(devices),
-		"devices":     devices,
+		"totalCount":  totalCount,
+		"devices":     page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -141,7 +208,7 @@ func (s *CoreMetadataService) getDeviceById(w http.ResponseWriter, r *http.Reque
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Device not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device not found")
 		return
 	}
 	
@@ -171,7 +238,7 @@ func (s *CoreMetadataService) getDeviceByName(w http.ResponseWriter, r *http.Req
 	s.mutex.RUnlock()
 	
 	if foundDevice == nil {
-		http.Error(w, "Device not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device not found")
 		return
 	}
 	
@@ -191,15 +258,16 @@ func (s *CoreMetadataService) updateDevice(w http.ResponseWriter, r *http.Reques
 	id := vars["id"]
 	
 	var updatedDevice models.Device
-	if err := json.NewDecoder(r.Body).Decode(&updatedDevice); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &updatedDevice); err != nil {
 		return
 	}
 	
 	s.mutex.Lock()
 	existingDevice, exists := s.devices[id]
-	if exists {
+	stale := exists && updatedDevice.Version != existingDevice.Version
+	if exists && !stale {
 		updatedDevice.Id = id
+		updatedDevice.Version = existingDevice.Version + 1
 		updatedDevice.Created = existingDevice.Created
 		updatedDevice.Modified = time.Now().UnixNano() / int64(time.Millisecond)
 		s.devices[id] = updatedDevice
@@ -207,14 +275,21 @@ func (s *CoreMetadataService) updateDevice(w http.ResponseWriter, r *http.Reques
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Device not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device not found")
+		return
+	}
+	if stale {
+		common.WriteVersionConflict(w, "Device", updatedDevice.Version, existingDevice.Version)
 		return
 	}
 	
+	s.notifyDeviceChange(r.Context(), updatedDevice, DeviceActionUpdate)
+	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
 		"message":    "Device updated successfully",
+		"version":    updatedDevice.Version,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -227,17 +302,19 @@ func (s *CoreMetadataService) deleteDevice(w http.ResponseWriter, r *http.Reques
 	id := vars["id"]
 	
 	s.mutex.Lock()
-	_, exists := s.devices[id]
+	device, exists := s.devices[id]
 	if exists {
 		delete(s.devices, id)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Device not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device not found")
 		return
 	}
 	
+	s.notifyDeviceChange(r.Context(), device, DeviceActionDelete)
+	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
@@ -252,8 +329,12 @@ func (s *CoreMetadataService) addDeviceProfile(w http.ResponseWriter, r *http.Re
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var profile models.DeviceProfile
-	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &profile); err != nil {
+		return
+	}
+	
+	if problems := validateDeviceProfile(profile); len(problems) > 0 {
+		common.WriteValidationErrors(w, "Invalid device profile", problems)
 		return
 	}
 	
@@ -280,18 +361,26 @@ func (s *CoreMetadataService) addDeviceProfile(w http.ResponseWriter, r *http.Re
 func (s *CoreMetadataService) getAllDeviceProfiles(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	profiles := make([]models.DeviceProfile, 0, len(s.deviceProfiles))
 	for _, profile := range s.deviceProfiles {
 		profiles = append(profiles, profile)
 	}
 	s.mutex.RUnlock()
+	sortDeviceProfilesByCreated(profiles)
+	page, totalCount := common.Paginate(profiles, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":     common.ServiceVersion,
 		"statusCode":     http.StatusOK,
-		"totalCount":     len(profiles),
-		"deviceProfiles": profiles,
+		"totalCount":     totalCount,
+		"deviceProfiles": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -308,7 +397,7 @@ func (s *CoreMetadataService) getDeviceProfileById(w http.ResponseWriter, r *htt
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Device profile not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device profile not found")
 		return
 	}
 	
@@ -338,7 +427,7 @@ func (s *CoreMetadataService) getDeviceProfileByName(w http.ResponseWriter, r *h
 	s.mutex.RUnlock()
 	
 	if foundProfile == nil {
-		http.Error(w, "Device profile not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device profile not found")
 		return
 	}
 	
@@ -356,8 +445,7 @@ func (s *CoreMetadataService) addDeviceService(w http.ResponseWriter, r *http.Re
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var deviceService models.DeviceService
-	if err := json.NewDecoder(r.Body).Decode(&deviceService); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &deviceService); err != nil {
 		return
 	}
 	
@@ -391,18 +479,26 @@ func (s *CoreMetadataService) addDeviceService(w http.ResponseWriter, r *http.Re
 func (s *CoreMetadataService) getAllDeviceServices(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	services := make([]models.DeviceService, 0, len(s.deviceServices))
 	for _, service := range s.deviceServices {
 		services = append(services, service)
 	}
 	s.mutex.RUnlock()
+	sortDeviceServicesByCreated(services)
+	page, totalCount := common.Paginate(services, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":     common.ServiceVersion,
 		"statusCode":     http.StatusOK,
-		"totalCount":     len(services),
-		"deviceServices": services,
+		"totalCount":     totalCount,
+		"deviceServices": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -419,7 +515,7 @@ func (s *CoreMetadataService) getDeviceServiceById(w http.ResponseWriter, r *htt
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Device service not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device service not found")
 		return
 	}
 	
@@ -449,7 +545,7 @@ func (s *CoreMetadataService) getDeviceServiceByName(w http.ResponseWriter, r *h
 	s.mutex.RUnlock()
 	
 	if foundService == nil {
-		http.Error(w, "Device service not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Device service not found")
 		return
 	}
 	
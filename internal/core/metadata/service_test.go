@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -60,8 +61,8 @@ func TestCoreMetadataService_AddDevice(t *testing.T) {
 				ServiceName: "TestService",
 				Protocols: map[string]models.ProtocolProperties{
 					"modbus": {
-						"Address": "192.168.1.100",
-						"Port":    "502",
+						Address: "192.168.1.100",
+						Port:    "502",
 					},
 				},
 			},
@@ -233,6 +234,14 @@ func TestCoreMetadataService_GetDeviceById(t *testing.T) {
 				device := response["device"].(map[string]interface{})
 				assert.Equal(t, testDevice.Id, device["id"])
 				assert.Equal(t, testDevice.Name, device["name"])
+			} else {
+				// Errors use the same JSON envelope as successful responses
+				assert.Equal(t, common.ContentTypeJSON, rr.Header().Get(common.ContentType))
+				var response common.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, common.ServiceVersion, response.ApiVersion)
+				assert.Equal(t, tt.expectedCode, response.StatusCode)
+				assert.Equal(t, "Device not found", response.Message)
 			}
 		})
 	}
@@ -402,6 +411,52 @@ func TestCoreMetadataService_DeleteDevice(t *testing.T) {
 	}
 }
 
+func TestCoreMetadataService_DeleteDeviceRequiresAdmin(t *testing.T) {
+	logger := logrus.New()
+	service := NewCoreMetadataService(logger)
+	service.devices["test-device-id"] = models.Device{Id: "test-device-id", Name: "TestDevice"}
+	service.RequireAdminForDeletes()
+	
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	
+	// Without JWTAuth having authenticated the caller, deletes are refused
+	req, err := http.NewRequest("DELETE", "/api/v3/device/id/test-device-id", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, service.devices, "test-device-id")
+	
+	// Reads are unaffected
+	req, err = http.NewRequest("GET", "/api/v3/device/id/test-device-id", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCoreMetadataService_AddDeviceDuplicateName(t *testing.T) {
+	service := NewCoreMetadataService(logrus.New())
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	service.SetClock(clock)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1"})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, clock.Now().UnixNano()/int64(time.Millisecond), service.devices[created["id"].(string)].Created)
+
+	// A second device with the same name is refused, as on import
+	rr = deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1", Description: "twin"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Len(t, service.devices, 1)
+}
+
 func TestCoreMetadataService_AddDeviceProfile(t *testing.T) {
 	logger := logrus.New()
 	service := NewCoreMetadataService(logger)
@@ -411,18 +466,26 @@ func TestCoreMetadataService_AddDeviceProfile(t *testing.T) {
 		Description:  "Test device profile",
 		Manufacturer: "TestManufacturer",
 		Model:        "TestModel",
+		DeviceResources: []models.DeviceResource{
+			{
+				Name: "Temperature",
+				Properties: models.ResourceProperties{
+					ValueType: common.ValueTypeFloat64,
+					ReadWrite: common.ReadWriteR,
+				},
+			},
+		},
 		DeviceCommands: []models.DeviceCommand{
 			{
 				Name: "Temperature",
-				Get:  true,
-				Set:  false,
+				ReadWrite: "R",
 			},
 		},
-		CoreCommands: []models.CoreCommand{
+		CoreCommands: []models.Command{
 			{
 				Name: "Temperature",
 				Get:  true,
-				Set:  false,
+				Put:  false,
 			},
 		},
 	}
@@ -506,8 +569,8 @@ func BenchmarkCoreMetadataService_AddDevice(b *testing.B) {
 		ServiceName: "BenchmarkService",
 		Protocols: map[string]models.ProtocolProperties{
 			"modbus": {
-				"Address": "192.168.1.100",
-				"Port":    "502",
+				Address: "192.168.1.100",
+				Port:    "502",
 			},
 		},
 	}
@@ -541,7 +604,7 @@ func TestCoreMetadataService_ConcurrentDeviceOperations(t *testing.T) {
 			defer wg.Done()
 			
 			device := models.Device{
-				Name:        "ConcurrentDevice",
+				Name:        fmt.Sprintf("ConcurrentDevice-%d", id),
 				Description: "Concurrent test device",
 				ProfileName: "ConcurrentProfile",
 				ServiceName: "ConcurrentService",
@@ -562,4 +625,97 @@ func TestCoreMetadataService_ConcurrentDeviceOperations(t *testing.T) {
 	
 	// Verify all devices were added
 	assert.Equal(t, numGoroutines, len(service.devices))
-}
\ No newline at end of file
+}
+func TestCoreMetadataService_UpdateDeviceVersionConflict(t *testing.T) {
+	service := NewCoreMetadataService(logrus.New())
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := deviceRequest(t, router, "POST", common.ApiDeviceRoute, models.Device{Name: "Thermostat-1"})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	id := created["id"].(string)
+
+	// Two clients update from the same version; the second would overwrite the first
+	rr = deviceRequest(t, router, "PUT", "/api/v3/device/id/"+id, models.Device{Name: "Thermostat-1", Description: "first", Version: 1})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var updated map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(t, float64(2), updated["version"])
+
+	rr = deviceRequest(t, router, "PUT", "/api/v3/device/id/"+id, models.Device{Name: "Thermostat-1", Description: "second", Version: 1})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "version 1 was sent but the current version is 2")
+	assert.Equal(t, "first", service.devices[id].Description)
+	assert.Equal(t, int64(2), service.devices[id].Version)
+}
+
+func TestCoreMetadataService_GetAllDevicesPaginated(t *testing.T) {
+	service := NewCoreMetadataService(logrus.New())
+	for _, name := range []string{"a", "b", "c"} {
+		service.devices[name] = models.Device{Id: name, Name: name}
+	}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	tests := []struct {
+		name        string
+		query       string
+		expectCode  int
+		expectCount int
+	}{
+		{"Default page", "", http.StatusOK, 3},
+		{"Limited", "?limit=2", http.StatusOK, 2},
+		{"Offset past end", "?offset=5", http.StatusOK, 0},
+		{"Limit over max", "?limit=5000", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("GET", common.ApiDeviceRoute+"/all"+tt.query, nil))
+			require.Equal(t, tt.expectCode, rr.Code)
+			if tt.expectCode != http.StatusOK {
+				return
+			}
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, float64(3), response["totalCount"])
+			assert.Len(t, response["devices"], tt.expectCount)
+		})
+	}
+}
+
+func TestCoreMetadataService_ListOrder(t *testing.T) {
+	service := NewCoreMetadataService(logrus.New())
+	for id, created := range map[string]int64{"d-1": 1000, "d-2": 3000, "d-3": 2000, "d-0": 3000} {
+		service.devices[id] = models.Device{Id: id, Name: id, Created: created}
+		service.deviceProfiles[id] = models.DeviceProfile{Id: id, Name: id, Created: created}
+		service.deviceServices[id] = models.DeviceService{Id: id, Name: id, Created: created}
+	}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	// Newest first, ties broken by id, the same on every call
+	expected := []interface{}{"d-0", "d-2", "d-3", "d-1"}
+	lists := map[string]string{
+		common.ApiDeviceRoute + "/all":        "devices",
+		common.ApiDeviceProfileRoute + "/all": "deviceProfiles",
+		common.ApiDeviceServiceRoute + "/all": "deviceServices",
+	}
+	for path, key := range lists {
+		for i := 0; i < 5; i++ {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+			require.Equal(t, http.StatusOK, rr.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			var ids []interface{}
+			for _, item := range response[key].([]interface{}) {
+				ids = append(ids, item.(map[string]interface{})["id"])
+			}
+			assert.Equal(t, expected, ids, path)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// knownValueTypes are the value types a device resource may declare
+var knownValueTypes = map[string]bool{
+	common.ValueTypeBool:    true,
+	common.ValueTypeString:  true,
+	common.ValueTypeUint8:   true,
+	common.ValueTypeUint16:  true,
+	common.ValueTypeUint32:  true,
+	common.ValueTypeUint64:  true,
+	common.ValueTypeInt8:    true,
+	common.ValueTypeInt16:   true,
+	common.ValueTypeInt32:   true,
+	common.ValueTypeInt64:   true,
+	common.ValueTypeFloat32: true,
+	common.ValueTypeFloat64: true,
+	common.ValueTypeBinary:  true,
+}
+
+// knownReadWrite are the access modes a device resource may declare
+var knownReadWrite = map[string]bool{
+	common.ReadWriteR:  true,
+	common.ReadWriteW:  true,
+	common.ReadWriteRW: true,
+}
+
+// validateDeviceProfile checks the structure devices rely on and returns every problem found,
+// so a malformed profile is rejected when it is uploaded rather than when a device uses it
+func validateDeviceProfile(profile models.DeviceProfile) []string {
+	var problems []string
+	if profile.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if len(profile.DeviceResources) == 0 {
+		problems = append(problems, "deviceResources must contain at least one resource")
+	}
+
+	for i, resource := range profile.DeviceResources {
+		field := fmt.Sprintf("deviceResources[%d]", i)
+		if resource.Name == "" {
+			problems = append(problems, field+": name is required")
+		}
+		if !knownValueTypes[resource.Properties.ValueType] {
+			problems = append(problems, fmt.Sprintf("%s: unknown valueType %q", field, resource.Properties.ValueType))
+		}
+		if !knownReadWrite[resource.Properties.ReadWrite] {
+			problems = append(problems, fmt.Sprintf("%s: readWrite %q must be R, W or RW", field, resource.Properties.ReadWrite))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,115 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// testResource returns a device resource with the given properties
+func testResource(name, valueType, readWrite string) models.DeviceResource {
+	return models.DeviceResource{
+		Name:       name,
+		Properties: models.ResourceProperties{ValueType: valueType, ReadWrite: readWrite},
+	}
+}
+
+func TestCoreMetadataService_AddDeviceProfileValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  models.DeviceProfile
+		problems []string
+	}{
+		{
+			name: "Valid profile",
+			profile: models.DeviceProfile{
+				Name: "Thermostat",
+				DeviceResources: []models.DeviceResource{
+					testResource("Temperature", common.ValueTypeFloat64, common.ReadWriteR),
+					testResource("SetPoint", common.ValueTypeInt32, common.ReadWriteRW),
+					testResource("Reset", common.ValueTypeBool, common.ReadWriteW),
+				},
+			},
+		},
+		{
+			name:     "No resources",
+			profile:  models.DeviceProfile{Name: "Empty"},
+			problems: []string{"deviceResources must contain at least one resource"},
+		},
+		{
+			name: "Unknown value type",
+			profile: models.DeviceProfile{
+				Name:            "Thermostat",
+				DeviceResources: []models.DeviceResource{testResource("Temperature", "Double", common.ReadWriteR)},
+			},
+			problems: []string{`deviceResources[0]: unknown valueType "Double"`},
+		},
+		{
+			name: "Missing value type",
+			profile: models.DeviceProfile{
+				Name:            "Thermostat",
+				DeviceResources: []models.DeviceResource{testResource("Temperature", "", common.ReadWriteR)},
+			},
+			problems: []string{`deviceResources[0]: unknown valueType ""`},
+		},
+		{
+			name: "Invalid read write",
+			profile: models.DeviceProfile{
+				Name: "Thermostat",
+				DeviceResources: []models.DeviceResource{
+					testResource("Temperature", common.ValueTypeFloat64, common.ReadWriteR),
+					testResource("SetPoint", common.ValueTypeFloat64, "WR"),
+				},
+			},
+			problems: []string{`deviceResources[1]: readWrite "WR" must be R, W or RW`},
+		},
+		{
+			name: "Every problem is reported",
+			profile: models.DeviceProfile{
+				DeviceResources: []models.DeviceResource{testResource("", "Decimal", "")},
+			},
+			problems: []string{
+				"name is required",
+				"deviceResources[0]: name is required",
+				`deviceResources[0]: unknown valueType "Decimal"`,
+				`deviceResources[0]: readWrite "" must be R, W or RW`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCoreMetadataService(logrus.New())
+			body, err := json.Marshal(tt.profile)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "/api/v3/deviceprofile", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(service.addDeviceProfile).ServeHTTP(rr, req)
+
+			if tt.problems == nil {
+				assert.Equal(t, http.StatusCreated, rr.Code)
+				assert.Len(t, service.deviceProfiles, 1)
+				return
+			}
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			var response common.ErrorResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+			assert.Equal(t, "Invalid device profile", response.Message)
+			assert.Equal(t, tt.problems, response.Errors)
+			assert.Empty(t, service.deviceProfiles)
+		})
+	}
+}
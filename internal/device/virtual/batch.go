@@ -0,0 +1,204 @@
+package virtual
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// DefaultMaxBatchSize is the most devices one batch or clone request may create unless SetMaxBatchSize overrides it
+const DefaultMaxBatchSize = 1000
+
+// BatchIndexPlaceholder is replaced by each device's number, from 1, in a batch's name pattern
+const BatchIndexPlaceholder = "{i}"
+
+// BatchRequest is the body of POST /api/v3/device/virtual/batch
+type BatchRequest struct {
+	Template    VirtualDevice `json:"template"`
+	Count       int           `json:"count"`
+	NamePattern string        `json:"namePattern"` // e.g. Virtual-Temp-{i}; the template's name followed by -{i} if empty
+}
+
+// SetMaxBatchSize caps how many devices one batch or clone request may create. Must be called before Initialize.
+func (s *DeviceVirtualService) SetMaxBatchSize(max int) {
+	if max <= 0 {
+		max = DefaultMaxBatchSize
+	}
+	s.maxBatchSize = max
+}
+
+// validateBatchCount checks that count devices may be created at once
+func (s *DeviceVirtualService) validateBatchCount(count int) error {
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1, got %d", count)
+	}
+	if count > s.maxBatchSize {
+		return fmt.Errorf("count %d exceeds the maximum of %d", count, s.maxBatchSize)
+	}
+	return nil
+}
+
+// copyDevice returns a new device configured like template, with its own maps and the given name
+func copyDevice(template VirtualDevice, name string) VirtualDevice {
+	device := template
+	device.Name = name
+	device.Protocols = copyStringMap(template.Protocols)
+	device.Generation = copyStringMap(template.Generation)
+	prepareNewDevice(&device)
+	return device
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
+
+// namesTakenLocked returns the set of device names in use. Caller must hold the lock.
+func (s *DeviceVirtualService) namesTakenLocked() map[string]bool {
+	taken := make(map[string]bool, len(s.virtualDevices))
+	for _, device := range s.virtualDevices {
+		taken[device.Name] = true
+	}
+	return taken
+}
+
+// addDevicesLocked stores new devices and returns their ids. Caller must hold the write lock.
+func (s *DeviceVirtualService) addDevicesLocked(devices []VirtualDevice) []string {
+	ids := make([]string, len(devices))
+	for i := range devices {
+		device := devices[i]
+		s.virtualDevices[device.Id] = &device
+		ids[i] = device.Id
+	}
+	return ids
+}
+
+// writeCreatedIds responds to a batch or clone request with the ids of the devices it created
+func writeCreatedIds(w http.ResponseWriter, ids []string) {
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusCreated,
+		"ids":        ids,
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// createVirtualDevices handles POST /api/v3/device/virtual/batch, creating Count devices from Template named
+// by NamePattern. Either all of them are created or, if a name is taken or the template is invalid, none.
+func (s *DeviceVirtualService) createVirtualDevices(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	var request BatchRequest
+	if err := common.DecodeJSON(w, r, &request); err != nil {
+		return
+	}
+	if err := s.validateBatchCount(request.Count); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pattern := request.NamePattern
+	if pattern == "" && request.Template.Name != "" {
+		pattern = request.Template.Name + "-" + BatchIndexPlaceholder
+	}
+	if !strings.Contains(pattern, BatchIndexPlaceholder) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("namePattern must contain %s, got %q", BatchIndexPlaceholder, pattern))
+		return
+	}
+	if _, err := generationInterval(&request.Template); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := newReadingGenerator(&request.Template); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	devices := make([]VirtualDevice, request.Count)
+	for i := range devices {
+		devices[i] = copyDevice(request.Template, strings.ReplaceAll(pattern, BatchIndexPlaceholder, strconv.Itoa(i+1)))
+	}
+
+	s.mutex.Lock()
+	taken := s.namesTakenLocked()
+	conflict := ""
+	for _, device := range devices {
+		if taken[device.Name] {
+			conflict = device.Name
+			break
+		}
+	}
+	var ids []string
+	if conflict == "" {
+		ids = s.addDevicesLocked(devices)
+	}
+	s.mutex.Unlock()
+
+	if conflict != "" {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Virtual device %s already exists", conflict))
+		return
+	}
+
+	s.logger.Infof("Created %d virtual devices named %s", len(ids), pattern)
+	writeCreatedIds(w, ids)
+}
+
+// cloneVirtualDevice handles POST /api/v3/device/virtual/{id}/clone?count=n, creating n copies of a device,
+// 1 by default. Clones are named after the device with the lowest numbers not already in use, e.g. Sensor-1.
+func (s *DeviceVirtualService) cloneVirtualDevice(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	count := 1
+	if value := r.URL.Query().Get("count"); value != "" {
+		var err error
+		if count, err = strconv.Atoi(value); err != nil {
+			common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("count must be an integer, got %q", value))
+			return
+		}
+	}
+	if err := s.validateBatchCount(count); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	source, exists := s.virtualDevices[id]
+	var ids []string
+	if exists {
+		taken := s.namesTakenLocked()
+		devices := make([]VirtualDevice, 0, count)
+		for i := 1; len(devices) < count; i++ {
+			name := fmt.Sprintf("%s-%d", source.Name, i)
+			if !taken[name] {
+				devices = append(devices, copyDevice(*source, name))
+			}
+		}
+		ids = s.addDevicesLocked(devices)
+	}
+	s.mutex.Unlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+		return
+	}
+
+	s.logger.Infof("Cloned virtual device %s %d times", id, len(ids))
+	writeCreatedIds(w, ids)
+}
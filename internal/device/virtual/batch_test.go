@@ -0,0 +1,128 @@
+package virtual
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// createdIds returns the ids listed by a batch or clone response
+func createdIds(t *testing.T, body []byte) []string {
+	var response struct {
+		Ids []string `json:"ids"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	return response.Ids
+}
+
+func TestDeviceVirtualService_CreateVirtualDevices(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+
+	body := `{"count":500,"namePattern":"Virtual-Temp-{i}","template":{"profileName":"TemperatureSensorProfile","interval":"1s","protocols":{"type":"temperature"},"generation":{"pattern":"sine"}}}`
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/batch", body)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	ids := createdIds(t, rr.Body.Bytes())
+	require.Len(t, ids, 500)
+
+	for i, id := range []string{ids[0], ids[499]} {
+		device := getDevice(t, service, id)
+		assert.Equal(t, []string{"Virtual-Temp-1", "Virtual-Temp-500"}[i], device.Name)
+		assert.Equal(t, "TemperatureSensorProfile", device.ProfileName)
+		assert.Equal(t, "1s", device.Interval)
+		assert.Equal(t, common.Unlocked, device.AdminState)
+		assert.Equal(t, common.DeviceVirtualServiceKey, device.ServiceName)
+		assert.Equal(t, map[string]string{"pattern": "sine"}, device.Generation)
+		assert.False(t, device.IsRunning)
+	}
+
+	// Each device has its own maps
+	service.virtualDevices[ids[0]].Protocols["type"] = "door"
+	assert.Equal(t, "temperature", service.virtualDevices[ids[1]].Protocols["type"])
+
+	// Without a pattern, devices are numbered after the template
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/batch", `{"count":2,"template":{"name":"Meter","protocols":{"type":"counter"}}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	ids = createdIds(t, rr.Body.Bytes())
+	assert.Equal(t, "Meter-1", getDevice(t, service, ids[0]).Name)
+	assert.Equal(t, "Meter-2", getDevice(t, service, ids[1]).Name)
+	assert.Len(t, service.virtualDevices, 505)
+}
+
+func TestDeviceVirtualService_CreateVirtualDevicesInvalid(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	service.SetMaxBatchSize(10)
+	before := len(service.virtualDevices)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"Zero count", `{"count":0,"namePattern":"D-{i}"}`, http.StatusBadRequest},
+		{"Count over the cap", `{"count":11,"namePattern":"D-{i}"}`, http.StatusBadRequest},
+		{"Pattern without index", `{"count":2,"namePattern":"D"}`, http.StatusBadRequest},
+		{"No name at all", `{"count":2}`, http.StatusBadRequest},
+		{"Invalid interval", `{"count":2,"namePattern":"D-{i}","template":{"interval":"1ms"}}`, http.StatusBadRequest},
+		{"Invalid generation", `{"count":2,"namePattern":"D-{i}","template":{"protocols":{"type":"door"},"generation":{"probability":"2"}}}`, http.StatusBadRequest},
+		{"Name taken", `{"count":3,"namePattern":"Virtual-{i}","template":{}}`, http.StatusCreated},
+		{"Name taken again", `{"count":5,"namePattern":"Virtual-{i}","template":{}}`, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/batch", tt.body)
+			assert.Equal(t, tt.status, rr.Code, rr.Body.String())
+		})
+	}
+
+	// Only the one successful batch created anything
+	assert.Len(t, service.virtualDevices, before+3)
+}
+
+func TestDeviceVirtualService_CloneVirtualDevice(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	service.SetMaxBatchSize(10)
+	id := deviceNamed(t, service, "Virtual-Humidity-Sensor-01")
+	path := "/api/v3/device/virtual/" + id + "/clone"
+
+	names := func(ids []string) []string {
+		var names []string
+		for _, id := range ids {
+			names = append(names, getDevice(t, service, id).Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	rr := sendJSONRequest(service, http.MethodPost, path+"?count=3", "")
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	ids := createdIds(t, rr.Body.Bytes())
+	assert.Equal(t, []string{"Virtual-Humidity-Sensor-01-1", "Virtual-Humidity-Sensor-01-2", "Virtual-Humidity-Sensor-01-3"}, names(ids))
+	clone := getDevice(t, service, ids[0])
+	source := getDevice(t, service, id)
+	assert.NotEqual(t, source.Id, clone.Id)
+	assert.Equal(t, source.ProfileName, clone.ProfileName)
+	assert.Equal(t, source.Protocols, clone.Protocols)
+
+	// Clones take the lowest free numbers
+	deleteRR := sendJSONRequest(service, http.MethodDelete, "/api/v3/device/virtual/"+ids[1], "")
+	require.Equal(t, http.StatusOK, deleteRR.Code)
+	rr = sendJSONRequest(service, http.MethodPost, path, "")
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	ids = createdIds(t, rr.Body.Bytes())
+	require.Len(t, ids, 1)
+	assert.Equal(t, "Virtual-Humidity-Sensor-01-2", getDevice(t, service, ids[0]).Name)
+
+	for _, query := range []string{"?count=0", "?count=11", "?count=many"} {
+		rr = sendJSONRequest(service, http.MethodPost, path+query, "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/missing/clone", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,160 @@
+package virtual
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// SetPointResource is the writable resource every virtual device has besides the one it generates
+const SetPointResource = "SetPoint"
+
+// DefaultSetPoint is the value of SetPoint until a command sets it
+const DefaultSetPoint = 0.0
+
+// deviceByNameLocked finds a device by name. Caller must hold the lock.
+func (s *DeviceVirtualService) deviceByNameLocked(name string) (*VirtualDevice, bool) {
+	for _, device := range s.virtualDevices {
+		if device.Name == name {
+			return device, true
+		}
+	}
+	return nil, false
+}
+
+// storeLatestReadingLocked makes reading, taken at now, the latest of its resource. Caller must hold the write lock.
+func (s *DeviceVirtualService) storeLatestReadingLocked(id string, reading models.Reading, now time.Time) {
+	reading.Origin = now.UnixNano() / int64(time.Millisecond)
+	if s.latestReadings[id] == nil {
+		s.latestReadings[id] = make(map[string]models.Reading)
+	}
+	s.latestReadings[id][reading.ResourceName] = reading
+}
+
+// commandDeviceLocked finds the device a command names, writing the error response and returning false
+// if it is missing or locked. Caller must hold the lock.
+func (s *DeviceVirtualService) commandDeviceLocked(w http.ResponseWriter, name string) (*VirtualDevice, bool) {
+	device, exists := s.deviceByNameLocked(name)
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("Virtual device %s not found", name))
+		return nil, false
+	}
+	if device.AdminState == common.Locked {
+		common.WriteError(w, http.StatusLocked, fmt.Sprintf("Virtual device %s is locked", name))
+		return nil, false
+	}
+	return device, true
+}
+
+// readCommand handles GET /api/v3/device/name/{name}/{command}, responding with the latest reading of the
+// named resource as an event. A device that has not generated a reading yet generates one now.
+func (s *DeviceVirtualService) readCommand(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	name := vars["name"]
+	resource := vars["command"]
+
+	s.mutex.Lock()
+	stored, ok := s.commandDeviceLocked(w, name)
+	if !ok {
+		s.mutex.Unlock()
+		return
+	}
+	device := *stored
+	generator := s.readingGeneratorLocked(stored)
+	if resource != generator.resource() && resource != SetPointResource {
+		s.mutex.Unlock()
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("Resource %s not found on virtual device %s", resource, name))
+		return
+	}
+	reading, exists := s.latestReadings[device.Id][resource]
+	if !exists {
+		now := s.clock.Now()
+		if resource == SetPointResource {
+			reading = newSetPointReading(device, DefaultSetPoint)
+			s.storeLatestReadingLocked(device.Id, reading, now)
+		} else {
+			reading = generator.next(device, now)
+			s.recordReadingLocked(device.Id, reading, now)
+		}
+		reading = s.latestReadings[device.Id][resource]
+	}
+	s.mutex.Unlock()
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"event":      newReadingEvent(device, reading, time.Unix(0, reading.Origin*int64(time.Millisecond))),
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeCommand handles PUT /api/v3/device/name/{name}/{command}. Only SetPoint is writable; its new value
+// is given under the resource name, e.g. {"SetPoint": 21.5}, or as "value".
+func (s *DeviceVirtualService) writeCommand(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	name := vars["name"]
+	resource := vars["command"]
+
+	var request map[string]interface{}
+	if err := common.DecodeJSON(w, r, &request); err != nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, ok := s.commandDeviceLocked(w, name)
+	if !ok {
+		return
+	}
+	if resource != SetPointResource {
+		if resource == s.readingGeneratorLocked(stored).resource() {
+			common.WriteError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Resource %s is read-only", resource))
+			return
+		}
+		common.WriteError(w, http.StatusNotFound, fmt.Sprintf("Resource %s not found on virtual device %s", resource, name))
+		return
+	}
+
+	raw, set := request[resource]
+	if !set {
+		raw, set = request["value"]
+	}
+	if !set {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("A value for %s is required", resource))
+		return
+	}
+	value, err := strconv.ParseFloat(fmt.Sprint(raw), 64)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("%s must be a number, got %v", resource, raw))
+		return
+	}
+
+	s.storeLatestReadingLocked(stored.Id, newSetPointReading(*stored, value), s.clock.Now())
+	s.logger.Infof("Set %s of virtual device %s to %g", resource, name, value)
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"message":    "Command executed successfully",
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// newSetPointReading returns a SetPoint reading of device with the given value
+func newSetPointReading(device VirtualDevice, value float64) models.Reading {
+	return models.NewSimpleReading(device.ProfileName, device.Name, SetPointResource, common.ValueTypeFloat64, strconv.FormatFloat(value, 'f', -1, 64))
+}
@@ -0,0 +1,100 @@
+package virtual
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// readCommandReading issues a GET command and returns the single reading of the event it responds with
+func readCommandReading(t *testing.T, service *DeviceVirtualService, device, resource string) models.Reading {
+	rr := sendJSONRequest(service, http.MethodGet, "/api/v3/device/name/"+device+"/"+resource, "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response struct {
+		Event models.Event `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, device, response.Event.DeviceName)
+	assert.Equal(t, resource, response.Event.SourceName)
+	require.Len(t, response.Event.Readings, 1)
+	return response.Event.Readings[0]
+}
+
+func TestDeviceVirtualService_ReadCommand(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.SetDryRun(true)
+	id := deviceNamed(t, service, "Virtual-Temperature-Sensor-01")
+
+	// A device that has not generated anything yet generates a reading on demand
+	first := readCommandReading(t, service, "Virtual-Temperature-Sensor-01", "Temperature")
+	assert.Equal(t, common.ValueTypeFloat64, first.ValueType)
+	assert.Equal(t, testStart.UnixNano()/int64(time.Millisecond), first.Origin)
+	assert.Equal(t, first, readCommandReading(t, service, "Virtual-Temperature-Sensor-01", "Temperature"))
+
+	// Afterwards it answers with the latest generated reading
+	clock.Advance(time.Minute)
+	service.publishSensorReading(context.Background(), id)
+	latest := service.histories[id].latest(1)[0]
+	reading := readCommandReading(t, service, "Virtual-Temperature-Sensor-01", "Temperature")
+	assert.Equal(t, latest.Value, reading.SimpleReading.Value)
+	assert.Equal(t, testStart.Add(time.Minute).UnixNano()/int64(time.Millisecond), reading.Origin)
+
+	assert.Equal(t, "0", readCommandReading(t, service, "Virtual-Temperature-Sensor-01", SetPointResource).SimpleReading.Value)
+}
+
+func TestDeviceVirtualService_WriteCommand(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+
+	rr := sendJSONRequest(service, http.MethodPut, "/api/v3/device/name/Virtual-Humidity-Sensor-01/SetPoint", `{"SetPoint":"21.5"}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	reading := readCommandReading(t, service, "Virtual-Humidity-Sensor-01", SetPointResource)
+	assert.Equal(t, "21.5", reading.SimpleReading.Value)
+	assert.Equal(t, common.ValueTypeFloat64, reading.ValueType)
+
+	rr = sendJSONRequest(service, http.MethodPut, "/api/v3/device/name/Virtual-Humidity-Sensor-01/SetPoint", `{"value":40}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "40", readCommandReading(t, service, "Virtual-Humidity-Sensor-01", SetPointResource).SimpleReading.Value)
+
+	// Other devices keep their own set point
+	assert.Equal(t, "0", readCommandReading(t, service, "Virtual-Pressure-Sensor-01", SetPointResource).SimpleReading.Value)
+}
+
+func TestDeviceVirtualService_CommandErrors(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"locked","adminState":"LOCKED","protocols":{"type":"door"}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"Unknown device", http.MethodGet, "/api/v3/device/name/missing/Temperature", "", http.StatusNotFound},
+		{"Unknown resource", http.MethodGet, "/api/v3/device/name/Virtual-Temperature-Sensor-01/Humidity", "", http.StatusNotFound},
+		{"Locked device", http.MethodGet, "/api/v3/device/name/locked/DoorOpen", "", http.StatusLocked},
+		{"Set unknown device", http.MethodPut, "/api/v3/device/name/missing/SetPoint", `{"SetPoint":1}`, http.StatusNotFound},
+		{"Set unknown resource", http.MethodPut, "/api/v3/device/name/Virtual-Temperature-Sensor-01/Humidity", `{"Humidity":1}`, http.StatusNotFound},
+		{"Set locked device", http.MethodPut, "/api/v3/device/name/locked/SetPoint", `{"SetPoint":1}`, http.StatusLocked},
+		{"Set read-only resource", http.MethodPut, "/api/v3/device/name/Virtual-Temperature-Sensor-01/Temperature", `{"Temperature":1}`, http.StatusMethodNotAllowed},
+		{"Set without value", http.MethodPut, "/api/v3/device/name/Virtual-Temperature-Sensor-01/SetPoint", `{}`, http.StatusBadRequest},
+		{"Set non-number", http.MethodPut, "/api/v3/device/name/Virtual-Temperature-Sensor-01/SetPoint", `{"SetPoint":"warm"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := sendJSONRequest(service, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, rr.Code, rr.Body.String())
+		})
+	}
+}
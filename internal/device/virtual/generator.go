@@ -0,0 +1,233 @@
+package virtual
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Device types, chosen by a device's "type" protocol property. Any other type generates a generic
+// float between 0 and 100.
+const (
+	DeviceTypeTemperature = "temperature"
+	DeviceTypeHumidity    = "humidity"
+	DeviceTypePressure    = "pressure"
+	DeviceTypeDoor        = "door"
+	DeviceTypeCounter     = "counter"
+	DeviceTypeState       = "state"
+	DeviceTypeBinary      = "binary"
+)
+
+// Keys of VirtualDevice.Generation, the parameters of a device's generator
+const (
+	// GenerationMin and GenerationMax bound float values and counters, and the size of binary blobs in bytes
+	GenerationMin = "min"
+	GenerationMax = "max"
+	// GenerationPattern is the waveform of a float device, one of the Pattern constants, or the
+	// comma-separated values of a state device, e.g. IDLE,RUNNING,FAULT
+	GenerationPattern = "pattern"
+	// GenerationProbability is the chance, from 0 to 1, that a door changes between open and closed on each reading
+	GenerationProbability = "probability"
+)
+
+// Generator defaults
+const (
+	DefaultDoorProbability = 0.1
+	DefaultCounterMax      = math.MaxUint32
+	DefaultStatePattern    = "IDLE,RUNNING,STOPPED"
+	DefaultBinaryMinSize   = 16
+	DefaultBinaryMaxSize   = 64
+	MaxBinarySize          = 64 * 1024
+)
+
+// readingGenerator produces the successive readings of one device, the next one taken at now, all for the
+// same resource. Generators that keep state between readings guard it themselves.
+type readingGenerator interface {
+	next(device VirtualDevice, now time.Time) models.Reading
+	resource() string
+}
+
+// generatorFactory builds the generator of a device type from the device's generation parameters
+type generatorFactory func(generation map[string]string) (readingGenerator, error)
+
+// generatorFactories holds the factory of each device type
+var generatorFactories = map[string]generatorFactory{
+	DeviceTypeTemperature: floatFactory("Temperature", "Celsius", 20, 35),
+	DeviceTypeHumidity:    floatFactory("Humidity", "Percent", 30, 70),
+	DeviceTypePressure:    floatFactory("Pressure", "hPa", 1013, 1033),
+	DeviceTypeDoor:        newDoorGenerator,
+	DeviceTypeCounter:     newCounterGenerator,
+	DeviceTypeState:       newStateGenerator,
+	DeviceTypeBinary:      newBinaryGenerator,
+}
+
+// genericFactory builds the generator of devices of unknown type
+var genericFactory = floatFactory("GenericSensor", "Units", 0, 100)
+
+// newReadingGenerator builds the generator for device's type and generation parameters, or reports
+// what is wrong with the parameters
+func newReadingGenerator(device *VirtualDevice) (readingGenerator, error) {
+	factory, known := generatorFactories[device.Protocols["type"]]
+	if !known {
+		factory = genericFactory
+	}
+	generator, err := factory(device.Generation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generation: %w", err)
+	}
+	return generator, nil
+}
+
+// floatParam reads a number from generation, fallback if it is not set
+func floatParam(generation map[string]string, key string, fallback float64) (float64, error) {
+	value, set := generation[key]
+	if !set {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return 0, fmt.Errorf("%s must be a number, got %q", key, value)
+	}
+	return parsed, nil
+}
+
+// intParam reads an integer from generation, fallback if it is not set
+func intParam(generation map[string]string, key string, fallback int64) (int64, error) {
+	value, set := generation[key]
+	if !set {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer, got %q", key, value)
+	}
+	return parsed, nil
+}
+
+// doorGenerator reports whether a door is open, starting closed and changing with probability on each reading
+type doorGenerator struct {
+	probability float64
+	mutex       sync.Mutex
+	open        bool
+}
+
+func newDoorGenerator(generation map[string]string) (readingGenerator, error) {
+	probability, err := floatParam(generation, GenerationProbability, DefaultDoorProbability)
+	if err != nil {
+		return nil, err
+	}
+	if probability < 0 || probability > 1 {
+		return nil, fmt.Errorf("probability must be between 0 and 1, got %g", probability)
+	}
+	return &doorGenerator{probability: probability}, nil
+}
+
+func (g *doorGenerator) next(device VirtualDevice, now time.Time) models.Reading {
+	g.mutex.Lock()
+	if rand.Float64() < g.probability {
+		g.open = !g.open
+	}
+	open := g.open
+	g.mutex.Unlock()
+	return models.NewSimpleReading(device.ProfileName, device.Name, g.resource(), common.ValueTypeBool, strconv.FormatBool(open))
+}
+
+func (g *doorGenerator) resource() string { return "DoorOpen" }
+
+// counterGenerator counts up by one from min on each reading, rolling over to min after max
+type counterGenerator struct {
+	min, max int64
+	mutex    sync.Mutex
+	value    int64
+}
+
+func newCounterGenerator(generation map[string]string) (readingGenerator, error) {
+	min, err := intParam(generation, GenerationMin, 0)
+	if err != nil {
+		return nil, err
+	}
+	max, err := intParam(generation, GenerationMax, DefaultCounterMax)
+	if err != nil {
+		return nil, err
+	}
+	if min >= max {
+		return nil, fmt.Errorf("min %d must be less than max %d", min, max)
+	}
+	return &counterGenerator{min: min, max: max, value: min}, nil
+}
+
+func (g *counterGenerator) next(device VirtualDevice, now time.Time) models.Reading {
+	g.mutex.Lock()
+	value := g.value
+	if g.value == g.max {
+		g.value = g.min
+	} else {
+		g.value++
+	}
+	g.mutex.Unlock()
+	return models.NewSimpleReading(device.ProfileName, device.Name, g.resource(), common.ValueTypeInt64, strconv.FormatInt(value, 10))
+}
+
+func (g *counterGenerator) resource() string { return "Count" }
+
+// stateGenerator picks one of a list of states at random on each reading
+type stateGenerator struct {
+	states []string
+}
+
+func newStateGenerator(generation map[string]string) (readingGenerator, error) {
+	pattern, set := generation[GenerationPattern]
+	if !set {
+		pattern = DefaultStatePattern
+	}
+	states := strings.Split(pattern, ",")
+	for i, state := range states {
+		states[i] = strings.TrimSpace(state)
+		if states[i] == "" {
+			return nil, fmt.Errorf("pattern must be a comma-separated list of states, got %q", pattern)
+		}
+	}
+	return stateGenerator{states: states}, nil
+}
+
+func (g stateGenerator) next(device VirtualDevice, now time.Time) models.Reading {
+	state := g.states[rand.Intn(len(g.states))]
+	return models.NewSimpleReading(device.ProfileName, device.Name, g.resource(), common.ValueTypeString, state)
+}
+
+func (g stateGenerator) resource() string { return "State" }
+
+// binaryGenerator generates random blobs of between min and max bytes
+type binaryGenerator struct {
+	min, max int
+}
+
+func newBinaryGenerator(generation map[string]string) (readingGenerator, error) {
+	min, err := intParam(generation, GenerationMin, DefaultBinaryMinSize)
+	if err != nil {
+		return nil, err
+	}
+	max, err := intParam(generation, GenerationMax, DefaultBinaryMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if min < 0 || max > MaxBinarySize || min > max {
+		return nil, fmt.Errorf("blob sizes must satisfy 0 <= min <= max <= %d, got min %d and max %d", MaxBinarySize, min, max)
+	}
+	return binaryGenerator{min: int(min), max: int(max)}, nil
+}
+
+func (g binaryGenerator) next(device VirtualDevice, now time.Time) models.Reading {
+	blob := make([]byte, g.min+rand.Intn(g.max-g.min+1))
+	rand.Read(blob)
+	return models.NewBinaryReading(device.ProfileName, device.Name, g.resource(), blob, "application/octet-stream")
+}
+
+func (g binaryGenerator) resource() string { return "Blob" }
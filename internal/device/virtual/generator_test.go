@@ -0,0 +1,187 @@
+package virtual
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// testGenerator builds the generator of a device of the given type and generation parameters
+func testGenerator(t *testing.T, deviceType string, generation map[string]string) (readingGenerator, VirtualDevice) {
+	device := VirtualDevice{Name: "device", ProfileName: "profile", Protocols: map[string]string{"type": deviceType}, Generation: generation}
+	generator, err := newReadingGenerator(&device)
+	require.NoError(t, err)
+	return generator, device
+}
+
+func TestFloatGenerator(t *testing.T) {
+	tests := []struct {
+		deviceType   string
+		generation   map[string]string
+		resourceName string
+		units        string
+		min, max     float64
+	}{
+		{DeviceTypeTemperature, nil, "Temperature", "Celsius", 20, 35},
+		{DeviceTypeHumidity, nil, "Humidity", "Percent", 30, 70},
+		{DeviceTypePressure, nil, "Pressure", "hPa", 1013, 1033},
+		{"unknown", nil, "GenericSensor", "Units", 0, 100},
+		{DeviceTypeTemperature, map[string]string{GenerationMin: "-40", GenerationMax: "-30.5"}, "Temperature", "Celsius", -40, -30.5},
+		{DeviceTypeHumidity, map[string]string{GenerationMin: "50", GenerationMax: "50"}, "Humidity", "Percent", 50, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.deviceType, func(t *testing.T) {
+			generator, device := testGenerator(t, tt.deviceType, tt.generation)
+			for i := 0; i < 100; i++ {
+				reading := generator.next(device, testStart)
+				assert.Equal(t, tt.resourceName, reading.ResourceName)
+				assert.Equal(t, common.ValueTypeFloat64, reading.ValueType)
+				assert.Equal(t, tt.units, reading.SimpleReading.Units)
+				assert.Equal(t, "device", reading.DeviceName)
+				assert.Equal(t, "profile", reading.ProfileName)
+				value, err := strconv.ParseFloat(reading.SimpleReading.Value, 64)
+				require.NoError(t, err)
+				assert.GreaterOrEqual(t, value, tt.min)
+				assert.LessOrEqual(t, value, tt.max)
+			}
+		})
+	}
+}
+
+func TestDoorGenerator(t *testing.T) {
+	values := func(probability string) []string {
+		generator, device := testGenerator(t, DeviceTypeDoor, map[string]string{GenerationProbability: probability})
+		var values []string
+		for i := 0; i < 4; i++ {
+			reading := generator.next(device, testStart)
+			assert.Equal(t, "DoorOpen", reading.ResourceName)
+			assert.Equal(t, common.ValueTypeBool, reading.ValueType)
+			values = append(values, reading.SimpleReading.Value)
+		}
+		return values
+	}
+
+	assert.Equal(t, []string{"false", "false", "false", "false"}, values("0"))
+	assert.Equal(t, []string{"true", "false", "true", "false"}, values("1"))
+}
+
+func TestCounterGenerator(t *testing.T) {
+	generator, device := testGenerator(t, DeviceTypeCounter, map[string]string{GenerationMin: "-1", GenerationMax: "2"})
+	var values []string
+	for i := 0; i < 9; i++ {
+		reading := generator.next(device, testStart)
+		assert.Equal(t, "Count", reading.ResourceName)
+		assert.Equal(t, common.ValueTypeInt64, reading.ValueType)
+		values = append(values, reading.SimpleReading.Value)
+	}
+	assert.Equal(t, []string{"-1", "0", "1", "2", "-1", "0", "1", "2", "-1"}, values)
+
+	generator, device = testGenerator(t, DeviceTypeCounter, nil)
+	assert.Equal(t, "0", generator.next(device, testStart).SimpleReading.Value)
+	assert.Equal(t, "1", generator.next(device, testStart).SimpleReading.Value)
+	assert.Equal(t, int64(DefaultCounterMax), generator.(*counterGenerator).max)
+}
+
+func TestStateGenerator(t *testing.T) {
+	generator, device := testGenerator(t, DeviceTypeState, map[string]string{GenerationPattern: "IDLE, RUNNING,FAULT"})
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		reading := generator.next(device, testStart)
+		assert.Equal(t, "State", reading.ResourceName)
+		assert.Equal(t, common.ValueTypeString, reading.ValueType)
+		seen[reading.SimpleReading.Value] = true
+	}
+	assert.Equal(t, map[string]bool{"IDLE": true, "RUNNING": true, "FAULT": true}, seen)
+
+	generator, device = testGenerator(t, DeviceTypeState, nil)
+	assert.Contains(t, []string{"IDLE", "RUNNING", "STOPPED"}, generator.next(device, testStart).SimpleReading.Value)
+}
+
+func TestBinaryGenerator(t *testing.T) {
+	generator, device := testGenerator(t, DeviceTypeBinary, map[string]string{GenerationMin: "4", GenerationMax: "8"})
+	sizes := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		reading := generator.next(device, testStart)
+		assert.Equal(t, "Blob", reading.ResourceName)
+		assert.Equal(t, common.ValueTypeBinary, reading.ValueType)
+		assert.Equal(t, "application/octet-stream", reading.BinaryReading.MediaType)
+		sizes[len(reading.BinaryReading.BinaryValue)] = true
+	}
+	assert.Equal(t, map[int]bool{4: true, 5: true, 6: true, 7: true, 8: true}, sizes)
+
+	generator, device = testGenerator(t, DeviceTypeBinary, map[string]string{GenerationMin: "0", GenerationMax: "0"})
+	assert.Empty(t, generator.next(device, testStart).BinaryReading.BinaryValue)
+}
+
+func TestNewReadingGenerator_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		deviceType string
+		generation map[string]string
+	}{
+		{"Float not a number", DeviceTypeTemperature, map[string]string{GenerationMin: "cold"}},
+		{"Float infinite", DeviceTypeTemperature, map[string]string{GenerationMax: "Inf"}},
+		{"Float min above max", DeviceTypePressure, map[string]string{GenerationMin: "2000"}},
+		{"Door probability above 1", DeviceTypeDoor, map[string]string{GenerationProbability: "1.5"}},
+		{"Door probability negative", DeviceTypeDoor, map[string]string{GenerationProbability: "-0.1"}},
+		{"Counter fractional", DeviceTypeCounter, map[string]string{GenerationMax: "9.5"}},
+		{"Counter empty range", DeviceTypeCounter, map[string]string{GenerationMin: "5", GenerationMax: "5"}},
+		{"State empty entry", DeviceTypeState, map[string]string{GenerationPattern: "ON,,OFF"}},
+		{"State empty pattern", DeviceTypeState, map[string]string{GenerationPattern: ""}},
+		{"Binary negative size", DeviceTypeBinary, map[string]string{GenerationMin: "-1"}},
+		{"Binary too large", DeviceTypeBinary, map[string]string{GenerationMax: "1000000"}},
+		{"Binary min above max", DeviceTypeBinary, map[string]string{GenerationMin: "100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := VirtualDevice{Protocols: map[string]string{"type": tt.deviceType}, Generation: tt.generation}
+			_, err := newReadingGenerator(&device)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDeviceVirtualService_Generation(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	client := &fakeMessageClient{}
+	service.SetMessageClient(client, "")
+
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"door","protocols":{"type":"door"},"generation":{"probability":"2"}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"meter","protocols":{"type":"counter"},"generation":{"min":"10","max":"11"}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	id := deviceNamed(t, service, "meter")
+
+	published := func() []string {
+		var values []string
+		for _, data := range client.data {
+			values = append(values, data.(models.Event).Readings[0].SimpleReading.Value)
+		}
+		return values
+	}
+
+	// Readings come from one counter that keeps counting between them
+	for i := 0; i < 3; i++ {
+		service.publishSensorReading(context.Background(), id)
+	}
+	assert.Equal(t, []string{"10", "11", "10"}, published())
+
+	// Updates are checked too, and start the counter over with the new parameters
+	path := "/api/v3/device/virtual/" + id
+	rr = sendJSONRequest(service, http.MethodPut, path, `{"name":"meter","protocols":{"type":"counter"},"generation":{"min":"5","max":"1"}}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	rr = sendJSONRequest(service, http.MethodPut, path, `{"name":"meter","protocols":{"type":"counter"},"generation":{"min":"100","max":"200"}}`)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	service.publishSensorReading(context.Background(), id)
+	assert.Equal(t, []string{"10", "11", "10", "100"}, published())
+}
@@ -0,0 +1,161 @@
+package virtual
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// DefaultHistorySize is how many readings of each device are kept unless SetHistorySize overrides it
+const DefaultHistorySize = 100
+
+// ReadingStats summarises the readings a device has generated since it was last started. Min, max and
+// mean cover its numeric readings and are left out when it has generated none.
+type ReadingStats struct {
+	Since time.Time `json:"since"` // when the device was started, or generated its first reading if never started
+	Count int64     `json:"count"`
+	Min   *float64  `json:"min,omitempty"`
+	Max   *float64  `json:"max,omitempty"`
+	Mean  *float64  `json:"mean,omitempty"`
+
+	numeric int64
+	sum     float64
+}
+
+// add counts a reading, with its value if it is numeric
+func (stats *ReadingStats) add(value float64, numeric bool) {
+	stats.Count++
+	if !numeric {
+		return
+	}
+	if stats.numeric == 0 || value < *stats.Min {
+		stats.Min = &value
+	}
+	if stats.numeric == 0 || value > *stats.Max {
+		stats.Max = &value
+	}
+	stats.numeric++
+	stats.sum += value
+	mean := stats.sum / float64(stats.numeric)
+	stats.Mean = &mean
+}
+
+// readingHistory is a ring buffer of a device's latest readings, with the stats of every reading since
+// the device was started. The service's lock guards it.
+type readingHistory struct {
+	readings []RecentReading // up to the buffer's size; once full, next is the oldest
+	size     int
+	next     int
+	stats    ReadingStats
+}
+
+func newReadingHistory(size int, since time.Time) *readingHistory {
+	return &readingHistory{size: size, stats: ReadingStats{Since: since}}
+}
+
+// add records a reading, overwriting the oldest once the buffer is full
+func (h *readingHistory) add(reading RecentReading, value float64, numeric bool) {
+	if len(h.readings) < h.size {
+		h.readings = append(h.readings, reading)
+	} else {
+		h.readings[h.next] = reading
+		h.next = (h.next + 1) % h.size
+	}
+	h.stats.add(value, numeric)
+}
+
+// latest returns a copy of the last n readings, oldest first; all of them if n is negative or more than are kept
+func (h *readingHistory) latest(n int) []RecentReading {
+	if h == nil {
+		return []RecentReading{}
+	}
+	if n < 0 || n > len(h.readings) {
+		n = len(h.readings)
+	}
+	ordered := append(append([]RecentReading{}, h.readings[h.next:]...), h.readings[:h.next]...)
+	return ordered[len(ordered)-n:]
+}
+
+// numericValue parses a reading's value when its type is a number
+func numericValue(valueType, value string) (float64, bool) {
+	switch valueType {
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64,
+		common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeFloat32, common.ValueTypeFloat64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		return parsed, err == nil
+	}
+	return 0, false
+}
+
+// SetHistorySize sets how many readings of each device are kept. Must be called before Initialize.
+func (s *DeviceVirtualService) SetHistorySize(size int) {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	s.historySize = size
+}
+
+// resetHistoryLocked starts the device's history over, as it is started at now. Caller must hold the write lock.
+func (s *DeviceVirtualService) resetHistoryLocked(id string, now time.Time) {
+	s.histories[id] = newReadingHistory(s.historySize, now)
+}
+
+// historySnapshot returns the device's kept readings and stats, and whether the device exists
+func (s *DeviceVirtualService) historySnapshot(id string) ([]RecentReading, ReadingStats, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if _, exists := s.virtualDevices[id]; !exists {
+		return nil, ReadingStats{}, false
+	}
+	history := s.histories[id]
+	if history == nil {
+		return []RecentReading{}, ReadingStats{}, true
+	}
+	return history.latest(-1), history.stats, true
+}
+
+// getDeviceReadings handles GET /api/v3/device/virtual/{id}/readings
+func (s *DeviceVirtualService) getDeviceReadings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	readings, _, exists := s.historySnapshot(vars["id"])
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"readings":   readings,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// getDeviceReadingStats handles GET /api/v3/device/virtual/{id}/readings/stats
+func (s *DeviceVirtualService) getDeviceReadingStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	_, stats, exists := s.historySnapshot(vars["id"])
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+		return
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"stats":      stats,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,142 @@
+package virtual
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func TestReadingHistory(t *testing.T) {
+	history := newReadingHistory(3, testStart)
+	assert.Empty(t, history.latest(-1))
+
+	for i, value := range []float64{5, -2, 9, 4} {
+		history.add(RecentReading{Time: testStart.Add(time.Duration(i) * time.Second), Value: "v"}, value, true)
+	}
+	history.add(RecentReading{Time: testStart.Add(4 * time.Second), Value: "OPEN"}, 0, false)
+
+	times := func(readings []RecentReading) []time.Duration {
+		var offsets []time.Duration
+		for _, reading := range readings {
+			offsets = append(offsets, reading.Time.Sub(testStart))
+		}
+		return offsets
+	}
+	assert.Equal(t, []time.Duration{2 * time.Second, 3 * time.Second, 4 * time.Second}, times(history.latest(-1)))
+	assert.Equal(t, []time.Duration{3 * time.Second, 4 * time.Second}, times(history.latest(2)))
+
+	// Stats cover every reading, not only those still kept, and only numeric values count towards min, max and mean
+	assert.Equal(t, int64(5), history.stats.Count)
+	assert.Equal(t, -2.0, *history.stats.Min)
+	assert.Equal(t, 9.0, *history.stats.Max)
+	assert.Equal(t, 4.0, *history.stats.Mean)
+
+	states := newReadingHistory(3, testStart)
+	states.add(RecentReading{Value: "IDLE"}, 0, false)
+	body, err := json.Marshal(states.stats)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"since":"`+testStart.Format(time.RFC3339Nano)+`","count":1}`, string(body))
+}
+
+func TestDeviceVirtualService_History(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.SetDryRun(true)
+	service.SetHistorySize(5)
+
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"count","protocols":{"type":"counter"}}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	id := deviceNamed(t, service, "count")
+
+	readings := func() []RecentReading {
+		rr := sendJSONRequest(service, http.MethodGet, "/api/v3/device/virtual/"+id+"/readings", "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response struct {
+			Readings []RecentReading `json:"readings"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Readings
+	}
+	stats := func() ReadingStats {
+		rr := sendJSONRequest(service, http.MethodGet, "/api/v3/device/virtual/"+id+"/readings/stats", "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response struct {
+			Stats ReadingStats `json:"stats"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Stats
+	}
+	values := func(readings []RecentReading) []string {
+		var values []string
+		for _, reading := range readings {
+			values = append(values, reading.Value)
+		}
+		return values
+	}
+
+	assert.Empty(t, readings())
+	assert.Equal(t, int64(0), stats().Count)
+
+	for i := 0; i < 8; i++ {
+		service.publishSensorReading(context.Background(), id)
+		clock.Advance(time.Second)
+	}
+	assert.Equal(t, []string{"3", "4", "5", "6", "7"}, values(readings()))
+	current := stats()
+	assert.Equal(t, int64(8), current.Count)
+	assert.True(t, testStart.Equal(current.Since))
+	assert.Equal(t, 0.0, *current.Min)
+	assert.Equal(t, 7.0, *current.Max)
+	assert.Equal(t, 3.5, *current.Mean)
+
+	// Starting the device starts its history over
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/"+id+"/start", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Empty(t, readings())
+	current = stats()
+	assert.Equal(t, int64(0), current.Count)
+	assert.True(t, testStart.Add(8*time.Second).Equal(current.Since))
+	assert.Nil(t, current.Min)
+
+	// The generator records readings while the history is read
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				readings()
+				stats()
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(DefaultInterval)
+		require.Eventually(t, func() bool { return stats().Count == int64(i+1) }, time.Second, time.Millisecond)
+	}
+	close(done)
+	wg.Wait()
+	assert.Len(t, readings(), 5)
+
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual/"+id+"/stop", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	service.generators.Wait()
+
+	for _, path := range []string{"/readings", "/readings/stats"} {
+		rr = sendJSONRequest(service, http.MethodGet, "/api/v3/device/virtual/missing"+path, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code, path)
+	}
+}
@@ -0,0 +1,92 @@
+package virtual
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// DefaultPublishTimeout bounds each POST of an event to core-data
+const DefaultPublishTimeout = 10 * time.Second
+
+// SetCoreDataURL makes generated readings go to core-data at baseURL, e.g. http://localhost:59880,
+// as events POSTed to /api/v3/event. Must be called before Initialize.
+func (s *DeviceVirtualService) SetCoreDataURL(baseURL string) {
+	s.coreDataURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetMessageClient makes generated readings go to topic on the message bus as events, in place of
+// core-data. Must be called before Initialize.
+func (s *DeviceVirtualService) SetMessageClient(client messaging.MessageClient, topic string) {
+	if topic == "" {
+		topic = messaging.MessageTopics.Events
+	}
+	s.messageClient = client
+	s.topic = topic
+}
+
+// SetDryRun makes the service only log the readings it generates, wherever it is set to send them.
+// Must be called before Initialize.
+func (s *DeviceVirtualService) SetDryRun(dryRun bool) {
+	s.dryRun = dryRun
+}
+
+// newReadingEvent wraps a reading generated for device in an event
+func newReadingEvent(device VirtualDevice, reading models.Reading, now time.Time) models.Event {
+	origin := now.UnixNano() / int64(time.Millisecond)
+	reading.Origin = origin
+	event := models.NewEvent(device.ProfileName, device.Name, reading.ResourceName)
+	event.Origin = origin
+	event.Readings = []models.Reading{reading}
+	return event
+}
+
+// publishing reports whether generated events leave the service
+func (s *DeviceVirtualService) publishing() bool {
+	return !s.dryRun && (s.messageClient != nil || s.coreDataURL != "")
+}
+
+// publishEvent sends event to the message bus if one is set, otherwise to core-data
+func (s *DeviceVirtualService) publishEvent(ctx context.Context, event models.Event) error {
+	if s.messageClient != nil {
+		if err := s.messageClient.Publish(s.topic, event); err != nil {
+			return fmt.Errorf("failed to publish event to topic %s: %w", s.topic, err)
+		}
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	address := s.coreDataURL + common.ApiEventRoute
+	ctx, cancel := context.WithTimeout(ctx, DefaultPublishTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	req.Header.Set(common.CorrelationHeader, models.GenerateUUID())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event to %s: %w", address, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", address, resp.Status)
+	}
+	return nil
+}
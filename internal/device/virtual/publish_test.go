@@ -0,0 +1,202 @@
+package virtual
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// fakeCoreData records the events POSTed to it and responds with status
+type fakeCoreData struct {
+	mutex  sync.Mutex
+	events []models.Event
+	status int
+}
+
+func (f *fakeCoreData) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if r.Method != http.MethodPost || r.URL.Path != common.ApiEventRoute || r.Header.Get(common.ContentType) != common.ContentTypeJSON {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var event models.Event
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.events = append(f.events, event)
+	w.WriteHeader(f.status)
+}
+
+func (f *fakeCoreData) posted() []models.Event {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]models.Event(nil), f.events...)
+}
+
+func (f *fakeCoreData) respondWith(status int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.status = status
+}
+
+// fakeMessageClient records what is published and fails with err, if set
+type fakeMessageClient struct {
+	topics []string
+	data   []interface{}
+	err    error
+}
+
+func (c *fakeMessageClient) Connect() error    { return nil }
+func (c *fakeMessageClient) Disconnect() error { return nil }
+func (c *fakeMessageClient) Publish(topic string, data interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.topics = append(c.topics, topic)
+	c.data = append(c.data, data)
+	return nil
+}
+func (c *fakeMessageClient) Subscribe(topic string, handler messaging.MessageHandler) error {
+	return nil
+}
+func (c *fakeMessageClient) Unsubscribe(topic string) error { return nil }
+
+// deviceNamed returns the id of the default device with the given name
+func deviceNamed(t *testing.T, service *DeviceVirtualService, name string) string {
+	for id, device := range service.virtualDevices {
+		if device.Name == name {
+			return id
+		}
+	}
+	t.Fatalf("no device named %s", name)
+	return ""
+}
+
+// getDevice returns the device with the given id as served by GET
+func getDevice(t *testing.T, service *DeviceVirtualService, id string) VirtualDevice {
+	rr := httptest.NewRecorder()
+	newTestRouter(service).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v3/device/virtual/"+id, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response struct {
+		VirtualDevice VirtualDevice `json:"virtualDevice"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.VirtualDevice
+}
+
+func TestDeviceVirtualService_PublishesToCoreData(t *testing.T) {
+	coreData := &fakeCoreData{status: http.StatusCreated}
+	server := httptest.NewServer(coreData)
+	defer server.Close()
+
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.SetCoreDataURL(server.URL + "/")
+	id := deviceNamed(t, service, "Virtual-Temperature-Sensor-01")
+
+	service.publishSensorReading(context.Background(), id)
+
+	events := coreData.posted()
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.NotEmpty(t, event.Id)
+	assert.Equal(t, "Virtual-Temperature-Sensor-01", event.DeviceName)
+	assert.Equal(t, "TemperatureSensorProfile", event.ProfileName)
+	assert.Equal(t, "Temperature", event.SourceName)
+	assert.Equal(t, testStart.UnixMilli(), event.Origin)
+	require.Len(t, event.Readings, 1)
+	reading := event.Readings[0]
+	assert.Equal(t, "Virtual-Temperature-Sensor-01", reading.DeviceName)
+	assert.Equal(t, "Temperature", reading.ResourceName)
+	assert.Equal(t, common.ValueTypeFloat64, reading.ValueType)
+	assert.Equal(t, "Celsius", reading.SimpleReading.Units)
+	assert.NotEmpty(t, reading.SimpleReading.Value)
+	assert.Equal(t, testStart.UnixMilli(), reading.Origin)
+
+	device := getDevice(t, service, id)
+	assert.Equal(t, 0, device.PublishErrors)
+	assert.True(t, device.LastPublishTime.Equal(testStart), device.LastPublishTime)
+
+	// Failures are counted and leave lastPublishTime alone
+	coreData.respondWith(http.StatusInternalServerError)
+	clock.Advance(5 * time.Second)
+	service.publishSensorReading(context.Background(), id)
+	service.publishSensorReading(context.Background(), id)
+
+	device = getDevice(t, service, id)
+	assert.Equal(t, 2, device.PublishErrors)
+	assert.True(t, device.LastPublishTime.Equal(testStart), device.LastPublishTime)
+	assert.True(t, device.LastReading.Equal(testStart.Add(5*time.Second)), device.LastReading)
+	assert.Len(t, coreData.posted(), 3)
+
+	// Updates keep the counts
+	body := `{"name":"Virtual-Temperature-Sensor-01","profileName":"TemperatureSensorProfile","protocols":{"type":"temperature"},"publishErrors":0}`
+	assert.Equal(t, http.StatusOK, sendJSONRequest(service, http.MethodPut, "/api/v3/device/virtual/"+id, body).Code)
+	assert.Equal(t, 2, getDevice(t, service, id).PublishErrors)
+}
+
+func TestDeviceVirtualService_PublishesToMessageBus(t *testing.T) {
+	coreData := &fakeCoreData{status: http.StatusCreated}
+	server := httptest.NewServer(coreData)
+	defer server.Close()
+
+	service := newTestService(common.NewFakeClock(testStart))
+	client := &fakeMessageClient{}
+	service.SetCoreDataURL(server.URL)
+	service.SetMessageClient(client, "")
+	id := deviceNamed(t, service, "Virtual-Humidity-Sensor-01")
+
+	service.publishSensorReading(context.Background(), id)
+	assert.Empty(t, coreData.posted(), "the message bus takes precedence")
+	require.Equal(t, []string{messaging.MessageTopics.Events}, client.topics)
+	event, ok := client.data[0].(models.Event)
+	require.True(t, ok)
+	assert.Equal(t, "Virtual-Humidity-Sensor-01", event.DeviceName)
+	assert.Equal(t, "Humidity", event.Readings[0].ResourceName)
+
+	client.err = errors.New("connection reset")
+	service.publishSensorReading(context.Background(), id)
+	assert.Equal(t, 1, getDevice(t, service, id).PublishErrors)
+}
+
+func TestDeviceVirtualService_DryRun(t *testing.T) {
+	coreData := &fakeCoreData{status: http.StatusCreated}
+	server := httptest.NewServer(coreData)
+	defer server.Close()
+
+	service := newTestService(common.NewFakeClock(testStart))
+	service.SetCoreDataURL(server.URL)
+	service.SetDryRun(true)
+	id := deviceNamed(t, service, "Virtual-Pressure-Sensor-01")
+
+	service.publishSensorReading(context.Background(), id)
+	assert.Empty(t, coreData.posted())
+	device := getDevice(t, service, id)
+	assert.True(t, device.LastReading.Equal(testStart))
+	assert.True(t, device.LastPublishTime.IsZero())
+	assert.Equal(t, 0, device.PublishErrors)
+}
+
+// sendJSONRequest serves a request with a JSON body
+func sendJSONRequest(service *DeviceVirtualService, method, path, body string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	newTestRouter(service).ServeHTTP(rr, req)
+	return rr
+}
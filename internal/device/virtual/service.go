@@ -4,8 +4,8 @@ import (
         "context"
         "encoding/json"
         "fmt"
-        "math/rand"
         "net/http"
+        "sort"
         "sync"
         "time"
 
@@ -15,6 +15,7 @@ import (
         "github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
         "github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
         "github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+        "github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
 )
 
 // VirtualDevice represents a simulated IoT device
@@ -27,8 +28,34 @@ type VirtualDevice struct {
         AdminState    string            `json:"adminState"`
         OperatingState string           `json:"operatingState"`
         Protocols     map[string]string `json:"protocols"`
+        Generation    map[string]string `json:"generation,omitempty"` // parameters of the generator for the type protocol property
         LastReading   time.Time         `json:"lastReading"`
         IsRunning     bool              `json:"isRunning"`
+        Interval      string            `json:"interval,omitempty"` // how often readings are generated, DefaultInterval if empty
+        AutoStart     bool              `json:"autoStart"`          // start generating when the service starts
+        PublishErrors int               `json:"publishErrors"`      // readings that failed to reach core-data or the message bus
+        LastPublishTime time.Time       `json:"lastPublishTime"`    // when a reading last reached core-data or the message bus
+}
+
+// DefaultInterval is how often a device without an Interval generates readings
+const DefaultInterval = 5 * time.Second
+
+// MinInterval is the shortest Interval a device may have
+const MinInterval = 100 * time.Millisecond
+
+// generationInterval returns how often device generates readings
+func generationInterval(device *VirtualDevice) (time.Duration, error) {
+        if device.Interval == "" {
+                return DefaultInterval, nil
+        }
+        interval, err := time.ParseDuration(device.Interval)
+        if err != nil {
+                return 0, fmt.Errorf("invalid interval %q", device.Interval)
+        }
+        if interval < MinInterval {
+                return 0, fmt.Errorf("interval %s is shorter than the minimum of %s", device.Interval, MinInterval)
+        }
+        return interval, nil
 }
 
 // DeviceVirtualService handles virtual device simulation
@@ -37,6 +64,20 @@ type DeviceVirtualService struct {
         virtualDevices map[string]*VirtualDevice
         mutex          sync.RWMutex
         stopChannels   map[string]chan bool
+        readingGenerators map[string]readingGenerator // by device id, built at the first reading after a change
+        histories      map[string]*readingHistory     // by device id
+        historySize    int                           // readings kept in each device's history
+        latestReadings map[string]map[string]models.Reading // by device id and resource name, read and set by commands
+        generators     sync.WaitGroup // Running data generators
+        clock          common.Clock
+        ctx            context.Context
+        cancel         context.CancelFunc // stops every generator, set by Initialize
+        coreDataURL    string                  // where events are POSTed, if set
+        messageClient  messaging.MessageClient // where events are published, if set; takes precedence over coreDataURL
+        topic          string
+        dryRun         bool                    // only log readings
+        httpClient     *http.Client
+        maxBatchSize   int // most devices one batch or clone request may create
 }
 
 // NewDeviceVirtualService creates a new device virtual service
@@ -45,6 +86,14 @@ func NewDeviceVirtualService(logger *logrus.Logger) *DeviceVirtualService {
                 logger:         logger,
                 virtualDevices: make(map[string]*VirtualDevice),
                 stopChannels:   make(map[string]chan bool),
+                readingGenerators: make(map[string]readingGenerator),
+                histories:      make(map[string]*readingHistory),
+                historySize:    DefaultHistorySize,
+                latestReadings: make(map[string]map[string]models.Reading),
+                clock:          common.RealClock{},
+                ctx:            context.Background(),
+                httpClient:     &http.Client{Timeout: DefaultPublishTimeout},
+                maxBatchSize:   DefaultMaxBatchSize,
         }
         
         // Initialize with some default virtual devices
@@ -53,6 +102,11 @@ func NewDeviceVirtualService(logger *logrus.Logger) *DeviceVirtualService {
         return service
 }
 
+// SetClock sets the clock that paces data generation. Must be called before Initialize.
+func (s *DeviceVirtualService) SetClock(clock common.Clock) {
+        s.clock = clock
+}
+
 // Initialize implements the BootstrapHandler interface
 func (s *DeviceVirtualService) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *bootstrap.DIContainer) bool {
         s.logger.Info("Initializing Device Virtual Service")
@@ -60,23 +114,79 @@ func (s *DeviceVirtualService) Initialize(ctx context.Context, wg *sync.WaitGrou
         // Add service to DI container
         dic.Add("DeviceVirtualService", s)
         
+        // Data generators stop when the service shuts down
+        s.mutex.Lock()
+        s.ctx, s.cancel = context.WithCancel(ctx)
+        generatorsCtx := s.ctx
+        s.mutex.Unlock()
+        
         // Start virtual device data generation
         s.startDataGeneration()
         
+        // Shutdown waits for every generator to return, so none publishes afterwards
+        wg.Add(1)
+        go func() {
+                defer wg.Done()
+                <-generatorsCtx.Done()
+                // Generators only start while holding the lock with ctx live, so once the lock has
+                // been taken here none can start behind Wait's back
+                s.mutex.Lock()
+                for _, device := range s.virtualDevices {
+                        device.IsRunning = false
+                }
+                s.stopChannels = make(map[string]chan bool)
+                s.mutex.Unlock()
+                s.generators.Wait()
+                s.logger.Info("Virtual device data generation stopped")
+        }()
+        
         s.logger.Info("Device Virtual Service initialization completed")
         return true
 }
 
+// Shutdown implements the bootstrap.ShutdownHandler interface. It stops every data generator and
+// waits for them to return, or for ctx to be done.
+func (s *DeviceVirtualService) Shutdown(ctx context.Context) error {
+        s.mutex.Lock()
+        if s.cancel != nil {
+                s.cancel()
+        }
+        s.mutex.Unlock()
+        
+        stopped := make(chan struct{})
+        go func() {
+                s.generators.Wait()
+                close(stopped)
+        }()
+        select {
+        case <-stopped:
+                return nil
+        case <-ctx.Done():
+                return fmt.Errorf("virtual device generators still running: %w", ctx.Err())
+        }
+}
+
 // AddRoutes adds device virtual specific routes
 func (s *DeviceVirtualService) AddRoutes(router *mux.Router) {
         // Virtual device management routes
         router.HandleFunc("/api/v3/device/virtual", s.getAllVirtualDevices).Methods("GET")
         router.HandleFunc("/api/v3/device/virtual", s.createVirtualDevice).Methods("POST")
+        router.HandleFunc("/api/v3/device/virtual/start-all", s.startAllDevices).Methods("POST")
+        router.HandleFunc("/api/v3/device/virtual/stop-all", s.stopAllDevices).Methods("POST")
+        router.HandleFunc("/api/v3/device/virtual/batch", s.createVirtualDevices).Methods("POST")
         router.HandleFunc("/api/v3/device/virtual/{id}", s.getVirtualDevice).Methods("GET")
         router.HandleFunc("/api/v3/device/virtual/{id}", s.updateVirtualDevice).Methods("PUT")
         router.HandleFunc("/api/v3/device/virtual/{id}", s.deleteVirtualDevice).Methods("DELETE")
         router.HandleFunc("/api/v3/device/virtual/{id}/start", s.startDevice).Methods("POST")
         router.HandleFunc("/api/v3/device/virtual/{id}/stop", s.stopDevice).Methods("POST")
+        router.HandleFunc("/api/v3/device/virtual/{id}/status", s.getDeviceStatus).Methods("GET")
+        router.HandleFunc("/api/v3/device/virtual/{id}/readings", s.getDeviceReadings).Methods("GET")
+        router.HandleFunc("/api/v3/device/virtual/{id}/readings/stats", s.getDeviceReadingStats).Methods("GET")
+        router.HandleFunc("/api/v3/device/virtual/{id}/clone", s.cloneVirtualDevice).Methods("POST")
+        
+        // Device service command routes, called by core-command
+        router.HandleFunc(common.ApiCommandRoute, s.readCommand).Methods("GET")
+        router.HandleFunc(common.ApiCommandRoute, s.writeCommand).Methods("PUT")
         
         s.logger.Info("Device Virtual routes registered")
 }
@@ -97,6 +207,7 @@ func (s *DeviceVirtualService) initializeDefaultDevices() {
                                 "type":    "temperature",
                         },
                         IsRunning: false,
+                        AutoStart: true,
                 },
                 {
                         Id:             models.GenerateUUID(),
@@ -111,6 +222,7 @@ func (s *DeviceVirtualService) initializeDefaultDevices() {
                                 "type":    "humidity",
                         },
                         IsRunning: false,
+                        AutoStart: true,
                 },
                 {
                         Id:             models.GenerateUUID(),
@@ -125,6 +237,7 @@ func (s *DeviceVirtualService) initializeDefaultDevices() {
                                 "type":    "pressure",
                         },
                         IsRunning: false,
+                        AutoStart: true,
                 },
         }
         
@@ -135,84 +248,125 @@ func (s *DeviceVirtualService) initializeDefaultDevices() {
         s.logger.Infof("Initialized %d default virtual devices", len(devices))
 }
 
-// startDataGeneration begins generating simulated sensor data
+// startDataGeneration begins generating simulated sensor data for the devices set to auto start
 func (s *DeviceVirtualService) startDataGeneration() {
-        s.mutex.RLock()
+        s.mutex.Lock()
         for _, device := range s.virtualDevices {
-                if !device.IsRunning {
-                        device.IsRunning = true
-                        s.stopChannels[device.Id] = make(chan bool)
-                        go s.generateDeviceData(device)
+                if device.AutoStart && !device.IsRunning {
+                        s.resetHistoryLocked(device.Id, s.clock.Now())
+                        s.startGeneratorLocked(device)
                 }
         }
-        s.mutex.RUnlock()
+        s.mutex.Unlock()
+}
+
+// startGeneratorLocked starts generating data for device at its interval until it is stopped or the
+// service shuts down. Caller must hold the lock.
+func (s *DeviceVirtualService) startGeneratorLocked(device *VirtualDevice) {
+        interval, err := generationInterval(device)
+        if err != nil {
+                // Intervals are checked when devices are saved, so this is unexpected
+                s.logger.Warnf("Device %s: %v, generating every %s", device.Name, err, DefaultInterval)
+                interval = DefaultInterval
+        }
+        stop := make(chan bool)
+        device.IsRunning = true
+        s.stopChannels[device.Id] = stop
+        s.generators.Add(1)
+        go s.generateDeviceData(s.ctx, device.Id, device.Name, interval, stop)
+}
+
+// stopGeneratorLocked stops the device's data generator, if it is running. Caller must hold the lock.
+func (s *DeviceVirtualService) stopGeneratorLocked(device *VirtualDevice) {
+        if !device.IsRunning {
+                return
+        }
+        device.IsRunning = false
+        if stop, exists := s.stopChannels[device.Id]; exists {
+                close(stop)
+                delete(s.stopChannels, device.Id)
+        }
 }
 
-// generateDeviceData simulates sensor readings for a virtual device
-func (s *DeviceVirtualService) generateDeviceData(device *VirtualDevice) {
-        ticker := time.NewTicker(5 * time.Second) // Generate data every 5 seconds
+// generateDeviceData simulates a sensor reading for a virtual device every interval until stop is
+// closed or ctx is cancelled. It only refers to the device by id, so it picks up updates and never
+// touches the device without holding the lock.
+func (s *DeviceVirtualService) generateDeviceData(ctx context.Context, id, name string, interval time.Duration, stop <-chan bool) {
+        defer s.generators.Done()
+        ticker := s.clock.NewTicker(interval)
         defer ticker.Stop()
         
         for {
                 select {
-                case <-ticker.C:
-                        s.publishSensorReading(device)
-                case <-s.stopChannels[device.Id]:
-                        s.logger.Infof("Stopping data generation for device: %s", device.Name)
+                case <-ticker.C():
+                        s.publishSensorReading(ctx, id)
+                case <-stop:
+                        s.logger.Infof("Stopping data generation for device: %s", name)
+                        return
+                case <-ctx.Done():
+                        s.logger.Debugf("Data generation for device %s stopped on shutdown", name)
                         return
                 }
         }
 }
 
-// publishSensorReading creates and publishes a sensor reading event
-func (s *DeviceVirtualService) publishSensorReading(device *VirtualDevice) {
-        reading := s.generateReading(device)
+// publishSensorReading creates a sensor reading for the device with the given id and publishes it as an
+// event, counting the device's publish errors
+func (s *DeviceVirtualService) publishSensorReading(ctx context.Context, id string) {
+        // Work from a copy so the reading is generated without holding the lock
+        s.mutex.Lock()
+        stored, exists := s.virtualDevices[id]
+        var device VirtualDevice
+        var generator readingGenerator
+        if exists {
+                device = *stored
+                generator = s.readingGeneratorLocked(stored)
+        }
+        s.mutex.Unlock()
+        if !exists {
+                return
+        }
         
-        // In a real implementation, this would publish to Core Data service
-        s.logger.Debugf("Generated reading for device %s: %v", device.Name, reading.SimpleReading.Value)
+        now := s.clock.Now()
+        reading := generator.next(device, now)
         
-        device.LastReading = time.Now()
+        var err error
+        published := s.publishing()
+        if published {
+                err = s.publishEvent(ctx, newReadingEvent(device, reading, now))
+                if err != nil {
+                        s.logger.Warnf("Failed to publish reading for device %s: %v", device.Name, err)
+                }
+        } else {
+                s.logger.Debugf("Generated reading for device %s: %v", device.Name, reading.SimpleReading.Value)
+        }
+        
+        s.mutex.Lock()
+        if stored, exists := s.virtualDevices[id]; exists {
+                stored.LastReading = now
+                s.recordReadingLocked(id, reading, now)
+                if err != nil {
+                        stored.PublishErrors++
+                } else if published {
+                        stored.LastPublishTime = s.clock.Now()
+                }
+        }
+        s.mutex.Unlock()
 }
 
-// generateReading creates a simulated sensor reading based on device type
-func (s *DeviceVirtualService) generateReading(device *VirtualDevice) models.Reading {
-        var value string
-        var units string
-        var resourceName string
-        var valueType string
-        
-        deviceType := device.Protocols["type"]
-        
-        switch deviceType {
-        case "temperature":
-                temp := 20.0 + rand.Float64()*15.0 // 20-35°C
-                value = fmt.Sprintf("%.2f", temp)
-                units = "Celsius"
-                resourceName = "Temperature"
-                valueType = common.ValueTypeFloat64
-        case "humidity":
-                humidity := 30.0 + rand.Float64()*40.0 // 30-70%
-                value = fmt.Sprintf("%.2f", humidity)
-                units = "Percent"
-                resourceName = "Humidity"
-                valueType = common.ValueTypeFloat64
-        case "pressure":
-                pressure := 1013.0 + rand.Float64()*20.0 // 1013-1033 hPa
-                value = fmt.Sprintf("%.2f", pressure)
-                units = "hPa"
-                resourceName = "Pressure"
-                valueType = common.ValueTypeFloat64
-        default:
-                genericValue := rand.Float64() * 100.0
-                value = fmt.Sprintf("%.2f", genericValue)
-                units = "Units"
-                resourceName = "GenericSensor"
-                valueType = common.ValueTypeFloat64
-        }
-        
-        reading := models.NewSimpleReading(device.ProfileName, device.Name, resourceName, valueType, value)
-        reading.SimpleReading.Units = units
-        return reading
+// readingGeneratorLocked returns the generator of device, building it if needed. Caller must hold the write lock.
+func (s *DeviceVirtualService) readingGeneratorLocked(device *VirtualDevice) readingGenerator {
+        if generator, exists := s.readingGenerators[device.Id]; exists {
+                return generator
+        }
+        generator, err := newReadingGenerator(device)
+        if err != nil {
+                // Generation parameters are checked when devices are saved, so this is unexpected
+                s.logger.Warnf("Device %s: %v, generating with defaults", device.Name, err)
+                generator, _ = newReadingGenerator(&VirtualDevice{Protocols: device.Protocols})
+        }
+        s.readingGenerators[device.Id] = generator
+        return generator
 }
 
 // HTTP Handlers
@@ -221,18 +375,33 @@ func (s *DeviceVirtualService) generateReading(device *VirtualDevice) models.Rea
 func (s *DeviceVirtualService) getAllVirtualDevices(w http.ResponseWriter, r *http.Request) {
         w.Header().Set(common.ContentType, common.ContentTypeJSON)
         
+        offset, limit, err := common.ParsePagination(r)
+        if err != nil {
+                common.WriteError(w, http.StatusBadRequest, err.Error())
+                return
+        }
+        
+        // Copy the devices so generators can keep updating them while the response is encoded
         s.mutex.RLock()
-        devices := make([]*VirtualDevice, 0, len(s.virtualDevices))
+        devices := make([]VirtualDevice, 0, len(s.virtualDevices))
         for _, device := range s.virtualDevices {
-                devices = append(devices, device)
+                devices = append(devices, *device)
         }
         s.mutex.RUnlock()
+        // Map order differs between requests, so sort before paging to keep pages from overlapping
+        sort.Slice(devices, func(i, j int) bool {
+                if devices[i].Name != devices[j].Name {
+                        return devices[i].Name < devices[j].Name
+                }
+                return devices[i].Id < devices[j].Id
+        })
+        page, totalCount := common.Paginate(devices, offset, limit)
         
         response := map[string]interface{}{
                 "apiVersion":     common.ServiceVersion,
                 "statusCode":     http.StatusOK,
-                "totalCount":     len(devices),
-                "virtualDevices": devices,
+                "totalCount":     totalCount,
+                "virtualDevices": page,
         }
         
         json.NewEncoder(w).Encode(response)
@@ -243,28 +412,34 @@ func (s *DeviceVirtualService) createVirtualDevice(w http.ResponseWriter, r *htt
         w.Header().Set(common.ContentType, common.ContentTypeJSON)
         
         var device VirtualDevice
-        if err := json.NewDecoder(r.Body).Decode(&device); err != nil {
+        if err := common.DecodeJSON(w, r, &device); err != nil {
                 s.logger.Errorf("Failed to decode virtual device: %v", err)
-                http.Error(w, "Invalid JSON", http.StatusBadRequest)
                 return
         }
-        
-        // Generate ID and set defaults
-        device.Id = models.GenerateUUID()
-        device.ServiceName = common.DeviceVirtualServiceKey
-        device.IsRunning = false
-        
-        if device.AdminState == "" {
-                device.AdminState = common.Unlocked
+        if _, err := generationInterval(&device); err != nil {
+                common.WriteError(w, http.StatusBadRequest, err.Error())
+                return
         }
-        if device.OperatingState == "" {
-                device.OperatingState = common.Up
+        if _, err := newReadingGenerator(&device); err != nil {
+                common.WriteError(w, http.StatusBadRequest, err.Error())
+                return
         }
         
+        prepareNewDevice(&device)
+        
+        // Commands find devices by name, so names must be unique
         s.mutex.Lock()
-        s.virtualDevices[device.Id] = &device
+        _, taken := s.deviceByNameLocked(device.Name)
+        if !taken {
+                s.virtualDevices[device.Id] = &device
+        }
         s.mutex.Unlock()
         
+        if taken {
+                common.WriteError(w, http.StatusConflict, fmt.Sprintf("Virtual device %s already exists", device.Name))
+                return
+        }
+        
         s.logger.Infof("Virtual device created: %s", device.Name)
         
         response := map[string]interface{}{
@@ -277,6 +452,24 @@ func (s *DeviceVirtualService) createVirtualDevice(w http.ResponseWriter, r *htt
         json.NewEncoder(w).Encode(response)
 }
 
+// prepareNewDevice gives a device about to be created its id and defaults, and clears any runtime state
+// that came with it
+func prepareNewDevice(device *VirtualDevice) {
+        device.Id = models.GenerateUUID()
+        device.ServiceName = common.DeviceVirtualServiceKey
+        device.IsRunning = false
+        device.LastReading = time.Time{}
+        device.PublishErrors = 0
+        device.LastPublishTime = time.Time{}
+        
+        if device.AdminState == "" {
+                device.AdminState = common.Unlocked
+        }
+        if device.OperatingState == "" {
+                device.OperatingState = common.Up
+        }
+}
+
 // getVirtualDevice handles GET /api/v3/device/virtual/{id}
 func (s *DeviceVirtualService) getVirtualDevice(w http.ResponseWriter, r *http.Request) {
         w.Header().Set(common.ContentType, common.ContentTypeJSON)
@@ -285,11 +478,15 @@ func (s *DeviceVirtualService) getVirtualDevice(w http.ResponseWriter, r *http.R
         id := vars["id"]
         
         s.mutex.RLock()
-        device, exists := s.virtualDevices[id]
+        stored, exists := s.virtualDevices[id]
+        var device VirtualDevice
+        if exists {
+                device = *stored
+        }
         s.mutex.RUnlock()
         
         if !exists {
-                http.Error(w, "Virtual device not found", http.StatusNotFound)
+                common.WriteError(w, http.StatusNotFound, "Virtual device not found")
                 return
         }
         
@@ -310,22 +507,56 @@ func (s *DeviceVirtualService) updateVirtualDevice(w http.ResponseWriter, r *htt
         id := vars["id"]
         
         var updatedDevice VirtualDevice
-        if err := json.NewDecoder(r.Body).Decode(&updatedDevice); err != nil {
-                http.Error(w, "Invalid JSON", http.StatusBadRequest)
+        if err := common.DecodeJSON(w, r, &updatedDevice); err != nil {
+                return
+        }
+        interval, err := generationInterval(&updatedDevice)
+        if err != nil {
+                common.WriteError(w, http.StatusBadRequest, err.Error())
+                return
+        }
+        if _, err := newReadingGenerator(&updatedDevice); err != nil {
+                common.WriteError(w, http.StatusBadRequest, err.Error())
                 return
         }
         
         s.mutex.Lock()
         existingDevice, exists := s.virtualDevices[id]
+        taken := false
         if exists {
+                other, found := s.deviceByNameLocked(updatedDevice.Name)
+                taken = found && other.Id != id
+        }
+        if exists && !taken {
                 updatedDevice.Id = id
                 updatedDevice.IsRunning = existingDevice.IsRunning
+                updatedDevice.LastReading = existingDevice.LastReading
+                updatedDevice.PublishErrors = existingDevice.PublishErrors
+                updatedDevice.LastPublishTime = existingDevice.LastPublishTime
                 s.virtualDevices[id] = &updatedDevice
+                // Counters, doors and waves carry on through other changes, such as to the interval,
+                // but start over with a new type or generation
+                if !sameGeneration(existingDevice, &updatedDevice) {
+                        delete(s.readingGenerators, id)
+                }
+                
+                // A running generator keeps its cadence, so a new interval needs a new generator
+                if previous, _ := generationInterval(existingDevice); existingDevice.IsRunning && previous != interval {
+                        s.stopGeneratorLocked(existingDevice)
+                        updatedDevice.IsRunning = false
+                        if s.ctx.Err() == nil {
+                                s.startGeneratorLocked(&updatedDevice)
+                        }
+                }
         }
         s.mutex.Unlock()
         
         if !exists {
-                http.Error(w, "Virtual device not found", http.StatusNotFound)
+                common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+                return
+        }
+        if taken {
+                common.WriteError(w, http.StatusConflict, fmt.Sprintf("Virtual device %s already exists", updatedDevice.Name))
                 return
         }
         
@@ -349,16 +580,16 @@ func (s *DeviceVirtualService) deleteVirtualDevice(w http.ResponseWriter, r *htt
         device, exists := s.virtualDevices[id]
         if exists {
                 // Stop data generation if running
-                if device.IsRunning {
-                        close(s.stopChannels[id])
-                        delete(s.stopChannels, id)
-                }
+                s.stopGeneratorLocked(device)
                 delete(s.virtualDevices, id)
+                delete(s.readingGenerators, id)
+                delete(s.histories, id)
+                delete(s.latestReadings, id)
         }
         s.mutex.Unlock()
         
         if !exists {
-                http.Error(w, "Virtual device not found", http.StatusNotFound)
+                common.WriteError(w, http.StatusNotFound, "Virtual device not found")
                 return
         }
         
@@ -380,15 +611,19 @@ func (s *DeviceVirtualService) startDevice(w http.ResponseWriter, r *http.Reques
         
         s.mutex.Lock()
         device, exists := s.virtualDevices[id]
-        if exists && !device.IsRunning {
-                device.IsRunning = true
-                s.stopChannels[id] = make(chan bool)
-                go s.generateDeviceData(device)
+        shuttingDown := s.ctx.Err() != nil
+        if exists && !device.IsRunning && !shuttingDown {
+                s.resetHistoryLocked(device.Id, s.clock.Now())
+                s.startGeneratorLocked(device)
         }
         s.mutex.Unlock()
         
         if !exists {
-                http.Error(w, "Virtual device not found", http.StatusNotFound)
+                common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+                return
+        }
+        if shuttingDown {
+                common.WriteError(w, http.StatusServiceUnavailable, "Service is shutting down")
                 return
         }
         
@@ -412,15 +647,13 @@ func (s *DeviceVirtualService) stopDevice(w http.ResponseWriter, r *http.Request
         
         s.mutex.Lock()
         device, exists := s.virtualDevices[id]
-        if exists && device.IsRunning {
-                device.IsRunning = false
-                close(s.stopChannels[id])
-                delete(s.stopChannels, id)
+        if exists {
+                s.stopGeneratorLocked(device)
         }
         s.mutex.Unlock()
         
         if !exists {
-                http.Error(w, "Virtual device not found", http.StatusNotFound)
+                common.WriteError(w, http.StatusNotFound, "Virtual device not found")
                 return
         }
         
@@ -432,5 +665,72 @@ func (s *DeviceVirtualService) stopDevice(w http.ResponseWriter, r *http.Request
                 "message":    "Virtual device stopped successfully",
         }
         
+        json.NewEncoder(w).Encode(response)
+}
+
+// startAllDevices handles POST /api/v3/device/virtual/start-all. Devices already running are left
+// as they are; the response counts the devices started and those that already were running.
+func (s *DeviceVirtualService) startAllDevices(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set(common.ContentType, common.ContentTypeJSON)
+        
+        started, alreadyRunning := 0, 0
+        s.mutex.Lock()
+        shuttingDown := s.ctx.Err() != nil
+        if !shuttingDown {
+                for _, device := range s.virtualDevices {
+                        if device.IsRunning {
+                                alreadyRunning++
+                                continue
+                        }
+                        s.resetHistoryLocked(device.Id, s.clock.Now())
+                        s.startGeneratorLocked(device)
+                        started++
+                }
+        }
+        s.mutex.Unlock()
+        
+        if shuttingDown {
+                common.WriteError(w, http.StatusServiceUnavailable, "Service is shutting down")
+                return
+        }
+        
+        s.logger.Infof("Started %d virtual devices", started)
+        
+        response := map[string]interface{}{
+                "apiVersion":     common.ServiceVersion,
+                "statusCode":     http.StatusOK,
+                "started":        started,
+                "alreadyRunning": alreadyRunning,
+        }
+        
+        json.NewEncoder(w).Encode(response)
+}
+
+// stopAllDevices handles POST /api/v3/device/virtual/stop-all. Devices already stopped are left as
+// they are; the response counts the devices stopped and those that already were stopped.
+func (s *DeviceVirtualService) stopAllDevices(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set(common.ContentType, common.ContentTypeJSON)
+        
+        stopped, alreadyStopped := 0, 0
+        s.mutex.Lock()
+        for _, device := range s.virtualDevices {
+                if !device.IsRunning {
+                        alreadyStopped++
+                        continue
+                }
+                s.stopGeneratorLocked(device)
+                stopped++
+        }
+        s.mutex.Unlock()
+        
+        s.logger.Infof("Stopped %d virtual devices", stopped)
+        
+        response := map[string]interface{}{
+                "apiVersion":     common.ServiceVersion,
+                "statusCode":     http.StatusOK,
+                "stopped":        stopped,
+                "alreadyStopped": alreadyStopped,
+        }
+        
         json.NewEncoder(w).Encode(response)
 }
\ No newline at end of file
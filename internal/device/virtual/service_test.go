@@ -0,0 +1,542 @@
+package virtual
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestService(clock common.Clock) *DeviceVirtualService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	service := NewDeviceVirtualService(logger)
+	service.SetClock(clock)
+	return service
+}
+
+func newTestRouter(service *DeviceVirtualService) *mux.Router {
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	return router
+}
+
+// lastReadings returns when each device last generated a reading
+func lastReadings(service *DeviceVirtualService) map[string]time.Time {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	readings := make(map[string]time.Time)
+	for id, device := range service.virtualDevices {
+		readings[id] = device.LastReading
+	}
+	return readings
+}
+
+func TestDeviceVirtualService_GeneratesOnClock(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.True(t, service.Initialize(ctx, &sync.WaitGroup{}, bootstrap.NewDIContainer()))
+
+	clock.BlockUntil(3)
+	clock.Advance(5 * time.Second)
+	assert.Eventually(t, func() bool {
+		for _, last := range lastReadings(service) {
+			if !last.Equal(testStart.Add(5 * time.Second)) {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDeviceVirtualService_StopsGeneratorsOnShutdown(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	clock.BlockUntil(3)
+
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("generators did not stop on shutdown")
+	}
+
+	// Every ticker is released and nothing is generated afterwards
+	assert.Zero(t, clock.Waiters())
+	clock.Advance(time.Minute)
+	for id, last := range lastReadings(service) {
+		assert.True(t, last.IsZero(), "device %s generated a reading after shutdown", id)
+	}
+	for _, device := range service.virtualDevices {
+		assert.False(t, device.IsRunning, device.Name)
+	}
+	assert.Empty(t, service.stopChannels)
+
+	// Devices cannot be restarted once the service is shutting down
+	var id string
+	for id = range service.virtualDevices {
+		break
+	}
+	rr := httptest.NewRecorder()
+	newTestRouter(service).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v3/device/virtual/"+id+"/start", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Zero(t, clock.Waiters())
+}
+
+func TestDeviceVirtualService_Shutdown(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	clock.BlockUntil(3)
+
+	// Shutdown stops the generators without waiting for ctx
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shutdownCancel()
+	require.NoError(t, service.Shutdown(shutdownCtx))
+	assert.Zero(t, clock.Waiters())
+
+	var id string
+	for id = range service.virtualDevices {
+		break
+	}
+	rr := httptest.NewRecorder()
+	newTestRouter(service).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v3/device/virtual/"+id+"/start", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("service did not stop after Shutdown")
+	}
+}
+
+func TestDeviceVirtualService_StopDevice(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.True(t, service.Initialize(ctx, &sync.WaitGroup{}, bootstrap.NewDIContainer()))
+	clock.BlockUntil(3)
+
+	var id string
+	for id = range service.virtualDevices {
+		break
+	}
+	rr := httptest.NewRecorder()
+	newTestRouter(service).ServeHTTP(rr, httptest.NewRequest("POST", "/api/v3/device/virtual/"+id+"/stop", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Eventually(t, func() bool { return clock.Waiters() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+// TestDeviceVirtualService_ConcurrentControl starts, stops, updates and reads devices while their
+// generators run. Run with -race to catch unguarded access to device state.
+func TestDeviceVirtualService_ConcurrentControl(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	router := newTestRouter(service)
+
+	var ids []string
+	for id := range service.virtualDevices {
+		ids = append(ids, id)
+	}
+	request := func(method, path, body string) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+		router.ServeHTTP(rr, req)
+	}
+
+	done := make(chan struct{})
+	var clients sync.WaitGroup
+	clients.Add(1)
+	go func() {
+		defer clients.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				clock.Advance(5 * time.Second)
+			}
+		}
+	}()
+	for _, id := range ids {
+		clients.Add(1)
+		go func(id string) {
+			defer clients.Done()
+			for i := 0; i < 50; i++ {
+				request("POST", "/api/v3/device/virtual/"+id+"/stop", "")
+				request("POST", "/api/v3/device/virtual/"+id+"/start", "")
+				request("GET", "/api/v3/device/virtual/"+id, "")
+				request("PUT", "/api/v3/device/virtual/"+id, `{"name":"renamed-`+id+`","protocols":{"type":"temperature"}}`)
+				request("GET", "/api/v3/device/virtual", "")
+			}
+		}(id)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(done)
+	clients.Wait()
+
+	cancel()
+	wg.Wait()
+	for _, device := range service.virtualDevices {
+		assert.Equal(t, "renamed-"+device.Id, device.Name)
+		assert.False(t, device.IsRunning)
+	}
+}
+
+// assertGeneratorsConsistent checks that exactly the running devices have a stop channel
+func assertGeneratorsConsistent(t *testing.T, service *DeviceVirtualService) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	for id, device := range service.virtualDevices {
+		_, hasStop := service.stopChannels[id]
+		assert.Equal(t, device.IsRunning, hasStop, "device %s", device.Name)
+	}
+	for id := range service.stopChannels {
+		assert.Contains(t, service.virtualDevices, id, "stop channel of a deleted device")
+	}
+}
+
+func TestDeviceVirtualService_StressCreateStartStopDelete(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	router := newTestRouter(service)
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	done := make(chan struct{})
+	var clients sync.WaitGroup
+	clients.Add(1)
+	go func() {
+		defer clients.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				clock.Advance(time.Second)
+			}
+		}
+	}()
+	for i := 0; i < 8; i++ {
+		clients.Add(1)
+		go func() {
+			defer clients.Done()
+			for j := 0; j < 30; j++ {
+				rr := request("POST", "/api/v3/device/virtual", `{"name":"stress","interval":"1s","protocols":{"type":"pressure"}}`)
+				var created struct {
+					Id string `json:"id"`
+				}
+				if !assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created)) {
+					return
+				}
+				path := "/api/v3/device/virtual/" + created.Id
+				request("POST", path+"/start", "")
+				request("PUT", path, `{"name":"stress","interval":"2s","protocols":{"type":"pressure"}}`)
+				if j%3 == 0 {
+					request("POST", path+"/stop", "")
+				}
+				if j%2 == 0 {
+					request("POST", "/api/v3/device/virtual/start-all", "")
+				} else {
+					request("POST", "/api/v3/device/virtual/stop-all", "")
+				}
+				assert.Equal(t, http.StatusOK, request("DELETE", path, "").Code)
+				// Deleting again, or controlling a deleted device, is a 404 rather than a panic
+				assert.Equal(t, http.StatusNotFound, request("DELETE", path, "").Code)
+				assert.Equal(t, http.StatusNotFound, request("POST", path+"/start", "").Code)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(done)
+	clients.Wait()
+	assertGeneratorsConsistent(t, service)
+
+	// Only the default devices are left and every generator still running stops on shutdown
+	assert.Len(t, service.virtualDevices, 3)
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("generators did not stop on shutdown")
+	}
+	assert.Zero(t, clock.Waiters())
+	assertGeneratorsConsistent(t, service)
+}
+
+func TestDeviceVirtualService_StartAllStopAll(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.virtualDevices = make(map[string]*VirtualDevice)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	router := newTestRouter(service)
+
+	request := func(method, path, body string) map[string]interface{} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+		router.ServeHTTP(rr, req)
+		require.Less(t, rr.Code, 300, rr.Body.String())
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	var ids []string
+	for _, name := range []string{"sensor-1", "sensor-2", "sensor-3"} {
+		response := request("POST", "/api/v3/device/virtual", `{"name":"`+name+`","protocols":{"type":"temperature"}}`)
+		ids = append(ids, response["id"].(string))
+	}
+	// One device is already running before start-all
+	request("POST", "/api/v3/device/virtual/"+ids[0]+"/start", "")
+	clock.BlockUntil(1)
+
+	response := request("POST", "/api/v3/device/virtual/start-all", "")
+	assert.Equal(t, float64(2), response["started"])
+	assert.Equal(t, float64(1), response["alreadyRunning"])
+	clock.BlockUntil(3)
+	for _, id := range ids {
+		assert.True(t, service.virtualDevices[id].IsRunning)
+		assert.Contains(t, service.stopChannels, id)
+	}
+
+	response = request("POST", "/api/v3/device/virtual/start-all", "")
+	assert.Equal(t, float64(0), response["started"])
+	assert.Equal(t, float64(3), response["alreadyRunning"])
+	assert.Len(t, service.stopChannels, 3)
+
+	response = request("POST", "/api/v3/device/virtual/stop-all", "")
+	assert.Equal(t, float64(3), response["stopped"])
+	assert.Equal(t, float64(0), response["alreadyStopped"])
+	assert.Eventually(t, func() bool { return clock.Waiters() == 0 }, time.Second, 10*time.Millisecond)
+	for _, id := range ids {
+		assert.False(t, service.virtualDevices[id].IsRunning)
+	}
+	assert.Empty(t, service.stopChannels)
+
+	response = request("POST", "/api/v3/device/virtual/stop-all", "")
+	assert.Equal(t, float64(0), response["stopped"])
+	assert.Equal(t, float64(3), response["alreadyStopped"])
+}
+
+func TestDeviceVirtualService_GetAllPaginationIsStable(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	service.virtualDevices = make(map[string]*VirtualDevice)
+	router := newTestRouter(service)
+
+	names := []string{"sensor-3", "sensor-1", "sensor-2", "sensor-8", "sensor-5", "sensor-4", "sensor-7", "sensor-6"}
+	for _, name := range names {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/v3/device/virtual", strings.NewReader(`{"name":"`+name+`"}`))
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	getPage := func(offset int) []VirtualDevice {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v3/device/virtual?limit=3&offset="+strconv.Itoa(offset), nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response struct {
+			TotalCount     int             `json:"totalCount"`
+			VirtualDevices []VirtualDevice `json:"virtualDevices"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, len(names), response.TotalCount)
+		return response.VirtualDevices
+	}
+
+	// Page through several times; every pass must return each device exactly once, in the same order
+	var first []VirtualDevice
+	for pass := 0; pass < 5; pass++ {
+		var devices []VirtualDevice
+		for offset := 0; offset < len(names); offset += 3 {
+			devices = append(devices, getPage(offset)...)
+		}
+		require.Len(t, devices, len(names))
+		seen := make(map[string]bool)
+		for i, device := range devices {
+			assert.False(t, seen[device.Id], "device %s repeated", device.Id)
+			seen[device.Id] = true
+			if i > 0 {
+				previous := devices[i-1]
+				assert.True(t, previous.Name < device.Name || previous.Name == device.Name && previous.Id < device.Id)
+			}
+		}
+		if first == nil {
+			first = devices
+		}
+		assert.Equal(t, first, devices)
+	}
+}
+
+// readingAt reports whether the device last generated a reading at at
+func readingAt(service *DeviceVirtualService, id string, at time.Time) func() bool {
+	return func() bool { return lastReadings(service)[id].Equal(at) }
+}
+
+func TestDeviceVirtualService_Interval(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.virtualDevices = map[string]*VirtualDevice{
+		"fast":   {Id: "fast", Name: "fast", Interval: "1s", AutoStart: true},
+		"manual": {Id: "manual", Name: "manual", Interval: "1s"},
+	}
+	service.logger.SetLevel(logrus.InfoLevel)
+	hook := test.NewLocal(service.logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	router := newTestRouter(service)
+
+	// Only the auto-start device generates, at its own interval
+	clock.BlockUntil(1)
+	assert.False(t, service.virtualDevices["manual"].IsRunning)
+	clock.Advance(time.Second)
+	assert.Eventually(t, readingAt(service, "fast", testStart.Add(time.Second)), time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	assert.Eventually(t, readingAt(service, "fast", testStart.Add(2*time.Second)), time.Second, time.Millisecond)
+	assert.True(t, lastReadings(service)["manual"].IsZero())
+
+	// Changing the interval of a running device restarts its generator at the new cadence
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/api/v3/device/virtual/fast", strings.NewReader(`{"name":"fast","interval":"3s"}`))
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, service.virtualDevices["fast"].IsRunning)
+	assert.Eventually(t, func() bool {
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "Stopping data generation for device: fast" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond, "the old generator stops")
+	clock.BlockUntil(1)
+
+	clock.Advance(2 * time.Second)
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, readingAt(service, "fast", testStart.Add(2*time.Second))(), "no reading before the new interval")
+	clock.Advance(time.Second)
+	assert.Eventually(t, readingAt(service, "fast", testStart.Add(5*time.Second)), time.Second, time.Millisecond)
+}
+
+func TestDeviceVirtualService_DuplicateName(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	humidity := deviceNamed(t, service, "Virtual-Humidity-Sensor-01")
+	count := len(service.virtualDevices)
+
+	// Commands address devices by name, so a second device may not take one in use
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"Virtual-Temperature-Sensor-01"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	assert.Len(t, service.virtualDevices, count)
+
+	rr = sendJSONRequest(service, http.MethodPut, "/api/v3/device/virtual/"+humidity, `{"name":"Virtual-Temperature-Sensor-01"}`)
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	assert.Equal(t, "Virtual-Humidity-Sensor-01", service.virtualDevices[humidity].Name)
+
+	// A device keeping its own name is not a conflict
+	rr = sendJSONRequest(service, http.MethodPut, "/api/v3/device/virtual/"+humidity, `{"name":"Virtual-Humidity-Sensor-01","interval":"1m"}`)
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Nor is a name that is free
+	rr = sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", `{"name":"Virtual-Flow-Sensor-01"}`)
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+}
+
+func TestDeviceVirtualService_IntervalValidation(t *testing.T) {
+	service := newTestService(common.NewFakeClock(testStart))
+	router := newTestRouter(service)
+	var id string
+	for id = range service.virtualDevices {
+		break
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{"Create with interval", "POST", "/api/v3/device/virtual", `{"name":"d-1","interval":"250ms"}`, http.StatusCreated},
+		{"Create with minimum interval", "POST", "/api/v3/device/virtual", `{"name":"d-2","interval":"100ms"}`, http.StatusCreated},
+		{"Create too fast", "POST", "/api/v3/device/virtual", `{"name":"d-3","interval":"50ms"}`, http.StatusBadRequest},
+		{"Create unparseable", "POST", "/api/v3/device/virtual", `{"name":"d-4","interval":"often"}`, http.StatusBadRequest},
+		{"Update too fast", "PUT", "/api/v3/device/virtual/" + id, `{"name":"d-5","interval":"99ms"}`, http.StatusBadRequest},
+		{"Update", "PUT", "/api/v3/device/virtual/" + id, `{"name":"d-5","interval":"1m"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(common.ContentType, common.ContentTypeJSON)
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+}
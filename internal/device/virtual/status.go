@@ -0,0 +1,105 @@
+package virtual
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// RecentReadingsLimit is how many of a device's latest readings its status reports
+const RecentReadingsLimit = 10
+
+// RecentReading is one of the latest readings of a device
+type RecentReading struct {
+	Time    time.Time `json:"time"`
+	Value   string    `json:"value"`             // binary values are given as their size
+	Anomaly string    `json:"anomaly,omitempty"` // the anomaly injected, if any
+}
+
+// patternReporter is implemented by generators that follow a pattern
+type patternReporter interface {
+	patternState() PatternState
+}
+
+// sameGeneration reports whether two versions of a device generate readings the same way
+func sameGeneration(a, b *VirtualDevice) bool {
+	if a.Protocols["type"] != b.Protocols["type"] {
+		return false
+	}
+	if len(a.Generation) == 0 && len(b.Generation) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Generation, b.Generation)
+}
+
+// recordReadingLocked adds a reading taken at now to the device's history and makes it the latest
+// of its resource. Caller must hold the write lock.
+func (s *DeviceVirtualService) recordReadingLocked(id string, reading models.Reading, now time.Time) {
+	s.storeLatestReadingLocked(id, reading, now)
+
+	recent := RecentReading{Time: now, Value: reading.SimpleReading.Value}
+	if reading.ValueType == common.ValueTypeBinary {
+		recent.Value = fmt.Sprintf("%d bytes", len(reading.BinaryReading.BinaryValue))
+	}
+	if anomaly, ok := reading.Tags[AnomalyTag].(string); ok {
+		recent.Anomaly = anomaly
+	}
+
+	history, exists := s.histories[id]
+	if !exists {
+		history = newReadingHistory(s.historySize, now)
+		s.histories[id] = history
+	}
+	value, numeric := numericValue(reading.ValueType, reading.SimpleReading.Value)
+	history.add(recent, value, numeric)
+}
+
+// getDeviceStatus handles GET /api/v3/device/virtual/{id}/status
+func (s *DeviceVirtualService) getDeviceStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	s.mutex.RLock()
+	stored, exists := s.virtualDevices[id]
+	var device VirtualDevice
+	if exists {
+		device = *stored
+	}
+	generator := s.readingGenerators[id]
+	recent := s.histories[id].latest(RecentReadingsLimit)
+	s.mutex.RUnlock()
+
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Virtual device not found")
+		return
+	}
+
+	status := map[string]interface{}{
+		"id":             device.Id,
+		"name":           device.Name,
+		"isRunning":      device.IsRunning,
+		"lastReading":    device.LastReading,
+		"recentReadings": recent,
+	}
+	// Only float devices follow a pattern, and only once they have generated a reading
+	if reporter, ok := generator.(patternReporter); ok {
+		status["pattern"] = reporter.patternState()
+	}
+
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"status":     status,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,213 @@
+package virtual
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// Patterns of a float device. Waves run between offset-amplitude and offset+amplitude, once per
+// period of the service clock, starting at the device's first reading.
+const (
+	PatternRandom   = "random"   // a fresh value in the range every reading
+	PatternSine     = "sine"     // starts at offset, rising
+	PatternSawtooth = "sawtooth" // rises from the bottom of the range to the top, then drops back
+	PatternStep     = "step"     // the bottom of the range for the first half of each period, the top for the second
+	PatternWalk     = "walk"     // starts at offset and moves by up to a tenth of amplitude each reading
+)
+
+// Anomalies a float device may inject instead of the pattern's value
+const (
+	AnomalySpike   = "spike"   // the value moved up or down by the anomaly magnitude
+	AnomalyDropout = "dropout" // a reading of 0
+)
+
+// More keys of VirtualDevice.Generation, for float devices. Amplitude and offset default to half the
+// width and the middle of min to max, and the anomaly magnitude to the full width.
+const (
+	GenerationAmplitude          = "amplitude"
+	GenerationOffset             = "offset"
+	GenerationPeriod             = "period" // a duration such as 30s
+	GenerationAnomaly            = "anomaly"
+	GenerationAnomalyProbability = "anomalyProbability"
+	GenerationAnomalyMagnitude   = "anomalyMagnitude"
+)
+
+// DefaultPeriod is the period of waves that do not set one
+const DefaultPeriod = time.Minute
+
+// AnomalyTag is the reading tag naming the anomaly injected into a reading
+const AnomalyTag = "anomaly"
+
+// PatternState is how far a float device has got through its pattern
+type PatternState struct {
+	Pattern   string  `json:"pattern"`
+	Phase     float64 `json:"phase"` // fraction of the current period elapsed at the last reading, 0 for random and walk
+	Value     float64 `json:"value"` // the last value of the pattern, before any anomaly
+	Readings  int     `json:"readings"`
+	Anomalies int     `json:"anomalies"`
+}
+
+// floatGenerator generates floats following a pattern, now and then replacing one with an anomaly
+type floatGenerator struct {
+	resourceName       string
+	units              string
+	pattern            string
+	offset, amplitude  float64
+	period             time.Duration
+	anomaly            string
+	anomalyProbability float64
+	anomalyMagnitude   float64
+
+	mutex sync.Mutex
+	start time.Time // of the first reading
+	state PatternState
+}
+
+// floatFactory returns the factory of a float device type with the given resource, units and default range
+func floatFactory(resourceName, units string, defaultMin, defaultMax float64) generatorFactory {
+	return func(generation map[string]string) (readingGenerator, error) {
+		min, err := floatParam(generation, GenerationMin, defaultMin)
+		if err != nil {
+			return nil, err
+		}
+		max, err := floatParam(generation, GenerationMax, defaultMax)
+		if err != nil {
+			return nil, err
+		}
+		if min > max {
+			return nil, fmt.Errorf("min %g is greater than max %g", min, max)
+		}
+
+		generator := &floatGenerator{resourceName: resourceName, units: units, period: DefaultPeriod}
+		if generator.offset, err = floatParam(generation, GenerationOffset, (min+max)/2); err != nil {
+			return nil, err
+		}
+		if generator.amplitude, err = floatParam(generation, GenerationAmplitude, (max-min)/2); err != nil {
+			return nil, err
+		}
+		if generator.amplitude < 0 {
+			return nil, fmt.Errorf("amplitude must not be negative, got %g", generator.amplitude)
+		}
+
+		generator.pattern = PatternRandom
+		if pattern, set := generation[GenerationPattern]; set {
+			generator.pattern = pattern
+		}
+		switch generator.pattern {
+		case PatternRandom, PatternSine, PatternSawtooth, PatternStep, PatternWalk:
+		default:
+			return nil, fmt.Errorf("pattern must be %s, %s, %s, %s or %s, got %q", PatternRandom, PatternSine, PatternSawtooth, PatternStep, PatternWalk, generator.pattern)
+		}
+		if period, set := generation[GenerationPeriod]; set {
+			if generator.period, err = time.ParseDuration(period); err != nil || generator.period <= 0 {
+				return nil, fmt.Errorf("period must be a positive duration such as 30s, got %q", period)
+			}
+		}
+
+		generator.anomaly = AnomalySpike
+		if anomaly, set := generation[GenerationAnomaly]; set {
+			generator.anomaly = anomaly
+		}
+		if generator.anomaly != AnomalySpike && generator.anomaly != AnomalyDropout {
+			return nil, fmt.Errorf("anomaly must be %s or %s, got %q", AnomalySpike, AnomalyDropout, generator.anomaly)
+		}
+		if generator.anomalyProbability, err = floatParam(generation, GenerationAnomalyProbability, 0); err != nil {
+			return nil, err
+		}
+		if generator.anomalyProbability < 0 || generator.anomalyProbability > 1 {
+			return nil, fmt.Errorf("anomalyProbability must be between 0 and 1, got %g", generator.anomalyProbability)
+		}
+		if generator.anomalyMagnitude, err = floatParam(generation, GenerationAnomalyMagnitude, 2*generator.amplitude); err != nil {
+			return nil, err
+		}
+		if generator.anomalyMagnitude < 0 {
+			return nil, fmt.Errorf("anomalyMagnitude must not be negative, got %g", generator.anomalyMagnitude)
+		}
+
+		generator.state.Pattern = generator.pattern
+		return generator, nil
+	}
+}
+
+func (g *floatGenerator) next(device VirtualDevice, now time.Time) models.Reading {
+	g.mutex.Lock()
+	value := g.patternValueLocked(now)
+	g.state.Value = value
+	g.state.Readings++
+	anomaly := ""
+	if g.anomalyProbability > 0 && rand.Float64() < g.anomalyProbability {
+		anomaly = g.anomaly
+		g.state.Anomalies++
+	}
+	g.mutex.Unlock()
+
+	switch anomaly {
+	case AnomalySpike:
+		if rand.Intn(2) == 0 {
+			value += g.anomalyMagnitude
+		} else {
+			value -= g.anomalyMagnitude
+		}
+	case AnomalyDropout:
+		value = 0
+	}
+
+	reading := models.NewSimpleReading(device.ProfileName, device.Name, g.resourceName, common.ValueTypeFloat64, fmt.Sprintf("%.2f", value))
+	reading.SimpleReading.Units = g.units
+	if anomaly != "" {
+		reading.Tags[AnomalyTag] = anomaly
+	}
+	return reading
+}
+
+func (g *floatGenerator) resource() string { return g.resourceName }
+
+// patternValueLocked returns the pattern's value at now. Caller must hold the generator's lock.
+func (g *floatGenerator) patternValueLocked(now time.Time) float64 {
+	first := g.state.Readings == 0
+	if first {
+		g.start = now
+	}
+	elapsed := now.Sub(g.start)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	phase := float64(elapsed%g.period) / float64(g.period)
+
+	low, high := g.offset-g.amplitude, g.offset+g.amplitude
+	switch g.pattern {
+	case PatternSine:
+		g.state.Phase = phase
+		return g.offset + g.amplitude*math.Sin(2*math.Pi*phase)
+	case PatternSawtooth:
+		g.state.Phase = phase
+		return low + 2*g.amplitude*phase
+	case PatternStep:
+		g.state.Phase = phase
+		if phase < 0.5 {
+			return low
+		}
+		return high
+	case PatternWalk:
+		if first {
+			return g.offset
+		}
+		value := g.state.Value + (rand.Float64()*2-1)*g.amplitude/10
+		return math.Max(low, math.Min(high, value))
+	default:
+		return low + rand.Float64()*2*g.amplitude
+	}
+}
+
+// patternState reports how far the generator has got through its pattern
+func (g *floatGenerator) patternState() PatternState {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.state
+}
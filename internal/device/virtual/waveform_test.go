@@ -0,0 +1,178 @@
+package virtual
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// valueAt returns the value generator reads at the given offset from testStart
+func valueAt(t *testing.T, generator readingGenerator, device VirtualDevice, offset time.Duration) float64 {
+	value, err := strconv.ParseFloat(generator.next(device, testStart.Add(offset)).SimpleReading.Value, 64)
+	require.NoError(t, err)
+	return value
+}
+
+func TestFloatGenerator_Sine(t *testing.T) {
+	generator, device := testGenerator(t, DeviceTypeTemperature, map[string]string{
+		GenerationPattern: PatternSine, GenerationOffset: "25", GenerationAmplitude: "5", GenerationPeriod: "40s",
+	})
+
+	assert.InDelta(t, 25, valueAt(t, generator, device, 0), 0.01)
+	assert.InDelta(t, 30, valueAt(t, generator, device, 10*time.Second), 0.01)
+	assert.InDelta(t, 25, valueAt(t, generator, device, 20*time.Second), 0.01)
+	assert.InDelta(t, 20, valueAt(t, generator, device, 30*time.Second), 0.01)
+
+	// The wave repeats every period
+	for offset := time.Duration(0); offset < 40*time.Second; offset += 3 * time.Second {
+		first := valueAt(t, generator, device, offset)
+		assert.InDelta(t, first, valueAt(t, generator, device, offset+40*time.Second), 0.01, "at %s", offset)
+		assert.InDelta(t, first, valueAt(t, generator, device, offset+400*time.Second), 0.01, "at %s", offset)
+	}
+
+	state := generator.(*floatGenerator).patternState()
+	assert.Equal(t, PatternSine, state.Pattern)
+	assert.InDelta(t, 0.975, state.Phase, 0.001) // the last reading was 39s into a period
+	assert.Zero(t, state.Anomalies)
+}
+
+func TestFloatGenerator_Patterns(t *testing.T) {
+	generation := func(pattern string) map[string]string {
+		return map[string]string{GenerationPattern: pattern, GenerationMin: "0", GenerationMax: "10", GenerationPeriod: "10s"}
+	}
+
+	generator, device := testGenerator(t, DeviceTypeHumidity, generation(PatternSawtooth))
+	for i, want := range []float64{0, 2, 4, 6, 8, 0} {
+		assert.InDelta(t, want, valueAt(t, generator, device, time.Duration(i)*2*time.Second), 0.01)
+	}
+
+	generator, device = testGenerator(t, DeviceTypeHumidity, generation(PatternStep))
+	for i, want := range []float64{0, 0, 0, 10, 10, 0} {
+		assert.InDelta(t, want, valueAt(t, generator, device, time.Duration(i)*2*time.Second), 0.01)
+	}
+
+	generator, device = testGenerator(t, DeviceTypeHumidity, generation(PatternWalk))
+	previous := valueAt(t, generator, device, 0)
+	assert.InDelta(t, 5, previous, 0.01)
+	for i := 1; i < 500; i++ {
+		value := valueAt(t, generator, device, time.Duration(i)*time.Second)
+		assert.GreaterOrEqual(t, value, 0.0)
+		assert.LessOrEqual(t, value, 10.0)
+		assert.InDelta(t, previous, value, 0.51)
+		previous = value
+	}
+}
+
+func TestFloatGenerator_Anomalies(t *testing.T) {
+	generator, device := testGenerator(t, DeviceTypePressure, map[string]string{
+		GenerationPattern: PatternStep, GenerationMin: "1000", GenerationMax: "1020", GenerationAnomalyProbability: "1", GenerationAnomalyMagnitude: "100",
+	})
+	for i := 0; i < 20; i++ {
+		reading := generator.next(device, testStart)
+		assert.Equal(t, AnomalySpike, reading.Tags[AnomalyTag])
+		value, err := strconv.ParseFloat(reading.SimpleReading.Value, 64)
+		require.NoError(t, err)
+		assert.Contains(t, []float64{900, 1100}, value)
+	}
+	assert.Equal(t, 20, generator.(*floatGenerator).patternState().Anomalies)
+
+	generator, device = testGenerator(t, DeviceTypePressure, map[string]string{GenerationAnomaly: AnomalyDropout, GenerationAnomalyProbability: "1"})
+	reading := generator.next(device, testStart)
+	assert.Equal(t, AnomalyDropout, reading.Tags[AnomalyTag])
+	assert.Equal(t, "0.00", reading.SimpleReading.Value)
+
+	generator, device = testGenerator(t, DeviceTypePressure, nil)
+	assert.NotContains(t, generator.next(device, testStart).Tags, AnomalyTag)
+}
+
+func TestFloatGenerator_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation map[string]string
+	}{
+		{"Unknown pattern", map[string]string{GenerationPattern: "square"}},
+		{"Negative amplitude", map[string]string{GenerationAmplitude: "-1"}},
+		{"Zero period", map[string]string{GenerationPeriod: "0s"}},
+		{"Period not a duration", map[string]string{GenerationPeriod: "often"}},
+		{"Unknown anomaly", map[string]string{GenerationAnomaly: "glitch"}},
+		{"Anomaly probability above 1", map[string]string{GenerationAnomalyProbability: "1.5"}},
+		{"Negative anomaly magnitude", map[string]string{GenerationAnomalyMagnitude: "-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := VirtualDevice{Protocols: map[string]string{"type": DeviceTypeTemperature}, Generation: tt.generation}
+			_, err := newReadingGenerator(&device)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDeviceVirtualService_Status(t *testing.T) {
+	clock := common.NewFakeClock(testStart)
+	service := newTestService(clock)
+	service.SetMessageClient(&fakeMessageClient{}, "")
+
+	body := `{"name":"wave","interval":"1s","protocols":{"type":"temperature"},"generation":{"pattern":"sawtooth","min":"0","max":"100","period":"100s"}}`
+	rr := sendJSONRequest(service, http.MethodPost, "/api/v3/device/virtual", body)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	id := deviceNamed(t, service, "wave")
+
+	type statusResponse struct {
+		Status struct {
+			Name           string          `json:"name"`
+			Pattern        *PatternState   `json:"pattern"`
+			RecentReadings []RecentReading `json:"recentReadings"`
+		} `json:"status"`
+	}
+	status := func() statusResponse {
+		rr := sendJSONRequest(service, http.MethodGet, "/api/v3/device/virtual/"+id+"/status", "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response statusResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	response := status()
+	assert.Equal(t, "wave", response.Status.Name)
+	assert.Nil(t, response.Status.Pattern)
+	assert.Empty(t, response.Status.RecentReadings)
+
+	for i := 0; i < 15; i++ {
+		service.publishSensorReading(context.Background(), id)
+		clock.Advance(10 * time.Second)
+	}
+
+	// A change of interval alone carries on with the same wave
+	body = `{"name":"wave","interval":"2s","protocols":{"type":"temperature"},"generation":{"pattern":"sawtooth","min":"0","max":"100","period":"100s"}}`
+	rr = sendJSONRequest(service, http.MethodPut, "/api/v3/device/virtual/"+id, body)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	service.publishSensorReading(context.Background(), id)
+
+	response = status()
+	require.NotNil(t, response.Status.Pattern)
+	assert.Equal(t, PatternSawtooth, response.Status.Pattern.Pattern)
+	assert.Equal(t, 16, response.Status.Pattern.Readings)
+	assert.InDelta(t, 0.5, response.Status.Pattern.Phase, 0.001)
+	require.Len(t, response.Status.RecentReadings, RecentReadingsLimit)
+	assert.Equal(t, "50.00", response.Status.RecentReadings[RecentReadingsLimit-1].Value)
+	assert.Equal(t, "60.00", response.Status.RecentReadings[0].Value)
+	assert.True(t, testStart.Add(60*time.Second).Equal(response.Status.RecentReadings[0].Time))
+
+	// A new generation starts the wave over
+	body = `{"name":"wave","interval":"2s","protocols":{"type":"temperature"},"generation":{"pattern":"sine","min":"0","max":"100","period":"100s"}}`
+	rr = sendJSONRequest(service, http.MethodPut, "/api/v3/device/virtual/"+id, body)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Nil(t, status().Status.Pattern)
+
+	rr = sendJSONRequest(service, http.MethodGet, "/api/v3/device/virtual/missing/status", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,178 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// Retention defaults for the background janitor
+const (
+	DefaultCleanupInterval = 10 * time.Minute
+	DefaultRetention       = 7 * 24 * time.Hour
+)
+
+// cleanableStatuses lists the statuses a notification must have before it can be purged.
+// Anything else still needs attention and is never removed by cleanup.
+var cleanableStatuses = map[string]bool{
+	NotificationStatusProcessed:    true,
+	NotificationStatusAcknowledged: true,
+}
+
+// SetRetention configures how often the janitor runs and how long processed notifications are kept.
+// A zero interval disables the janitor. Must be called before Initialize.
+func (s *SupportNotificationsService) SetRetention(interval, retention time.Duration) {
+	s.cleanupInterval = interval
+	s.retention = retention
+}
+
+// runJanitor periodically purges processed notifications older than the retention period
+func (s *SupportNotificationsService) runJanitor(ctx context.Context) {
+	ticker := s.clock.NewTicker(s.cleanupInterval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Notification janitor started: interval %v, retention %v", s.cleanupInterval, s.retention)
+
+	for {
+		select {
+		case <-ticker.C():
+			cutoff := s.clock.Now().Add(-s.retention).UnixNano() / int64(time.Millisecond)
+			removed := s.purgeNotifications(cutoff, cleanableStatuses)
+			if removed > 0 {
+				s.logger.Infof("Notification janitor removed %d notifications", removed)
+			}
+		case <-ctx.Done():
+			s.logger.Info("Notification janitor stopped")
+			return
+		}
+	}
+}
+
+// purgeNotifications removes notifications created before cutoff (milliseconds) whose status is in statuses.
+// A nil statuses map matches every status. Returns the number of notifications removed.
+func (s *SupportNotificationsService) purgeNotifications(cutoff int64, statuses map[string]bool) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make(map[string]bool)
+	for id, notification := range s.notifications {
+		if notification.Created >= cutoff {
+			continue
+		}
+		if statuses != nil && !statuses[notification.Status] {
+			continue
+		}
+		ids[id] = true
+	}
+
+	s.removeNotificationsLocked(ids)
+	return len(ids)
+}
+
+// removeNotificationsLocked deletes the given notifications and their transmissions. Caller must hold the write lock.
+func (s *SupportNotificationsService) removeNotificationsLocked(ids map[string]bool) {
+	if len(ids) == 0 {
+		return
+	}
+
+	for id := range ids {
+		delete(s.notifications, id)
+	}
+	for id, transmission := range s.transmissions {
+		if ids[transmission.NotificationId] {
+			delete(s.transmissions, id)
+		}
+	}
+}
+
+// deleteNotificationsByAge handles DELETE /api/v3/notification/age/{age}
+func (s *SupportNotificationsService) deleteNotificationsByAge(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	age, err := strconv.ParseInt(vars["age"], 10, 64)
+	if err != nil || age < 0 {
+		common.WriteError(w, http.StatusBadRequest, "Age must be a non-negative number of milliseconds")
+		return
+	}
+
+	// Optional ?status=PROCESSED,ACKNOWLEDGED restricts which notifications are removed
+	var statuses map[string]bool
+	if raw := r.URL.Query().Get("status"); raw != "" {
+		statuses = make(map[string]bool)
+		for _, status := range strings.Split(raw, ",") {
+			status = strings.ToUpper(strings.TrimSpace(status))
+			if !cleanableStatuses[status] {
+				common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status %q, allowed values: %s, %s", status, NotificationStatusProcessed, NotificationStatusAcknowledged))
+				return
+			}
+			statuses[status] = true
+		}
+	}
+
+	cutoff := s.clock.Now().UnixNano()/int64(time.Millisecond) - age
+	removed := s.purgeNotifications(cutoff, statuses)
+
+	s.logger.Infof("Deleted %d notifications older than %dms", removed, age)
+
+	response := map[string]interface{}{
+		"apiVersion":   common.ServiceVersion,
+		"statusCode":   http.StatusOK,
+		"deletedCount": removed,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// cleanupNotifications handles DELETE /api/v3/cleanup
+func (s *SupportNotificationsService) cleanupNotifications(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	// Everything created up to now that has been processed
+	cutoff := s.clock.Now().UnixNano()/int64(time.Millisecond) + 1
+	removed := s.purgeNotifications(cutoff, cleanableStatuses)
+
+	s.logger.Infof("Cleanup removed %d processed notifications", removed)
+
+	response := map[string]interface{}{
+		"apiVersion":   common.ServiceVersion,
+		"statusCode":   http.StatusOK,
+		"deletedCount": removed,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// getTransmissionsByNotificationId handles GET /api/v3/transmission/notification/id/{id}
+func (s *SupportNotificationsService) getTransmissionsByNotificationId(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	s.mutex.RLock()
+	transmissions := []Transmission{}
+	for _, transmission := range s.transmissions {
+		if transmission.NotificationId == id {
+			transmissions = append(transmissions, transmission)
+		}
+	}
+	s.mutex.RUnlock()
+
+	response := map[string]interface{}{
+		"apiVersion":    common.ServiceVersion,
+		"statusCode":    http.StatusOK,
+		"totalCount":    len(transmissions),
+		"transmissions": transmissions,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
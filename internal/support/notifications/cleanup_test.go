@@ -0,0 +1,183 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// seedCleanupFixtures stores one old and one recent notification per status, each with a transmission
+func seedCleanupFixtures(service *SupportNotificationsService) {
+	old := nowMillis() - int64(time.Hour/time.Millisecond)
+	recent := nowMillis()
+
+	fixtures := []Notification{
+		{Id: "old-processed", Status: NotificationStatusProcessed, Created: old},
+		{Id: "old-acknowledged", Status: NotificationStatusAcknowledged, Created: old},
+		{Id: "old-failed", Status: NotificationStatusFailed, Created: old},
+		{Id: "recent-processed", Status: NotificationStatusProcessed, Created: recent},
+	}
+	for _, notification := range fixtures {
+		service.notifications[notification.Id] = notification
+		service.transmissions["t-"+notification.Id] = Transmission{Id: "t-" + notification.Id, NotificationId: notification.Id}
+	}
+}
+
+func doDelete(t *testing.T, router *mux.Router, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("DELETE", path, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSupportNotificationsService_DeleteNotificationsByAge(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		remaining    []string
+	}{
+		{"All statuses", "/api/v3/notification/age/60000", http.StatusOK, []string{"recent-processed"}},
+		{"Acknowledged only", "/api/v3/notification/age/60000?status=acknowledged", http.StatusOK, []string{"old-processed", "old-failed", "recent-processed"}},
+		{"Processed and acknowledged", "/api/v3/notification/age/60000?status=PROCESSED,ACKNOWLEDGED", http.StatusOK, []string{"old-failed", "recent-processed"}},
+		{"Invalid status", "/api/v3/notification/age/60000?status=FAILED", http.StatusBadRequest, []string{"old-processed", "old-acknowledged", "old-failed", "recent-processed"}},
+		{"Invalid age", "/api/v3/notification/age/soon", http.StatusBadRequest, []string{"old-processed", "old-acknowledged", "old-failed", "recent-processed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			seedCleanupFixtures(service)
+			router := mux.NewRouter()
+			service.AddRoutes(router)
+
+			rr := doDelete(t, router, tt.path)
+			assert.Equal(t, tt.expectedCode, rr.Code)
+
+			assert.Len(t, service.notifications, len(tt.remaining))
+			assert.Len(t, service.transmissions, len(tt.remaining))
+			for _, id := range tt.remaining {
+				assert.Contains(t, service.notifications, id)
+				assert.Contains(t, service.transmissions, "t-"+id)
+			}
+		})
+	}
+}
+
+func TestSupportNotificationsService_Cleanup(t *testing.T) {
+	service := newTestService()
+	seedCleanupFixtures(service)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := doDelete(t, router, "/api/v3/cleanup")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, float64(3), response["deletedCount"])
+
+	assert.Len(t, service.notifications, 1)
+	assert.Contains(t, service.notifications, "old-failed")
+	assert.Len(t, service.transmissions, 1)
+}
+
+func TestSupportNotificationsService_DeleteNotificationRemovesTransmissions(t *testing.T) {
+	service := newTestService()
+	seedCleanupFixtures(service)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	rr := doDelete(t, router, "/api/v3/notification/id/old-failed")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	assert.NotContains(t, service.transmissions, "t-old-failed")
+	assert.Len(t, service.transmissions, 3)
+}
+
+func TestSupportNotificationsService_ProcessRecordsTransmissions(t *testing.T) {
+	service := newTestService()
+	service.subscriptions["sub-1"] = Subscription{Id: "sub-1", Name: "ok", Channels: []Channel{{Type: "EMAIL"}}}
+	service.subscriptions["sub-2"] = Subscription{Id: "sub-2", Name: "broken", Channels: []Channel{{Type: "PIGEON"}}}
+	service.notifications["n-1"] = Notification{Id: "n-1", Status: NotificationStatusNew}
+
+	service.processNotification(service.notifications["n-1"])
+
+	statuses := map[string]string{}
+	for _, transmission := range service.transmissions {
+		assert.Equal(t, "n-1", transmission.NotificationId)
+		statuses[transmission.SubscriptionName] = transmission.Status
+	}
+	assert.Equal(t, map[string]string{"ok": TransmissionStatusSent, "broken": TransmissionStatusFailed}, statuses)
+}
+
+func TestSupportNotificationsService_Janitor(t *testing.T) {
+	service := newTestService()
+	seedCleanupFixtures(service)
+	service.SetRetention(10*time.Millisecond, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+
+	assert.Eventually(t, func() bool {
+		service.mutex.RLock()
+		defer service.mutex.RUnlock()
+		return len(service.notifications) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+
+	assert.Contains(t, service.notifications, "old-failed")
+	assert.Contains(t, service.notifications, "recent-processed")
+}
+
+func TestSupportNotificationsService_JanitorFollowsClock(t *testing.T) {
+	clock := common.NewFakeClock(time.Now())
+	service := newTestService()
+	service.SetClock(clock)
+	seedCleanupFixtures(service)
+	service.SetRetention(10*time.Minute, 30*time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	remaining := func() int {
+		service.mutex.RLock()
+		defer service.mutex.RUnlock()
+		return len(service.notifications)
+	}
+
+	// Nothing is purged until the first tick
+	clock.BlockUntil(1)
+	assert.Equal(t, 4, remaining())
+	clock.Advance(10 * time.Minute)
+	assert.Eventually(t, func() bool { return remaining() == 2 }, time.Second, 10*time.Millisecond)
+
+	// The recent notification ages past retention as the clock moves on
+	clock.Advance(10 * time.Minute)
+	assert.Never(t, func() bool { return remaining() < 2 }, 100*time.Millisecond, 10*time.Millisecond)
+	clock.Advance(20 * time.Minute)
+	assert.Eventually(t, func() bool { return remaining() == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+	assert.Contains(t, service.notifications, "old-failed")
+}
@@ -0,0 +1,94 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultEscalationTimeout is how long a critical notification may go unacknowledged before it is
+// escalated, for subscriptions that name an escalation subscription without a timeout
+const DefaultEscalationTimeout = 15 * time.Minute
+
+// validateEscalation checks a subscription's escalation chain settings
+func validateEscalation(subscription *Subscription) error {
+	if subscription.EscalationSubscription == "" {
+		if subscription.EscalationTimeout != "" {
+			return errors.New("escalationTimeout requires an escalationSubscription")
+		}
+		return nil
+	}
+	if subscription.EscalationSubscription == subscription.Name {
+		return errors.New("a subscription cannot escalate to itself")
+	}
+	if subscription.EscalationTimeout != "" {
+		timeout, err := time.ParseDuration(subscription.EscalationTimeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid escalationTimeout %q, must be a positive duration such as 10m", subscription.EscalationTimeout)
+		}
+	}
+	return nil
+}
+
+// escalationTimeout returns how long a critical notification delivered to subscription may go unacknowledged
+func escalationTimeout(subscription Subscription) time.Duration {
+	timeout, err := time.ParseDuration(subscription.EscalationTimeout)
+	if err != nil || timeout <= 0 {
+		return DefaultEscalationTimeout
+	}
+	return timeout
+}
+
+// escalate waits for the escalation timeout of the subscription a critical notification was delivered
+// to and, unless the notification has been acknowledged or deleted by then, delivers it to the
+// escalation subscription and marks it ESCALATED. The escalation subscription's own escalation
+// subscription is then given the same chance, and so on down the chain; visited holds the names of
+// the subscriptions already in the chain so one that loops back stops.
+func (s *SupportNotificationsService) escalate(notification Notification, from Subscription, visited map[string]bool) {
+	select {
+	case <-s.clock.After(escalationTimeout(from)):
+	case <-s.ctx.Done():
+		return
+	}
+
+	s.mutex.RLock()
+	stored, exists := s.notifications[notification.Id]
+	target, found := s.subscriptionByNameLocked(from.EscalationSubscription)
+	s.mutex.RUnlock()
+
+	if !exists || stored.Status == NotificationStatusAcknowledged {
+		return
+	}
+	switch {
+	case !found:
+		s.logger.Warnf("Cannot escalate notification %s: escalation subscription %s of %s not found", notification.Id, from.EscalationSubscription, from.Name)
+		return
+	case target.AdminState == SubscriptionAdminStateLocked:
+		s.logger.Warnf("Cannot escalate notification %s: escalation subscription %s is locked", notification.Id, target.Name)
+		return
+	case visited[target.Name]:
+		s.logger.Warnf("Not escalating notification %s to %s again: the escalation chain of %s loops", notification.Id, target.Name, from.Name)
+		return
+	}
+
+	s.logger.Warnf("Escalating critical notification %s from subscription %s to %s after %s without acknowledgement", notification.Id, from.Name, target.Name, escalationTimeout(from))
+	transmission, channels := s.transmit(notification, target)
+
+	s.mutex.Lock()
+	stored, exists = s.notifications[notification.Id]
+	if exists {
+		s.transmissions[transmission.Id] = transmission
+	}
+	escalated := exists && channels > 0 && stored.Status != NotificationStatusAcknowledged
+	if escalated && canTransition(stored.Status, NotificationStatusEscalated) {
+		stored.Status = NotificationStatusEscalated
+		stored.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+		s.notifications[notification.Id] = stored
+	}
+	s.mutex.Unlock()
+
+	if escalated && target.EscalationSubscription != "" {
+		visited[target.Name] = true
+		s.escalate(notification, target, visited)
+	}
+}
@@ -0,0 +1,144 @@
+package notifications
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// newEscalationService returns a service on a fake clock where operators escalate to on-call after
+// ten minutes, on-call to managers after five and managers back to operators. Only operators match
+// critical notifications directly.
+func newEscalationService(t *testing.T) (*SupportNotificationsService, *common.FakeClock, *mockSMSSender) {
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	service := newTestService()
+	service.SetClock(clock)
+	sender := &mockSMSSender{}
+	service.SetSMSSender(sender)
+	for _, subscription := range []Subscription{
+		{Id: "sub-1", Name: "operators", Channels: []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}, EscalationSubscription: "on-call", EscalationTimeout: "10m"},
+		{Id: "sub-2", Name: "on-call", Severities: []string{NotificationSeverityMinor}, Channels: []Channel{{Type: "SMS", Recipients: []string{"+15550100"}}}, EscalationSubscription: "managers", EscalationTimeout: "5m"},
+		{Id: "sub-3", Name: "managers", Severities: []string{NotificationSeverityMinor}, Channels: []Channel{{Type: "SMS", Recipients: []string{"+15550199"}}}, EscalationSubscription: "operators"},
+	} {
+		require.NoError(t, service.saveSubscriptionLocked(subscription))
+	}
+	return service, clock, sender
+}
+
+// notificationStatus returns the stored status of the notification with the given id
+func notificationStatus(service *SupportNotificationsService, id string) string {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	return service.notifications[id].Status
+}
+
+// smsRecipients returns who the mock sender has texted, in order
+func smsRecipients(sender *mockSMSSender) []string {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+	var recipients []string
+	for _, to := range sender.to {
+		recipients = append(recipients, to...)
+	}
+	return recipients
+}
+
+func TestSupportNotificationsService_EscalationChain(t *testing.T) {
+	service, clock, sender := newEscalationService(t)
+	service.notifications["n-1"] = Notification{Id: "n-1", Severity: NotificationSeverityCritical, Status: NotificationStatusNew}
+
+	service.processNotification(service.notifications["n-1"])
+	assert.Equal(t, NotificationStatusProcessed, notificationStatus(service, "n-1"))
+
+	// Nobody acknowledges, so on-call hears about it once the timeout passes and not before
+	clock.BlockUntil(1)
+	clock.Advance(10*time.Minute - time.Second)
+	assert.Empty(t, smsRecipients(sender))
+	assert.Equal(t, NotificationStatusProcessed, notificationStatus(service, "n-1"))
+
+	clock.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		return notificationStatus(service, "n-1") == NotificationStatusEscalated
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"+15550100"}, smsRecipients(sender))
+
+	// Then the chain continues to managers, and stops rather than loop back to operators
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Minute)
+	assert.Eventually(t, func() bool {
+		return len(smsRecipients(sender)) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"+15550100", "+15550199"}, smsRecipients(sender))
+
+	clock.BlockUntil(1)
+	clock.Advance(DefaultEscalationTimeout)
+	assert.Eventually(t, func() bool {
+		return clock.Waiters() == 0
+	}, time.Second, time.Millisecond)
+
+	service.mutex.RLock()
+	received := map[string]int{}
+	for _, transmission := range service.transmissions {
+		received[transmission.SubscriptionName]++
+	}
+	service.mutex.RUnlock()
+	assert.Equal(t, map[string]int{"operators": 1, "on-call": 1, "managers": 1}, received)
+	assert.Equal(t, NotificationStatusEscalated, notificationStatus(service, "n-1"))
+}
+
+func TestSupportNotificationsService_EscalationAcknowledged(t *testing.T) {
+	service, clock, sender := newEscalationService(t)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	service.notifications["n-1"] = Notification{Id: "n-1", Severity: NotificationSeverityCritical, Status: NotificationStatusProcessed}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.escalate(service.notifications["n-1"], service.subscriptions["sub-1"], map[string]bool{"operators": true})
+	}()
+	clock.BlockUntil(1)
+
+	rr := sendJSON(t, router, "PUT", "/api/v3/notification/id/n-1/status/ACKNOWLEDGED", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	clock.Advance(10 * time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("escalation did not finish")
+	}
+	assert.Empty(t, smsRecipients(sender))
+	assert.Empty(t, service.transmissions)
+	assert.Equal(t, NotificationStatusAcknowledged, notificationStatus(service, "n-1"))
+}
+
+func TestSupportNotificationsService_EscalationValidation(t *testing.T) {
+	channels := []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}
+	tests := []struct {
+		name         string
+		subscription Subscription
+		expectedCode int
+	}{
+		{"Chain with timeout", Subscription{Name: "operators", Channels: channels, EscalationSubscription: "on-call", EscalationTimeout: "90s"}, http.StatusCreated},
+		{"Chain with default timeout", Subscription{Name: "operators", Channels: channels, EscalationSubscription: "on-call"}, http.StatusCreated},
+		{"Timeout without chain", Subscription{Name: "operators", Channels: channels, EscalationTimeout: "10m"}, http.StatusBadRequest},
+		{"Escalates to itself", Subscription{Name: "operators", Channels: channels, EscalationSubscription: "operators"}, http.StatusBadRequest},
+		{"Unparseable timeout", Subscription{Name: "operators", Channels: channels, EscalationSubscription: "on-call", EscalationTimeout: "soon"}, http.StatusBadRequest},
+		{"Negative timeout", Subscription{Name: "operators", Channels: channels, EscalationSubscription: "on-call", EscalationTimeout: "-5m"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			rr := postJSON(t, service.addSubscription, "/api/v3/subscription", tt.subscription)
+			assert.Equal(t, tt.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+}
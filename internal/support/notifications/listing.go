@@ -0,0 +1,109 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// listParams holds the pagination and time window of a notification listing request
+type listParams struct {
+	offset int
+	limit  int
+	start  int64
+	end    int64
+}
+
+// parseListParams reads offset, limit, start and end (milliseconds) from the query string
+func parseListParams(r *http.Request) (listParams, error) {
+	params := listParams{
+		start: 0,
+		end:   math.MaxInt64,
+	}
+	query := r.URL.Query()
+
+	var err error
+	if params.offset, params.limit, err = common.ParsePagination(r); err != nil {
+		return params, err
+	}
+	if raw := query.Get(common.Start); raw != "" {
+		start, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || start < 0 {
+			return params, fmt.Errorf("invalid start %q: must be a non-negative timestamp in milliseconds", raw)
+		}
+		params.start = start
+	}
+	if raw := query.Get(common.End); raw != "" {
+		end, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || end < 0 {
+			return params, fmt.Errorf("invalid end %q: must be a non-negative timestamp in milliseconds", raw)
+		}
+		params.end = end
+	}
+	if params.end < params.start {
+		return params, fmt.Errorf("end must not be before start")
+	}
+
+	return params, nil
+}
+
+// collectNotifications returns the page of notifications selected by match and params, newest first,
+// along with the total number of matches before pagination
+func (s *SupportNotificationsService) collectNotifications(params listParams, match func(Notification) bool) ([]Notification, int) {
+	s.mutex.RLock()
+	matched := []Notification{}
+	for _, notification := range s.notifications {
+		if notification.Created < params.start || notification.Created > params.end {
+			continue
+		}
+		if match(notification) {
+			matched = append(matched, notification)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sortNotificationsByCreated(matched)
+
+	return common.Paginate(matched, params.offset, params.limit)
+}
+
+// sortNotificationsByCreated orders notifications newest first, tie-broken by id
+func sortNotificationsByCreated(notifications []Notification) {
+	sort.Slice(notifications, func(i, j int) bool {
+		return common.NewestFirst(notifications[i].Created, notifications[i].Id, notifications[j].Created, notifications[j].Id)
+	})
+}
+
+// sortSubscriptionsByCreated orders subscriptions newest first, tie-broken by id
+func sortSubscriptionsByCreated(subscriptions []Subscription) {
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return common.NewestFirst(subscriptions[i].Created, subscriptions[i].Id, subscriptions[j].Created, subscriptions[j].Id)
+	})
+}
+
+// writeNotificationList serves a filtered, paginated notification listing
+func (s *SupportNotificationsService) writeNotificationList(w http.ResponseWriter, r *http.Request, match func(Notification) bool) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+
+	params, err := parseListParams(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notifications, total := s.collectNotifications(params, match)
+
+	response := map[string]interface{}{
+		"apiVersion":    common.ServiceVersion,
+		"statusCode":    http.StatusOK,
+		"totalCount":    total,
+		"notifications": notifications,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
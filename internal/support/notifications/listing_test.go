@@ -0,0 +1,104 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportNotificationsService_NotificationListing(t *testing.T) {
+	service := newTestService()
+	for i := 1; i <= 5; i++ {
+		category := "SECURITY"
+		if i%2 == 0 {
+			category = "HEALTH"
+		}
+		id := fmt.Sprintf("n-%d", i)
+		service.notifications[id] = Notification{
+			Id:       id,
+			Category: category,
+			Labels:   []string{"door"},
+			Status:   NotificationStatusProcessed,
+			Created:  int64(i * 1000),
+		}
+	}
+
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedTotal int
+		expectedIds   []string
+	}{
+		{"All newest first", "/api/v3/notification/all", 5, []string{"n-5", "n-4", "n-3", "n-2", "n-1"}},
+		{"Paginated", "/api/v3/notification/all?offset=1&limit=2", 5, []string{"n-4", "n-3"}},
+		{"Offset past end", "/api/v3/notification/all?offset=10", 5, []string{}},
+		{"Time range", "/api/v3/notification/all?start=2000&end=4000", 3, []string{"n-4", "n-3", "n-2"}},
+		{"Category with range", "/api/v3/notification/category/SECURITY?start=2000", 2, []string{"n-5", "n-3"}},
+		{"Label paginated", "/api/v3/notification/label/door?limit=1", 5, []string{"n-5"}},
+		{"Status with end", "/api/v3/notification/status/PROCESSED?end=1000", 1, []string{"n-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.path, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var response struct {
+				TotalCount    int            `json:"totalCount"`
+				Notifications []Notification `json:"notifications"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedTotal, response.TotalCount)
+
+			ids := []string{}
+			for _, notification := range response.Notifications {
+				ids = append(ids, notification.Id)
+			}
+			assert.Equal(t, tt.expectedIds, ids)
+		})
+	}
+}
+
+func TestSupportNotificationsService_NotificationListingInvalidParams(t *testing.T) {
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	paths := []string{
+		"/api/v3/notification/all?offset=-1",
+		"/api/v3/notification/all?limit=abc",
+		"/api/v3/notification/all?limit=100000",
+		"/api/v3/notification/category/SECURITY?start=-5",
+		"/api/v3/notification/label/door?end=soon",
+		"/api/v3/notification/status/NEW?start=5000&end=1000",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			req, err := http.NewRequest("GET", path, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			assert.Equal(t, float64(http.StatusBadRequest), response["statusCode"])
+			assert.NotEmpty(t, response["message"])
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// SetMessageClient makes the service accept notifications published to topic on the message bus.
+// A nil client leaves the REST API as the only way in. Must be called before Initialize.
+func (s *SupportNotificationsService) SetMessageClient(client messaging.MessageClient, topic string) {
+	if topic == "" {
+		topic = messaging.MessageTopics.Notifications
+	}
+	s.messageClient = client
+	s.topic = topic
+}
+
+// handleNotificationMessage decodes a notification received from the message bus and accepts it
+// exactly as POST /api/v3/notification would. Malformed payloads are logged and returned as errors
+// so the message client does not acknowledge them.
+func (s *SupportNotificationsService) handleNotificationMessage(topic string, data []byte) error {
+	var notification Notification
+	if err := json.Unmarshal(data, &notification); err != nil {
+		s.logger.Warnf("Discarding malformed notification from topic %s: %v", topic, err)
+		return fmt.Errorf("failed to decode notification: %w", err)
+	}
+
+	if err := s.acceptNotification(&notification); err != nil {
+		s.logger.Warnf("Discarding invalid notification from topic %s: %v", topic, err)
+		return fmt.Errorf("invalid notification: %w", err)
+	}
+
+	s.logger.Infof("Notification created from message bus: %s", notification.Id)
+	return nil
+}
@@ -0,0 +1,144 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+)
+
+// fakeMessageClient is an in-process MessageClient that lets tests deliver messages directly
+type fakeMessageClient struct {
+	handlers map[string]messaging.MessageHandler
+	mutex    sync.Mutex
+}
+
+func newFakeMessageClient() *fakeMessageClient {
+	return &fakeMessageClient{handlers: make(map[string]messaging.MessageHandler)}
+}
+
+func (f *fakeMessageClient) Connect() error    { return nil }
+func (f *fakeMessageClient) Disconnect() error { return nil }
+
+func (f *fakeMessageClient) Publish(topic string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return f.deliver(topic, payload)
+}
+
+func (f *fakeMessageClient) Subscribe(topic string, handler messaging.MessageHandler) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.handlers[topic] = handler
+	return nil
+}
+
+func (f *fakeMessageClient) Unsubscribe(topic string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.handlers, topic)
+	return nil
+}
+
+func (f *fakeMessageClient) subscribed(topic string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	_, ok := f.handlers[topic]
+	return ok
+}
+
+// deliver hands a raw payload to the topic's handler, returning its error as the NACK
+func (f *fakeMessageClient) deliver(topic string, data []byte) error {
+	f.mutex.Lock()
+	handler, ok := f.handlers[topic]
+	f.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("no subscriber for topic %s", topic)
+	}
+	return handler(topic, data)
+}
+
+// notificationCount returns the number of stored notifications under the service lock
+func notificationCount(service *SupportNotificationsService) int {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	return len(service.notifications)
+}
+
+func TestSupportNotificationsService_MessageBus(t *testing.T) {
+	service := newTestService()
+	client := newFakeMessageClient()
+	service.SetMessageClient(client, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	topic := messaging.MessageTopics.Notifications
+	require.True(t, client.subscribed(topic))
+
+	tests := []struct {
+		name    string
+		payload string
+		valid   bool
+	}{
+		{"Valid", `{"category":"SECURITY","content":"Door forced open","severity":"critical"}`, true},
+		{"Malformed JSON", `{"category":`, false},
+		{"Missing content", `{"category":"SECURITY"}`, false},
+		{"Missing category and labels", `{"content":"alert"}`, false},
+		{"Invalid severity", `{"category":"SECURITY","content":"alert","severity":"URGENT"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := notificationCount(service)
+
+			err := client.deliver(topic, []byte(tt.payload))
+
+			if !tt.valid {
+				assert.Error(t, err)
+				assert.Equal(t, before, notificationCount(service))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, before+1, notificationCount(service))
+		})
+	}
+
+	// Defaults are applied exactly as for the REST API
+	service.mutex.RLock()
+	for _, notification := range service.notifications {
+		assert.NotEmpty(t, notification.Id)
+		assert.NotZero(t, notification.Created)
+		assert.Equal(t, "text/plain", notification.ContentType)
+		assert.Equal(t, NotificationSeverityCritical, notification.Severity)
+	}
+	service.mutex.RUnlock()
+
+	// Shutdown stops the subscription
+	cancel()
+	wg.Wait()
+	assert.False(t, client.subscribed(topic))
+}
+
+func TestSupportNotificationsService_MessageBusCustomTopic(t *testing.T) {
+	service := newTestService()
+	client := newFakeMessageClient()
+	service.SetMessageClient(client, "site.alerts")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+
+	assert.True(t, client.subscribed("site.alerts"))
+	assert.False(t, client.subscribed(messaging.MessageTopics.Notifications))
+}
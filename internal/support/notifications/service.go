@@ -1,11 +1,18 @@
 package notifications
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,6 +21,8 @@ import (
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/metrics"
 )
 
 // Notification represents a system notification
@@ -31,6 +40,51 @@ type Notification struct {
 	Modified    int64                  `json:"modified"`
 }
 
+// Notification statuses
+const (
+	NotificationStatusNew          = "NEW"
+	NotificationStatusProcessed    = "PROCESSED"
+	NotificationStatusFailed       = "FAILED"
+	NotificationStatusAcknowledged = "ACKNOWLEDGED"
+	NotificationStatusEscalated    = "ESCALATED"
+)
+
+// notificationStatusTransitions lists the statuses each status may move to.
+// ACKNOWLEDGED is terminal so a handled notification can't be reopened.
+var notificationStatusTransitions = map[string][]string{
+	NotificationStatusNew:          {NotificationStatusProcessed, NotificationStatusFailed, NotificationStatusAcknowledged, NotificationStatusEscalated},
+	NotificationStatusProcessed:    {NotificationStatusAcknowledged, NotificationStatusEscalated},
+	NotificationStatusFailed:       {NotificationStatusProcessed, NotificationStatusAcknowledged, NotificationStatusEscalated},
+	NotificationStatusEscalated:    {NotificationStatusAcknowledged},
+	NotificationStatusAcknowledged: {},
+}
+
+// notificationStatuses lists every valid status in lifecycle order
+var notificationStatuses = []string{
+	NotificationStatusNew,
+	NotificationStatusProcessed,
+	NotificationStatusFailed,
+	NotificationStatusAcknowledged,
+	NotificationStatusEscalated,
+}
+
+// canTransition reports whether a notification may move from one status to another
+func canTransition(from, to string) bool {
+	for _, allowed := range notificationStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification severities
+const (
+	NotificationSeverityMinor    = "MINOR"
+	NotificationSeverityNormal   = "NORMAL"
+	NotificationSeverityCritical = "CRITICAL"
+)
+
 // Subscription represents a notification subscription
 type Subscription struct {
 	Id           string            `json:"id"`
@@ -38,14 +92,44 @@ type Subscription struct {
 	Channels     []Channel         `json:"channels"`
 	Categories   []string          `json:"categories"`
 	Labels       []string          `json:"labels"`
+	Severities   []string          `json:"severities"`
 	Receiver     string            `json:"receiver"`
 	Description  string            `json:"description"`
 	ResendLimit  int               `json:"resendLimit"`
 	ResendInterval string          `json:"resendInterval"`
+	AdminState   string            `json:"adminState"`
+	Escalation   bool              `json:"escalation"`
+	EscalationSubscription string  `json:"escalationSubscription,omitempty"` // receives critical notifications left unacknowledged for EscalationTimeout
+	EscalationTimeout      string  `json:"escalationTimeout,omitempty"`      // DefaultEscalationTimeout if empty
+	Version      int64             `json:"version"` // incremented on every update; updates must send the version they read
 	Created      int64             `json:"created"`
 	Modified     int64             `json:"modified"`
 }
 
+// Subscription admin states; LOCKED subscriptions receive nothing
+const (
+	SubscriptionAdminStateUnlocked = common.Unlocked
+	SubscriptionAdminStateLocked   = common.Locked
+)
+
+// Channel types
+const (
+	ChannelTypeEmail   = "EMAIL"
+	ChannelTypeSMS     = "SMS"
+	ChannelTypeWebhook = "WEBHOOK"
+)
+
+// Channel properties
+const (
+	// ChannelPropertyPath is the URL path a webhook channel posts to
+	ChannelPropertyPath = "path"
+	// ChannelPropertyScheme is the URL scheme of a webhook channel, http unless set
+	ChannelPropertyScheme = "scheme"
+)
+
+// errSubscriptionNameConflict is returned when a subscription name is already taken by another subscription
+var errSubscriptionNameConflict = errors.New("subscription name already in use")
+
 // Channel represents a notification channel (email, SMS, etc.)
 type Channel struct {
 	Type       string            `json:"type"`
@@ -53,25 +137,64 @@ type Channel struct {
 	Port       int               `json:"port,omitempty"`
 	Recipients []string          `json:"recipients"`
 	Properties map[string]string `json:"properties,omitempty"`
+	Template   string            `json:"template,omitempty"`
+}
+
+// Transmission records a single delivery attempt of a notification to a subscription
+type Transmission struct {
+	Id               string `json:"id"`
+	NotificationId   string `json:"notificationId"`
+	SubscriptionName string `json:"subscriptionName"`
+	Status           string `json:"status"`
+	Message          string `json:"message,omitempty"`
+	Created          int64  `json:"created"`
 }
 
+// Transmission statuses
+const (
+	TransmissionStatusSent   = "SENT"
+	TransmissionStatusFailed = "FAILED"
+)
+
 // SupportNotificationsService handles notifications and subscriptions
 type SupportNotificationsService struct {
-	logger        *logrus.Logger
-	notifications map[string]Notification
-	subscriptions map[string]Subscription
-	mutex         sync.RWMutex
+	logger          *logrus.Logger
+	notifications   map[string]Notification
+	subscriptions   map[string]Subscription
+	subscriptionIds map[string]string
+	transmissions   map[string]Transmission
+	cleanupInterval time.Duration
+	retention       time.Duration
+	clock           common.Clock
+	httpClient      *http.Client
+	messageClient   messaging.MessageClient
+	smsSender       SMSSender
+	ctx             context.Context
+	topic           string
+	mutex           sync.RWMutex
 }
 
 // NewSupportNotificationsService creates a new support notifications service
 func NewSupportNotificationsService(logger *logrus.Logger) *SupportNotificationsService {
 	return &SupportNotificationsService{
-		logger:        logger,
-		notifications: make(map[string]Notification),
-		subscriptions: make(map[string]Subscription),
+		logger:          logger,
+		notifications:   make(map[string]Notification),
+		subscriptions:   make(map[string]Subscription),
+		subscriptionIds: make(map[string]string),
+		transmissions:   make(map[string]Transmission),
+		cleanupInterval: DefaultCleanupInterval,
+		retention:       DefaultRetention,
+		clock:           common.RealClock{},
+		httpClient:      &http.Client{Timeout: 10 * time.Second},
+		ctx:             context.Background(),
 	}
 }
 
+// SetClock sets the clock used for timestamps, resend delays and the janitor. Must be called before Initialize.
+func (s *SupportNotificationsService) SetClock(clock common.Clock) {
+	s.clock = clock
+}
+
 // Initialize implements the BootstrapHandler interface
 func (s *SupportNotificationsService) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *bootstrap.DIContainer) bool {
 	s.logger.Info("Initializing Support Notifications Service")
@@ -79,6 +202,44 @@ func (s *SupportNotificationsService) Initialize(ctx context.Context, wg *sync.W
 	// Add service to DI container
 	dic.Add("SupportNotificationsService", s)
 	
+	// Pending resends are abandoned once the service shuts down
+	s.ctx = ctx
+	
+	// Expose the stored notification count as a domain gauge
+	err := metrics.RegisterGaugeFunc("support_notifications_notifications", "Number of notifications stored by Support Notifications.", func() float64 {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return float64(len(s.notifications))
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to register notification count metric: %v", err)
+	}
+	
+	// Enforce notification retention in the background until shutdown
+	if s.cleanupInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runJanitor(ctx)
+		}()
+	}
+	
+	// Accept notifications published on the message bus until shutdown
+	if s.messageClient != nil {
+		if err := s.messageClient.Subscribe(s.topic, s.handleNotificationMessage); err != nil {
+			s.logger.Errorf("Failed to subscribe to notification topic %s: %v", s.topic, err)
+			return false
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			if err := s.messageClient.Unsubscribe(s.topic); err != nil {
+				s.logger.Warnf("Failed to unsubscribe from notification topic %s: %v", s.topic, err)
+			}
+		}()
+	}
+	
 	s.logger.Info("Support Notifications Service initialization completed")
 	return true
 }
@@ -93,6 +254,13 @@ func (s *SupportNotificationsService) AddRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v3/notification/category/{category}", s.getNotificationsByCategory).Methods("GET")
 	router.HandleFunc("/api/v3/notification/label/{label}", s.getNotificationsByLabel).Methods("GET")
 	router.HandleFunc("/api/v3/notification/status/{status}", s.getNotificationsByStatus).Methods("GET")
+	router.HandleFunc("/api/v3/notification/id/{id}/status/{status}", s.updateNotificationStatusById).Methods("PUT")
+	router.HandleFunc("/api/v3/notification/acknowledged/{acknowledged}", s.getNotificationsByAcknowledged).Methods("GET")
+	router.HandleFunc("/api/v3/notification/age/{age}", s.deleteNotificationsByAge).Methods("DELETE")
+	router.HandleFunc("/api/v3/cleanup", s.cleanupNotifications).Methods("DELETE")
+	
+	// Transmission routes
+	router.HandleFunc("/api/v3/transmission/notification/id/{id}", s.getTransmissionsByNotificationId).Methods("GET")
 	
 	// Subscription routes
 	router.HandleFunc("/api/v3/subscription", s.addSubscription).Methods("POST")
@@ -101,6 +269,10 @@ func (s *SupportNotificationsService) AddRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v3/subscription/id/{id}", s.updateSubscription).Methods("PUT")
 	router.HandleFunc("/api/v3/subscription/id/{id}", s.deleteSubscription).Methods("DELETE")
 	router.HandleFunc("/api/v3/subscription/name/{name}", s.getSubscriptionByName).Methods("GET")
+	router.HandleFunc("/api/v3/subscription/name/{name}", s.updateSubscriptionByName).Methods("PUT")
+	router.HandleFunc("/api/v3/subscription/name/{name}", s.deleteSubscriptionByName).Methods("DELETE")
+	router.HandleFunc("/api/v3/subscription/id/{id}/test", s.testSubscription).Methods("POST")
+	router.HandleFunc("/api/v3/subscription/name/{name}/test", s.testSubscriptionByName).Methods("POST")
 	
 	s.logger.Info("Support Notifications routes registered")
 }
@@ -112,35 +284,16 @@ func (s *SupportNotificationsService) addNotification(w http.ResponseWriter, r *
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var notification Notification
-	if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+	if err := common.DecodeJSON(w, r, &notification); err != nil {
 		s.logger.Errorf("Failed to decode notification: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
-	// Generate ID and timestamps
-	notification.Id = models.GenerateUUID()
-	notification.Created = time.Now().UnixNano() / int64(time.Millisecond)
-	notification.Modified = notification.Created
-	
-	// Set defaults
-	if notification.Status == "" {
-		notification.Status = "NEW"
-	}
-	if notification.ContentType == "" {
-		notification.ContentType = "text/plain"
-	}
-	if notification.Severity == "" {
-		notification.Severity = "NORMAL"
+	if err := s.acceptNotification(&notification); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 	
-	s.mutex.Lock()
-	s.notifications[notification.Id] = notification
-	s.mutex.Unlock()
-	
-	// Process notification (send to subscribers)
-	go s.processNotification(notification)
-	
 	s.logger.Infof("Notification created: %s", notification.Id)
 	
 	response := map[string]interface{}{
@@ -153,25 +306,71 @@ func (s *SupportNotificationsService) addNotification(w http.ResponseWriter, r *
 	json.NewEncoder(w).Encode(response)
 }
 
-// getAllNotifications handles GET /api/v3/notification/all
-func (s *SupportNotificationsService) getAllNotifications(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+// validateNotification applies defaults and checks the fields every notification needs
+func validateNotification(notification *Notification) error {
+	if notification.Content == "" {
+		return errors.New("notification content is required")
+	}
+	if notification.Category == "" && len(notification.Labels) == 0 {
+		return errors.New("notification requires a category or at least one label")
+	}
 	
-	s.mutex.RLock()
-	notifications := make([]Notification, 0, len(s.notifications))
-	for _, notification := range s.notifications {
-		notifications = append(notifications, notification)
+	if notification.ContentType == "" {
+		notification.ContentType = "text/plain"
 	}
-	s.mutex.RUnlock()
 	
-	response := map[string]interface{}{
-		"apiVersion":    common.ServiceVersion,
-		"statusCode":    http.StatusOK,
-		"totalCount":    len(notifications),
-		"notifications": notifications,
+	notification.Severity = strings.ToUpper(notification.Severity)
+	if notification.Severity == "" {
+		notification.Severity = NotificationSeverityNormal
+	}
+	if !validSeverity(notification.Severity) {
+		return invalidSeverityError(notification.Severity)
+	}
+	return nil
+}
+
+// validSeverity reports whether severity is one of the notification severities
+func validSeverity(severity string) bool {
+	switch severity {
+	case NotificationSeverityMinor, NotificationSeverityNormal, NotificationSeverityCritical:
+		return true
+	}
+	return false
+}
+
+// invalidSeverityError describes an unknown severity and lists the allowed ones
+func invalidSeverityError(severity string) error {
+	return fmt.Errorf("invalid severity %q, allowed values: %s, %s, %s", severity, NotificationSeverityMinor, NotificationSeverityNormal, NotificationSeverityCritical)
+}
+
+// acceptNotification validates a new notification, stores it and starts delivery.
+// It is shared by the REST API and the message bus so both behave identically.
+func (s *SupportNotificationsService) acceptNotification(notification *Notification) error {
+	if err := validateNotification(notification); err != nil {
+		return err
 	}
 	
-	json.NewEncoder(w).Encode(response)
+	// Generate ID and timestamps
+	notification.Id = models.GenerateUUID()
+	notification.Created = s.clock.Now().UnixNano() / int64(time.Millisecond)
+	notification.Modified = notification.Created
+	
+	// Every notification starts as NEW; processNotification moves it on
+	notification.Status = NotificationStatusNew
+	
+	s.mutex.Lock()
+	s.notifications[notification.Id] = *notification
+	s.mutex.Unlock()
+	
+	// Process notification (send to subscribers)
+	go s.processNotification(*notification)
+	
+	return nil
+}
+
+// getAllNotifications handles GET /api/v3/notification/all
+func (s *SupportNotificationsService) getAllNotifications(w http.ResponseWriter, r *http.Request) {
+	s.writeNotificationList(w, r, func(Notification) bool { return true })
 }
 
 // getNotificationById handles GET /api/v3/notification/id/{id}
@@ -186,7 +385,7 @@ func (s *SupportNotificationsService) getNotificationById(w http.ResponseWriter,
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Notification not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Notification not found")
 		return
 	}
 	
@@ -201,23 +400,121 @@ func (s *SupportNotificationsService) getNotificationById(w http.ResponseWriter,
 
 // processNotification sends notification to all matching subscribers
 func (s *SupportNotificationsService) processNotification(notification Notification) {
+	// Snapshot matching subscriptions so delivery happens without holding the lock.
+	// Escalation targets only receive notifications that could not be delivered anywhere else.
 	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-	
+	var matched, escalations []Subscription
 	for _, subscription := range s.subscriptions {
-		if s.matchesSubscription(notification, subscription) {
-			s.sendNotification(notification, subscription)
+		if !s.matchesSubscription(notification, subscription) {
+			continue
+		}
+		if subscription.Escalation {
+			escalations = append(escalations, subscription)
+		} else {
+			matched = append(matched, subscription)
+		}
+	}
+	s.mutex.RUnlock()
+	
+	status := NotificationStatusProcessed
+	delivered := 0
+	transmissions := make([]Transmission, 0, len(matched))
+	var chained []Subscription
+	for _, subscription := range matched {
+		transmission, channels := s.transmit(notification, subscription)
+		if transmission.Status == TransmissionStatusFailed {
+			status = NotificationStatusFailed
+		}
+		delivered += channels
+		transmissions = append(transmissions, transmission)
+		if notification.Severity == NotificationSeverityCritical && channels > 0 && subscription.EscalationSubscription != "" {
+			chained = append(chained, subscription)
+		}
+	}
+	
+	// A critical notification nobody received is escalated rather than dropped
+	if notification.Severity == NotificationSeverityCritical && len(matched) > 0 && delivered == 0 && len(escalations) > 0 {
+		s.logger.Warnf("Escalating critical notification %s after delivery failed on all channels", notification.Id)
+		for _, subscription := range escalations {
+			transmission, channels := s.transmit(notification, subscription)
+			if channels > 0 {
+				status = NotificationStatusEscalated
+			}
+			transmissions = append(transmissions, transmission)
 		}
 	}
 	
-	// Update notification status
-	notification.Status = "PROCESSED"
-	notification.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-	s.notifications[notification.Id] = notification
+	s.updateNotificationStatus(notification.Id, status, transmissions)
+	
+	// Critical notifications go down their subscriptions' escalation chains until acknowledged
+	for _, subscription := range chained {
+		go s.escalate(notification, subscription, map[string]bool{subscription.Name: true})
+	}
 }
 
-// matchesSubscription checks if notification matches subscription criteria
+// transmit delivers a notification to one subscription and returns the transmission record
+// along with the number of channels that received it
+func (s *SupportNotificationsService) transmit(notification Notification, subscription Subscription) (Transmission, int) {
+	transmission := Transmission{
+		Id:               models.GenerateUUID(),
+		NotificationId:   notification.Id,
+		SubscriptionName: subscription.Name,
+		Status:           TransmissionStatusSent,
+		Created:          s.clock.Now().UnixNano() / int64(time.Millisecond),
+	}
+	
+	delivered, err := s.sendNotification(notification, subscription)
+	if err != nil {
+		s.logger.Errorf("Failed to deliver notification %s to subscription %s: %v", notification.Id, subscription.Name, err)
+		transmission.Status = TransmissionStatusFailed
+		transmission.Message = err.Error()
+	}
+	return transmission, delivered
+}
+
+// updateNotificationStatus moves a stored NEW notification to its processed state and records its transmissions
+func (s *SupportNotificationsService) updateNotificationStatus(id string, status string, transmissions []Transmission) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	
+	stored, exists := s.notifications[id]
+	if !exists || stored.Status != NotificationStatusNew {
+		// Deleted or already moved on while delivery was in flight
+		return
+	}
+	
+	stored.Status = status
+	stored.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+	s.notifications[id] = stored
+	
+	for _, transmission := range transmissions {
+		s.transmissions[transmission.Id] = transmission
+	}
+}
+
+// matchesSubscription checks if notification matches subscription criteria. Each of severities,
+// categories and labels that the subscription lists narrows what it receives: the notification's
+// severity and category must be among those listed, and at least one of its labels must be. A
+// subscription that lists none of them matches every notification. Locked subscriptions match nothing.
 func (s *SupportNotificationsService) matchesSubscription(notification Notification, subscription Subscription) bool {
+	if subscription.AdminState == SubscriptionAdminStateLocked {
+		return false
+	}
+	
+	// Check severities
+	if len(subscription.Severities) > 0 {
+		severityMatch := false
+		for _, severity := range subscription.Severities {
+			if severity == notification.Severity {
+				severityMatch = true
+				break
+			}
+		}
+		if !severityMatch {
+			return false
+		}
+	}
+	
 	// Check categories
 	if len(subscription.Categories) > 0 {
 		categoryMatch := false
@@ -254,38 +551,238 @@ func (s *SupportNotificationsService) matchesSubscription(notification Notificat
 	return true
 }
 
-// sendNotification sends notification through subscription channels
-func (s *SupportNotificationsService) sendNotification(notification Notification, subscription Subscription) {
+// sendNotification sends notification through subscription channels, retrying each failed channel
+// up to the subscription's ResendLimit. Returns the number of channels that delivered.
+func (s *SupportNotificationsService) sendNotification(notification Notification, subscription Subscription) (int, error) {
+	resendInterval, err := time.ParseDuration(subscription.ResendInterval)
+	if err != nil {
+		resendInterval = 0
+	}
+	
+	delivered := 0
+	var failed []string
 	for _, channel := range subscription.Channels {
-		switch channel.Type {
-		case "EMAIL":
-			s.sendEmailNotification(notification, channel)
-		case "SMS":
-			s.sendSMSNotification(notification, channel)
-		case "WEBHOOK":
-			s.sendWebhookNotification(notification, channel)
-		default:
-			s.logger.Warnf("Unknown channel type: %s", channel.Type)
+		err := s.sendToChannel(notification, channel)
+		for attempt := 0; err != nil && attempt < subscription.ResendLimit; attempt++ {
+			s.logger.Warnf("Channel %s failed for notification %s, resending (%d/%d): %v", channel.Type, notification.Id, attempt+1, subscription.ResendLimit, err)
+			select {
+			case <-s.clock.After(resendInterval):
+			case <-s.ctx.Done():
+				return delivered, fmt.Errorf("delivery of notification %s abandoned on shutdown: %w", notification.Id, err)
+			}
+			err = s.sendToChannel(notification, channel)
 		}
+		if err != nil {
+			s.logger.Warnf("Channel %s failed for notification %s: %v", channel.Type, notification.Id, err)
+			failed = append(failed, channel.Type)
+			continue
+		}
+		delivered++
+	}
+	
+	if len(failed) > 0 {
+		return delivered, fmt.Errorf("delivery failed on channels: %v", failed)
+	}
+	return delivered, nil
+}
+
+// sendToChannel makes a single delivery attempt on one channel, rendering the channel template into the content
+func (s *SupportNotificationsService) sendToChannel(notification Notification, channel Channel) error {
+	content, err := renderContent(notification, channel)
+	if err != nil {
+		// Templates are checked when the subscription is saved, so this is unexpected; send the raw content
+		s.logger.Warnf("Failed to render %s template for notification %s, sending raw content: %v", channel.Type, notification.Id, err)
+	} else {
+		notification.Content = content
+	}
+	
+	switch channel.Type {
+	case ChannelTypeEmail:
+		return s.sendEmailNotification(notification, channel)
+	case ChannelTypeSMS:
+		return s.sendSMSNotification(notification, channel)
+	case ChannelTypeWebhook:
+		return s.sendWebhookNotification(notification, channel)
+	default:
+		return fmt.Errorf("unknown channel type: %s", channel.Type)
 	}
 }
 
 // sendEmailNotification simulates sending email notification
-func (s *SupportNotificationsService) sendEmailNotification(notification Notification, channel Channel) {
+func (s *SupportNotificationsService) sendEmailNotification(notification Notification, channel Channel) error {
 	s.logger.Infof("Sending email notification: %s to %v", notification.Content, channel.Recipients)
 	// In a real implementation, this would integrate with an email service
+	return nil
 }
 
-// sendSMSNotification simulates sending SMS notification
-func (s *SupportNotificationsService) sendSMSNotification(notification Notification, channel Channel) {
-	s.logger.Infof("Sending SMS notification: %s to %v", notification.Content, channel.Recipients)
-	// In a real implementation, this would integrate with an SMS service
+// sendWebhookNotification posts the notification as JSON to the channel's webhook URL
+func (s *SupportNotificationsService) sendWebhookNotification(notification Notification, channel Channel) error {
+	target := webhookURL(channel)
+	s.logger.Infof("Sending webhook notification: %s to %s", notification.Content, target)
+	
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	
+	resp, err := s.httpClient.Post(target, common.ContentTypeJSON, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("webhook request to %s failed: %w", target, err)
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", target, resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookURL builds the target URL of a webhook channel from its host, port and properties
+func webhookURL(channel Channel) string {
+	scheme := channel.Properties[ChannelPropertyScheme]
+	if scheme == "" {
+		scheme = "http"
+	}
+	host := channel.Host
+	if channel.Port > 0 {
+		host = net.JoinHostPort(channel.Host, strconv.Itoa(channel.Port))
+	}
+	path := channel.Properties[ChannelPropertyPath]
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	target := url.URL{Scheme: scheme, Host: host, Path: path}
+	return target.String()
 }
 
-// sendWebhookNotification simulates sending webhook notification
-func (s *SupportNotificationsService) sendWebhookNotification(notification Notification, channel Channel) {
-	s.logger.Infof("Sending webhook notification: %s to %s", notification.Content, channel.Host)
-	// In a real implementation, this would make HTTP requests to webhook URLs
+// renderContent executes the channel template over the notification, or returns the raw content when there is none
+func renderContent(notification Notification, channel Channel) (string, error) {
+	if channel.Template == "" {
+		return notification.Content, nil
+	}
+	
+	tmpl, err := template.New(channel.Type).Parse(channel.Template)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, notification); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return content.String(), nil
+}
+
+// validateChannel checks that a channel has what its type needs to deliver
+func validateChannel(channel Channel) error {
+	// Render against an empty notification so unknown fields fail now rather than at delivery
+	if _, err := renderContent(Notification{}, channel); err != nil {
+		return fmt.Errorf("%s channel: %w", channel.Type, err)
+	}
+	
+	switch channel.Type {
+	case ChannelTypeEmail:
+		if len(channel.Recipients) == 0 {
+			return errors.New("email channel requires at least one recipient")
+		}
+		for _, recipient := range channel.Recipients {
+			if !strings.Contains(recipient, "@") {
+				return fmt.Errorf("invalid email recipient %q", recipient)
+			}
+		}
+	case ChannelTypeSMS:
+		if len(channel.Recipients) == 0 {
+			return errors.New("SMS channel requires at least one recipient")
+		}
+	case ChannelTypeWebhook:
+		if channel.Host == "" {
+			return errors.New("webhook channel requires a host")
+		}
+		if strings.Contains(channel.Host, "/") {
+			return fmt.Errorf("invalid webhook host %q, set the path in the %q property", channel.Host, ChannelPropertyPath)
+		}
+		if channel.Properties[ChannelPropertyPath] == "" {
+			return fmt.Errorf("webhook channel requires a %q property", ChannelPropertyPath)
+		}
+	default:
+		return fmt.Errorf("invalid channel type %q, allowed values: %s, %s, %s", channel.Type, ChannelTypeEmail, ChannelTypeSMS, ChannelTypeWebhook)
+	}
+	return nil
+}
+
+// normalizeAdminState upper-cases the subscription admin state, defaulting to UNLOCKED,
+// and reports whether the result is valid
+func normalizeAdminState(subscription *Subscription) bool {
+	subscription.AdminState = strings.ToUpper(subscription.AdminState)
+	if subscription.AdminState == "" {
+		subscription.AdminState = SubscriptionAdminStateUnlocked
+	}
+	return subscription.AdminState == SubscriptionAdminStateUnlocked || subscription.AdminState == SubscriptionAdminStateLocked
+}
+
+// validateSubscription normalizes and checks a subscription received from a client
+func validateSubscription(subscription *Subscription) error {
+	if subscription.Name == "" {
+		return errors.New("subscription name is required")
+	}
+	if !normalizeAdminState(subscription) {
+		return fmt.Errorf("invalid adminState, allowed values: %s, %s", SubscriptionAdminStateUnlocked, SubscriptionAdminStateLocked)
+	}
+	for i, severity := range subscription.Severities {
+		subscription.Severities[i] = strings.ToUpper(severity)
+		if !validSeverity(subscription.Severities[i]) {
+			return invalidSeverityError(severity)
+		}
+	}
+	if err := validateEscalation(subscription); err != nil {
+		return err
+	}
+	if len(subscription.Channels) == 0 {
+		return errors.New("at least one channel is required")
+	}
+	for i := range subscription.Channels {
+		channel := &subscription.Channels[i]
+		channel.Type = strings.ToUpper(channel.Type)
+		if err := validateChannel(*channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveSubscriptionLocked stores a subscription and keeps the name index in sync.
+// Caller must hold the write lock.
+func (s *SupportNotificationsService) saveSubscriptionLocked(subscription Subscription) error {
+	if ownerId, taken := s.subscriptionIds[subscription.Name]; taken && ownerId != subscription.Id {
+		return errSubscriptionNameConflict
+	}
+	if existing, exists := s.subscriptions[subscription.Id]; exists && existing.Name != subscription.Name {
+		delete(s.subscriptionIds, existing.Name)
+	}
+	s.subscriptions[subscription.Id] = subscription
+	s.subscriptionIds[subscription.Name] = subscription.Id
+	return nil
+}
+
+// deleteSubscriptionLocked removes a subscription and its name index entry. Caller must hold the write lock.
+func (s *SupportNotificationsService) deleteSubscriptionLocked(id string) bool {
+	subscription, exists := s.subscriptions[id]
+	if !exists {
+		return false
+	}
+	delete(s.subscriptions, id)
+	delete(s.subscriptionIds, subscription.Name)
+	return true
+}
+
+// subscriptionByNameLocked looks a subscription up through the name index. Caller must hold the lock.
+func (s *SupportNotificationsService) subscriptionByNameLocked(name string) (Subscription, bool) {
+	id, exists := s.subscriptionIds[name]
+	if !exists {
+		return Subscription{}, false
+	}
+	subscription, exists := s.subscriptions[id]
+	return subscription, exists
 }
 
 // Subscription handlers
@@ -295,16 +792,16 @@ func (s *SupportNotificationsService) addSubscription(w http.ResponseWriter, r *
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var subscription Subscription
-	if err := json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+	if err := common.DecodeJSON(w, r, &subscription); err != nil {
 		s.logger.Errorf("Failed to decode subscription: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
 	// Generate ID and timestamps
 	subscription.Id = models.GenerateUUID()
-	subscription.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	subscription.Created = s.clock.Now().UnixNano() / int64(time.Millisecond)
 	subscription.Modified = subscription.Created
+	subscription.Version = 1
 	
 	// Set defaults
 	if subscription.ResendLimit == 0 {
@@ -313,11 +810,20 @@ func (s *SupportNotificationsService) addSubscription(w http.ResponseWriter, r *
 	if subscription.ResendInterval == "" {
 		subscription.ResendInterval = "5m"
 	}
+	if err := validateSubscription(&subscription); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	
 	s.mutex.Lock()
-	s.subscriptions[subscription.Id] = subscription
+	err := s.saveSubscriptionLocked(subscription)
 	s.mutex.Unlock()
 	
+	if err != nil {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Subscription %s already exists", subscription.Name))
+		return
+	}
+	
 	s.logger.Infof("Subscription created: %s", subscription.Name)
 	
 	response := map[string]interface{}{
@@ -334,18 +840,26 @@ func (s *SupportNotificationsService) addSubscription(w http.ResponseWriter, r *
 func (s *SupportNotificationsService) getAllSubscriptions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	subscriptions := make([]Subscription, 0, len(s.subscriptions))
 	for _, subscription := range s.subscriptions {
 		subscriptions = append(subscriptions, subscription)
 	}
 	s.mutex.RUnlock()
+	sortSubscriptionsByCreated(subscriptions)
+	page, totalCount := common.Paginate(subscriptions, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":    common.ServiceVersion,
 		"statusCode":    http.StatusOK,
-		"totalCount":    len(subscriptions),
-		"subscriptions": subscriptions,
+		"totalCount":    totalCount,
+		"subscriptions": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -353,85 +867,39 @@ func (s *SupportNotificationsService) getAllSubscriptions(w http.ResponseWriter,
 
 // getNotificationsByCategory handles GET /api/v3/notification/category/{category}
 func (s *SupportNotificationsService) getNotificationsByCategory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(common.ContentType, common.ContentTypeJSON)
-	
 	vars := mux.Vars(r)
 	category := vars["category"]
 	
-	s.mutex.RLock()
-	var categoryNotifications []Notification
-	for _, notification := range s.notifications {
-		if notification.Category == category {
-			categoryNotifications = append(categoryNotifications, notification)
-		}
-	}
-	s.mutex.RUnlock()
-	
-	response := map[string]interface{}{
-		"apiVersion":    common.ServiceVersion,
-		"statusCode":    http.StatusOK,
-		"totalCount":    len(categoryNotifications),
-		"notifications": categoryNotifications,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+	s.writeNotificationList(w, r, func(notification Notification) bool {
+		return notification.Category == category
+	})
 }
 
 // Additional handlers for other endpoints would follow the same pattern...
 
 // getNotificationsByLabel handles GET /api/v3/notification/label/{label}
 func (s *SupportNotificationsService) getNotificationsByLabel(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(common.ContentType, common.ContentTypeJSON)
-	
 	vars := mux.Vars(r)
 	label := vars["label"]
 	
-	s.mutex.RLock()
-	var labelNotifications []Notification
-	for _, notification := range s.notifications {
+	s.writeNotificationList(w, r, func(notification Notification) bool {
 		for _, notifLabel := range notification.Labels {
 			if notifLabel == label {
-				labelNotifications = append(labelNotifications, notification)
-				break
+				return true
 			}
 		}
-	}
-	s.mutex.RUnlock()
-	
-	response := map[string]interface{}{
-		"apiVersion":    common.ServiceVersion,
-		"statusCode":    http.StatusOK,
-		"totalCount":    len(labelNotifications),
-		"notifications": labelNotifications,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+		return false
+	})
 }
 
 // getNotificationsByStatus handles GET /api/v3/notification/status/{status}
 func (s *SupportNotificationsService) getNotificationsByStatus(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(common.ContentType, common.ContentTypeJSON)
-	
 	vars := mux.Vars(r)
 	status := vars["status"]
 	
-	s.mutex.RLock()
-	var statusNotifications []Notification
-	for _, notification := range s.notifications {
-		if notification.Status == status {
-			statusNotifications = append(statusNotifications, notification)
-		}
-	}
-	s.mutex.RUnlock()
-	
-	response := map[string]interface{}{
-		"apiVersion":    common.ServiceVersion,
-		"statusCode":    http.StatusOK,
-		"totalCount":    len(statusNotifications),
-		"notifications": statusNotifications,
-	}
-	
-	json.NewEncoder(w).Encode(response)
+	s.writeNotificationList(w, r, func(notification Notification) bool {
+		return notification.Status == status
+	})
 }
 
 // getSubscriptionById handles GET /api/v3/subscription/id/{id}
@@ -446,7 +914,7 @@ func (s *SupportNotificationsService) getSubscriptionById(w http.ResponseWriter,
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Subscription not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
 		return
 	}
 	
@@ -461,29 +929,65 @@ func (s *SupportNotificationsService) getSubscriptionById(w http.ResponseWriter,
 
 // updateSubscription handles PUT /api/v3/subscription/id/{id}
 func (s *SupportNotificationsService) updateSubscription(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set(common.ContentType, common.ContentTypeJSON)
-	
 	vars := mux.Vars(r)
 	id := vars["id"]
 	
+	s.replaceSubscription(w, r, "", func() (Subscription, bool) {
+		subscription, exists := s.subscriptions[id]
+		return subscription, exists
+	})
+}
+
+// updateSubscriptionByName handles PUT /api/v3/subscription/name/{name}
+func (s *SupportNotificationsService) updateSubscriptionByName(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	
+	s.replaceSubscription(w, r, name, func() (Subscription, bool) {
+		return s.subscriptionByNameLocked(name)
+	})
+}
+
+// replaceSubscription stores the subscription in the request body in place of the one returned by lookup,
+// which runs under the write lock. A body without a name takes defaultName.
+func (s *SupportNotificationsService) replaceSubscription(w http.ResponseWriter, r *http.Request, defaultName string, lookup func() (Subscription, bool)) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
 	var updatedSubscription Subscription
-	if err := json.NewDecoder(r.Body).Decode(&updatedSubscription); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &updatedSubscription); err != nil {
+		return
+	}
+	if updatedSubscription.Name == "" {
+		updatedSubscription.Name = defaultName
+	}
+	if err := validateSubscription(&updatedSubscription); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	
 	s.mutex.Lock()
-	existingSubscription, exists := s.subscriptions[id]
-	if exists {
-		updatedSubscription.Id = id
+	existingSubscription, exists := lookup()
+	stale := exists && updatedSubscription.Version != existingSubscription.Version
+	var err error
+	if exists && !stale {
+		updatedSubscription.Id = existingSubscription.Id
+		updatedSubscription.Version = existingSubscription.Version + 1
 		updatedSubscription.Created = existingSubscription.Created
-		updatedSubscription.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.subscriptions[id] = updatedSubscription
+		updatedSubscription.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+		err = s.saveSubscriptionLocked(updatedSubscription)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Subscription not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	if stale {
+		common.WriteVersionConflict(w, "Subscription", updatedSubscription.Version, existingSubscription.Version)
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Subscription %s already exists", updatedSubscription.Name))
 		return
 	}
 	
@@ -491,6 +995,7 @@ func (s *SupportNotificationsService) updateSubscription(w http.ResponseWriter,
 		"apiVersion": common.ServiceVersion,
 		"statusCode": http.StatusOK,
 		"message":    "Subscription updated successfully",
+		"version":    updatedSubscription.Version,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -504,14 +1009,11 @@ func (s *SupportNotificationsService) deleteSubscription(w http.ResponseWriter,
 	id := vars["id"]
 	
 	s.mutex.Lock()
-	_, exists := s.subscriptions[id]
-	if exists {
-		delete(s.subscriptions, id)
-	}
+	exists := s.deleteSubscriptionLocked(id)
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Subscription not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
 		return
 	}
 	
@@ -532,24 +1034,127 @@ func (s *SupportNotificationsService) getSubscriptionByName(w http.ResponseWrite
 	name := vars["name"]
 	
 	s.mutex.RLock()
-	var foundSubscription *Subscription
-	for _, subscription := range s.subscriptions {
-		if subscription.Name == name {
-			foundSubscription = &subscription
-			break
-		}
-	}
+	subscription, exists := s.subscriptionByNameLocked(name)
 	s.mutex.RUnlock()
 	
-	if foundSubscription == nil {
-		http.Error(w, "Subscription not found", http.StatusNotFound)
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
 		return
 	}
 	
 	response := map[string]interface{}{
 		"apiVersion":   common.ServiceVersion,
 		"statusCode":   http.StatusOK,
-		"subscription": *foundSubscription,
+		"subscription": subscription,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+// deleteSubscriptionByName handles DELETE /api/v3/subscription/name/{name}
+func (s *SupportNotificationsService) deleteSubscriptionByName(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	name := vars["name"]
+	
+	s.mutex.Lock()
+	exists := false
+	if subscription, found := s.subscriptionByNameLocked(name); found {
+		exists = s.deleteSubscriptionLocked(subscription.Id)
+	}
+	s.mutex.Unlock()
+	
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"message":    "Subscription deleted successfully",
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+// channelTestResult reports the outcome of a test send on one channel and how long the send took
+type channelTestResult struct {
+	Type      string  `json:"type"`
+	Success   bool    `json:"success"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+// testSubscription handles POST /api/v3/subscription/id/{id}/test
+func (s *SupportNotificationsService) testSubscription(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	s.sendTestNotification(w, func() (Subscription, bool) {
+		subscription, exists := s.subscriptions[id]
+		return subscription, exists
+	})
+}
+
+// testSubscriptionByName handles POST /api/v3/subscription/name/{name}/test
+func (s *SupportNotificationsService) testSubscriptionByName(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	
+	s.sendTestNotification(w, func() (Subscription, bool) {
+		return s.subscriptionByNameLocked(name)
+	})
+}
+
+// sendTestNotification sends a synthetic notification through each channel of the subscription returned
+// by lookup, which runs under the read lock, and responds with the result of every channel. Channels are
+// tried once, without the subscription's resends, so a misconfigured one shows up straight away.
+func (s *SupportNotificationsService) sendTestNotification(w http.ResponseWriter, lookup func() (Subscription, bool)) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	s.mutex.RLock()
+	subscription, exists := lookup()
+	s.mutex.RUnlock()
+	
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+	
+	// The synthetic notification is delivered once per channel and never stored
+	now := s.clock.Now().UnixNano() / int64(time.Millisecond)
+	notification := Notification{
+		Id:          models.GenerateUUID(),
+		Category:    "TEST",
+		Content:     fmt.Sprintf("Test notification for subscription %s", subscription.Name),
+		ContentType: "text/plain",
+		Sender:      common.SupportNotificationsServiceKey,
+		Severity:    NotificationSeverityNormal,
+		Status:      NotificationStatusNew,
+		Created:     now,
+		Modified:    now,
+	}
+	
+	results := make([]channelTestResult, 0, len(subscription.Channels))
+	for _, channel := range subscription.Channels {
+		result := channelTestResult{Type: channel.Type, Success: true}
+		start := s.clock.Now()
+		if err := s.sendToChannel(notification, channel); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		result.LatencyMs = float64(s.clock.Now().Sub(start)) / float64(time.Millisecond)
+		results = append(results, result)
+	}
+	
+	s.logger.Infof("Test notification sent for subscription %s", subscription.Name)
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"results":    results,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -565,12 +1170,12 @@ func (s *SupportNotificationsService) deleteNotification(w http.ResponseWriter,
 	s.mutex.Lock()
 	_, exists := s.notifications[id]
 	if exists {
-		delete(s.notifications, id)
+		s.removeNotificationsLocked(map[string]bool{id: true})
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Notification not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Notification not found")
 		return
 	}
 	
@@ -581,4 +1186,61 @@ func (s *SupportNotificationsService) deleteNotification(w http.ResponseWriter,
 	}
 	
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+// updateNotificationStatusById handles PUT /api/v3/notification/id/{id}/status/{status}
+func (s *SupportNotificationsService) updateNotificationStatusById(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	id := vars["id"]
+	status := strings.ToUpper(vars["status"])
+	
+	if _, valid := notificationStatusTransitions[status]; !valid {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status %q, allowed values: %s", vars["status"], strings.Join(notificationStatuses, ", ")))
+		return
+	}
+	
+	s.mutex.Lock()
+	notification, exists := s.notifications[id]
+	allowed := exists && canTransition(notification.Status, status)
+	if allowed {
+		notification.Status = status
+		notification.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+		s.notifications[id] = notification
+	}
+	s.mutex.Unlock()
+	
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Notification not found")
+		return
+	}
+	
+	if !allowed {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Cannot change notification status from %s to %s", notification.Status, status))
+		return
+	}
+	
+	s.logger.Infof("Notification %s status changed to %s", id, status)
+	
+	response := map[string]interface{}{
+		"apiVersion":   common.ServiceVersion,
+		"statusCode":   http.StatusOK,
+		"notification": notification,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+// getNotificationsByAcknowledged handles GET /api/v3/notification/acknowledged/{acknowledged}
+func (s *SupportNotificationsService) getNotificationsByAcknowledged(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	acknowledged, err := strconv.ParseBool(vars["acknowledged"])
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, "Acknowledged must be true or false")
+		return
+	}
+	
+	s.writeNotificationList(w, r, func(notification Notification) bool {
+		return (notification.Status == NotificationStatusAcknowledged) == acknowledged
+	})
+}
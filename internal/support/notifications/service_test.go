@@ -0,0 +1,810 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func newTestService() *SupportNotificationsService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	return NewSupportNotificationsService(logger)
+}
+
+func postJSON(t *testing.T, handler http.HandlerFunc, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", path, bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSupportNotificationsService_AddNotificationValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification Notification
+		expectedCode int
+	}{
+		{"Valid", Notification{Category: "SECURITY", Content: "alert"}, http.StatusCreated},
+		{"Labels only", Notification{Labels: []string{"door"}, Content: "alert"}, http.StatusCreated},
+		{"Missing content", Notification{Category: "SECURITY"}, http.StatusBadRequest},
+		{"Missing category and labels", Notification{Content: "alert"}, http.StatusBadRequest},
+		{"Invalid severity", Notification{Category: "SECURITY", Content: "alert", Severity: "URGENT"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			rr := postJSON(t, service.addNotification, "/api/v3/notification", tt.notification)
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestSupportNotificationsService_ProcessNotificationStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		channelType    string
+		expectedStatus string
+	}{
+		{"Delivered", "EMAIL", NotificationStatusProcessed},
+		{"Unknown channel", "PIGEON", NotificationStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			service.subscriptions["sub-1"] = Subscription{
+				Id:         "sub-1",
+				Name:       "sub-1",
+				Categories: []string{"SECURITY"},
+				Channels:   []Channel{{Type: tt.channelType, Recipients: []string{"ops@example.com"}}},
+			}
+			service.notifications["n-1"] = Notification{Id: "n-1", Category: "SECURITY", Status: NotificationStatusNew}
+
+			service.processNotification(service.notifications["n-1"])
+
+			assert.Equal(t, tt.expectedStatus, service.notifications["n-1"].Status)
+			assert.NotZero(t, service.notifications["n-1"].Modified)
+		})
+	}
+}
+
+func TestSupportNotificationsService_ProcessDeletedNotification(t *testing.T) {
+	service := newTestService()
+	notification := Notification{Id: "n-1", Status: NotificationStatusNew}
+
+	// Notification was deleted while delivery was in flight
+	service.processNotification(notification)
+
+	assert.Equal(t, 0, len(service.notifications))
+}
+
+func TestSupportNotificationsService_ConcurrentNotificationsAndSubscriptions(t *testing.T) {
+	service := newTestService()
+
+	const workers = 20
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			subscription := Subscription{
+				Name:       fmt.Sprintf("sub-%d", i),
+				Categories: []string{"SECURITY"},
+				Channels:   []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}},
+			}
+			rr := postJSON(t, service.addSubscription, "/api/v3/subscription", subscription)
+			assert.Equal(t, http.StatusCreated, rr.Code)
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+			notification := Notification{
+				Category: "SECURITY",
+				Content:  fmt.Sprintf("alert %d", i),
+				Sender:   "test",
+			}
+			rr := postJSON(t, service.addNotification, "/api/v3/notification", notification)
+			assert.Equal(t, http.StatusCreated, rr.Code)
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		service.mutex.RLock()
+		defer service.mutex.RUnlock()
+
+		for _, notification := range service.notifications {
+			if notification.Status != NotificationStatusProcessed {
+				return false
+			}
+		}
+		return len(service.notifications) == workers
+	}, 5*time.Second, 10*time.Millisecond)
+
+	service.mutex.RLock()
+	assert.Equal(t, workers, len(service.subscriptions))
+	service.mutex.RUnlock()
+}
+
+func TestSupportNotificationsService_UpdateNotificationStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		current      string
+		status       string
+		expectedCode int
+	}{
+		{"Acknowledge processed", NotificationStatusProcessed, "ACKNOWLEDGED", http.StatusOK},
+		{"Lowercase status", NotificationStatusNew, "acknowledged", http.StatusOK},
+		{"Escalate failed", NotificationStatusFailed, "ESCALATED", http.StatusOK},
+		{"Un-acknowledge", NotificationStatusAcknowledged, "NEW", http.StatusConflict},
+		{"Back to new", NotificationStatusProcessed, "NEW", http.StatusConflict},
+		{"Invalid status", NotificationStatusNew, "DONE", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			service.notifications["n-1"] = Notification{Id: "n-1", Status: tt.current}
+
+			router := mux.NewRouter()
+			service.AddRoutes(router)
+
+			req, err := http.NewRequest("PUT", "/api/v3/notification/id/n-1/status/"+tt.status, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+
+			switch tt.expectedCode {
+			case http.StatusOK:
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				notification := response["notification"].(map[string]interface{})
+				assert.Equal(t, strings.ToUpper(tt.status), notification["status"])
+				assert.NotZero(t, service.notifications["n-1"].Modified)
+			case http.StatusBadRequest:
+				assert.Contains(t, rr.Body.String(), NotificationStatusAcknowledged)
+				assert.Equal(t, tt.current, service.notifications["n-1"].Status)
+			default:
+				assert.Equal(t, tt.current, service.notifications["n-1"].Status)
+			}
+		})
+	}
+}
+
+func TestSupportNotificationsService_UpdateNotificationStatusNotFound(t *testing.T) {
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	req, err := http.NewRequest("PUT", "/api/v3/notification/id/missing/status/ACKNOWLEDGED", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	var response common.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestSupportNotificationsService_GetNotificationsByAcknowledged(t *testing.T) {
+	service := newTestService()
+	service.notifications["n-1"] = Notification{Id: "n-1", Status: NotificationStatusAcknowledged}
+	service.notifications["n-2"] = Notification{Id: "n-2", Status: NotificationStatusProcessed}
+	service.notifications["n-3"] = Notification{Id: "n-3", Status: NotificationStatusNew}
+
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	tests := []struct {
+		name          string
+		value         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{"Acknowledged", "true", http.StatusOK, 1},
+		{"Unhandled queue", "false", http.StatusOK, 2},
+		{"Invalid", "maybe", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/api/v3/notification/acknowledged/"+tt.value, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusOK {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, float64(tt.expectedCount), response["totalCount"])
+			}
+		})
+	}
+}
+
+func TestSupportNotificationsService_AcknowledgedListOrder(t *testing.T) {
+	service := newTestService()
+	for id, created := range map[string]int64{"n-1": 1000, "n-2": 3000, "n-3": 2000, "n-0": 3000} {
+		service.notifications[id] = Notification{Id: id, Status: NotificationStatusNew, Created: created}
+	}
+	service.notifications["n-4"] = Notification{Id: "n-4", Status: NotificationStatusAcknowledged, Created: 4000}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	// The unhandled queue comes back newest first, ties broken by id, the same on every call
+	for i := 0; i < 5; i++ {
+		rr := sendJSON(t, router, "GET", "/api/v3/notification/acknowledged/false", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Notifications []Notification `json:"notifications"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		var ids []string
+		for _, notification := range response.Notifications {
+			ids = append(ids, notification.Id)
+		}
+		assert.Equal(t, []string{"n-0", "n-2", "n-3", "n-1"}, ids)
+	}
+
+	// Paged like the other listings, with the total counting every match
+	rr := sendJSON(t, router, "GET", "/api/v3/notification/acknowledged/false?offset=1&limit=2", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		TotalCount    int            `json:"totalCount"`
+		Notifications []Notification `json:"notifications"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 4, response.TotalCount)
+	require.Len(t, response.Notifications, 2)
+	assert.Equal(t, "n-2", response.Notifications[0].Id)
+	assert.Equal(t, "n-3", response.Notifications[1].Id)
+
+	rr = sendJSON(t, router, "GET", "/api/v3/notification/acknowledged/false?limit=-5", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// And windowed by created time
+	rr = sendJSON(t, router, "GET", "/api/v3/notification/acknowledged/false?start=1500&end=2500", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.TotalCount)
+	require.Len(t, response.Notifications, 1)
+	assert.Equal(t, "n-3", response.Notifications[0].Id)
+
+	rr = sendJSON(t, router, "GET", "/api/v3/notification/acknowledged/false?start=2500&end=1500", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSupportNotificationsService_Escalation(t *testing.T) {
+	tests := []struct {
+		name           string
+		severity       string
+		channelType    string
+		expectedStatus string
+		escalated      bool
+	}{
+		{"Critical undelivered", NotificationSeverityCritical, "PIGEON", NotificationStatusEscalated, true},
+		{"Normal undelivered", NotificationSeverityNormal, "PIGEON", NotificationStatusFailed, false},
+		{"Critical delivered", NotificationSeverityCritical, "EMAIL", NotificationStatusProcessed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			service.SetSMSSender(&mockSMSSender{})
+			service.subscriptions["sub-1"] = Subscription{
+				Id:             "sub-1",
+				Name:           "operators",
+				Channels:       []Channel{{Type: tt.channelType}},
+				ResendLimit:    2,
+				ResendInterval: "1ms",
+			}
+			service.subscriptions["sub-2"] = Subscription{
+				Id:         "sub-2",
+				Name:       "on-call",
+				Channels:   []Channel{{Type: "SMS", Recipients: []string{"+15550100"}}},
+				Escalation: true,
+			}
+			service.notifications["n-1"] = Notification{Id: "n-1", Severity: tt.severity, Status: NotificationStatusNew}
+
+			service.processNotification(service.notifications["n-1"])
+
+			assert.Equal(t, tt.expectedStatus, service.notifications["n-1"].Status)
+
+			received := map[string]bool{}
+			for _, transmission := range service.transmissions {
+				received[transmission.SubscriptionName] = true
+			}
+			assert.True(t, received["operators"])
+			assert.Equal(t, tt.escalated, received["on-call"])
+		})
+	}
+}
+
+func TestSupportNotificationsService_MatchesSubscription(t *testing.T) {
+	service := newTestService()
+	notification := Notification{Category: "SECURITY", Severity: NotificationSeverityCritical, Labels: []string{"door"}}
+
+	tests := []struct {
+		name         string
+		subscription Subscription
+		expected     bool
+	}{
+		{"No filters", Subscription{}, true},
+		{"Empty lists match all", Subscription{Severities: []string{}, Categories: []string{}, Labels: []string{}}, true},
+		{"Severity match", Subscription{Severities: []string{NotificationSeverityCritical}}, true},
+		{"Severity among several", Subscription{Severities: []string{NotificationSeverityNormal, NotificationSeverityCritical}}, true},
+		{"Severity mismatch", Subscription{Severities: []string{NotificationSeverityMinor}}, false},
+		{"Category match", Subscription{Categories: []string{"HEALTH", "SECURITY"}}, true},
+		{"Category mismatch", Subscription{Categories: []string{"HEALTH"}}, false},
+		{"Label match", Subscription{Labels: []string{"window", "door"}}, true},
+		{"Label mismatch", Subscription{Labels: []string{"window"}}, false},
+		{"Category and severity", Subscription{Categories: []string{"SECURITY"}, Severities: []string{NotificationSeverityCritical}}, true},
+		{"Category matches, severity does not", Subscription{Categories: []string{"SECURITY"}, Severities: []string{NotificationSeverityMinor}}, false},
+		{"Severity matches, label does not", Subscription{Severities: []string{NotificationSeverityCritical}, Labels: []string{"window"}}, false},
+		{"All three match", Subscription{Severities: []string{NotificationSeverityCritical}, Categories: []string{"SECURITY"}, Labels: []string{"door"}}, true},
+		{"All three, category does not", Subscription{Severities: []string{NotificationSeverityCritical}, Categories: []string{"HEALTH"}, Labels: []string{"door"}}, false},
+		{"Locked", Subscription{AdminState: SubscriptionAdminStateLocked}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, service.matchesSubscription(notification, tt.subscription))
+		})
+	}
+}
+
+func TestSupportNotificationsService_AddSubscriptionAdminState(t *testing.T) {
+	service := newTestService()
+
+	channels := []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}
+
+	rr := postJSON(t, service.addSubscription, "/api/v3/subscription", Subscription{Name: "default", Channels: channels})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	for _, subscription := range service.subscriptions {
+		assert.Equal(t, SubscriptionAdminStateUnlocked, subscription.AdminState)
+	}
+
+	rr = postJSON(t, service.addSubscription, "/api/v3/subscription", Subscription{Name: "bad", AdminState: "DISABLED", Channels: channels})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSupportNotificationsService_SubscriptionSeveritiesNormalized(t *testing.T) {
+	service := newTestService()
+	subscription := Subscription{Name: "critical-only", Severities: []string{"critical"}, Channels: []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}}
+
+	rr := postJSON(t, service.addSubscription, "/api/v3/subscription", subscription)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	for _, stored := range service.subscriptions {
+		assert.Equal(t, []string{NotificationSeverityCritical}, stored.Severities)
+		assert.True(t, service.matchesSubscription(Notification{Severity: NotificationSeverityCritical}, stored))
+		assert.False(t, service.matchesSubscription(Notification{Severity: NotificationSeverityNormal}, stored))
+	}
+}
+
+func sendJSON(t *testing.T, router *mux.Router, method, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestSupportNotificationsService_SubscriptionValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription Subscription
+		expectedCode int
+	}{
+		{"Valid", Subscription{Name: "ops", Channels: []Channel{{Type: "email", Recipients: []string{"ops@example.com"}}}}, http.StatusCreated},
+		{"Valid webhook", Subscription{Name: "ops", Channels: []Channel{{Type: "WEBHOOK", Host: "hooks.local", Properties: map[string]string{"path": "/alerts"}}}}, http.StatusCreated},
+		{"Email without recipients", Subscription{Name: "ops", Channels: []Channel{{Type: "EMAIL"}}}, http.StatusBadRequest},
+		{"Email recipient without @", Subscription{Name: "ops", Channels: []Channel{{Type: "EMAIL", Recipients: []string{"ops.example.com"}}}}, http.StatusBadRequest},
+		{"Webhook without host", Subscription{Name: "ops", Channels: []Channel{{Type: "WEBHOOK", Properties: map[string]string{"path": "/alerts"}}}}, http.StatusBadRequest},
+		{"Webhook without path", Subscription{Name: "ops", Channels: []Channel{{Type: "WEBHOOK", Host: "hooks.local"}}}, http.StatusBadRequest},
+		{"Webhook host with path", Subscription{Name: "ops", Channels: []Channel{{Type: "WEBHOOK", Host: "hooks.local/alerts", Properties: map[string]string{"path": "/alerts"}}}}, http.StatusBadRequest},
+		{"Missing name", Subscription{Channels: []Channel{{Type: "EMAIL"}}}, http.StatusBadRequest},
+		{"No channels", Subscription{Name: "ops"}, http.StatusBadRequest},
+		{"Unknown channel type", Subscription{Name: "ops", Channels: []Channel{{Type: "PIGEON"}}}, http.StatusBadRequest},
+		{"Valid severities", Subscription{Name: "ops", Severities: []string{"critical", NotificationSeverityMinor}, Channels: []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}}, http.StatusCreated},
+		{"Unknown severity", Subscription{Name: "ops", Severities: []string{"URGENT"}, Channels: []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			rr := postJSON(t, service.addSubscription, "/api/v3/subscription", tt.subscription)
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestSupportNotificationsService_SubscriptionNameUniqueness(t *testing.T) {
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	channels := []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}
+	rr := sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "ops", Channels: channels})
+	require.Equal(t, http.StatusCreated, rr.Code)
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "oncall", Channels: channels})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	oncallId := created["id"].(string)
+
+	// Duplicate create
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "ops", Channels: channels})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Rename onto an existing name, by id and by name
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/id/"+oncallId, Subscription{Name: "ops", Channels: channels, Version: 1})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/oncall", Subscription{Name: "ops", Channels: channels, Version: 1})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Rename to a free name frees the old one
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/oncall", Subscription{Name: "pager", Channels: channels, Version: 1})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = sendJSON(t, router, "GET", "/api/v3/subscription/name/oncall", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = sendJSON(t, router, "GET", "/api/v3/subscription/name/pager", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var fetched map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &fetched))
+	assert.Equal(t, oncallId, fetched["subscription"].(map[string]interface{})["id"])
+
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "oncall", Channels: channels})
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestSupportNotificationsService_SubscriptionByName(t *testing.T) {
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	channels := []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}
+	rr := sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "ops", Channels: channels})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	// Body without a name keeps the one in the path
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/ops", Subscription{Description: "updated", Channels: channels, Version: 1})
+	require.Equal(t, http.StatusOK, rr.Code)
+	subscription, exists := service.subscriptionByNameLocked("ops")
+	require.True(t, exists)
+	assert.Equal(t, "updated", subscription.Description)
+
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/ops", Subscription{Channels: nil})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/missing", Subscription{Channels: channels})
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = sendJSON(t, router, "DELETE", "/api/v3/subscription/name/ops", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = sendJSON(t, router, "DELETE", "/api/v3/subscription/name/ops", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Empty(t, service.subscriptions)
+	assert.Empty(t, service.subscriptionIds)
+}
+
+func TestSupportNotificationsService_TestSubscription(t *testing.T) {
+	var received Notification
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/alerts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	host, portValue, err := net.SplitHostPort(strings.TrimPrefix(webhook.URL, "http://"))
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portValue)
+	require.NoError(t, err)
+
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	subscription := Subscription{
+		Name: "ops",
+		Channels: []Channel{
+			{Type: "EMAIL", Recipients: []string{"ops@example.com"}},
+			{Type: "WEBHOOK", Host: host, Port: port, Properties: map[string]string{"path": "/alerts"}},
+			{Type: "WEBHOOK", Host: host, Port: port, Properties: map[string]string{"path": "/typo"}},
+		},
+	}
+	rr := sendJSON(t, router, "POST", "/api/v3/subscription", subscription)
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription/name/ops/test", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Results []channelTestResult `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Results, 3)
+	assert.True(t, response.Results[0].Success)
+	assert.True(t, response.Results[1].Success)
+	assert.False(t, response.Results[2].Success)
+	assert.Contains(t, response.Results[2].Error, "404")
+
+	assert.Equal(t, "TEST", received.Category)
+	assert.Contains(t, received.Content, "ops")
+
+	// Test sends are not stored as notifications
+	assert.Empty(t, service.notifications)
+	assert.Empty(t, service.transmissions)
+
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription/name/missing/test", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// clockedSMSSender takes latency on the clock to send, failing when err is set
+type clockedSMSSender struct {
+	clock   *common.FakeClock
+	latency time.Duration
+	err     error
+}
+
+func (c *clockedSMSSender) Send(to []string, body string) error {
+	c.clock.Advance(c.latency)
+	return c.err
+}
+
+func TestSupportNotificationsService_TestSubscriptionById(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	service := newTestService()
+	service.SetClock(clock)
+	sender := &clockedSMSSender{clock: clock, latency: 250 * time.Millisecond}
+	service.SetSMSSender(sender)
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	subscription := Subscription{
+		Name: "on-call",
+		Channels: []Channel{
+			{Type: "SMS", Recipients: []string{"+15550100"}},
+			{Type: "EMAIL", Recipients: []string{"ops@example.com"}},
+		},
+	}
+	rr := sendJSON(t, router, "POST", "/api/v3/subscription", subscription)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	var created struct {
+		Id string `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+	test := func() []map[string]interface{} {
+		rr := sendJSON(t, router, "POST", "/api/v3/subscription/id/"+created.Id+"/test", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Results
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "SMS", "success": true, "latencyMs": float64(250)},
+		{"type": "EMAIL", "success": true, "latencyMs": float64(0)},
+	}, test())
+
+	// A failing channel is reported without stopping the others or being retried
+	sender.err = errors.New("provider rejected the number")
+	sender.latency = 40 * time.Millisecond
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "SMS", "success": false, "error": "provider rejected the number", "latencyMs": float64(40)},
+		{"type": "EMAIL", "success": true, "latencyMs": float64(0)},
+	}, test())
+	assert.Empty(t, service.notifications)
+	assert.Empty(t, service.transmissions)
+
+	rr = sendJSON(t, router, "POST", "/api/v3/subscription/id/missing/test", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWebhookURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  Channel
+		expected string
+	}{
+		{"Host and path", Channel{Host: "hooks.local", Properties: map[string]string{"path": "/alerts"}}, "http://hooks.local/alerts"},
+		{"Port and relative path", Channel{Host: "hooks.local", Port: 8080, Properties: map[string]string{"path": "alerts"}}, "http://hooks.local:8080/alerts"},
+		{"Scheme", Channel{Host: "hooks.local", Properties: map[string]string{"path": "/a", "scheme": "https"}}, "https://hooks.local/a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, webhookURL(tt.channel))
+		})
+	}
+}
+
+func TestRenderContent(t *testing.T) {
+	notification := Notification{
+		Id:       "n-1",
+		Category: "SECURITY",
+		Content:  "Door forced open",
+		Severity: NotificationSeverityCritical,
+		Labels:   []string{"door", "lobby"},
+	}
+
+	tests := []struct {
+		name        string
+		template    string
+		expected    string
+		expectError bool
+	}{
+		{"No template", "", "Door forced open", false},
+		{"SMS one-liner", "[{{.Severity}}] {{.Content}}", "[CRITICAL] Door forced open", false},
+		{"Email HTML with labels", "<h1>{{.Category}}</h1><p>{{.Content}}</p>{{range .Labels}}<i>{{.}}</i>{{end}}", "<h1>SECURITY</h1><p>Door forced open</p><i>door</i><i>lobby</i>", false},
+		{"Missing field", "{{.Location}}", "", true},
+		{"Parse error", "{{.Content", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := renderContent(notification, Channel{Type: "SMS", Template: tt.template})
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, content)
+		})
+	}
+}
+
+func TestSupportNotificationsService_SubscriptionTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     string
+		expectedCode int
+	}{
+		{"Valid template", "{{.Severity}}: {{.Content}}", http.StatusCreated},
+		{"Missing field", "{{.Location}}", http.StatusBadRequest},
+		{"Parse error", "{{if .Content}}", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			subscription := Subscription{Name: "ops", Channels: []Channel{{Type: "SMS", Recipients: []string{"+15550100"}, Template: tt.template}}}
+			rr := postJSON(t, service.addSubscription, "/api/v3/subscription", subscription)
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestSupportNotificationsService_WebhookDeliversRenderedContent(t *testing.T) {
+	var received Notification
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer webhook.Close()
+
+	host, portValue, err := net.SplitHostPort(strings.TrimPrefix(webhook.URL, "http://"))
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portValue)
+	require.NoError(t, err)
+
+	service := newTestService()
+	channel := Channel{Type: "WEBHOOK", Host: host, Port: port, Properties: map[string]string{"path": "/"}, Template: "{{.Category}}/{{.Content}}"}
+	require.NoError(t, service.sendToChannel(Notification{Category: "SECURITY", Content: "alert"}, channel))
+
+	assert.Equal(t, "SECURITY/alert", received.Content)
+}
+
+func TestSupportNotificationsService_ResendStopsOnShutdown(t *testing.T) {
+	service := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	service.ctx = ctx
+	cancel()
+
+	subscription := Subscription{
+		Name:           "operators",
+		Channels:       []Channel{{Type: "PIGEON"}},
+		ResendLimit:    5,
+		ResendInterval: "1h",
+	}
+
+	start := time.Now()
+	delivered, err := service.sendNotification(Notification{Id: "n-1"}, subscription)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSupportNotificationsService_SubscriptionVersionConflict(t *testing.T) {
+	service := newTestService()
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	channels := []Channel{{Type: "EMAIL", Recipients: []string{"ops@example.com"}}}
+	rr := sendJSON(t, router, "POST", "/api/v3/subscription", Subscription{Name: "ops", Channels: channels})
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	// Two clients update from the same version; the second would overwrite the first
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/ops", Subscription{Description: "first", Channels: channels, Version: 1})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/ops", Subscription{Description: "second", Channels: channels, Version: 1})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	subscription, exists := service.subscriptionByNameLocked("ops")
+	require.True(t, exists)
+	assert.Equal(t, "first", subscription.Description)
+	assert.Equal(t, int64(2), subscription.Version)
+
+	// Retrying with the current version succeeds
+	rr = sendJSON(t, router, "PUT", "/api/v3/subscription/name/ops", Subscription{Description: "second", Channels: channels, Version: 2})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestSupportNotificationsService_SubscriptionListOrder(t *testing.T) {
+	service := newTestService()
+	for id, created := range map[string]int64{"s-1": 1000, "s-2": 3000, "s-3": 2000, "s-0": 3000} {
+		service.subscriptions[id] = Subscription{Id: id, Name: id, Created: created}
+	}
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+
+	// Newest first, ties broken by id, the same on every call
+	for i := 0; i < 5; i++ {
+		rr := sendJSON(t, router, "GET", "/api/v3/subscription/all", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Subscriptions []Subscription `json:"subscriptions"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		var ids []string
+		for _, subscription := range response.Subscriptions {
+			ids = append(ids, subscription.Id)
+		}
+		assert.Equal(t, []string{"s-0", "s-2", "s-3", "s-1"}, ids)
+	}
+}
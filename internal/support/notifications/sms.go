@@ -0,0 +1,148 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// MaxSMSLength is the longest SMS body sent; longer content is truncated with an ellipsis
+const MaxSMSLength = 160
+
+// SMS provider secret, stored at edgex/support-notifications/sms
+const (
+	SMSSecretName       = "sms"
+	SMSSecretAccountSid = "accountSid"
+	SMSSecretAuthToken  = "authToken"
+	SMSSecretFrom       = "from"
+	SMSSecretBaseURL    = "baseUrl"
+)
+
+// DefaultTwilioBaseURL is the Twilio REST API used unless the secret overrides it
+const DefaultTwilioBaseURL = "https://api.twilio.com"
+
+// SMSSender delivers a text message to one or more phone numbers
+type SMSSender interface {
+	Send(to []string, body string) error
+}
+
+// TwilioSMSSender sends SMS through the Twilio Messages API, one request per recipient
+type TwilioSMSSender struct {
+	baseURL    string
+	accountSid string
+	authToken  string
+	from       string
+	httpClient *http.Client
+	logger     *logrus.Logger
+}
+
+// NewTwilioSMSSender creates a Twilio sender from the service's SMS secret
+func NewTwilioSMSSender(secretProvider *secrets.SecretProvider, logger *logrus.Logger) (*TwilioSMSSender, error) {
+	secret, err := secretProvider.GetServiceSecrets(common.SupportNotificationsServiceKey, SMSSecretName,
+		SMSSecretAccountSid, SMSSecretAuthToken, SMSSecretFrom, SMSSecretBaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret[SMSSecretAccountSid] == "" || secret[SMSSecretAuthToken] == "" || secret[SMSSecretFrom] == "" {
+		return nil, fmt.Errorf("incomplete SMS credentials, %s, %s and %s are required", SMSSecretAccountSid, SMSSecretAuthToken, SMSSecretFrom)
+	}
+
+	baseURL := secret[SMSSecretBaseURL]
+	if baseURL == "" {
+		baseURL = DefaultTwilioBaseURL
+	}
+
+	return &TwilioSMSSender{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		accountSid: secret[SMSSecretAccountSid],
+		authToken:  secret[SMSSecretAuthToken],
+		from:       secret[SMSSecretFrom],
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		logger:     logger,
+	}, nil
+}
+
+// Send posts the message to every recipient, failing if any recipient could not be reached
+func (t *TwilioSMSSender) Send(to []string, body string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.baseURL, url.PathEscape(t.accountSid))
+
+	var failed []string
+	for _, recipient := range to {
+		if err := t.sendOne(endpoint, recipient, body); err != nil {
+			t.logger.Warnf("Failed to send SMS to %s: %v", recipient, err)
+			failed = append(failed, recipient)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("SMS delivery failed for recipients: %v", failed)
+	}
+	return nil
+}
+
+// sendOne posts a single message
+func (t *TwilioSMSSender) sendOne(endpoint, recipient, body string) error {
+	form := url.Values{}
+	form.Set("To", recipient)
+	form.Set("From", t.from)
+	form.Set("Body", body)
+
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(common.ContentType, "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.accountSid, t.authToken)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// SetSMSSender configures the provider SMS channels deliver through. Without one, SMS delivery fails.
+func (s *SupportNotificationsService) SetSMSSender(sender SMSSender) {
+	s.smsSender = sender
+}
+
+// sendSMSNotification sends the notification content to the channel's recipients
+func (s *SupportNotificationsService) sendSMSNotification(notification Notification, channel Channel) error {
+	if s.smsSender == nil {
+		return errors.New("no SMS provider configured")
+	}
+
+	body := notification.Content
+	if truncated, ok := truncateSMS(body); ok {
+		s.logger.Warnf("SMS body for notification %s exceeds %d characters, truncating", notification.Id, MaxSMSLength)
+		body = truncated
+	}
+
+	s.logger.Infof("Sending SMS notification %s to %v", notification.Id, channel.Recipients)
+	return s.smsSender.Send(channel.Recipients, body)
+}
+
+// truncateSMS shortens body to MaxSMSLength characters, ending in an ellipsis, and reports whether it did
+func truncateSMS(body string) (string, bool) {
+	runes := []rune(body)
+	if len(runes) <= MaxSMSLength {
+		return body, false
+	}
+	return string(runes[:MaxSMSLength-1]) + "…", true
+}
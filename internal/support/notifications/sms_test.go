@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// mockSMSSender records every message instead of sending it, optionally failing the first attempts
+type mockSMSSender struct {
+	failures int
+	to       [][]string
+	bodies   []string
+	mutex    sync.Mutex
+}
+
+func (m *mockSMSSender) Send(to []string, body string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("provider unavailable")
+	}
+	m.to = append(m.to, to)
+	m.bodies = append(m.bodies, body)
+	return nil
+}
+
+func TestSupportNotificationsService_SendSMS(t *testing.T) {
+	service := newTestService()
+	sender := &mockSMSSender{}
+	service.SetSMSSender(sender)
+
+	channel := Channel{Type: ChannelTypeSMS, Recipients: []string{"+15550100", "+15550101"}, Template: "[{{.Severity}}] {{.Content}}"}
+	notification := Notification{Id: "n-1", Severity: NotificationSeverityCritical, Content: "Door forced open"}
+
+	require.NoError(t, service.sendToChannel(notification, channel))
+
+	require.Len(t, sender.bodies, 1)
+	assert.Equal(t, "[CRITICAL] Door forced open", sender.bodies[0])
+	assert.Equal(t, []string{"+15550100", "+15550101"}, sender.to[0])
+}
+
+func TestSupportNotificationsService_SendSMSTruncates(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	service := NewSupportNotificationsService(logger)
+	sender := &mockSMSSender{}
+	service.SetSMSSender(sender)
+
+	content := strings.Repeat("é", MaxSMSLength+40)
+	require.NoError(t, service.sendSMSNotification(Notification{Id: "n-1", Content: content}, Channel{Type: ChannelTypeSMS, Recipients: []string{"+15550100"}}))
+
+	require.Len(t, sender.bodies, 1)
+	assert.Equal(t, MaxSMSLength, utf8.RuneCountInString(sender.bodies[0]))
+	assert.True(t, strings.HasSuffix(sender.bodies[0], "…"))
+
+	warned := false
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, "truncating") {
+			warned = true
+		}
+	}
+	assert.True(t, warned)
+}
+
+func TestSupportNotificationsService_SendSMSRetries(t *testing.T) {
+	service := newTestService()
+	sender := &mockSMSSender{failures: 2}
+	service.SetSMSSender(sender)
+
+	subscription := Subscription{
+		Name:           "on-call",
+		Channels:       []Channel{{Type: ChannelTypeSMS, Recipients: []string{"+15550100"}}},
+		ResendLimit:    2,
+		ResendInterval: "1ms",
+	}
+
+	delivered, err := service.sendNotification(Notification{Id: "n-1", Content: "alert"}, subscription)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Len(t, sender.bodies, 1)
+}
+
+func TestSupportNotificationsService_SendSMSWithoutProvider(t *testing.T) {
+	service := newTestService()
+
+	err := service.sendSMSNotification(Notification{Id: "n-1", Content: "alert"}, Channel{Type: ChannelTypeSMS, Recipients: []string{"+15550100"}})
+
+	assert.Error(t, err)
+}
+
+func TestTruncateSMS(t *testing.T) {
+	short := strings.Repeat("a", MaxSMSLength)
+	body, truncated := truncateSMS(short)
+	assert.False(t, truncated)
+	assert.Equal(t, short, body)
+
+	body, truncated = truncateSMS(short + "b")
+	assert.True(t, truncated)
+	assert.Equal(t, strings.Repeat("a", MaxSMSLength-1)+"…", body)
+}
+
+func TestTwilioSMSSender_Send(t *testing.T) {
+	var received []map[string]string
+	var mutex sync.Mutex
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "AC123" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+
+		mutex.Lock()
+		received = append(received, map[string]string{"To": r.PostForm.Get("To"), "From": r.PostForm.Get("From"), "Body": r.PostForm.Get("Body")})
+		mutex.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer provider.Close()
+
+	logger := logrus.New()
+	secretsClient := secrets.NewInMemorySecretsClient(logger)
+	require.NoError(t, secretsClient.StoreSecret("edgex/support-notifications/sms", map[string]string{
+		SMSSecretAccountSid: "AC123",
+		SMSSecretAuthToken:  "token",
+		SMSSecretFrom:       "+15559999",
+		SMSSecretBaseURL:    provider.URL,
+	}))
+
+	sender, err := NewTwilioSMSSender(secrets.NewSecretProvider(secretsClient, logger), logger)
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send([]string{"+15550100", "+15550101"}, "Door forced open"))
+
+	require.Len(t, received, 2)
+	assert.Equal(t, map[string]string{"To": "+15550100", "From": "+15559999", "Body": "Door forced open"}, received[0])
+	assert.Equal(t, "+15550101", received[1]["To"])
+}
+
+func TestTwilioSMSSender_ProviderError(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid number", http.StatusBadRequest)
+	}))
+	defer provider.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	secretsClient := secrets.NewInMemorySecretsClient(logger)
+	require.NoError(t, secretsClient.StoreSecret("edgex/support-notifications/sms", map[string]string{
+		SMSSecretAccountSid: "AC123",
+		SMSSecretAuthToken:  "token",
+		SMSSecretFrom:       "+15559999",
+		SMSSecretBaseURL:    provider.URL,
+	}))
+
+	sender, err := NewTwilioSMSSender(secrets.NewSecretProvider(secretsClient, logger), logger)
+	require.NoError(t, err)
+
+	err = sender.Send([]string{"+15550100"}, "alert")
+	assert.Error(t, err)
+}
+
+func TestNewTwilioSMSSender_MissingCredentials(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	secretsClient := secrets.NewInMemorySecretsClient(logger)
+
+	_, err := NewTwilioSMSSender(secrets.NewSecretProvider(secretsClient, logger), logger)
+	assert.Error(t, err)
+
+	require.NoError(t, secretsClient.StoreSecret("edgex/support-notifications/sms", map[string]string{SMSSecretAccountSid: "AC123"}))
+	_, err = NewTwilioSMSSender(secrets.NewSecretProvider(secretsClient, logger), logger)
+	assert.Error(t, err)
+}
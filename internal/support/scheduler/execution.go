@@ -0,0 +1,242 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// DefaultRequestTimeout bounds each request a scheduled job makes unless SetRequestTimeout overrides it
+const DefaultRequestTimeout = 30 * time.Second
+
+// JobExecution records the outcome of one run of a scheduled job
+type JobExecution struct {
+	Timestamp  int64  `json:"timestamp"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Attempts   int    `json:"attempts"` // Invocations made, including retries, across all actions
+	Latency    string `json:"latency"`
+	Error      string `json:"error,omitempty"`
+}
+
+// jobStatus tracks the most recent execution of a job, how many runs in a row have failed and how many
+// were skipped to avoid overlapping
+type jobStatus struct {
+	lastExecution       JobExecution
+	executed            bool // Whether lastExecution is set
+	consecutiveFailures int
+	skippedRuns         int
+}
+
+// SetRequestTimeout bounds how long a scheduled job waits for its target. Must be called before Initialize.
+func (s *SupportSchedulerService) SetRequestTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	s.httpClient = &http.Client{Timeout: timeout}
+}
+
+// SetSecretProvider lets actions without a password read their credentials from the secret
+// store, at edgex/support-scheduler/<action name>. Must be called before Initialize.
+func (s *SupportSchedulerService) SetSecretProvider(secretProvider *secrets.SecretProvider) {
+	s.secretProvider = secretProvider
+}
+
+// executeScheduledJob invokes every unlocked ScheduleAction attached to the event, in name order, and records
+// the combined result: the status of the last action and the errors of all that failed, which it also returns.
+// Cancelling ctx aborts the request in flight. Unless the event allows overlap, nothing is run and false is
+// returned while a previous run of the event is still in flight.
+func (s *SupportSchedulerService) executeScheduledJob(ctx context.Context, event ScheduleEvent) (JobExecution, bool) {
+	if !s.beginExecution(event) {
+		s.logger.Warnf("Skipped job %s: its previous run is still in flight", event.Name)
+		return JobExecution{}, false
+	}
+	defer s.endExecution(event.Id)
+
+	s.logger.Infof("Executing scheduled job: %s", event.Name)
+
+	s.mutex.RLock()
+	actions, err := s.intervalActionsLocked(event)
+	s.mutex.RUnlock()
+
+	start := s.clock.Now()
+	var statusCode, attempts int
+	var failures []error
+	if err != nil {
+		failures = append(failures, err)
+	}
+	for _, action := range actions {
+		var actionErr error
+		var actionAttempts int
+		statusCode, actionAttempts, actionErr = s.invokeActionWithRetry(ctx, action)
+		attempts += actionAttempts
+		if actionErr != nil {
+			failures = append(failures, actionErr)
+		}
+	}
+	err = errors.Join(failures...)
+
+	execution := JobExecution{
+		Timestamp:  start.UnixNano() / int64(time.Millisecond),
+		StatusCode: statusCode,
+		Attempts:   attempts,
+		Latency:    s.clock.Now().Sub(start).String(),
+	}
+	if err != nil {
+		execution.Error = err.Error()
+	}
+	consecutiveFailures := s.recordExecution(event.Id, execution)
+
+	if err != nil {
+		s.logger.Warnf("Job %s failed (%d consecutive failures): %v", event.Name, consecutiveFailures, err)
+		return execution, true
+	}
+	s.logger.Infof("Job %s executed %d action(s): status %d in %s", event.Name, len(actions), statusCode, execution.Latency)
+	return execution, true
+}
+
+// beginExecution marks a run of the event as in flight, or counts it as skipped and returns false if the
+// event does not allow overlap and a previous run is still in flight
+func (s *SupportSchedulerService) beginExecution(event ScheduleEvent) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.executing[event.Id] > 0 && !event.AllowOverlap {
+		status := s.jobStatuses[event.Id]
+		status.skippedRuns++
+		s.jobStatuses[event.Id] = status
+		s.skippedRuns++
+		return false
+	}
+	s.executing[event.Id]++
+	return true
+}
+
+// endExecution marks a run of an event as finished
+func (s *SupportSchedulerService) endExecution(eventId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.executing[eventId]--; s.executing[eventId] <= 0 {
+		delete(s.executing, eventId)
+	}
+}
+
+// intervalActionsLocked returns the unlocked actions attached to the event through their IntervalName,
+// in name order, plus the action named by the event's Addressable if it isn't already among them.
+// A missing Addressable action is returned as an error. Caller must hold the lock.
+func (s *SupportSchedulerService) intervalActionsLocked(event ScheduleEvent) ([]ScheduleAction, error) {
+	var actions []ScheduleAction
+	attached := false
+	for _, action := range s.actionsByIntervalLocked(event.Name) {
+		if action.Name == event.Addressable {
+			attached = true
+		}
+		if action.AdminState != common.Locked {
+			actions = append(actions, action)
+		}
+	}
+
+	if event.Addressable == "" || attached {
+		return actions, nil
+	}
+	action, found := s.scheduleActionByNameLocked(event.Addressable)
+	if !found {
+		return actions, fmt.Errorf("schedule action %q not found", event.Addressable)
+	}
+	if action.AdminState != common.Locked {
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// recordExecution stores the execution for a job and returns its consecutive failure count
+func (s *SupportSchedulerService) recordExecution(eventId string, execution JobExecution) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	status := s.jobStatuses[eventId]
+	status.lastExecution = execution
+	status.executed = true
+	if execution.Error != "" {
+		status.consecutiveFailures++
+		s.failedRuns++
+	} else {
+		status.consecutiveFailures = 0
+	}
+	s.jobStatuses[eventId] = status
+	return status.consecutiveFailures
+}
+
+// invokeAction performs the HTTP request an action describes, sending Parameters as the body.
+// Any non-2xx status is an error.
+func (s *SupportSchedulerService) invokeAction(ctx context.Context, action ScheduleAction) (int, error) {
+	var body io.Reader
+	if action.Parameters != "" {
+		body = strings.NewReader(action.Parameters)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, action.HTTPMethod, actionURL(action), body)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request for action %s: %w", action.Name, err)
+	}
+	if body != nil {
+		req.Header.Set(common.ContentType, common.ContentTypeJSON)
+	}
+
+	username, password := s.actionCredentials(action)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request to %s failed: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s %s returned status %d", action.HTTPMethod, req.URL, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// actionCredentials returns the basic auth credentials for an action, from the action itself
+// or, when it names a user without a password, from the secret store
+func (s *SupportSchedulerService) actionCredentials(action ScheduleAction) (string, string) {
+	if action.User == "" || action.Password != "" || s.secretProvider == nil {
+		return action.User, action.Password
+	}
+
+	secret, err := s.secretProvider.GetServiceSecrets(common.SupportSchedulerServiceKey, action.Name, "password")
+	if err != nil {
+		s.logger.Warnf("No credentials found for schedule action %s: %v", action.Name, err)
+		return action.User, ""
+	}
+	return action.User, secret["password"]
+}
+
+// actionURL builds the target URL of an action, e.g. http://localhost:59880/api/v3/event/age/0
+func actionURL(action ScheduleAction) string {
+	scheme := strings.ToLower(action.Protocol)
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	host := action.Address
+	if action.Port > 0 {
+		host = fmt.Sprintf("%s:%d", host, action.Port)
+	}
+
+	path := action.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
@@ -0,0 +1,369 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// targetAction returns an action pointing at the given test server, with retries disabled
+func targetAction(t *testing.T, target *httptest.Server, name string) ScheduleAction {
+	parsed, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(parsed.Host)
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return ScheduleAction{
+		Id:         name,
+		Name:       name,
+		Protocol:   "HTTP",
+		HTTPMethod: "DELETE",
+		Address:    host,
+		Port:       portNumber,
+		Path:       "/api/v3/event/age/0",
+		Parameters: `{"reason":"cleanup"}`,
+		Retry:      &RetryPolicy{MaxRetries: 0},
+	}
+}
+
+func TestSupportSchedulerService_ExecuteScheduledJob(t *testing.T) {
+	type request struct {
+		method, path, body, user, password string
+	}
+	received := make(chan request, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		user, password, _ := r.BasicAuth()
+		received <- request{r.Method, r.URL.Path, string(body), user, password}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer target.Close()
+
+	service := newTestService()
+	action := targetAction(t, target, "purge")
+	action.User = "admin"
+	action.Password = "secret"
+	service.putScheduleActionLocked(action)
+	event := ScheduleEvent{Id: "e-1", Name: "nightly-purge", Schedule: "@daily", Addressable: "purge"}
+	service.putScheduleEventLocked(event)
+
+	service.executeScheduledJob(context.Background(), event)
+
+	got := <-received
+	assert.Equal(t, "DELETE", got.method)
+	assert.Equal(t, "/api/v3/event/age/0", got.path)
+	assert.Equal(t, `{"reason":"cleanup"}`, got.body)
+	assert.Equal(t, "admin", got.user)
+	assert.Equal(t, "secret", got.password)
+
+	status := service.jobStatuses["e-1"]
+	assert.Equal(t, http.StatusAccepted, status.lastExecution.StatusCode)
+	assert.Empty(t, status.lastExecution.Error)
+	assert.NotEmpty(t, status.lastExecution.Latency)
+	assert.Equal(t, 0, status.consecutiveFailures)
+}
+
+func TestSupportSchedulerService_ExecuteScheduledJobFailures(t *testing.T) {
+	healthy := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer target.Close()
+
+	service := newTestService()
+	action := targetAction(t, target, "purge")
+	service.putScheduleActionLocked(action)
+	event := ScheduleEvent{Id: "e-1", Name: "nightly-purge", Schedule: "@daily", Addressable: "purge"}
+	service.putScheduleEventLocked(event)
+
+	service.executeScheduledJob(context.Background(), event)
+	service.executeScheduledJob(context.Background(), event)
+
+	status := service.jobStatuses["e-1"]
+	assert.Equal(t, 2, status.consecutiveFailures)
+	assert.Equal(t, http.StatusInternalServerError, status.lastExecution.StatusCode)
+	assert.Contains(t, status.lastExecution.Error, "500")
+
+	// A success resets the count
+	healthy = true
+	service.executeScheduledJob(context.Background(), event)
+	assert.Equal(t, 0, service.jobStatuses["e-1"].consecutiveFailures)
+}
+
+func TestSupportSchedulerService_ExecuteScheduledJobMissingAction(t *testing.T) {
+	service := newTestService()
+	event := ScheduleEvent{Id: "e-1", Name: "orphan", Schedule: "@daily", Addressable: "missing"}
+
+	service.executeScheduledJob(context.Background(), event)
+
+	status := service.jobStatuses["e-1"]
+	assert.Equal(t, 1, status.consecutiveFailures)
+	assert.Contains(t, status.lastExecution.Error, "not found")
+}
+
+func TestSupportSchedulerService_ExecuteIntervalActions(t *testing.T) {
+	var mutex sync.Mutex
+	var paths []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		paths = append(paths, r.URL.Path)
+		mutex.Unlock()
+	}))
+	defer target.Close()
+
+	service := newTestService()
+	event := ScheduleEvent{Id: "e-1", Name: "midnight", Schedule: "@daily"}
+	service.putScheduleEventLocked(event)
+
+	attach := func(name, interval, adminState string) {
+		action := targetAction(t, target, name)
+		action.Path = "/" + name
+		action.IntervalName = interval
+		action.AdminState = adminState
+		service.putScheduleActionLocked(action)
+	}
+	attach("purge", "midnight", common.Unlocked)
+	attach("backup", "midnight", common.Unlocked)
+	attach("paused", "midnight", common.Locked)
+	attach("hourly", "other", common.Unlocked)
+
+	service.executeScheduledJob(context.Background(), event)
+
+	assert.Equal(t, []string{"/backup", "/purge"}, paths)
+	status := service.jobStatuses["e-1"]
+	assert.Empty(t, status.lastExecution.Error)
+	assert.Equal(t, 0, status.consecutiveFailures)
+}
+
+func TestSupportSchedulerService_ExecuteScheduledJobTimeout(t *testing.T) {
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer target.Close()
+	defer close(release)
+
+	service := newTestService()
+	service.SetRequestTimeout(50 * time.Millisecond)
+	action := targetAction(t, target, "slow")
+	service.putScheduleActionLocked(action)
+	event := ScheduleEvent{Id: "e-1", Name: "slow-job", Schedule: "@daily", Addressable: "slow"}
+
+	start := time.Now()
+	service.executeScheduledJob(context.Background(), event)
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, 1, service.jobStatuses["e-1"].consecutiveFailures)
+}
+
+func TestSupportSchedulerService_ActionCredentialsFromSecrets(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	secretsClient := secrets.NewInMemorySecretsClient(logger)
+	require.NoError(t, secretsClient.StoreSecret("edgex/support-scheduler/purge", map[string]string{"password": "from-vault"}))
+
+	service := newTestService()
+	service.SetSecretProvider(secrets.NewSecretProvider(secretsClient, logger))
+
+	user, password := service.actionCredentials(ScheduleAction{Name: "purge", User: "admin"})
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "from-vault", password)
+
+	// An explicit password wins
+	_, password = service.actionCredentials(ScheduleAction{Name: "purge", User: "admin", Password: "inline"})
+	assert.Equal(t, "inline", password)
+}
+
+func TestActionURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   ScheduleAction
+		expected string
+	}{
+		{"Full", ScheduleAction{Protocol: "HTTP", Address: "localhost", Port: 59880, Path: "/api/v3/ping"}, "http://localhost:59880/api/v3/ping"},
+		{"HTTPS without port", ScheduleAction{Protocol: "HTTPS", Address: "example.com", Path: "/hook"}, "https://example.com/hook"},
+		{"Default scheme and relative path", ScheduleAction{Address: "core-data", Port: 59880, Path: "api/v3/ping"}, "http://core-data:59880/api/v3/ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, actionURL(tt.action))
+		})
+	}
+}
+
+func TestSupportSchedulerService_ScheduleEventReportsExecution(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+	id := createEvent(t, router, ScheduleEvent{Name: "orphan", Schedule: "@every 1h", Addressable: "missing"})
+
+	service.mutex.RLock()
+	event := service.scheduleEvents[id]
+	service.mutex.RUnlock()
+	service.executeScheduledJob(context.Background(), event)
+
+	rr := sendJSON(t, router, "GET", "/api/v3/scheduleevent/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		ScheduleEvent ScheduleEvent `json:"scheduleEvent"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.NotNil(t, response.ScheduleEvent.LastExecution)
+	assert.Contains(t, response.ScheduleEvent.LastExecution.Error, "not found")
+	assert.Equal(t, 1, response.ScheduleEvent.ConsecutiveFailures)
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	assert.Equal(t, 1, service.failedRuns)
+}
+
+func TestSupportSchedulerService_TriggerScheduleEvent(t *testing.T) {
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer target.Close()
+
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	service := newTestService()
+	service.SetClock(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	router := newTestRouter(service)
+
+	action := targetAction(t, target, "purge")
+	action.IntervalName = "hourly"
+	service.putScheduleActionLocked(action)
+	id := createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@every 1h"})
+	clock.BlockUntil(1)
+	nextRun := getEvent(t, router, id).NextRun
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/"+id+"/trigger", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var response struct {
+		Execution JobExecution `json:"execution"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusAccepted, response.Execution.StatusCode)
+	assert.Equal(t, 1, response.Execution.Attempts)
+	assert.Empty(t, response.Execution.Error)
+	assert.Equal(t, int32(1), hits.Load(), "the target is hit exactly once")
+
+	// The run is the job's latest, and the recurring schedule is untouched
+	event := getEvent(t, router, id)
+	require.NotNil(t, event.LastExecution)
+	assert.Equal(t, http.StatusAccepted, event.LastExecution.StatusCode)
+	assert.Equal(t, nextRun, event.NextRun)
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(time.Hour)
+	assert.Eventually(t, func() bool { return hits.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/missing/trigger", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestSupportSchedulerService_OverlapProtection(t *testing.T) {
+	for _, allowOverlap := range []bool{false, true} {
+		t.Run("AllowOverlap="+strconv.FormatBool(allowOverlap), func(t *testing.T) {
+			// The target holds every request until released, counting how many it holds at once
+			var inFlight, maxInFlight, hits atomic.Int32
+			release := make(chan struct{})
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					max := maxInFlight.Load()
+					if current <= max || maxInFlight.CompareAndSwap(max, current) {
+						break
+					}
+				}
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer target.Close()
+
+			clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			service := newTestService()
+			service.SetClock(clock)
+			ctx, cancel := context.WithCancel(context.Background())
+			var wg sync.WaitGroup
+			require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+			defer func() {
+				cancel()
+				wg.Wait()
+			}()
+			router := newTestRouter(service)
+
+			action := targetAction(t, target, "slow")
+			action.IntervalName = "hourly"
+			service.putScheduleActionLocked(action)
+			id := createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@every 1h", AllowOverlap: allowOverlap})
+
+			// The first run is still in flight when the second comes due
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+			require.Eventually(t, func() bool { return inFlight.Load() == 1 }, time.Second, time.Millisecond)
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+
+			if allowOverlap {
+				require.Eventually(t, func() bool { return inFlight.Load() == 2 }, time.Second, time.Millisecond)
+				assert.Zero(t, getEvent(t, router, id).SkippedRuns)
+			} else {
+				require.Eventually(t, func() bool { return getEvent(t, router, id).SkippedRuns == 1 }, time.Second, time.Millisecond)
+				assert.Nil(t, getEvent(t, router, id).LastExecution)
+				rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/"+id+"/trigger", nil)
+				assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+				assert.Equal(t, 2, getEvent(t, router, id).SkippedRuns)
+				assert.Equal(t, int32(1), maxInFlight.Load())
+			}
+
+			// Once the target catches up, the next run goes ahead
+			close(release)
+			require.Eventually(t, func() bool { return inFlight.Load() == 0 }, time.Second, time.Millisecond)
+			require.Eventually(t, func() bool {
+				service.mutex.RLock()
+				defer service.mutex.RUnlock()
+				return len(service.executing) == 0
+			}, time.Second, time.Millisecond)
+			before := hits.Load()
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+			require.Eventually(t, func() bool { return hits.Load() == before+1 }, time.Second, time.Millisecond)
+			require.Eventually(t, func() bool { return getEvent(t, router, id).LastExecution != nil }, time.Second, time.Millisecond)
+			assert.Empty(t, getEvent(t, router, id).LastExecution.Error)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// Redis key layout for the scheduler: one hash of events and one of actions, each field an id
+// holding the item as JSON
+const (
+	redisScheduleEventsKey  = "edgex:support-scheduler:events"
+	redisScheduleActionsKey = "edgex:support-scheduler:actions"
+)
+
+// RedisScheduleStore implements ScheduleStore using Redis
+type RedisScheduleStore struct {
+	client *redis.Client
+	logger *logrus.Logger
+	ctx    context.Context
+}
+
+// NewRedisScheduleStore creates a new Redis schedule store, using database credentials from the secret provider when present
+func NewRedisScheduleStore(addr string, db int, secretProvider *secrets.SecretProvider, logger *logrus.Logger) *RedisScheduleStore {
+	options := &redis.Options{
+		Addr: addr,
+		DB:   db,
+	}
+
+	if secretProvider != nil {
+		username, password, err := secretProvider.GetDatabaseCredentials(common.SupportSchedulerServiceKey)
+		if err != nil {
+			logger.Infof("No database credentials found, connecting to Redis without authentication: %v", err)
+		} else {
+			options.Username = username
+			options.Password = password
+		}
+	}
+
+	return &RedisScheduleStore{
+		client: redis.NewClient(options),
+		logger: logger,
+		ctx:    context.Background(),
+	}
+}
+
+// Connect verifies the Redis connection
+func (r *RedisScheduleStore) Connect() error {
+	if err := r.client.Ping(r.ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that Redis is reachable; suitable as a health check
+func (r *RedisScheduleStore) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis unreachable: %w", err)
+	}
+	return nil
+}
+
+// Close closes the Redis connection
+func (r *RedisScheduleStore) Close() error {
+	return r.client.Close()
+}
+
+// LoadEvents returns every saved event
+func (r *RedisScheduleStore) LoadEvents() ([]ScheduleEvent, error) {
+	var events []ScheduleEvent
+	err := r.load(redisScheduleEventsKey, func(data []byte) error {
+		var event ScheduleEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return err
+		}
+		events = append(events, event)
+		return nil
+	})
+	return events, err
+}
+
+// LoadActions returns every saved action
+func (r *RedisScheduleStore) LoadActions() ([]ScheduleAction, error) {
+	var actions []ScheduleAction
+	err := r.load(redisScheduleActionsKey, func(data []byte) error {
+		var action ScheduleAction
+		if err := json.Unmarshal(data, &action); err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	return actions, err
+}
+
+// SaveEvent stores an event, replacing any with the same id
+func (r *RedisScheduleStore) SaveEvent(event ScheduleEvent) error {
+	return r.save(redisScheduleEventsKey, event.Id, event)
+}
+
+// SaveAction stores an action, replacing any with the same id
+func (r *RedisScheduleStore) SaveAction(action ScheduleAction) error {
+	return r.save(redisScheduleActionsKey, action.Id, action)
+}
+
+// DeleteEvent removes an event
+func (r *RedisScheduleStore) DeleteEvent(id string) error {
+	if err := r.client.HDel(r.ctx, redisScheduleEventsKey, id).Err(); err != nil {
+		return fmt.Errorf("failed to delete schedule event %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteAction removes an action
+func (r *RedisScheduleStore) DeleteAction(id string) error {
+	if err := r.client.HDel(r.ctx, redisScheduleActionsKey, id).Err(); err != nil {
+		return fmt.Errorf("failed to delete schedule action %s: %w", id, err)
+	}
+	return nil
+}
+
+// save stores item as JSON under id in the hash at key
+func (r *RedisScheduleStore) save(key, id string, item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", id, err)
+	}
+	if err := r.client.HSet(r.ctx, key, id, data).Err(); err != nil {
+		return fmt.Errorf("failed to save %s: %w", id, err)
+	}
+	return nil
+}
+
+// load passes each item in the hash at key to decode
+func (r *RedisScheduleStore) load(key string, decode func(data []byte) error) error {
+	items, err := r.client.HGetAll(r.ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	for id, data := range items {
+		if err := decode([]byte(data)); err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build integration
+
+package scheduler
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedisScheduleStore_Conformance(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "localhost:6379"
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	runScheduleStoreConformance(t, func(t *testing.T) ScheduleStore {
+		store := NewRedisScheduleStore(addr, 15, nil, logger)
+		if err := store.Connect(); err != nil {
+			t.Skipf("Redis not available at %s: %v", addr, err)
+		}
+		if err := store.client.FlushDB(store.ctx).Err(); err != nil {
+			t.Fatalf("failed to flush Redis test database: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Retry defaults used for actions without their own policy, and for fields a policy leaves unset
+const (
+	DefaultMaxRetries        = 3
+	DefaultInitialBackoff    = time.Second
+	DefaultBackoffMultiplier = 2.0
+	DefaultMaxBackoff        = 30 * time.Second
+)
+
+// RetryPolicy controls how failed invocations of an action are retried. Backoffs are duration
+// strings such as "500ms". MaxRetries of zero disables retries.
+type RetryPolicy struct {
+	MaxRetries     int     `json:"maxRetries"`
+	InitialBackoff string  `json:"initialBackoff,omitempty"`
+	Multiplier     float64 `json:"multiplier,omitempty"`
+	MaxBackoff     string  `json:"maxBackoff,omitempty"`
+}
+
+// backoff is a validated RetryPolicy
+type backoff struct {
+	maxRetries int
+	initial    time.Duration
+	multiplier float64
+	max        time.Duration
+}
+
+// retryBackoff returns the action's retry policy with defaults applied
+func retryBackoff(action ScheduleAction) (backoff, error) {
+	policy := backoff{
+		maxRetries: DefaultMaxRetries,
+		initial:    DefaultInitialBackoff,
+		multiplier: DefaultBackoffMultiplier,
+		max:        DefaultMaxBackoff,
+	}
+	if action.Retry == nil {
+		return policy, nil
+	}
+
+	retry := action.Retry
+	if retry.MaxRetries < 0 {
+		return policy, fmt.Errorf("maxRetries must not be negative")
+	}
+	policy.maxRetries = retry.MaxRetries
+	if retry.Multiplier != 0 {
+		if retry.Multiplier < 1 {
+			return policy, fmt.Errorf("multiplier must be at least 1")
+		}
+		policy.multiplier = retry.Multiplier
+	}
+
+	var err error
+	if retry.InitialBackoff != "" {
+		if policy.initial, err = time.ParseDuration(retry.InitialBackoff); err != nil || policy.initial <= 0 {
+			return policy, fmt.Errorf("invalid initialBackoff %q", retry.InitialBackoff)
+		}
+	}
+	if retry.MaxBackoff != "" {
+		if policy.max, err = time.ParseDuration(retry.MaxBackoff); err != nil || policy.max <= 0 {
+			return policy, fmt.Errorf("invalid maxBackoff %q", retry.MaxBackoff)
+		}
+	}
+	return policy, nil
+}
+
+// delay returns how long to wait before the given retry, counting from 1
+func (b backoff) delay(retry int) time.Duration {
+	delay := float64(b.initial)
+	for i := 1; i < retry; i++ {
+		delay *= b.multiplier
+		if delay >= float64(b.max) {
+			return b.max
+		}
+	}
+	if delay > float64(b.max) {
+		return b.max
+	}
+	return time.Duration(delay)
+}
+
+// invokeActionWithRetry invokes an action, retrying transport errors and 429/5xx responses with
+// exponential backoff. Retries stop early once the next wait would run past ctx's deadline.
+// It returns the last status code, the number of attempts made and the last error.
+func (s *SupportSchedulerService) invokeActionWithRetry(ctx context.Context, action ScheduleAction) (int, int, error) {
+	policy, err := retryBackoff(action)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid retry policy for action %s: %w", action.Name, err)
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		statusCode, err := s.invokeAction(ctx, action)
+		if err == nil || attempts > policy.maxRetries || !retryable(statusCode) || ctx.Err() != nil {
+			return statusCode, attempts, err
+		}
+
+		wait := policy.delay(attempts)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return statusCode, attempts, err
+		}
+		s.logger.Debugf("Retrying action %s in %s after attempt %d failed: %v", action.Name, wait, attempts, err)
+
+		timer := s.clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return statusCode, attempts, err
+		case <-timer.C():
+		}
+	}
+}
+
+// retryable reports whether a failure with the given status code may succeed if tried again.
+// A zero status code means the request never got a response.
+func retryable(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
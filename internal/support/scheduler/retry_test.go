@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	policy, err := retryBackoff(ScheduleAction{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxRetries, policy.maxRetries)
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, DefaultMaxBackoff, policy.delay(10))
+
+	policy, err = retryBackoff(ScheduleAction{Retry: &RetryPolicy{MaxRetries: 5, InitialBackoff: "100ms", Multiplier: 3, MaxBackoff: "500ms"}})
+	require.NoError(t, err)
+	assert.Equal(t, 5, policy.maxRetries)
+	assert.Equal(t, 300*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 500*time.Millisecond, policy.delay(3))
+
+	invalid := []RetryPolicy{
+		{MaxRetries: -1},
+		{InitialBackoff: "soon"},
+		{MaxBackoff: "-1s"},
+		{Multiplier: 0.5},
+	}
+	for _, retry := range invalid {
+		retry := retry
+		_, err := retryBackoff(ScheduleAction{Retry: &retry})
+		assert.Error(t, err, "%+v", retry)
+	}
+}
+
+func TestSupportSchedulerService_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer target.Close()
+
+	service := newTestService()
+	action := targetAction(t, target, "purge")
+	action.Retry = &RetryPolicy{MaxRetries: 3, InitialBackoff: "10ms"}
+	service.putScheduleActionLocked(action)
+	event := ScheduleEvent{Id: "e-1", Name: "nightly-purge", Schedule: "@daily", Addressable: "purge"}
+
+	service.executeScheduledJob(context.Background(), event)
+
+	status := service.jobStatuses["e-1"]
+	assert.Empty(t, status.lastExecution.Error)
+	assert.Equal(t, http.StatusOK, status.lastExecution.StatusCode)
+	assert.Equal(t, 3, status.lastExecution.Attempts)
+	assert.Equal(t, 0, status.consecutiveFailures)
+}
+
+func TestSupportSchedulerService_RetryLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		retry    RetryPolicy
+		deadline time.Duration
+		attempts int
+	}{
+		{"Gives up after max retries", http.StatusBadGateway, RetryPolicy{MaxRetries: 2, InitialBackoff: "10ms"}, 0, 3},
+		{"Client errors are not retried", http.StatusBadRequest, RetryPolicy{MaxRetries: 3, InitialBackoff: "10ms"}, 0, 1},
+		{"Stops before the next run", http.StatusServiceUnavailable, RetryPolicy{MaxRetries: 3, InitialBackoff: "1s"}, 200 * time.Millisecond, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer target.Close()
+
+			service := newTestService()
+			action := targetAction(t, target, "purge")
+			action.Retry = &tt.retry
+			service.putScheduleActionLocked(action)
+			event := ScheduleEvent{Id: "e-1", Name: "nightly-purge", Schedule: "@daily", Addressable: "purge"}
+
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			start := time.Now()
+			service.executeScheduledJob(ctx, event)
+
+			status := service.jobStatuses["e-1"]
+			assert.Equal(t, tt.attempts, status.lastExecution.Attempts)
+			assert.Equal(t, int32(tt.attempts), atomic.LoadInt32(&calls))
+			assert.Equal(t, tt.status, status.lastExecution.StatusCode)
+			assert.NotEmpty(t, status.lastExecution.Error)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// everyPrefix starts an interval schedule such as @every 15m
+const everyPrefix = "@every "
+
+// scheduleParser accepts standard 5-field cron specs, 6-field specs with leading seconds,
+// and descriptors such as @hourly and @every 90s
+var scheduleParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ValidateSchedule checks a schedule expression: a cron spec of 5 fields, or 6 with leading seconds,
+// optionally prefixed with CRON_TZ=<zone>; a descriptor such as @hourly; or an interval such as
+// @every 15m. For an interval it returns how often the schedule fires, which must be a whole number
+// of seconds, at least one. Other schedules fire at calendar times rather than a fixed interval, so
+// their duration is 0.
+func ValidateSchedule(expr string) (time.Duration, error) {
+	if expr == "" {
+		return 0, errors.New("schedule is required")
+	}
+	if strings.HasPrefix(expr, everyPrefix) {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, everyPrefix)))
+		if err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		// The cron library silently rounds anything else up or down to a whole second
+		if interval < time.Second || interval%time.Second != 0 {
+			return 0, fmt.Errorf("invalid schedule %q: interval must be a whole number of seconds, at least 1s", expr)
+		}
+		return interval, nil
+	}
+	if _, err := scheduleParser.Parse(expr); err != nil {
+		return 0, fmt.Errorf("invalid schedule %q: %w", expr, err)
+	}
+	return 0, nil
+}
+
+// parseSchedule validates a schedule expression and returns the schedule it describes
+func parseSchedule(spec string) (cron.Schedule, error) {
+	if _, err := ValidateSchedule(spec); err != nil {
+		return nil, err
+	}
+	return scheduleParser.Parse(spec)
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		interval time.Duration
+	}{
+		{"Five fields", "*/5 * * * *", 0},
+		{"Six fields with seconds", "30 */5 * * * *", 0},
+		{"Ranges and lists", "0 8-18 * * MON-FRI", 0},
+		{"Time zone prefix", "CRON_TZ=Europe/Berlin 0 6 * * *", 0},
+		{"Descriptor", "@hourly", 0},
+		{"Every minutes", "@every 15m", 15 * time.Minute},
+		{"Every hours", "@every 2h", 2 * time.Hour},
+		{"Every compound", "@every 1h30m", 90 * time.Minute},
+		{"Every minimum", "@every 1s", time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, err := ValidateSchedule(tt.schedule)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.interval, interval)
+		})
+	}
+}
+
+func TestValidateSchedule_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		expected string
+	}{
+		{"Empty", "", "schedule is required"},
+		{"Garbage", "every five minutes", `invalid schedule "every five minutes"`},
+		{"Too few fields", "* * *", `invalid schedule "* * *"`},
+		{"Too many fields", "0 0 0 * * * *", `invalid schedule "0 0 0 * * * *"`},
+		{"Minute out of range", "61 * * * *", `invalid schedule "61 * * * *"`},
+		{"Unknown descriptor", "@fortnightly", `invalid schedule "@fortnightly"`},
+		{"Unknown time zone", "CRON_TZ=Mars/Olympus 0 6 * * *", `invalid schedule "CRON_TZ=Mars/Olympus 0 6 * * *"`},
+		{"Every without duration", "@every ", `invalid schedule "@every "`},
+		{"Every unit missing", "@every 5", `invalid schedule "@every 5"`},
+		{"Every words", "@every soon", `invalid schedule "@every soon"`},
+		{"Every zero", "@every 0s", "interval must be a whole number of seconds, at least 1s"},
+		{"Every negative", "@every -5m", "interval must be a whole number of seconds, at least 1s"},
+		{"Every sub-second", "@every 500ms", "interval must be a whole number of seconds, at least 1s"},
+		{"Every fractional seconds", "@every 1500ms", "interval must be a whole number of seconds, at least 1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateSchedule(tt.schedule)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.expected)
+			}
+		})
+	}
+}
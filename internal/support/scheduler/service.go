@@ -3,48 +3,83 @@ package scheduler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/metrics"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 )
 
-// ScheduleEvent represents a scheduled job
+// ScheduleEvent represents a scheduled job, the interval in EdgeX terms. When it fires, every unlocked
+// ScheduleAction whose IntervalName matches its Name is executed.
 type ScheduleEvent struct {
 	Id          string `json:"id"`
 	Name        string `json:"name"`
 	Schedule    string `json:"schedule"`    // Cron expression
-	Addressable string `json:"addressable"` // Target endpoint
+	Addressable string `json:"addressable"` // Optional name of one more ScheduleAction to run, kept for older clients
 	Parameters  string `json:"parameters"`
 	Service     string `json:"service"`
 	AdminState  string `json:"adminState"`
 	Created     int64  `json:"created"`
 	Modified    int64  `json:"modified"`
+	NextRun     int64  `json:"nextRun,omitempty"` // Next execution in milliseconds, only while the event is running
+	NextRunTime string `json:"nextRunTime,omitempty"` // NextRun as RFC 3339 in the event's time zone
+	Timezone    string `json:"timezone,omitempty"`    // IANA name of the zone the cron expression is evaluated in, UTC if empty
+	
+	// Optional window, in milliseconds, outside which the event never fires. A RunOnce event fires
+	// a single time, at its first activation in the window, then is marked COMPLETED in Status.
+	StartTimestamp int64  `json:"startTimestamp,omitempty"`
+	EndTimestamp   int64  `json:"endTimestamp,omitempty"`
+	RunOnce        bool   `json:"runOnce,omitempty"`
+	Status         string `json:"status,omitempty"`
+
+	// A run that comes due while the previous one is still in flight is skipped unless AllowOverlap is set
+	AllowOverlap bool `json:"allowOverlap,omitempty"`
+
+	// Outcome of the most recent run and the number of runs in a row that have failed
+	LastExecution       *JobExecution `json:"lastExecution,omitempty"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	SkippedRuns         int           `json:"skippedRuns"` // Runs skipped because the previous one was still in flight
 }
 
-// ScheduleAction represents a scheduled action
+// ScheduleAction represents a scheduled action, the interval action in EdgeX terms
 type ScheduleAction struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Schedule    string `json:"schedule"`
-	Target      string `json:"target"`
-	Protocol    string `json:"protocol"`
-	HTTPMethod  string `json:"httpMethod"`
-	Address     string `json:"address"`
-	Port        int    `json:"port"`
-	Path        string `json:"path"`
-	Parameters  string `json:"parameters"`
-	User        string `json:"user"`
-	Password    string `json:"password"`
-	AdminState  string `json:"adminState"`
-	Created     int64  `json:"created"`
-	Modified    int64  `json:"modified"`
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	IntervalName string `json:"intervalName"` // Name of the ScheduleEvent that triggers this action
+	Schedule     string `json:"schedule"`
+	Target       string `json:"target"`
+	Protocol     string `json:"protocol"`
+	HTTPMethod   string `json:"httpMethod"`
+	Address      string `json:"address"`
+	Port         int    `json:"port"`
+	Path         string `json:"path"`
+	Parameters   string `json:"parameters"`
+	User         string `json:"user"`
+	Password     string `json:"password"`
+	AdminState   string `json:"adminState"`
+	Created      int64  `json:"created"`
+	Modified     int64  `json:"modified"`
+	
+	// How failed invocations are retried; DefaultMaxRetries with 1s doubling backoff when unset
+	Retry *RetryPolicy `json:"retry,omitempty"`
+}
+
+// runningJob is a job registered with the scheduler. Cancelling its context stops the job firing,
+// stops the timer ending its window and aborts an execution in flight.
+type runningJob struct {
+	schedule cron.Schedule
+	cancel   context.CancelFunc
 }
 
 // SupportSchedulerService handles scheduled jobs and actions
@@ -52,7 +87,21 @@ type SupportSchedulerService struct {
 	logger          *logrus.Logger
 	scheduleEvents  map[string]ScheduleEvent
 	scheduleActions map[string]ScheduleAction
-	runningJobs     map[string]*time.Ticker
+	eventIdsByName  map[string]string
+	actionIdsByName map[string]string
+	runningJobs     map[string]runningJob
+	jobStatuses     map[string]jobStatus
+	executing       map[string]int  // Runs in flight, by event id
+	skippedRuns     int             // Runs of all events skipped to avoid overlapping
+	failedRuns      int             // Runs of all events that failed
+	clock           common.Clock
+	started         bool           // Jobs only fire once Initialize has run
+	jobs            sync.WaitGroup // Job loops and the executions they start
+	httpClient      *http.Client
+	secretProvider  *secrets.SecretProvider
+	store           ScheduleStore // Where events and actions are saved, if anywhere
+	ctx             context.Context
+	cancel          context.CancelFunc // Stops every job, set by Initialize
 	mutex           sync.RWMutex
 }
 
@@ -62,10 +111,27 @@ func NewSupportSchedulerService(logger *logrus.Logger) *SupportSchedulerService
 		logger:          logger,
 		scheduleEvents:  make(map[string]ScheduleEvent),
 		scheduleActions: make(map[string]ScheduleAction),
-		runningJobs:     make(map[string]*time.Ticker),
+		eventIdsByName:  make(map[string]string),
+		actionIdsByName: make(map[string]string),
+		runningJobs:     make(map[string]runningJob),
+		jobStatuses:     make(map[string]jobStatus),
+		executing:       make(map[string]int),
+		clock:           common.RealClock{},
+		httpClient:      &http.Client{Timeout: DefaultRequestTimeout},
+		ctx:             context.Background(),
 	}
 }
 
+// SetClock sets the clock schedules are evaluated against. Must be called before Initialize.
+func (s *SupportSchedulerService) SetClock(clock common.Clock) {
+	s.clock = clock
+}
+
+// SetStore saves events and actions in store, and has Initialize load them from it. Must be called before Initialize.
+func (s *SupportSchedulerService) SetStore(store ScheduleStore) {
+	s.store = store
+}
+
 // Initialize implements the BootstrapHandler interface
 func (s *SupportSchedulerService) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *bootstrap.DIContainer) bool {
 	s.logger.Info("Initializing Support Scheduler Service")
@@ -73,10 +139,81 @@ func (s *SupportSchedulerService) Initialize(ctx context.Context, wg *sync.WaitG
 	// Add service to DI container
 	dic.Add("SupportSchedulerService", s)
 	
+	if err := s.loadStore(); err != nil {
+		s.logger.Errorf("Failed to load schedule events and actions: %v", err)
+		return false
+	}
+	
+	// Run scheduled jobs until the service shuts down. Jobs registered before now are restarted
+	// so they run under the service's context.
+	s.mutex.Lock()
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.started = true
+	registered := make([]string, 0, len(s.runningJobs))
+	for id := range s.runningJobs {
+		registered = append(registered, id)
+	}
+	for _, id := range registered {
+		if err := s.startScheduledJobLocked(s.scheduleEvents[id]); err != nil {
+			s.logger.Errorf("Failed to start scheduled job %s: %v", id, err)
+		}
+	}
+	s.mutex.Unlock()
+	
+	err := metrics.RegisterCounterFunc("support_scheduler_runs_skipped_total", "Number of scheduled runs skipped because the previous run of the job was still in flight.", func() float64 {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return float64(s.skippedRuns)
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to register skipped run metric: %v", err)
+	}
+	err = metrics.RegisterCounterFunc("support_scheduler_runs_failed_total", "Number of scheduled or triggered runs in which an action failed.", func() float64 {
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		return float64(s.failedRuns)
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to register failed run metric: %v", err)
+	}
+	
+	jobsCtx := s.ctx
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-jobsCtx.Done()
+		// Wait for jobs already executing to return
+		s.jobs.Wait()
+		s.logger.Info("Scheduler stopped")
+	}()
+	
 	s.logger.Info("Support Scheduler Service initialization completed")
 	return true
 }
 
+// Shutdown implements the bootstrap.ShutdownHandler interface. It stops every job firing, aborts the
+// executions in flight and waits for them to return, or for ctx to be done.
+func (s *SupportSchedulerService) Shutdown(ctx context.Context) error {
+	s.mutex.Lock()
+	s.started = false
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mutex.Unlock()
+	
+	stopped := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduled jobs still running: %w", ctx.Err())
+	}
+}
+
 // AddRoutes adds support scheduler specific routes
 func (s *SupportSchedulerService) AddRoutes(router *mux.Router) {
 	// Schedule Event routes
@@ -86,6 +223,9 @@ func (s *SupportSchedulerService) AddRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v3/scheduleevent/id/{id}", s.updateScheduleEvent).Methods("PUT")
 	router.HandleFunc("/api/v3/scheduleevent/id/{id}", s.deleteScheduleEvent).Methods("DELETE")
 	router.HandleFunc("/api/v3/scheduleevent/name/{name}", s.getScheduleEventByName).Methods("GET")
+	router.HandleFunc("/api/v3/scheduleevent/id/{id}/pause", s.pauseScheduleEvent).Methods("POST")
+	router.HandleFunc("/api/v3/scheduleevent/id/{id}/resume", s.resumeScheduleEvent).Methods("POST")
+	router.HandleFunc("/api/v3/scheduleevent/id/{id}/trigger", s.triggerScheduleEvent).Methods("POST")
 	
 	// Schedule Action routes
 	router.HandleFunc("/api/v3/scheduleaction", s.addScheduleAction).Methods("POST")
@@ -94,6 +234,7 @@ func (s *SupportSchedulerService) AddRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v3/scheduleaction/id/{id}", s.updateScheduleAction).Methods("PUT")
 	router.HandleFunc("/api/v3/scheduleaction/id/{id}", s.deleteScheduleAction).Methods("DELETE")
 	router.HandleFunc("/api/v3/scheduleaction/name/{name}", s.getScheduleActionByName).Methods("GET")
+	router.HandleFunc("/api/v3/scheduleaction/interval/{name}", s.getScheduleActionsByInterval).Methods("GET")
 	
 	s.logger.Info("Support Scheduler routes registered")
 }
@@ -105,29 +246,58 @@ func (s *SupportSchedulerService) addScheduleEvent(w http.ResponseWriter, r *htt
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var event ScheduleEvent
-	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+	if err := common.DecodeJSON(w, r, &event); err != nil {
 		s.logger.Errorf("Failed to decode schedule event: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	
+	if event.Name == "" {
+		common.WriteError(w, http.StatusBadRequest, "Schedule event name is required")
+		return
+	}
+	if err := validateScheduleEvent(event); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	
 	// Generate ID and timestamps
 	event.Id = models.GenerateUUID()
-	event.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	event.Status = ""
+	event.Created = s.clock.Now().UnixNano() / int64(time.Millisecond)
 	event.Modified = event.Created
 	
 	// Set defaults
 	if event.AdminState == "" {
 		event.AdminState = common.Unlocked
 	}
+	if !validAdminState(event.AdminState) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid admin state %q", event.AdminState))
+		return
+	}
 	
 	s.mutex.Lock()
-	s.scheduleEvents[event.Id] = event
+	_, duplicate := s.eventIdsByName[event.Name]
+	var err error
+	if !duplicate {
+		err = s.putScheduleEventLocked(event)
+	}
 	s.mutex.Unlock()
 	
+	if duplicate {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule event %s already exists", event.Name))
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to create schedule event %s: %v", event.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
 	// Start the scheduled job if it's enabled
 	if event.AdminState == common.Unlocked {
-		s.startScheduledJob(event)
+		if err := s.startScheduledJob(event); err != nil {
+			s.logger.Errorf("Failed to start schedule event %s: %v", event.Name, err)
+		}
 	}
 	
 	s.logger.Infof("Schedule event created: %s", event.Name)
@@ -146,23 +316,45 @@ func (s *SupportSchedulerService) addScheduleEvent(w http.ResponseWriter, r *htt
 func (s *SupportSchedulerService) getAllScheduleEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	events := make([]ScheduleEvent, 0, len(s.scheduleEvents))
 	for _, event := range s.scheduleEvents {
-		events = append(events, event)
+		events = append(events, s.withStatusLocked(event))
 	}
 	s.mutex.RUnlock()
+	sortScheduleEventsByCreated(events)
+	page, totalCount := common.Paginate(events, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":     common.ServiceVersion,
 		"statusCode":     http.StatusOK,
-		"totalCount":     len(events),
-		"scheduleEvents": events,
+		"totalCount":     totalCount,
+		"scheduleEvents": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
 }
 
+// sortScheduleEventsByCreated orders schedule events newest first, tie-broken by id
+func sortScheduleEventsByCreated(events []ScheduleEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		return common.NewestFirst(events[i].Created, events[i].Id, events[j].Created, events[j].Id)
+	})
+}
+
+// sortScheduleActionsByCreated orders schedule actions newest first, tie-broken by id
+func sortScheduleActionsByCreated(actions []ScheduleAction) {
+	sort.Slice(actions, func(i, j int) bool {
+		return common.NewestFirst(actions[i].Created, actions[i].Id, actions[j].Created, actions[j].Id)
+	})
+}
+
 // getScheduleEventById handles GET /api/v3/scheduleevent/id/{id}
 func (s *SupportSchedulerService) getScheduleEventById(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
@@ -172,10 +364,11 @@ func (s *SupportSchedulerService) getScheduleEventById(w http.ResponseWriter, r
 	
 	s.mutex.RLock()
 	event, exists := s.scheduleEvents[id]
+	event = s.withStatusLocked(event)
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Schedule event not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
 		return
 	}
 	
@@ -188,56 +381,248 @@ func (s *SupportSchedulerService) getScheduleEventById(w http.ResponseWriter, r
 	json.NewEncoder(w).Encode(response)
 }
 
-// startScheduledJob creates and starts a scheduled job
-func (s *SupportSchedulerService) startScheduledJob(event ScheduleEvent) {
-	// For simplicity, we'll use a fixed interval instead of parsing cron expressions
-	// In a real implementation, you'd use a cron library like github.com/robfig/cron
+// validAdminState reports whether state is LOCKED or UNLOCKED
+func validAdminState(state string) bool {
+	return state == common.Locked || state == common.Unlocked
+}
+
+// startScheduledJob registers the event with the scheduler, replacing any job already running for it
+func (s *SupportSchedulerService) startScheduledJob(event ScheduleEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.startScheduledJobLocked(event)
+}
+
+// startScheduledJobLocked is startScheduledJob for callers already holding the lock
+func (s *SupportSchedulerService) startScheduledJobLocked(event ScheduleEvent) error {
+	s.stopScheduledJobLocked(event.Id)
+	if event.Status == StatusCompleted {
+		return nil
+	}
 	
-	var interval time.Duration
-	switch event.Schedule {
-	case "@every 1m":
-		interval = time.Minute
-	case "@every 5m":
-		interval = 5 * time.Minute
-	case "@every 10m":
-		interval = 10 * time.Minute
-	case "@every 1h":
-		interval = time.Hour
-	default:
-		interval = 5 * time.Minute // Default to 5 minutes
+	schedule, err := eventSchedule(event)
+	if err != nil {
+		return fmt.Errorf("failed to schedule job %s: %w", event.Name, err)
+	}
+	next := schedule.Next(s.clock.Now())
+	if next.IsZero() {
+		// The window has already closed
+		s.completeScheduleEventLocked(event.Id)
+		return nil
+	}
+	if event.RunOnce {
+		schedule = onceAt(next)
 	}
 	
-	ticker := time.NewTicker(interval)
-	s.mutex.Lock()
-	s.runningJobs[event.Id] = ticker
-	s.mutex.Unlock()
+	// Executions run under a per-job context so stopping the job also aborts a request in flight
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.runningJobs[event.Id] = runningJob{schedule: schedule, cancel: cancel}
+	if !s.started {
+		return nil
+	}
 	
-	go func() {
-		for range ticker.C {
-			s.executeScheduledJob(event)
+	s.jobs.Add(1)
+	go s.runJob(ctx, schedule, func() {
+		// Retries must give up before the next run is due
+		runCtx := ctx
+		now := s.clock.Now()
+		if next := schedule.Next(now); !next.IsZero() {
+			var cancelRun context.CancelFunc
+			runCtx, cancelRun = context.WithTimeout(ctx, next.Sub(now))
+			defer cancelRun()
 		}
-	}()
+		s.executeScheduledJob(runCtx, event)
+		if event.RunOnce {
+			s.completeScheduleEvent(ctx, event.Id)
+		}
+	})
+	if end := millisToTime(event.EndTimestamp); !end.IsZero() {
+		s.jobs.Add(1)
+		go func() {
+			defer s.jobs.Done()
+			timer := s.clock.NewTimer(end.Sub(s.clock.Now()))
+			defer timer.Stop()
+			select {
+			case <-timer.C():
+				s.completeScheduleEvent(ctx, event.Id)
+			case <-ctx.Done():
+			}
+		}()
+	}
 	
-	s.logger.Infof("Started scheduled job: %s with interval: %v", event.Name, interval)
+	s.logger.Infof("Started scheduled job: %s with schedule: %s", event.Name, event.Schedule)
+	return nil
 }
 
-// executeScheduledJob executes a scheduled job
-func (s *SupportSchedulerService) executeScheduledJob(event ScheduleEvent) {
-	s.logger.Infof("Executing scheduled job: %s", event.Name)
+// runJob calls run each time the schedule comes due on the service clock, until ctx is cancelled or the
+// schedule has no runs left. Each run gets its own goroutine, so a slow run does not delay the next one.
+func (s *SupportSchedulerService) runJob(ctx context.Context, schedule cron.Schedule, run func()) {
+	defer s.jobs.Done()
+	for {
+		now := s.clock.Now()
+		next := schedule.Next(now)
+		if next.IsZero() {
+			return
+		}
+		timer := s.clock.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		s.jobs.Add(1)
+		go func() {
+			defer s.jobs.Done()
+			run()
+		}()
+	}
+}
+
+// withStatusLocked returns the event with its last execution, failure count and, if its job is running,
+// NextRun filled in. Caller must hold the lock.
+func (s *SupportSchedulerService) withStatusLocked(event ScheduleEvent) ScheduleEvent {
+	event.NextRun = 0
+	event.NextRunTime = ""
+	event.LastExecution = nil
+	event.ConsecutiveFailures = 0
+	event.SkippedRuns = 0
+	if status, ok := s.jobStatuses[event.Id]; ok {
+		if status.executed {
+			execution := status.lastExecution
+			event.LastExecution = &execution
+		}
+		event.ConsecutiveFailures = status.consecutiveFailures
+		event.SkippedRuns = status.skippedRuns
+	}
+	
+	job, running := s.runningJobs[event.Id]
+	if !running {
+		return event
+	}
 	
-	// In a real implementation, this would make HTTP requests to the addressable endpoint
-	// For now, we'll just log the execution
-	s.logger.Infof("Job %s executed successfully at %v", event.Name, time.Now())
+	if next := job.schedule.Next(s.clock.Now()); !next.IsZero() {
+		event.NextRun = next.UnixNano() / int64(time.Millisecond)
+		if location, err := eventLocation(event); err == nil {
+			event.NextRunTime = next.In(location).Format(time.RFC3339)
+		}
+	}
+	return event
 }
 
 // stopScheduledJob stops a running scheduled job
 func (s *SupportSchedulerService) stopScheduledJob(eventId string) {
 	s.mutex.Lock()
-	if ticker, exists := s.runningJobs[eventId]; exists {
-		ticker.Stop()
-		delete(s.runningJobs, eventId)
+	defer s.mutex.Unlock()
+	s.stopScheduledJobLocked(eventId)
+}
+
+// stopScheduledJobLocked removes a job from the scheduler and aborts any execution in flight. Caller must hold the lock.
+func (s *SupportSchedulerService) stopScheduledJobLocked(eventId string) {
+	job, exists := s.runningJobs[eventId]
+	if !exists {
+		return
 	}
-	s.mutex.Unlock()
+	job.cancel()
+	delete(s.runningJobs, eventId)
+}
+
+// scheduleEventByNameLocked finds an event by name. Caller must hold the lock.
+func (s *SupportSchedulerService) scheduleEventByNameLocked(name string) (ScheduleEvent, bool) {
+	event, found := s.scheduleEvents[s.eventIdsByName[name]]
+	return event, found
+}
+
+// putScheduleEventLocked saves an event to the store, if any, then keeps it and the name index in step
+// with it. Nothing changes if the save fails. Caller must hold the lock.
+func (s *SupportSchedulerService) putScheduleEventLocked(event ScheduleEvent) error {
+	if s.store != nil {
+		if err := s.store.SaveEvent(event); err != nil {
+			return fmt.Errorf("failed to save schedule event %s: %w", event.Name, err)
+		}
+	}
+	if existing, exists := s.scheduleEvents[event.Id]; exists && existing.Name != event.Name {
+		delete(s.eventIdsByName, existing.Name)
+	}
+	s.scheduleEvents[event.Id] = event
+	s.eventIdsByName[event.Name] = event.Id
+	return nil
+}
+
+// removeScheduleEventLocked deletes an event from the store, if any, then from memory with its name index
+// entry. Nothing changes if the delete fails. Caller must hold the lock.
+func (s *SupportSchedulerService) removeScheduleEventLocked(id string) error {
+	if s.store != nil {
+		if err := s.store.DeleteEvent(id); err != nil {
+			return fmt.Errorf("failed to delete schedule event %s: %w", s.scheduleEvents[id].Name, err)
+		}
+	}
+	delete(s.eventIdsByName, s.scheduleEvents[id].Name)
+	delete(s.scheduleEvents, id)
+	return nil
+}
+
+// putScheduleActionLocked saves an action to the store, if any, then keeps it and the name index in step
+// with it. Nothing changes if the save fails. Caller must hold the lock.
+func (s *SupportSchedulerService) putScheduleActionLocked(action ScheduleAction) error {
+	if s.store != nil {
+		if err := s.store.SaveAction(action); err != nil {
+			return fmt.Errorf("failed to save schedule action %s: %w", action.Name, err)
+		}
+	}
+	if existing, exists := s.scheduleActions[action.Id]; exists && existing.Name != action.Name {
+		delete(s.actionIdsByName, existing.Name)
+	}
+	s.scheduleActions[action.Id] = action
+	s.actionIdsByName[action.Name] = action.Id
+	return nil
+}
+
+// removeScheduleActionLocked deletes an action and its name index entry. Caller must hold the lock.
+func (s *SupportSchedulerService) removeScheduleActionLocked(id string) error {
+	if s.store != nil {
+		if err := s.store.DeleteAction(id); err != nil {
+			return fmt.Errorf("failed to delete schedule action %s: %w", s.scheduleActions[id].Name, err)
+		}
+	}
+	delete(s.actionIdsByName, s.scheduleActions[id].Name)
+	delete(s.scheduleActions, id)
+	return nil
+}
+
+// nameTakenLocked reports whether name belongs to an item other than id in the given index. Caller must hold the lock.
+func (s *SupportSchedulerService) nameTakenLocked(idsByName map[string]string, name, id string) bool {
+	owner, taken := idsByName[name]
+	return taken && owner != id
+}
+
+// validateIntervalLocked checks that the interval an action references exists. Caller must hold the lock.
+func (s *SupportSchedulerService) validateIntervalLocked(intervalName string) error {
+	if intervalName == "" {
+		return nil
+	}
+	if _, found := s.scheduleEventByNameLocked(intervalName); !found {
+		return fmt.Errorf("interval %q not found", intervalName)
+	}
+	return nil
+}
+
+// actionsByIntervalLocked returns the actions attached to an interval, sorted by name. Caller must hold the lock.
+func (s *SupportSchedulerService) actionsByIntervalLocked(intervalName string) []ScheduleAction {
+	actions := make([]ScheduleAction, 0)
+	for _, action := range s.scheduleActions {
+		if action.IntervalName == intervalName {
+			actions = append(actions, action)
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	return actions
+}
+
+// scheduleActionByNameLocked finds an action by name. Caller must hold the lock.
+func (s *SupportSchedulerService) scheduleActionByNameLocked(name string) (ScheduleAction, bool) {
+	action, found := s.scheduleActions[s.actionIdsByName[name]]
+	return action, found
 }
 
 // Schedule Action handlers
@@ -247,15 +632,14 @@ func (s *SupportSchedulerService) addScheduleAction(w http.ResponseWriter, r *ht
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
 	var action ScheduleAction
-	if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+	if err := common.DecodeJSON(w, r, &action); err != nil {
 		s.logger.Errorf("Failed to decode schedule action: %v", err)
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 	
 	// Generate ID and timestamps
 	action.Id = models.GenerateUUID()
-	action.Created = time.Now().UnixNano() / int64(time.Millisecond)
+	action.Created = s.clock.Now().UnixNano() / int64(time.Millisecond)
 	action.Modified = action.Created
 	
 	// Set defaults
@@ -269,10 +653,38 @@ func (s *SupportSchedulerService) addScheduleAction(w http.ResponseWriter, r *ht
 		action.Protocol = "HTTP"
 	}
 	
+	if action.Name == "" {
+		common.WriteError(w, http.StatusBadRequest, "Schedule action name is required")
+		return
+	}
+	if _, err := retryBackoff(action); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid retry policy: %v", err))
+		return
+	}
+	
 	s.mutex.Lock()
-	s.scheduleActions[action.Id] = action
+	_, duplicate := s.actionIdsByName[action.Name]
+	err := s.validateIntervalLocked(action.IntervalName)
+	var saveErr error
+	if !duplicate && err == nil {
+		saveErr = s.putScheduleActionLocked(action)
+	}
 	s.mutex.Unlock()
 	
+	if duplicate {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule action %s already exists", action.Name))
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if saveErr != nil {
+		s.logger.Errorf("Failed to create schedule action %s: %v", action.Name, saveErr)
+		common.WriteError(w, http.StatusInternalServerError, saveErr.Error())
+		return
+	}
+	
 	s.logger.Infof("Schedule action created: %s", action.Name)
 	
 	response := map[string]interface{}{
@@ -289,18 +701,26 @@ func (s *SupportSchedulerService) addScheduleAction(w http.ResponseWriter, r *ht
 func (s *SupportSchedulerService) getAllScheduleActions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
 	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
 	s.mutex.RLock()
 	actions := make([]ScheduleAction, 0, len(s.scheduleActions))
 	for _, action := range s.scheduleActions {
 		actions = append(actions, action)
 	}
 	s.mutex.RUnlock()
+	sortScheduleActionsByCreated(actions)
+	page, totalCount := common.Paginate(actions, offset, limit)
 	
 	response := map[string]interface{}{
 		"apiVersion":      common.ServiceVersion,
 		"statusCode":      http.StatusOK,
-		"totalCount":      len(actions),
-		"scheduleActions": actions,
+		"totalCount":      totalCount,
+		"scheduleActions": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -314,35 +734,75 @@ func (s *SupportSchedulerService) updateScheduleEvent(w http.ResponseWriter, r *
 	id := vars["id"]
 	
 	var updatedEvent ScheduleEvent
-	if err := json.NewDecoder(r.Body).Decode(&updatedEvent); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &updatedEvent); err != nil {
+		return
+	}
+	
+	if err := validateScheduleEvent(updatedEvent); err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	if updatedEvent.AdminState != "" && !validAdminState(updatedEvent.AdminState) {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid admin state %q", updatedEvent.AdminState))
+		return
+	}
+	if updatedEvent.Name == "" {
+		common.WriteError(w, http.StatusBadRequest, "Schedule event name is required")
 		return
 	}
 	
 	s.mutex.Lock()
 	existingEvent, exists := s.scheduleEvents[id]
-	if exists {
-		// Stop existing job
-		if ticker, running := s.runningJobs[id]; running {
-			ticker.Stop()
-			delete(s.runningJobs, id)
-		}
-		
+	duplicate := s.nameTakenLocked(s.eventIdsByName, updatedEvent.Name, id)
+	var saveErr error
+	if exists && !duplicate {
 		updatedEvent.Id = id
 		updatedEvent.Created = existingEvent.Created
-		updatedEvent.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.scheduleEvents[id] = updatedEvent
+		updatedEvent.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+		// An update that doesn't mention the admin state leaves a paused event paused
+		if updatedEvent.AdminState == "" {
+			updatedEvent.AdminState = existingEvent.AdminState
+		}
+		// An updated event gets a fresh window, so it is no longer completed
+		updatedEvent.Status = ""
+		saveErr = s.putScheduleEventLocked(updatedEvent)
+	}
+	if exists && !duplicate && saveErr == nil {
+		// Keep attached actions attached when the interval is renamed
+		if updatedEvent.Name != existingEvent.Name {
+			for _, action := range s.scheduleActions {
+				if action.IntervalName == existingEvent.Name {
+					action.IntervalName = updatedEvent.Name
+					if err := s.putScheduleActionLocked(action); err != nil {
+						s.logger.Errorf("Failed to move schedule action %s to %s: %v", action.Name, updatedEvent.Name, err)
+					}
+				}
+			}
+		}
+		
+		// Restart the job on its new schedule only if it is enabled
+		s.stopScheduledJobLocked(id)
+		if updatedEvent.AdminState == common.Unlocked {
+			if err := s.startScheduledJobLocked(updatedEvent); err != nil {
+				s.logger.Errorf("Failed to restart schedule event %s: %v", updatedEvent.Name, err)
+			}
+		}
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Schedule event not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
 		return
 	}
-	
-	// Start new job if enabled
-	if updatedEvent.AdminState == common.Unlocked {
-		s.startScheduledJob(updatedEvent)
+	if duplicate {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule event %s already exists", updatedEvent.Name))
+		return
+	}
+	if saveErr != nil {
+		s.logger.Errorf("Failed to update schedule event %s: %v", updatedEvent.Name, saveErr)
+		common.WriteError(w, http.StatusInternalServerError, saveErr.Error())
+		return
 	}
 	
 	response := map[string]interface{}{
@@ -354,6 +814,104 @@ func (s *SupportSchedulerService) updateScheduleEvent(w http.ResponseWriter, r *
 	json.NewEncoder(w).Encode(response)
 }
 
+// pauseScheduleEvent handles POST /api/v3/scheduleevent/id/{id}/pause
+func (s *SupportSchedulerService) pauseScheduleEvent(w http.ResponseWriter, r *http.Request) {
+	s.setScheduleEventAdminState(w, r, common.Locked)
+}
+
+// resumeScheduleEvent handles POST /api/v3/scheduleevent/id/{id}/resume
+func (s *SupportSchedulerService) resumeScheduleEvent(w http.ResponseWriter, r *http.Request) {
+	s.setScheduleEventAdminState(w, r, common.Unlocked)
+}
+
+// triggerScheduleEvent handles POST /api/v3/scheduleevent/id/{id}/trigger. It runs the event's job once,
+// now, whatever its schedule or admin state, and responds with the execution when it has finished. The
+// execution counts as the job's latest, but the recurring schedule carries on as before.
+func (s *SupportSchedulerService) triggerScheduleEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	s.mutex.RLock()
+	event, exists := s.scheduleEvents[id]
+	s.mutex.RUnlock()
+	
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
+		return
+	}
+	
+	s.logger.Infof("Triggering schedule event %s", event.Name)
+	execution, executed := s.executeScheduledJob(r.Context(), event)
+	if !executed {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule event %s is already running", event.Name))
+		return
+	}
+	
+	response := map[string]interface{}{
+		"apiVersion": common.ServiceVersion,
+		"statusCode": http.StatusOK,
+		"execution":  execution,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+// setScheduleEventAdminState locks or unlocks an event, stopping or starting its job to match
+func (s *SupportSchedulerService) setScheduleEventAdminState(w http.ResponseWriter, r *http.Request, state string) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	s.mutex.Lock()
+	event, exists := s.scheduleEvents[id]
+	completed := exists && state == common.Unlocked && event.Status == StatusCompleted
+	var err error
+	if exists && !completed {
+		if event.AdminState != state {
+			event.AdminState = state
+			event.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+			err = s.putScheduleEventLocked(event)
+		}
+		
+		if err != nil {
+			event = s.scheduleEvents[id]
+		} else if state == common.Unlocked {
+			err = s.startScheduledJobLocked(event)
+		} else {
+			s.stopScheduledJobLocked(id)
+		}
+		event = s.withStatusLocked(event)
+	}
+	s.mutex.Unlock()
+	
+	if !exists {
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
+		return
+	}
+	if completed {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule event %s has completed; update it to schedule it again", event.Name))
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to set schedule event %s to %s: %v", event.Name, state, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	s.logger.Infof("Schedule event %s is now %s", event.Name, state)
+	
+	response := map[string]interface{}{
+		"apiVersion":    common.ServiceVersion,
+		"statusCode":    http.StatusOK,
+		"scheduleEvent": event,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
 // deleteScheduleEvent handles DELETE /api/v3/scheduleevent/id/{id}
 func (s *SupportSchedulerService) deleteScheduleEvent(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(common.ContentType, common.ContentTypeJSON)
@@ -362,18 +920,35 @@ func (s *SupportSchedulerService) deleteScheduleEvent(w http.ResponseWriter, r *
 	id := vars["id"]
 	
 	s.mutex.Lock()
-	_, exists := s.scheduleEvents[id]
+	event, exists := s.scheduleEvents[id]
+	attached := 0
+	var err error
 	if exists {
-		// Stop the job
-		s.stopScheduledJob(id)
-		delete(s.scheduleEvents, id)
+		attached = len(s.actionsByIntervalLocked(event.Name))
+		if attached == 0 {
+			if err = s.removeScheduleEventLocked(id); err == nil {
+				delete(s.jobStatuses, id)
+			}
+		}
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Schedule event not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
 		return
 	}
+	if attached > 0 {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule event %s still has %d schedule action(s) attached", event.Name, attached))
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to delete schedule event %s: %v", event.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	
+	// Stop the job
+	s.stopScheduledJob(id)
 	
 	response := map[string]interface{}{
 		"apiVersion": common.ServiceVersion,
@@ -394,24 +969,19 @@ func (s *SupportSchedulerService) getScheduleEventByName(w http.ResponseWriter,
 	name := vars["name"]
 	
 	s.mutex.RLock()
-	var foundEvent *ScheduleEvent
-	for _, event := range s.scheduleEvents {
-		if event.Name == name {
-			foundEvent = &event
-			break
-		}
-	}
+	event, found := s.scheduleEventByNameLocked(name)
+	event = s.withStatusLocked(event)
 	s.mutex.RUnlock()
 	
-	if foundEvent == nil {
-		http.Error(w, "Schedule event not found", http.StatusNotFound)
+	if !found {
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
 		return
 	}
 	
 	response := map[string]interface{}{
 		"apiVersion":    common.ServiceVersion,
 		"statusCode":    http.StatusOK,
-		"scheduleEvent": *foundEvent,
+		"scheduleEvent": event,
 	}
 	
 	json.NewEncoder(w).Encode(response)
@@ -429,7 +999,7 @@ func (s *SupportSchedulerService) getScheduleActionById(w http.ResponseWriter, r
 	s.mutex.RUnlock()
 	
 	if !exists {
-		http.Error(w, "Schedule action not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule action not found")
 		return
 	}
 	
@@ -450,23 +1020,47 @@ func (s *SupportSchedulerService) updateScheduleAction(w http.ResponseWriter, r
 	id := vars["id"]
 	
 	var updatedAction ScheduleAction
-	if err := json.NewDecoder(r.Body).Decode(&updatedAction); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if err := common.DecodeJSON(w, r, &updatedAction); err != nil {
+		return
+	}
+	
+	if updatedAction.Name == "" {
+		common.WriteError(w, http.StatusBadRequest, "Schedule action name is required")
+		return
+	}
+	if _, err := retryBackoff(updatedAction); err != nil {
+		common.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid retry policy: %v", err))
 		return
 	}
 	
 	s.mutex.Lock()
 	existingAction, exists := s.scheduleActions[id]
-	if exists {
+	duplicate := s.nameTakenLocked(s.actionIdsByName, updatedAction.Name, id)
+	err := s.validateIntervalLocked(updatedAction.IntervalName)
+	var saveErr error
+	if exists && !duplicate && err == nil {
 		updatedAction.Id = id
 		updatedAction.Created = existingAction.Created
-		updatedAction.Modified = time.Now().UnixNano() / int64(time.Millisecond)
-		s.scheduleActions[id] = updatedAction
+		updatedAction.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+		saveErr = s.putScheduleActionLocked(updatedAction)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Schedule action not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule action not found")
+		return
+	}
+	if duplicate {
+		common.WriteError(w, http.StatusConflict, fmt.Sprintf("Schedule action %s already exists", updatedAction.Name))
+		return
+	}
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if saveErr != nil {
+		s.logger.Errorf("Failed to update schedule action %s: %v", updatedAction.Name, saveErr)
+		common.WriteError(w, http.StatusInternalServerError, saveErr.Error())
 		return
 	}
 	
@@ -487,14 +1081,20 @@ func (s *SupportSchedulerService) deleteScheduleAction(w http.ResponseWriter, r
 	id := vars["id"]
 	
 	s.mutex.Lock()
-	_, exists := s.scheduleActions[id]
+	action, exists := s.scheduleActions[id]
+	var err error
 	if exists {
-		delete(s.scheduleActions, id)
+		err = s.removeScheduleActionLocked(id)
 	}
 	s.mutex.Unlock()
 	
 	if !exists {
-		http.Error(w, "Schedule action not found", http.StatusNotFound)
+		common.WriteError(w, http.StatusNotFound, "Schedule action not found")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to delete schedule action %s: %v", action.Name, err)
+		common.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	
@@ -515,25 +1115,53 @@ func (s *SupportSchedulerService) getScheduleActionByName(w http.ResponseWriter,
 	name := vars["name"]
 	
 	s.mutex.RLock()
-	var foundAction *ScheduleAction
-	for _, action := range s.scheduleActions {
-		if action.Name == name {
-			foundAction = &action
-			break
-		}
-	}
+	action, found := s.scheduleActionByNameLocked(name)
 	s.mutex.RUnlock()
 	
-	if foundAction == nil {
-		http.Error(w, "Schedule action not found", http.StatusNotFound)
+	if !found {
+		common.WriteError(w, http.StatusNotFound, "Schedule action not found")
 		return
 	}
 	
 	response := map[string]interface{}{
 		"apiVersion":     common.ServiceVersion,
 		"statusCode":     http.StatusOK,
-		"scheduleAction": *foundAction,
+		"scheduleAction": action,
+	}
+	
+	json.NewEncoder(w).Encode(response)
+}
+
+// getScheduleActionsByInterval handles GET /api/v3/scheduleaction/interval/{name}
+func (s *SupportSchedulerService) getScheduleActionsByInterval(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	
+	vars := mux.Vars(r)
+	name := vars["name"]
+	
+	offset, limit, err := common.ParsePagination(r)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	s.mutex.RLock()
+	_, found := s.scheduleEventByNameLocked(name)
+	actions := s.actionsByIntervalLocked(name)
+	s.mutex.RUnlock()
+	
+	if !found {
+		common.WriteError(w, http.StatusNotFound, "Schedule event not found")
+		return
+	}
+	page, totalCount := common.Paginate(actions, offset, limit)
+	
+	response := map[string]interface{}{
+		"apiVersion":      common.ServiceVersion,
+		"statusCode":      http.StatusOK,
+		"totalCount":      totalCount,
+		"scheduleActions": page,
 	}
 	
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
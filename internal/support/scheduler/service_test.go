@@ -0,0 +1,523 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func newTestService() *SupportSchedulerService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	return NewSupportSchedulerService(logger)
+}
+
+func newTestRouter(service *SupportSchedulerService) *mux.Router {
+	router := mux.NewRouter()
+	service.AddRoutes(router)
+	return router
+}
+
+func sendJSON(t *testing.T, router *mux.Router, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+// createEvent posts a schedule event and returns its id
+func createEvent(t *testing.T, router *mux.Router, event ScheduleEvent) string {
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", event)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response["id"].(string)
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		valid    bool
+	}{
+		{"Five fields", "*/5 * * * *", true},
+		{"Six fields with seconds", "30 */5 * * * *", true},
+		{"Every", "@every 90s", true},
+		{"Descriptor", "@hourly", true},
+		{"Empty", "", false},
+		{"Garbage", "every five minutes", false},
+		{"Out of range", "61 * * * *", false},
+		{"Bad duration", "@every soon", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSchedule(tt.schedule)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_NextRun(t *testing.T) {
+	schedule, err := parseSchedule("0 2 * * *")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC), schedule.Next(from))
+
+	schedule, err = parseSchedule("@every 90s")
+	require.NoError(t, err)
+	assert.Equal(t, from.Add(90*time.Second), schedule.Next(from))
+}
+
+func TestSupportSchedulerService_AddScheduleEventValidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		schedule     string
+		expectedCode int
+	}{
+		{"Valid cron", "*/5 * * * *", http.StatusCreated},
+		{"Valid every", "@every 1m", http.StatusCreated},
+		{"Missing", "", http.StatusBadRequest},
+		{"Unparseable", "every five minutes", http.StatusBadRequest},
+		{"Zero interval", "@every 0s", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			router := newTestRouter(service)
+
+			rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "job", Schedule: tt.schedule})
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode != http.StatusCreated {
+				assert.Empty(t, service.scheduleEvents)
+			}
+		})
+	}
+}
+
+func TestSupportSchedulerService_GetScheduleEventNextRun(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+
+	id := createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@every 1h"})
+	lockedId := createEvent(t, router, ScheduleEvent{Name: "locked", Schedule: "@every 1h", AdminState: common.Locked})
+
+	rr := sendJSON(t, router, "GET", "/api/v3/scheduleevent/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		ScheduleEvent ScheduleEvent `json:"scheduleEvent"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	next := time.Unix(0, response.ScheduleEvent.NextRun*int64(time.Millisecond))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), next, 5*time.Second)
+
+	// Locked events are not running, so they have no next run
+	rr = sendJSON(t, router, "GET", "/api/v3/scheduleevent/id/"+lockedId, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	response.ScheduleEvent = ScheduleEvent{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Zero(t, response.ScheduleEvent.NextRun)
+
+	rr = sendJSON(t, router, "GET", "/api/v3/scheduleevent/name/hourly", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.NotZero(t, response.ScheduleEvent.NextRun)
+}
+
+func TestSupportSchedulerService_UpdateScheduleEvent(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+
+	id := createEvent(t, router, ScheduleEvent{Name: "job", Schedule: "@every 1h"})
+
+	rr := sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "job", Schedule: "not a schedule", AdminState: common.Unlocked})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "@every 1h", service.scheduleEvents[id].Schedule)
+
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "job", Schedule: "@every -5m", AdminState: common.Unlocked})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "@every 1h", service.scheduleEvents[id].Schedule)
+
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "job", Schedule: "0 2 * * *", AdminState: common.Unlocked})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "0 2 * * *", service.scheduleEvents[id].Schedule)
+	assert.Len(t, service.runningJobs, 1)
+
+	// Locking stops the job
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "job", Schedule: "0 2 * * *", AdminState: common.Locked})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, service.runningJobs)
+}
+
+func TestSupportSchedulerService_DeleteScheduleEvent(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+
+	id := createEvent(t, router, ScheduleEvent{Name: "job", Schedule: "@every 1h"})
+	require.Len(t, service.runningJobs, 1)
+
+	rr := sendJSON(t, router, "DELETE", "/api/v3/scheduleevent/id/"+id, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, service.scheduleEvents)
+	assert.Empty(t, service.runningJobs)
+}
+
+func TestSupportSchedulerService_RunsJobsUntilShutdown(t *testing.T) {
+	service := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+
+	router := newTestRouter(service)
+	id := createEvent(t, router, ScheduleEvent{Name: "fast", Schedule: "@every 1s"})
+
+	service.mutex.RLock()
+	require.Len(t, service.runningJobs, 1)
+	service.mutex.RUnlock()
+	assert.Eventually(t, func() bool {
+		return getEvent(t, router, id).LastExecution != nil
+	}, 3*time.Second, 50*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
+
+// assertGoroutinesAtMost waits briefly for the goroutine count to drop to limit. It polls in the
+// test goroutine because assert.Eventually would itself add goroutines to the count.
+func assertGoroutinesAtMost(t *testing.T, limit int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > limit && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), limit)
+}
+
+func TestSupportSchedulerService_DeletedJobsDoNotLeakGoroutines(t *testing.T) {
+	service := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	before := runtime.NumGoroutine()
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	running := runtime.NumGoroutine()
+
+	router := newTestRouter(service)
+	for i := 0; i < 100; i++ {
+		id := createEvent(t, router, ScheduleEvent{Name: fmt.Sprintf("job-%d", i), Schedule: "@every 1h"})
+		rr := sendJSON(t, router, "DELETE", "/api/v3/scheduleevent/id/"+id, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	assert.Empty(t, service.runningJobs)
+	assertGoroutinesAtMost(t, running)
+
+	// Shutdown stops the scheduler itself
+	cancel()
+	wg.Wait()
+	assertGoroutinesAtMost(t, before)
+}
+
+func TestSupportSchedulerService_DeleteAbortsRunningExecution(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer target.Close()
+	defer close(release)
+
+	clock := common.NewFakeClock(time.Now())
+	service := newTestService()
+	service.SetClock(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Initialize(ctx, &sync.WaitGroup{}, bootstrap.NewDIContainer())
+	router := newTestRouter(service)
+	action := targetAction(t, target, "slow")
+	service.putScheduleActionLocked(action)
+	id := createEvent(t, router, ScheduleEvent{Name: "slow-job", Schedule: "@every 1h", Addressable: "slow"})
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	<-started
+
+	// The job's loop and its execution are all that is running
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.jobs.Wait()
+	}()
+
+	rr := sendJSON(t, router, "DELETE", "/api/v3/scheduleevent/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("execution was not aborted when its job was deleted")
+	}
+}
+
+func TestSupportSchedulerService_Shutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer target.Close()
+	defer close(release)
+
+	clock := common.NewFakeClock(time.Now())
+	service := newTestService()
+	service.SetClock(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+	router := newTestRouter(service)
+	service.putScheduleActionLocked(targetAction(t, target, "slow"))
+	createEvent(t, router, ScheduleEvent{Name: "slow-job", Schedule: "@every 1h", Addressable: "slow"})
+	createEvent(t, router, ScheduleEvent{Name: "idle-job", Schedule: "@every 24h"})
+
+	clock.BlockUntil(2)
+	clock.Advance(time.Hour)
+	<-started
+
+	// Shutdown aborts the execution in flight and stops every job, without waiting for ctx
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer shutdownCancel()
+	require.NoError(t, service.Shutdown(shutdownCtx))
+	assert.Zero(t, clock.Waiters())
+
+	// Jobs added afterwards are not started
+	createEvent(t, router, ScheduleEvent{Name: "late-job", Schedule: "@every 1h"})
+	assert.Zero(t, clock.Waiters())
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("scheduler did not stop after Shutdown")
+	}
+}
+
+func TestSupportSchedulerService_PauseAndResume(t *testing.T) {
+	var executions int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&executions, 1)
+	}))
+	defer target.Close()
+
+	service := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service.Initialize(ctx, &sync.WaitGroup{}, bootstrap.NewDIContainer())
+	router := newTestRouter(service)
+
+	action := targetAction(t, target, "ping")
+	service.putScheduleActionLocked(action)
+	id := createEvent(t, router, ScheduleEvent{Name: "fast", Schedule: "@every 1s", Addressable: "ping"})
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&executions) > 0
+	}, 3*time.Second, 20*time.Millisecond)
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/"+id+"/pause", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	paused := atomic.LoadInt32(&executions)
+
+	// A paused job reports its state and has no next run
+	event := getEvent(t, router, id)
+	assert.Equal(t, common.Locked, event.AdminState)
+	assert.Zero(t, event.NextRun)
+
+	// Updating a paused event must not restart it
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "fast", Schedule: "@every 1s", Addressable: "ping"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, common.Locked, getEvent(t, router, id).AdminState)
+
+	time.Sleep(2500 * time.Millisecond)
+	assert.Equal(t, paused, atomic.LoadInt32(&executions), "paused job fired")
+
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/"+id+"/resume", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	event = getEvent(t, router, id)
+	assert.Equal(t, common.Unlocked, event.AdminState)
+	assert.NotZero(t, event.NextRun)
+	assert.GreaterOrEqual(t, event.Modified, event.Created)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&executions) > paused
+	}, 3*time.Second, 20*time.Millisecond)
+}
+
+func TestSupportSchedulerService_PauseErrors(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/missing/pause", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/missing/resume", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "bad", Schedule: "@hourly", AdminState: "PAUSED"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// getEvent fetches a schedule event through the API
+func getEvent(t *testing.T, router *mux.Router, id string) ScheduleEvent {
+	rr := sendJSON(t, router, "GET", "/api/v3/scheduleevent/id/"+id, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var response struct {
+		ScheduleEvent ScheduleEvent `json:"scheduleEvent"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return response.ScheduleEvent
+}
+
+func TestSupportSchedulerService_IntervalActions(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+	eventId := createEvent(t, router, ScheduleEvent{Name: "midnight", Schedule: "@daily", AdminState: common.Locked})
+
+	// Actions must reference an existing interval
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: "orphan", IntervalName: "noon"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	for _, name := range []string{"purge", "backup"} {
+		rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: name, IntervalName: "midnight"})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: "standalone"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	listNames := func(interval string) []string {
+		rr := sendJSON(t, router, "GET", "/api/v3/scheduleaction/interval/"+interval, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response struct {
+			TotalCount      int              `json:"totalCount"`
+			ScheduleActions []ScheduleAction `json:"scheduleActions"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		names := make([]string, 0, len(response.ScheduleActions))
+		for _, action := range response.ScheduleActions {
+			names = append(names, action.Name)
+		}
+		assert.Equal(t, len(names), response.TotalCount)
+		return names
+	}
+	assert.Equal(t, []string{"backup", "purge"}, listNames("midnight"))
+
+	rr = sendJSON(t, router, "GET", "/api/v3/scheduleaction/interval/noon", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// An interval with attached actions can't be deleted
+	rr = sendJSON(t, router, "DELETE", "/api/v3/scheduleevent/id/"+eventId, nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// Renaming the interval keeps its actions attached
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+eventId, ScheduleEvent{Name: "nightly", Schedule: "@daily"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, []string{"backup", "purge"}, listNames("nightly"))
+}
+
+func TestSupportSchedulerService_UniqueNames(t *testing.T) {
+	service := newTestService()
+	router := newTestRouter(service)
+
+	firstId := createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@hourly", AdminState: common.Locked})
+	secondId := createEvent(t, router, ScheduleEvent{Name: "daily", Schedule: "@daily", AdminState: common.Locked})
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "hourly", Schedule: "@every 1m"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+secondId, ScheduleEvent{Name: "hourly", Schedule: "@daily"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "daily", getEvent(t, router, secondId).Name)
+
+	// A renamed event is found under its new name only, freeing the old one
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+firstId, ScheduleEvent{Name: "every-hour", Schedule: "@hourly", AdminState: common.Locked})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = sendJSON(t, router, "GET", "/api/v3/scheduleevent/name/every-hour", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = sendJSON(t, router, "GET", "/api/v3/scheduleevent/name/hourly", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@hourly", AdminState: common.Locked})
+
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: "purge"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: "purge"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSupportSchedulerService_ListOrder(t *testing.T) {
+	service := newTestService()
+	for id, created := range map[string]int64{"x-1": 1000, "x-2": 3000, "x-3": 2000, "x-0": 3000} {
+		service.scheduleEvents[id] = ScheduleEvent{Id: id, Name: id, Created: created}
+		service.scheduleActions[id] = ScheduleAction{Id: id, Name: id, Created: created}
+	}
+	router := newTestRouter(service)
+
+	// Newest first, ties broken by id, the same on every call
+	expected := []interface{}{"x-0", "x-2", "x-3", "x-1"}
+	lists := map[string]string{
+		"/api/v3/scheduleevent/all":  "scheduleEvents",
+		"/api/v3/scheduleaction/all": "scheduleActions",
+	}
+	for path, key := range lists {
+		for i := 0; i < 5; i++ {
+			rr := sendJSON(t, router, "GET", path, nil)
+			require.Equal(t, http.StatusOK, rr.Code)
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			var ids []interface{}
+			for _, item := range response[key].([]interface{}) {
+				ids = append(ids, item.(map[string]interface{})["id"])
+			}
+			assert.Equal(t, expected, ids, path)
+		}
+	}
+}
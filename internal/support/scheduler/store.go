@@ -0,0 +1,244 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// ScheduleStore persists schedule events and actions so they survive a restart. The service keeps
+// working copies in memory; the store only has to save changes and hand everything back at startup.
+type ScheduleStore interface {
+	// LoadEvents and LoadActions return everything saved, in no particular order
+	LoadEvents() ([]ScheduleEvent, error)
+	LoadActions() ([]ScheduleAction, error)
+	// SaveEvent and SaveAction store a new or updated item, replacing any with the same id
+	SaveEvent(event ScheduleEvent) error
+	SaveAction(action ScheduleAction) error
+	// DeleteEvent and DeleteAction remove an item; removing one that is not there is not an error
+	DeleteEvent(id string) error
+	DeleteAction(id string) error
+}
+
+// loadStore fills the service with the events and actions saved in its store, if it has one, and registers
+// the jobs of unlocked events to start with the service
+func (s *SupportSchedulerService) loadStore() error {
+	if s.store == nil {
+		return nil
+	}
+	events, err := s.store.LoadEvents()
+	if err != nil {
+		return err
+	}
+	actions, err := s.store.LoadActions()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Straight into memory, as they are already saved
+	for _, action := range actions {
+		s.scheduleActions[action.Id] = action
+		s.actionIdsByName[action.Name] = action.Id
+	}
+	for _, event := range events {
+		s.scheduleEvents[event.Id] = event
+		s.eventIdsByName[event.Name] = event.Id
+	}
+	for _, event := range events {
+		if event.AdminState != common.Unlocked {
+			continue
+		}
+		if err := s.startScheduledJobLocked(event); err != nil {
+			s.logger.Errorf("Failed to schedule job %s: %v", event.Name, err)
+		}
+	}
+
+	s.logger.Infof("Loaded %d schedule event(s) and %d schedule action(s)", len(events), len(actions))
+	return nil
+}
+
+// fileScheduleDocument is the layout of a FileScheduleStore's file
+type fileScheduleDocument struct {
+	Events  []ScheduleEvent  `json:"events"`
+	Actions []ScheduleAction `json:"actions"`
+}
+
+// FileScheduleStore implements ScheduleStore with a JSON file, rewritten in full on each change. The new
+// contents are written beside the file and renamed over it, so a crash never leaves half a file behind.
+type FileScheduleStore struct {
+	path    string
+	events  map[string]ScheduleEvent
+	actions map[string]ScheduleAction
+	mutex   sync.Mutex
+}
+
+// NewFileScheduleStore creates a store kept in the file at path, reading what it already holds. A missing
+// file is an empty store; its directory must exist.
+func NewFileScheduleStore(path string) (*FileScheduleStore, error) {
+	store := &FileScheduleStore{
+		path:    path,
+		events:  make(map[string]ScheduleEvent),
+		actions: make(map[string]ScheduleAction),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule store %s: %w", path, err)
+	}
+	var document fileScheduleDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule store %s: %w", path, err)
+	}
+	for _, event := range document.Events {
+		store.events[event.Id] = event
+	}
+	for _, action := range document.Actions {
+		store.actions[action.Id] = action
+	}
+	return store, nil
+}
+
+// LoadEvents returns every saved event
+func (f *FileScheduleStore) LoadEvents() ([]ScheduleEvent, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	events := make([]ScheduleEvent, 0, len(f.events))
+	for _, event := range f.events {
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// LoadActions returns every saved action
+func (f *FileScheduleStore) LoadActions() ([]ScheduleAction, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	actions := make([]ScheduleAction, 0, len(f.actions))
+	for _, action := range f.actions {
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// SaveEvent stores an event and rewrites the file
+func (f *FileScheduleStore) SaveEvent(event ScheduleEvent) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.events[event.Id]
+	f.events[event.Id] = event
+	if err := f.writeLocked(); err != nil {
+		if existed {
+			f.events[event.Id] = previous
+		} else {
+			delete(f.events, event.Id)
+		}
+		return err
+	}
+	return nil
+}
+
+// SaveAction stores an action and rewrites the file
+func (f *FileScheduleStore) SaveAction(action ScheduleAction) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.actions[action.Id]
+	f.actions[action.Id] = action
+	if err := f.writeLocked(); err != nil {
+		if existed {
+			f.actions[action.Id] = previous
+		} else {
+			delete(f.actions, action.Id)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteEvent removes an event and rewrites the file
+func (f *FileScheduleStore) DeleteEvent(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.events[id]
+	if !existed {
+		return nil
+	}
+	delete(f.events, id)
+	if err := f.writeLocked(); err != nil {
+		f.events[id] = previous
+		return err
+	}
+	return nil
+}
+
+// DeleteAction removes an action and rewrites the file
+func (f *FileScheduleStore) DeleteAction(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	previous, existed := f.actions[id]
+	if !existed {
+		return nil
+	}
+	delete(f.actions, id)
+	if err := f.writeLocked(); err != nil {
+		f.actions[id] = previous
+		return err
+	}
+	return nil
+}
+
+// writeLocked replaces the file with the store's contents, in id order so the file diffs cleanly.
+// Caller must hold the lock.
+func (f *FileScheduleStore) writeLocked() error {
+	document := fileScheduleDocument{
+		Events:  make([]ScheduleEvent, 0, len(f.events)),
+		Actions: make([]ScheduleAction, 0, len(f.actions)),
+	}
+	for _, event := range f.events {
+		document.Events = append(document.Events, event)
+	}
+	for _, action := range f.actions {
+		document.Actions = append(document.Actions, action)
+	}
+	sort.Slice(document.Events, func(i, j int) bool { return document.Events[i].Id < document.Events[j].Id })
+	sort.Slice(document.Actions, func(i, j int) bool { return document.Actions[i].Id < document.Actions[j].Id })
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule store: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write schedule store %s: %w", f.path, err)
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write schedule store %s: %w", f.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// runScheduleStoreConformance exercises the ScheduleStore contract against any implementation.
+// newStore must return an empty store for every call.
+func runScheduleStoreConformance(t *testing.T, newStore func(t *testing.T) ScheduleStore) {
+	eventNames := func(t *testing.T, store ScheduleStore) []string {
+		events, err := store.LoadEvents()
+		require.NoError(t, err)
+		var names []string
+		for _, event := range events {
+			names = append(names, event.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		store := newStore(t)
+		events, err := store.LoadEvents()
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		actions, err := store.LoadActions()
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+
+	t.Run("Save and load", func(t *testing.T) {
+		store := newStore(t)
+		event := ScheduleEvent{Id: "e-1", Name: "hourly", Schedule: "@every 1h", AdminState: common.Unlocked, Created: 1000}
+		require.NoError(t, store.SaveEvent(event))
+		action := ScheduleAction{Id: "a-1", Name: "purge", IntervalName: "hourly", Retry: &RetryPolicy{MaxRetries: 2}}
+		require.NoError(t, store.SaveAction(action))
+
+		events, err := store.LoadEvents()
+		require.NoError(t, err)
+		assert.Equal(t, []ScheduleEvent{event}, events)
+		actions, err := store.LoadActions()
+		require.NoError(t, err)
+		assert.Equal(t, []ScheduleAction{action}, actions)
+	})
+
+	t.Run("Save replaces", func(t *testing.T) {
+		store := newStore(t)
+		require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-1", Name: "hourly"}))
+		require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-2", Name: "daily"}))
+		require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-1", Name: "renamed"}))
+		assert.Equal(t, []string{"daily", "renamed"}, eventNames(t, store))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := newStore(t)
+		require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-1", Name: "hourly"}))
+		require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-2", Name: "daily"}))
+		require.NoError(t, store.SaveAction(ScheduleAction{Id: "a-1", Name: "purge"}))
+
+		require.NoError(t, store.DeleteEvent("e-1"))
+		require.NoError(t, store.DeleteEvent("missing"))
+		assert.Equal(t, []string{"daily"}, eventNames(t, store))
+
+		require.NoError(t, store.DeleteAction("a-1"))
+		require.NoError(t, store.DeleteAction("missing"))
+		actions, err := store.LoadActions()
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+}
+
+func TestFileScheduleStore_Conformance(t *testing.T) {
+	runScheduleStoreConformance(t, func(t *testing.T) ScheduleStore {
+		store, err := NewFileScheduleStore(filepath.Join(t.TempDir(), "schedules.json"))
+		require.NoError(t, err)
+		return store
+	})
+}
+
+func TestFileScheduleStore_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	store, err := NewFileScheduleStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.SaveEvent(ScheduleEvent{Id: "e-1", Name: "hourly"}))
+	require.NoError(t, store.SaveAction(ScheduleAction{Id: "a-1", Name: "purge"}))
+
+	reopened, err := NewFileScheduleStore(path)
+	require.NoError(t, err)
+	events, err := reopened.LoadEvents()
+	require.NoError(t, err)
+	assert.Equal(t, []ScheduleEvent{{Id: "e-1", Name: "hourly"}}, events)
+	actions, err := reopened.LoadActions()
+	require.NoError(t, err)
+	assert.Equal(t, []ScheduleAction{{Id: "a-1", Name: "purge"}}, actions)
+
+	// Only the file itself is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+	_, err = NewFileScheduleStore(path)
+	assert.Error(t, err)
+}
+
+func TestFileScheduleStore_WriteFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gone")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	store, err := NewFileScheduleStore(filepath.Join(dir, "schedules.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(dir))
+
+	assert.Error(t, store.SaveEvent(ScheduleEvent{Id: "e-1", Name: "hourly"}))
+	events, err := store.LoadEvents()
+	require.NoError(t, err)
+	assert.Empty(t, events, "a failed save leaves the store as it was")
+}
+
+// failingScheduleStore is a ScheduleStore whose writes all fail
+type failingScheduleStore struct{}
+
+var errStoreDown = errors.New("store down")
+
+func (failingScheduleStore) LoadEvents() ([]ScheduleEvent, error)   { return nil, nil }
+func (failingScheduleStore) LoadActions() ([]ScheduleAction, error) { return nil, nil }
+func (failingScheduleStore) SaveEvent(ScheduleEvent) error          { return errStoreDown }
+func (failingScheduleStore) SaveAction(ScheduleAction) error        { return errStoreDown }
+func (failingScheduleStore) DeleteEvent(string) error               { return errStoreDown }
+func (failingScheduleStore) DeleteAction(string) error              { return errStoreDown }
+
+func TestSupportSchedulerService_StoreFailure(t *testing.T) {
+	service := newTestService()
+	service.SetStore(failingScheduleStore{})
+	router := newTestRouter(service)
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "hourly", Schedule: "@every 1h"})
+	assert.Equal(t, http.StatusInternalServerError, rr.Code, rr.Body.String())
+	rr = sendJSON(t, router, "POST", "/api/v3/scheduleaction", ScheduleAction{Name: "purge"})
+	assert.Equal(t, http.StatusInternalServerError, rr.Code, rr.Body.String())
+
+	assert.Empty(t, service.scheduleEvents)
+	assert.Empty(t, service.eventIdsByName)
+	assert.Empty(t, service.scheduleActions)
+	assert.Empty(t, service.runningJobs)
+}
+
+func TestSupportSchedulerService_Restart(t *testing.T) {
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer target.Close()
+
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// start runs a service on a fresh store over the file, as a restart would
+	start := func(t *testing.T) (*SupportSchedulerService, func()) {
+		store, err := NewFileScheduleStore(path)
+		require.NoError(t, err)
+		service := newTestService()
+		service.SetClock(clock)
+		service.SetStore(store)
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		require.True(t, service.Initialize(ctx, &wg, bootstrap.NewDIContainer()))
+		return service, func() {
+			cancel()
+			wg.Wait()
+		}
+	}
+
+	service, stop := start(t)
+	router := newTestRouter(service)
+	hourly := createEvent(t, router, ScheduleEvent{Name: "hourly", Schedule: "@every 1h"})
+	createEvent(t, router, ScheduleEvent{Name: "paused", Schedule: "@every 1m", AdminState: common.Locked})
+	action := targetAction(t, target, "ping")
+	action.Id = ""
+	action.IntervalName = "hourly"
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleaction", action)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	stop()
+
+	// Only the unlocked event's job resumes, and it still runs its action
+	service, stop = start(t)
+	defer func() { stop() }()
+	router = newTestRouter(service)
+	assert.Equal(t, "@every 1h", getEvent(t, router, hourly).Schedule)
+	assert.NotZero(t, getEvent(t, router, hourly).NextRun)
+	assert.Len(t, service.scheduleEvents, 2)
+	assert.Len(t, service.runningJobs, 1)
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	assert.Eventually(t, func() bool { return hits.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Deletes are saved too
+	rr = sendJSON(t, router, "DELETE", "/api/v3/scheduleaction/id/"+service.actionIdsByName["ping"], nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = sendJSON(t, router, "DELETE", "/api/v3/scheduleevent/id/"+hourly, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	stop()
+
+	service, stop = start(t)
+	assert.Len(t, service.scheduleEvents, 1)
+	assert.Empty(t, service.scheduleActions)
+	assert.Empty(t, service.runningJobs)
+}
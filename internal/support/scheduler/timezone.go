@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+	// Embed the zone database so time zones resolve in minimal containers without tzdata
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+)
+
+// eventLocation returns the time zone the event's cron expression is evaluated in, UTC unless it names one
+func eventLocation(event ScheduleEvent) (*time.Location, error) {
+	if event.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", event.Timezone, err)
+	}
+	return location, nil
+}
+
+// inLocation makes a cron expression fire at wall-clock times in location. Interval schedules such as
+// @every are unaffected, and an expression with its own CRON_TZ prefix keeps it unless the event
+// names a time zone.
+func inLocation(schedule cron.Schedule, location *time.Location, explicit bool) cron.Schedule {
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if ok && (explicit || spec.Location == time.Local) {
+		spec.Location = location
+	}
+	return schedule
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSchedule_Timezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	schedule, err := eventSchedule(ScheduleEvent{Schedule: "0 6 * * *", Timezone: "America/New_York"})
+	require.NoError(t, err)
+
+	// 06:00 local is 11:00 UTC before the spring DST change and 10:00 UTC after it
+	first := schedule.Next(time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 3, 9, 11, 0, 0, 0, time.UTC), first.UTC())
+	second := schedule.Next(first)
+	assert.Equal(t, time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC), second.UTC())
+	assert.Equal(t, "2024-03-10T06:00:00-04:00", second.In(newYork).Format(time.RFC3339))
+	assert.Equal(t, 23*time.Hour, second.Sub(first))
+
+	// And back to 11:00 UTC when DST ends
+	before := schedule.Next(time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 11, 2, 10, 0, 0, 0, time.UTC), before.UTC())
+	after := schedule.Next(before)
+	assert.Equal(t, time.Date(2024, 11, 3, 11, 0, 0, 0, time.UTC), after.UTC())
+	assert.Equal(t, 25*time.Hour, after.Sub(before))
+}
+
+func TestEventSchedule_DefaultsToUTC(t *testing.T) {
+	schedule, err := eventSchedule(ScheduleEvent{Schedule: "0 6 * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 6, 0, 0, 0, time.UTC), schedule.Next(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)).UTC())
+
+	// A CRON_TZ prefix still applies when the event names no zone, and the event's zone wins when it does
+	schedule, err = eventSchedule(ScheduleEvent{Schedule: "CRON_TZ=Asia/Tokyo 0 6 * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 21, 0, 0, 0, time.UTC), schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)).UTC())
+	schedule, err = eventSchedule(ScheduleEvent{Schedule: "CRON_TZ=Asia/Tokyo 0 6 * * *", Timezone: "UTC"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 6, 0, 0, 0, time.UTC), schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)).UTC())
+}
+
+func TestSupportSchedulerService_Timezone(t *testing.T) {
+	service, _ := newRunningService(t)
+	router := newTestRouter(service)
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "bad-zone", Schedule: "0 6 * * *", Timezone: "Mars/Olympus_Mons"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	id := createEvent(t, router, ScheduleEvent{Name: "plant-morning", Schedule: "0 6 * * *", Timezone: "Asia/Kolkata"})
+	event := getEvent(t, router, id)
+	assert.Equal(t, "Asia/Kolkata", event.Timezone)
+	require.NotEmpty(t, event.NextRunTime)
+
+	next, err := time.Parse(time.RFC3339, event.NextRunTime)
+	require.NoError(t, err)
+	assert.Equal(t, event.NextRun, next.UnixMilli())
+	assert.Contains(t, event.NextRunTime, "T06:00:00+05:30")
+
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+id, ScheduleEvent{Name: "plant-morning", Schedule: "0 6 * * *", Timezone: "Nowhere"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
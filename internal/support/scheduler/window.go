@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// StatusCompleted marks an event whose window has ended or whose single run has happened.
+// Completed events stay queryable but never fire again unless they are updated.
+const StatusCompleted = "COMPLETED"
+
+// boundedSchedule restricts a schedule to the window between start and end; zero bounds are open.
+// A zero time from Next tells the cron scheduler there are no more runs.
+type boundedSchedule struct {
+	schedule cron.Schedule
+	start    time.Time
+	end      time.Time
+}
+
+// Next returns the first activation after t that falls inside the window
+func (b boundedSchedule) Next(t time.Time) time.Time {
+	if !b.start.IsZero() && t.Before(b.start) {
+		// Step back a moment so an activation exactly at start is included
+		t = b.start.Add(-time.Nanosecond)
+	}
+	next := b.schedule.Next(t)
+	if !b.end.IsZero() && next.After(b.end) {
+		return time.Time{}
+	}
+	return next
+}
+
+// onceAt fires a single time, at its instant
+type onceAt time.Time
+
+// Next returns the instant if it is still after t
+func (o onceAt) Next(t time.Time) time.Time {
+	if at := time.Time(o); at.After(t) {
+		return at
+	}
+	return time.Time{}
+}
+
+// validateScheduleEvent checks the schedule expression and window of an event
+func validateScheduleEvent(event ScheduleEvent) error {
+	if event.EndTimestamp != 0 && event.EndTimestamp < event.StartTimestamp {
+		return errors.New("endTimestamp must not be before startTimestamp")
+	}
+	_, err := eventSchedule(event)
+	return err
+}
+
+// eventSchedule builds the schedule an event runs on. A run-once event with a start time
+// may omit the expression to run exactly at its start.
+func eventSchedule(event ScheduleEvent) (cron.Schedule, error) {
+	location, err := eventLocation(event)
+	if err != nil {
+		return nil, err
+	}
+	start := millisToTime(event.StartTimestamp)
+	if event.RunOnce && event.Schedule == "" && !start.IsZero() {
+		return onceAt(start), nil
+	}
+
+	schedule, err := parseSchedule(event.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	schedule = inLocation(schedule, location, event.Timezone != "")
+	return boundedSchedule{schedule: schedule, start: start, end: millisToTime(event.EndTimestamp)}, nil
+}
+
+// millisToTime converts a millisecond timestamp to a time, with zero meaning unset
+func millisToTime(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
+
+// completeScheduleEvent marks an event completed and removes its job. ctx is the context of the job
+// asking for completion; if it has been cancelled, the job was stopped or replaced and nothing changes.
+func (s *SupportSchedulerService) completeScheduleEvent(ctx context.Context, eventId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	s.completeScheduleEventLocked(eventId)
+}
+
+// completeScheduleEventLocked marks an event completed and removes its job. Caller must hold the lock.
+func (s *SupportSchedulerService) completeScheduleEventLocked(eventId string) {
+	s.stopScheduledJobLocked(eventId)
+
+	event, exists := s.scheduleEvents[eventId]
+	if !exists {
+		return
+	}
+	event.Status = StatusCompleted
+	event.Modified = s.clock.Now().UnixNano() / int64(time.Millisecond)
+	if err := s.putScheduleEventLocked(event); err != nil {
+		// The job has stopped all the same
+		s.logger.Errorf("Failed to mark schedule event %s completed: %v", event.Name, err)
+		return
+	}
+	s.logger.Infof("Schedule event %s completed", event.Name)
+}
@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/bootstrap"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func TestBoundedSchedule(t *testing.T) {
+	schedule, err := parseSchedule("0 * * * *")
+	require.NoError(t, err)
+	start := time.Date(2024, 5, 4, 2, 0, 0, 0, time.Local)
+	bounded := boundedSchedule{schedule: schedule, start: start, end: start.Add(2 * time.Hour)}
+
+	// Nothing fires before start, and an activation exactly at start counts
+	assert.Equal(t, start, bounded.Next(start.Add(-24*time.Hour)))
+	assert.Equal(t, start.Add(time.Hour), bounded.Next(start))
+	assert.Equal(t, start.Add(2*time.Hour), bounded.Next(start.Add(time.Hour)))
+	assert.True(t, bounded.Next(start.Add(2*time.Hour)).IsZero())
+
+	once := onceAt(start)
+	assert.Equal(t, start, once.Next(start.Add(-time.Minute)))
+	assert.True(t, once.Next(start).IsZero())
+}
+
+func TestValidateScheduleEvent(t *testing.T) {
+	now := time.Now().UnixMilli()
+	tests := []struct {
+		name  string
+		event ScheduleEvent
+		valid bool
+	}{
+		{"Window", ScheduleEvent{Schedule: "*/10 * * * *", StartTimestamp: now, EndTimestamp: now + 3600000}, true},
+		{"End before start", ScheduleEvent{Schedule: "*/10 * * * *", StartTimestamp: now, EndTimestamp: now - 1}, false},
+		{"Run once at start", ScheduleEvent{RunOnce: true, StartTimestamp: now}, true},
+		{"Run once without start", ScheduleEvent{RunOnce: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScheduleEvent(tt.event)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// newRunningService returns an initialized service whose jobs call a counting test server
+func newRunningService(t *testing.T) (*SupportSchedulerService, *int32) {
+	return newRunningServiceWithClock(t, common.RealClock{})
+}
+
+// newRunningServiceWithClock is newRunningService with schedules evaluated against clock
+func newRunningServiceWithClock(t *testing.T, clock common.Clock) (*SupportSchedulerService, *int32) {
+	var executions int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&executions, 1)
+	}))
+	t.Cleanup(target.Close)
+
+	service := newTestService()
+	service.SetClock(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	service.Initialize(ctx, &sync.WaitGroup{}, bootstrap.NewDIContainer())
+
+	action := targetAction(t, target, "ping")
+	service.putScheduleActionLocked(action)
+	return service, &executions
+}
+
+func TestSupportSchedulerService_FiresOnClock(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2024, 5, 4, 12, 0, 0, 0, time.UTC))
+	service, executions := newRunningServiceWithClock(t, clock)
+	router := newTestRouter(service)
+	end := clock.Now().Add(150 * time.Second)
+	id := createEvent(t, router, ScheduleEvent{Name: "minutely", Schedule: "@every 1m", EndTimestamp: end.UnixMilli(), Addressable: "ping"})
+
+	// The job waits on its next run and on the end of its window
+	clock.BlockUntil(2)
+	assert.Equal(t, clock.Now().Add(time.Minute).UnixMilli(), getEvent(t, router, id).NextRun)
+	clock.Advance(59 * time.Second)
+	assert.Never(t, func() bool { return atomic.LoadInt32(executions) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(executions) == 1 }, time.Second, 10*time.Millisecond)
+	clock.BlockUntil(2)
+	clock.Advance(time.Minute)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(executions) == 2 }, time.Second, 10*time.Millisecond)
+
+	// The window closes before a third run is due, leaving only its end timer
+	clock.Advance(30 * time.Second)
+	require.Eventually(t, func() bool {
+		return getEvent(t, router, id).Status == StatusCompleted
+	}, time.Second, 10*time.Millisecond)
+	clock.Advance(time.Hour)
+	assert.Never(t, func() bool { return atomic.LoadInt32(executions) > 2 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestSupportSchedulerService_RunOnce(t *testing.T) {
+	service, executions := newRunningService(t)
+	router := newTestRouter(service)
+	id := createEvent(t, router, ScheduleEvent{Name: "once", Schedule: "@every 1s", RunOnce: true, Addressable: "ping"})
+
+	require.Eventually(t, func() bool {
+		return getEvent(t, router, id).Status == StatusCompleted
+	}, 3*time.Second, 20*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(executions))
+
+	// Completed events stay queryable, never fire again and can't simply be resumed
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(executions))
+	event := getEvent(t, router, id)
+	assert.Zero(t, event.NextRun)
+	assert.NotNil(t, event.LastExecution)
+
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent/id/"+id+"/resume", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestSupportSchedulerService_Window(t *testing.T) {
+	service, executions := newRunningService(t)
+	router := newTestRouter(service)
+	now := time.Now()
+
+	// Nothing runs before the window opens
+	start := now.Add(time.Hour)
+	futureId := createEvent(t, router, ScheduleEvent{Name: "later", Schedule: "@every 1s", StartTimestamp: start.UnixMilli(), Addressable: "ping"})
+	assert.GreaterOrEqual(t, getEvent(t, router, futureId).NextRun, start.UnixMilli())
+
+	// A window that already closed completes straight away
+	pastId := createEvent(t, router, ScheduleEvent{Name: "past", Schedule: "@every 1s", EndTimestamp: now.Add(-time.Minute).UnixMilli(), Addressable: "ping"})
+	assert.Equal(t, StatusCompleted, getEvent(t, router, pastId).Status)
+
+	// Reversed windows are rejected
+	rr := sendJSON(t, router, "POST", "/api/v3/scheduleevent", ScheduleEvent{Name: "reversed", Schedule: "@hourly", StartTimestamp: now.UnixMilli(), EndTimestamp: now.Add(-time.Hour).UnixMilli()})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	time.Sleep(1200 * time.Millisecond)
+	assert.Zero(t, atomic.LoadInt32(executions))
+
+	// The event completes when its window closes
+	closingId := createEvent(t, router, ScheduleEvent{Name: "closing", Schedule: "@every 1s", EndTimestamp: time.Now().Add(1500 * time.Millisecond).UnixMilli(), Addressable: "ping"})
+	assert.Empty(t, getEvent(t, router, closingId).Status)
+	require.Eventually(t, func() bool {
+		return getEvent(t, router, closingId).Status == StatusCompleted
+	}, 3*time.Second, 20*time.Millisecond)
+	fired := atomic.LoadInt32(executions)
+	assert.NotZero(t, fired)
+	time.Sleep(1200 * time.Millisecond)
+	assert.Equal(t, fired, atomic.LoadInt32(executions), "completed job fired")
+
+	// Updating a completed event schedules it again
+	rr = sendJSON(t, router, "PUT", "/api/v3/scheduleevent/id/"+pastId, ScheduleEvent{Name: "past", Schedule: "@every 1s", Addressable: "ping"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	event := getEvent(t, router, pastId)
+	assert.Empty(t, event.Status)
+	assert.NotZero(t, event.NextRun)
+}
@@ -0,0 +1,144 @@
+package bootstrap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+const testSigningKey = "test-signing-key"
+
+func newAuthRouter(t *testing.T) *mux.Router {
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, secretsClient.StoreSecret(JWTSecretPath, map[string]string{JWTSigningKeyKey: testSigningKey}))
+	auth, err := JWTAuth(secretsClient)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/device/id/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("read"))
+	}).Methods("GET")
+	router.Handle("/api/v3/device/id/{id}", RequireRole(RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("deleted"))
+	}))).Methods("DELETE")
+	router.Use(auth)
+	return router
+}
+
+func signToken(t *testing.T, key string, roles []string, expiresIn time.Duration) string {
+	claims := RoleClaims{
+		Roles: roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "operator",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+	require.NoError(t, err)
+	return token
+}
+
+func sendWithToken(router *mux.Router, method, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v3/device/id/d-1", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestJWTAuth_ValidToken(t *testing.T) {
+	router := newAuthRouter(t)
+	token := signToken(t, testSigningKey, []string{"viewer", RoleAdmin}, time.Hour)
+
+	rr := sendWithToken(router, "DELETE", token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "deleted", rr.Body.String())
+}
+
+func TestJWTAuth_RejectsInvalidTokens(t *testing.T) {
+	router := newAuthRouter(t)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Expired", signToken(t, testSigningKey, []string{RoleAdmin}, -time.Minute)},
+		{"Bad signature", signToken(t, "another-key", []string{RoleAdmin}, time.Hour)},
+		{"Malformed", "not-a-jwt"},
+		{"No expiry", func() string {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, RoleClaims{Roles: []string{RoleAdmin}}).SignedString([]byte(testSigningKey))
+			require.NoError(t, err)
+			return token
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Invalid tokens are rejected even on routes that don't require a role
+			for _, method := range []string{"GET", "DELETE"} {
+				rr := sendWithToken(router, method, tt.token)
+				assert.Equal(t, http.StatusUnauthorized, rr.Code, method)
+				assert.Contains(t, rr.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestJWTAuth_RejectsOtherAlgorithms(t *testing.T) {
+	router := newAuthRouter(t)
+	claims := RoleClaims{Roles: []string{RoleAdmin}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, sendWithToken(router, "DELETE", token).Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	router := newAuthRouter(t)
+
+	// Role mismatch
+	rr := sendWithToken(router, "DELETE", signToken(t, testSigningKey, []string{"viewer"}, time.Hour))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// No token at all
+	rr = sendWithToken(router, "DELETE", "")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Unprotected routes stay open
+	rr = sendWithToken(router, "GET", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "read", rr.Body.String())
+}
+
+func TestJWTAuth_MissingSigningKey(t *testing.T) {
+	_, err := JWTAuth(secrets.NewInMemorySecretsClient(logrus.New()))
+	assert.Error(t, err)
+}
+
+func TestNewJWTAuth_SigningKeyFromConfig(t *testing.T) {
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	_, err := NewJWTAuth(AuthConfig{JWT: true}, secretsClient)
+	assert.Error(t, err)
+	auth, err := NewJWTAuth(AuthConfig{JWT: true, SigningKey: testSigningKey}, secretsClient)
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/device/id/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("read"))
+	}).Methods("GET")
+	router.Use(auth)
+
+	assert.Equal(t, http.StatusOK, sendWithToken(router, "GET", signToken(t, testSigningKey, nil, time.Hour)).Code)
+	assert.Equal(t, http.StatusUnauthorized, sendWithToken(router, "GET", signToken(t, "other-key", nil, time.Hour)).Code)
+}
@@ -0,0 +1,382 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// ConfigFileEnv names the environment variable holding the path of the service configuration file
+const ConfigFileEnv = "EDGEX_CONFIG_FILE"
+
+// ConfigFileFlag names the command line flag holding the path of the service configuration file
+const ConfigFileFlag = "config"
+
+// Redacted is what the config route shows in place of a set field tagged `redact:"true"`
+const Redacted = "********"
+
+// Configuration is implemented by every service configuration through the BaseConfig it embeds.
+// Bootstrap adds the service's configuration to the DIContainer under common.ConfigurationName.
+type Configuration interface {
+	BaseConfiguration() BaseConfig
+}
+
+// BaseConfig holds the settings every EdgeX service shares. Service-specific
+// configuration structs embed it (with `yaml:",inline"`) and add their own sections.
+type BaseConfig struct {
+	Service    ServiceConfig    `json:"Service" yaml:"Service" toml:"Service"`
+	Registry   RegistryConfig   `json:"Registry" yaml:"Registry" toml:"Registry"`
+	Database   DatabaseConfig   `json:"Database" yaml:"Database" toml:"Database"`
+	MessageBus MessageBusConfig `json:"MessageBus" yaml:"MessageBus" toml:"MessageBus"`
+	RateLimit  RateLimitConfig  `json:"RateLimit" yaml:"RateLimit" toml:"RateLimit"`
+	Auth       AuthConfig       `json:"Auth" yaml:"Auth" toml:"Auth"`
+	Middleware MiddlewareConfig `json:"Middleware" yaml:"Middleware" toml:"Middleware"`
+	CORS       CORSConfig       `json:"CORS" yaml:"CORS" toml:"CORS"`
+}
+
+// ServiceConfig describes where the service listens
+type ServiceConfig struct {
+	Host string `json:"Host" yaml:"Host" toml:"Host" env:"SERVICE_HOST"`
+	Port int    `json:"Port" yaml:"Port" toml:"Port" env:"PORT,SERVICE_PORT"`
+	// ShutdownTimeout bounds how long in-flight requests may drain on shutdown
+	ShutdownTimeout time.Duration `json:"ShutdownTimeout" yaml:"ShutdownTimeout" toml:"ShutdownTimeout" env:"SERVICE_SHUTDOWN_TIMEOUT"`
+	// MaxRequestBodySize caps request bodies, in bytes
+	MaxRequestBodySize int64 `json:"MaxRequestBodySize" yaml:"MaxRequestBodySize" toml:"MaxRequestBodySize" env:"SERVICE_MAX_REQUEST_BODY_SIZE"`
+	// BindHost is the address the server listens on; empty listens on every interface. Host is the
+	// address other services reach it at.
+	BindHost string `json:"BindHost" yaml:"BindHost" toml:"BindHost" env:"SERVICE_BIND_HOST"`
+	// ReadTimeout, WriteTimeout and IdleTimeout bound reading a request, writing its response and
+	// keeping an idle connection open; zero means DefaultReadTimeout, DefaultWriteTimeout or DefaultIdleTimeout
+	ReadTimeout  time.Duration `json:"ReadTimeout" yaml:"ReadTimeout" toml:"ReadTimeout" env:"SERVICE_READ_TIMEOUT"`
+	WriteTimeout time.Duration `json:"WriteTimeout" yaml:"WriteTimeout" toml:"WriteTimeout" env:"SERVICE_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `json:"IdleTimeout" yaml:"IdleTimeout" toml:"IdleTimeout" env:"SERVICE_IDLE_TIMEOUT"`
+	TLS          TLSConfig     `json:"TLS" yaml:"TLS" toml:"TLS"`
+}
+
+// TLSConfig serves HTTPS instead of HTTP when it names a certificate, either as files or as a secret
+// holding the PEM certificate and key under "cert" and "key"
+type TLSConfig struct {
+	CertFile   string `json:"CertFile" yaml:"CertFile" toml:"CertFile" env:"SERVICE_TLS_CERT_FILE"`
+	KeyFile    string `json:"KeyFile" yaml:"KeyFile" toml:"KeyFile" env:"SERVICE_TLS_KEY_FILE"`
+	SecretPath string `json:"SecretPath" yaml:"SecretPath" toml:"SecretPath" env:"SERVICE_TLS_SECRET_PATH"`
+	// MinVersion is the oldest TLS version accepted, "1.2" or "1.3"; empty means 1.2
+	MinVersion string `json:"MinVersion" yaml:"MinVersion" toml:"MinVersion" env:"SERVICE_TLS_MIN_VERSION"`
+}
+
+// Enabled reports whether the service should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.SecretPath != ""
+}
+
+// RegistryConfig describes the service registry
+type RegistryConfig struct {
+	Type string `json:"Type" yaml:"Type" toml:"Type" env:"REGISTRY_TYPE"`
+	Host string `json:"Host" yaml:"Host" toml:"Host" env:"REGISTRY_HOST,CONSUL_HOST"`
+	Port int    `json:"Port" yaml:"Port" toml:"Port" env:"REGISTRY_PORT,CONSUL_PORT"`
+}
+
+// DatabaseConfig describes the persistence backend. An empty Host means in-memory storage.
+// A Username and Password set here are stored in the secret store at startup as the service's
+// database credentials.
+type DatabaseConfig struct {
+	Type     string `json:"Type" yaml:"Type" toml:"Type" env:"DATABASE_TYPE"`
+	Host     string `json:"Host" yaml:"Host" toml:"Host" env:"DATABASE_HOST,REDIS_HOST"`
+	Port     int    `json:"Port" yaml:"Port" toml:"Port" env:"DATABASE_PORT,REDIS_PORT"`
+	Username string `json:"Username" yaml:"Username" toml:"Username" env:"DATABASE_USERNAME"`
+	Password string `json:"Password" yaml:"Password" toml:"Password" env:"DATABASE_PASSWORD" redact:"true"`
+}
+
+// NewSecretProvider returns a secret provider over an in-memory secret store, holding the database
+// credentials of config, when set, as those of serviceName
+func NewSecretProvider(serviceName string, config DatabaseConfig, logger *logrus.Logger) (*secrets.SecretProvider, error) {
+	secretProvider := secrets.NewSecretProvider(secrets.NewInMemorySecretsClient(logger), logger)
+	if config.Username == "" && config.Password == "" {
+		return secretProvider, nil
+	}
+	credentials := map[string]string{"username": config.Username, "password": config.Password}
+	if err := secretProvider.StoreServiceCredentials(serviceName, "database", credentials); err != nil {
+		return nil, fmt.Errorf("failed to store database credentials: %w", err)
+	}
+	return secretProvider, nil
+}
+
+// MessageBusConfig describes the message bus. An empty Host disables messaging.
+type MessageBusConfig struct {
+	Type string `json:"Type" yaml:"Type" toml:"Type" env:"MESSAGEBUS_TYPE"`
+	Host string `json:"Host" yaml:"Host" toml:"Host" env:"MESSAGEBUS_HOST"`
+	Port int    `json:"Port" yaml:"Port" toml:"Port" env:"MESSAGEBUS_PORT"`
+}
+
+// RateLimitConfig throttles incoming requests. A RequestsPerSecond of zero, and no Routes, disables rate limiting.
+// KeyBy selects what each bucket is tracked per: "ip", "correlationId", or empty for one shared bucket.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `json:"RequestsPerSecond" yaml:"RequestsPerSecond" toml:"RequestsPerSecond" env:"RATE_LIMIT_RPS"`
+	Burst             int     `json:"Burst" yaml:"Burst" toml:"Burst" env:"RATE_LIMIT_BURST"`
+	KeyBy             string  `json:"KeyBy" yaml:"KeyBy" toml:"KeyBy" env:"RATE_LIMIT_KEY_BY"`
+	// TrustedProxies lists the addresses or CIDR ranges of reverse proxies whose X-Forwarded-For header
+	// names the client when keying by ip; requests from anywhere else are keyed by their own address
+	TrustedProxies []string `json:"TrustedProxies" yaml:"TrustedProxies" toml:"TrustedProxies" env:"RATE_LIMIT_TRUSTED_PROXIES"`
+	// MaxKeys caps how many keys get a bucket of their own; further keys share one until idle buckets
+	// are forgotten. Zero means DefaultRateLimitMaxKeys.
+	MaxKeys int `json:"MaxKeys" yaml:"MaxKeys" toml:"MaxKeys" env:"RATE_LIMIT_MAX_KEYS"`
+	// Routes give matching requests their own limit in place of the one above. The first match applies.
+	Routes []RouteRateLimitConfig `json:"Routes" yaml:"Routes" toml:"Routes"`
+}
+
+// RouteRateLimitConfig limits the requests whose path starts with PathPrefix and, if Methods is set,
+// whose method is one of them, e.g. POST to /api/v3/event. A RequestsPerSecond of zero exempts them.
+type RouteRateLimitConfig struct {
+	PathPrefix        string   `json:"PathPrefix" yaml:"PathPrefix" toml:"PathPrefix"`
+	Methods           []string `json:"Methods" yaml:"Methods" toml:"Methods"`
+	RequestsPerSecond float64  `json:"RequestsPerSecond" yaml:"RequestsPerSecond" toml:"RequestsPerSecond"`
+	Burst             int      `json:"Burst" yaml:"Burst" toml:"Burst"`
+}
+
+// Enabled reports whether any request is rate limited
+func (c RateLimitConfig) Enabled() bool {
+	if c.RequestsPerSecond > 0 {
+		return true
+	}
+	for _, route := range c.Routes {
+		if route.RequestsPerSecond > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthConfig controls request authentication. With JWT enabled, bearer tokens are verified
+// against the signing key in the secret store and destructive routes require the admin role.
+// A SigningKey set here is stored in the secret store at startup, for stores that don't already hold one.
+type AuthConfig struct {
+	JWT        bool   `json:"JWT" yaml:"JWT" toml:"JWT" env:"AUTH_JWT_ENABLED"`
+	SigningKey string `json:"SigningKey" yaml:"SigningKey" toml:"SigningKey" env:"AUTH_JWT_SIGNING_KEY" redact:"true"`
+}
+
+// MiddlewareConfig switches off parts of the standard middleware Bootstrap installs on every route.
+// Both are on by default.
+type MiddlewareConfig struct {
+	// DisableAccessLog stops the log entry written for each request
+	DisableAccessLog bool `json:"DisableAccessLog" yaml:"DisableAccessLog" toml:"DisableAccessLog" env:"MIDDLEWARE_DISABLE_ACCESS_LOG"`
+	// DisableRecovery lets handler panics reach net/http, which drops the connection
+	DisableRecovery bool `json:"DisableRecovery" yaml:"DisableRecovery" toml:"DisableRecovery" env:"MIDDLEWARE_DISABLE_RECOVERY"`
+}
+
+// BaseConfiguration returns the shared settings, so handlers can read them whatever the service's configuration type
+func (c BaseConfig) BaseConfiguration() BaseConfig {
+	return c
+}
+
+// NewBaseConfig returns the default configuration for a service listening on port
+func NewBaseConfig(port int) BaseConfig {
+	return BaseConfig{
+		Service: ServiceConfig{
+			Host:               "localhost",
+			Port:               port,
+			ShutdownTimeout:    DefaultShutdownTimeout,
+			MaxRequestBodySize: DefaultMaxRequestBodySize,
+			ReadTimeout:        DefaultReadTimeout,
+			WriteTimeout:       DefaultWriteTimeout,
+			IdleTimeout:        DefaultIdleTimeout,
+		},
+		Registry: RegistryConfig{
+			Type: "consul",
+			Host: "localhost",
+			Port: 8500,
+		},
+		Database: DatabaseConfig{
+			Type: "redis",
+			Port: 6379,
+		},
+		MessageBus: MessageBusConfig{
+			Type: "redis",
+			Port: 6379,
+		},
+		CORS: DefaultCORSConfig(),
+	}
+}
+
+// ConfigFilePath returns the configuration file named by the -config flag in args, the command line
+// arguments after the program name, falling back to the EDGEX_CONFIG_FILE environment variable.
+// It is empty if neither is set.
+func ConfigFilePath(args []string) (string, error) {
+	flags := flag.NewFlagSet("service", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String(ConfigFileFlag, "", "path of the YAML or TOML configuration file")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if *path != "" {
+		return *path, nil
+	}
+	return os.Getenv(ConfigFileEnv), nil
+}
+
+// LoadConfig reads the YAML or TOML file at path into out, then overlays environment variables
+// named by `env` struct tags. A tag may list several variables; the first one set wins.
+// Values already in out act as defaults. An empty path skips the file and applies only the environment.
+func LoadConfig(path string, out interface{}) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config target must be a non-nil pointer to a struct, got %T", out)
+	}
+
+	if path != "" {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(contents, out)
+		case ".toml":
+			err = toml.Unmarshal(contents, out)
+		default:
+			return fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .toml", filepath.Ext(path))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	return overlayEnv(target.Elem())
+}
+
+// overlayEnv sets struct fields from the environment variables named in their `env` tags, recursing into nested structs
+func overlayEnv(value reflect.Value) error {
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		fieldType := valueType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		if field.Kind() == reflect.Struct {
+			if err := overlayEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := fieldType.Tag.Get("env")
+		if tag == "" {
+			continue
+		}
+		for _, name := range strings.Split(tag, ",") {
+			raw, set := os.LookupEnv(strings.TrimSpace(name))
+			if !set {
+				continue
+			}
+			if err := setField(field, raw); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %w", raw, name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// setField parses raw into a field of a supported kind
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", field.Type())
+		}
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		field.Set(reflect.ValueOf(parts).Convert(field.Type()))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// RedactConfig returns a copy of config, a struct or a pointer to one, with every set string field tagged
+// `redact:"true"` replaced by Redacted, in nested structs too. Anything else is returned as it is.
+func RedactConfig(config interface{}) interface{} {
+	value := reflect.ValueOf(config)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return config
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return config
+	}
+	redacted := reflect.New(value.Type()).Elem()
+	redacted.Set(value)
+	redactFields(redacted)
+	return redacted.Interface()
+}
+
+// redactFields blanks out the tagged fields of a settable struct, recursing into nested structs
+func redactFields(value reflect.Value) {
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		fieldType := valueType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			redactFields(field)
+			continue
+		}
+		if fieldType.Tag.Get("redact") == "true" && field.Kind() == reflect.String && field.String() != "" {
+			field.SetString(Redacted)
+		}
+	}
+}
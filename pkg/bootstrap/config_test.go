@@ -0,0 +1,291 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// testConfig mirrors how a service extends BaseConfig with its own section
+type testConfig struct {
+	BaseConfig `yaml:",inline"`
+	Writable   testWritable `json:"Writable" yaml:"Writable" toml:"Writable"`
+}
+
+type testWritable struct {
+	LogLevel string        `json:"LogLevel" yaml:"LogLevel" toml:"LogLevel" env:"WRITABLE_LOG_LEVEL"`
+	Interval time.Duration `json:"Interval" yaml:"Interval" toml:"Interval" env:"WRITABLE_INTERVAL"`
+	Enabled  bool          `json:"Enabled" yaml:"Enabled" toml:"Enabled" env:"WRITABLE_ENABLED"`
+	Topics   []string      `json:"Topics" yaml:"Topics" toml:"Topics" env:"WRITABLE_TOPICS"`
+}
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+const yamlConfig = `
+Service:
+  Host: core-data
+  Port: 48080
+Registry:
+  Host: consul
+Writable:
+  LogLevel: DEBUG
+  Interval: 30s
+  Topics: [a, b]
+`
+
+const tomlConfig = `
+[Service]
+Host = "core-data"
+Port = 48080
+
+[Registry]
+Host = "consul"
+
+[Writable]
+LogLevel = "DEBUG"
+Interval = "30s"
+Topics = ["a", "b"]
+`
+
+func TestLoadConfig_ParseFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{"YAML", "configuration.yaml", yamlConfig},
+		{"YML", "configuration.yml", yamlConfig},
+		{"TOML", "configuration.toml", tomlConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig{BaseConfig: NewBaseConfig(59880)}
+			require.NoError(t, LoadConfig(writeConfigFile(t, tt.file, tt.contents), &config))
+
+			assert.Equal(t, "core-data", config.Service.Host)
+			assert.Equal(t, 48080, config.Service.Port)
+			assert.Equal(t, "consul", config.Registry.Host)
+			assert.Equal(t, "DEBUG", config.Writable.LogLevel)
+			assert.Equal(t, 30*time.Second, config.Writable.Interval)
+			assert.Equal(t, []string{"a", "b"}, config.Writable.Topics)
+
+			// Defaults survive for keys the file doesn't mention
+			assert.Equal(t, 8500, config.Registry.Port)
+			assert.Equal(t, 6379, config.Database.Port)
+		})
+	}
+}
+
+func TestLoadConfig_EnvOverridePrecedence(t *testing.T) {
+	path := writeConfigFile(t, "configuration.yaml", yamlConfig)
+
+	t.Setenv("PORT", "50000")
+	t.Setenv("CONSUL_HOST", "consul-fallback")
+	t.Setenv("REGISTRY_HOST", "registry")
+	t.Setenv("REDIS_HOST", "redis")
+	t.Setenv("WRITABLE_INTERVAL", "1m")
+	t.Setenv("WRITABLE_ENABLED", "true")
+	t.Setenv("WRITABLE_TOPICS", "x, y")
+
+	config := testConfig{BaseConfig: NewBaseConfig(59880)}
+	require.NoError(t, LoadConfig(path, &config))
+
+	// Environment beats the file, and the first listed variable beats later ones
+	assert.Equal(t, 50000, config.Service.Port)
+	assert.Equal(t, "registry", config.Registry.Host)
+	assert.Equal(t, time.Minute, config.Writable.Interval)
+	assert.Equal(t, []string{"x", "y"}, config.Writable.Topics)
+	assert.True(t, config.Writable.Enabled)
+
+	// Environment beats defaults, and the file beats defaults where no variable is set
+	assert.Equal(t, "redis", config.Database.Host)
+	assert.Equal(t, "DEBUG", config.Writable.LogLevel)
+}
+
+func TestLoadConfig_EnvOnly(t *testing.T) {
+	t.Setenv("CONSUL_HOST", "consul")
+
+	config := NewBaseConfig(59880)
+	require.NoError(t, LoadConfig("", &config))
+
+	assert.Equal(t, 59880, config.Service.Port)
+	assert.Equal(t, "consul", config.Registry.Host)
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	t.Run("Missing file", func(t *testing.T) {
+		config := NewBaseConfig(59880)
+		assert.Error(t, LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"), &config))
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		config := NewBaseConfig(59880)
+		assert.Error(t, LoadConfig(writeConfigFile(t, "configuration.json", "{}"), &config))
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		config := NewBaseConfig(59880)
+		assert.Error(t, LoadConfig(writeConfigFile(t, "configuration.toml", "[Service\nPort = "), &config))
+	})
+
+	t.Run("Invalid environment value", func(t *testing.T) {
+		t.Setenv("PORT", "eighty")
+		config := NewBaseConfig(59880)
+		err := LoadConfig("", &config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "PORT")
+	})
+
+	t.Run("Non-pointer target", func(t *testing.T) {
+		assert.Error(t, LoadConfig("", NewBaseConfig(59880)))
+	})
+}
+
+func TestAddCommonRoutes_Config(t *testing.T) {
+	config := NewBaseConfig(59880)
+	config.Database.Host = "redis"
+
+	router := mux.NewRouter()
+	AddCommonRoutes(router, common.CoreDataServiceKey, common.ServiceVersion, config)
+
+	req, err := http.NewRequest("GET", common.ApiConfigRoute, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		ServiceName string     `json:"serviceName"`
+		Config      BaseConfig `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, common.CoreDataServiceKey, response.ServiceName)
+	assert.Equal(t, config, response.Config)
+}
+
+func TestConfigFilePath(t *testing.T) {
+	t.Setenv(ConfigFileEnv, "/etc/edgex/env.yaml")
+
+	path, err := ConfigFilePath([]string{"-config", "/etc/edgex/flag.toml"})
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/edgex/flag.toml", path, "the flag wins over the environment")
+
+	path, err = ConfigFilePath([]string{"--config=/etc/edgex/flag.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/edgex/flag.yaml", path)
+
+	path, err = ConfigFilePath(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/edgex/env.yaml", path)
+
+	t.Setenv(ConfigFileEnv, "")
+	path, err = ConfigFilePath(nil)
+	require.NoError(t, err)
+	assert.Empty(t, path)
+
+	_, err = ConfigFilePath([]string{"-port", "1"})
+	assert.Error(t, err)
+}
+
+// secretConfig has a section holding a credential
+type secretConfig struct {
+	BaseConfig `yaml:",inline"`
+	Store      secretStore `json:"Store"`
+}
+
+type secretStore struct {
+	User     string `json:"User"`
+	Password string `json:"Password" redact:"true"`
+	Token    string `json:"Token" redact:"true"`
+}
+
+func TestRedactConfig(t *testing.T) {
+	config := secretConfig{BaseConfig: NewBaseConfig(59880), Store: secretStore{User: "edgex", Password: "hunter2"}}
+
+	redacted, ok := RedactConfig(config).(secretConfig)
+	require.True(t, ok)
+	assert.Equal(t, secretStore{User: "edgex", Password: Redacted}, redacted.Store, "fields left empty stay empty")
+	assert.Equal(t, config.BaseConfig, redacted.BaseConfig)
+	assert.Equal(t, "hunter2", config.Store.Password, "the original is left alone")
+
+	redacted, ok = RedactConfig(&config).(secretConfig)
+	require.True(t, ok)
+	assert.Equal(t, Redacted, redacted.Store.Password)
+
+	assert.Equal(t, "plain", RedactConfig("plain"))
+	assert.Nil(t, RedactConfig(nil))
+}
+
+func TestNewSecretProvider(t *testing.T) {
+	t.Setenv("DATABASE_USERNAME", "edgex")
+	t.Setenv("DATABASE_PASSWORD", "hunter2")
+	config := NewBaseConfig(59880)
+	require.NoError(t, LoadConfig("", &config))
+
+	secretProvider, err := NewSecretProvider(common.CoreDataServiceKey, config.Database, logrus.New())
+	require.NoError(t, err)
+	username, password, err := secretProvider.GetDatabaseCredentials(common.CoreDataServiceKey)
+	require.NoError(t, err)
+	assert.Equal(t, "edgex", username)
+	assert.Equal(t, "hunter2", password)
+	assert.Equal(t, Redacted, RedactConfig(config).(BaseConfig).Database.Password)
+
+	// Without credentials the store holds none, and services connect without authentication
+	secretProvider, err = NewSecretProvider(common.CoreDataServiceKey, NewBaseConfig(59880).Database, logrus.New())
+	require.NoError(t, err)
+	_, _, err = secretProvider.GetDatabaseCredentials(common.CoreDataServiceKey)
+	assert.Error(t, err)
+}
+
+func TestAddCommonRoutes_ConfigRedacted(t *testing.T) {
+	config := secretConfig{BaseConfig: NewBaseConfig(59880), Store: secretStore{Password: "hunter2"}}
+
+	router := mux.NewRouter()
+	AddCommonRoutes(router, common.CoreDataServiceKey, common.ServiceVersion, config)
+
+	req, err := http.NewRequest("GET", common.ApiConfigRoute, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hunter2")
+	var response struct {
+		Config secretConfig `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, Redacted, response.Config.Store.Password)
+	assert.Equal(t, 59880, response.Config.Service.Port)
+}
+
+func TestConfiguration(t *testing.T) {
+	config := testConfig{BaseConfig: NewBaseConfig(59880)}
+	config.Writable.LogLevel = "DEBUG"
+
+	// Handlers read the shared settings whatever the service's configuration type
+	dic := NewDIContainer()
+	dic.Add(common.ConfigurationName, config)
+	shared, ok := GetAs[Configuration](dic, common.ConfigurationName)
+	require.True(t, ok)
+	assert.Equal(t, 59880, shared.BaseConfiguration().Service.Port)
+	service, ok := GetAs[testConfig](dic, common.ConfigurationName)
+	require.True(t, ok)
+	assert.Equal(t, "DEBUG", service.Writable.LogLevel)
+}
@@ -0,0 +1,134 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/messaging"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/registry"
+)
+
+// Errors returned by Get, wrapped with the name of the service
+var (
+	ErrServiceNotFound  = errors.New("service not found in the DI container")
+	ErrServiceWrongType = errors.New("service in the DI container has the wrong type")
+)
+
+// DIContainer provides dependency injection
+type DIContainer struct {
+	services map[string]interface{}
+	mutex    sync.RWMutex
+}
+
+// NewDIContainer creates a new dependency injection container
+func NewDIContainer() *DIContainer {
+	return &DIContainer{
+		services: make(map[string]interface{}),
+	}
+}
+
+// Add adds a service to the container
+func (c *DIContainer) Add(name string, service interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.services[name] = service
+}
+
+// Get retrieves a service from the container, nil if it is missing. Prefer the typed Get function.
+func (c *DIContainer) Get(name string) interface{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.services[name]
+}
+
+// Exists reports whether a service is registered under name
+func (c *DIContainer) Exists(name string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	_, ok := c.services[name]
+	return ok
+}
+
+// Remove deletes a service from the container
+func (c *DIContainer) Remove(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.services, name)
+}
+
+// Names returns the names of all registered services in sorted order
+func (c *DIContainer) Names() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	names := make([]string, 0, len(c.services))
+	for name := range c.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetAs retrieves a service from the container as type T. It reports false if the
+// service is missing or has a different type.
+func GetAs[T any](dic *DIContainer, name string) (T, bool) {
+	service, ok := dic.Get(name).(T)
+	return service, ok
+}
+
+// Get retrieves a service from the container as type T, with an error naming the service if it is
+// missing or has a different type
+func Get[T any](dic *DIContainer, name string) (T, error) {
+	var zero T
+	dic.mutex.RLock()
+	service, exists := dic.services[name]
+	dic.mutex.RUnlock()
+	if !exists {
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+	typed, ok := service.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s is a %T, not a %s", ErrServiceWrongType, name, service, reflect.TypeOf(&zero).Elem())
+	}
+	return typed, nil
+}
+
+// MustGet is Get for services the caller cannot do without, panicking with the error if it fails
+func MustGet[T any](dic *DIContainer, name string) T {
+	service, err := Get[T](dic, name)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+// GetLoggingClient returns the logger Bootstrap adds under common.LoggingClientName
+func GetLoggingClient(dic *DIContainer) (*logrus.Logger, error) {
+	return Get[*logrus.Logger](dic, common.LoggingClientName)
+}
+
+// GetConfiguration returns the service configuration Bootstrap adds under common.ConfigurationName
+func GetConfiguration(dic *DIContainer) (Configuration, error) {
+	return Get[Configuration](dic, common.ConfigurationName)
+}
+
+// GetHealthRegistry returns the registry Bootstrap adds under common.HealthRegistryName
+func GetHealthRegistry(dic *DIContainer) (*HealthRegistry, error) {
+	return Get[*HealthRegistry](dic, common.HealthRegistryName)
+}
+
+// GetRegistryClient returns the client the RegistryHandler adds under common.RegistryClientName
+func GetRegistryClient(dic *DIContainer) (registry.RegistryClient, error) {
+	return Get[registry.RegistryClient](dic, common.RegistryClientName)
+}
+
+// GetMessageClient returns the message bus client added under common.MessagingClientName
+func GetMessageClient(dic *DIContainer) (messaging.MessageClient, error) {
+	return Get[messaging.MessageClient](dic, common.MessagingClientName)
+}
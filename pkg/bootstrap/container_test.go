@@ -0,0 +1,124 @@
+package bootstrap
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/registry"
+)
+
+func TestDIContainer_GetAs(t *testing.T) {
+	dic := NewDIContainer()
+	logger := logrus.New()
+	dic.Add("logger", logger)
+
+	got, ok := GetAs[*logrus.Logger](dic, "logger")
+	assert.True(t, ok)
+	assert.Same(t, logger, got)
+
+	// Wrong type
+	wrong, ok := GetAs[*http.Server](dic, "logger")
+	assert.False(t, ok)
+	assert.Nil(t, wrong)
+
+	// Missing service
+	_, ok = GetAs[*logrus.Logger](dic, "missing")
+	assert.False(t, ok)
+}
+
+func TestDIContainer_RemoveAndNames(t *testing.T) {
+	dic := NewDIContainer()
+	dic.Add("b", 1)
+	dic.Add("a", 2)
+	assert.Equal(t, []string{"a", "b"}, dic.Names())
+
+	dic.Remove("a")
+	assert.Equal(t, []string{"b"}, dic.Names())
+	assert.Nil(t, dic.Get("a"))
+	_, ok := GetAs[int](dic, "a")
+	assert.False(t, ok)
+
+	// Removing an unknown name is a no-op
+	dic.Remove("missing")
+	assert.Equal(t, []string{"b"}, dic.Names())
+}
+
+func TestDIContainer_Exists(t *testing.T) {
+	dic := NewDIContainer()
+	assert.False(t, dic.Exists("logger"))
+
+	// A nil service is still registered
+	dic.Add("logger", nil)
+	assert.True(t, dic.Exists("logger"))
+
+	dic.Remove("logger")
+	assert.False(t, dic.Exists("logger"))
+}
+
+func TestDIContainer_Get(t *testing.T) {
+	dic := NewDIContainer()
+	logger := logrus.New()
+	dic.Add(common.LoggingClientName, logger)
+
+	got, err := Get[*logrus.Logger](dic, common.LoggingClientName)
+	require.NoError(t, err)
+	assert.Same(t, logger, got)
+
+	_, err = Get[*http.Server](dic, common.LoggingClientName)
+	assert.ErrorIs(t, err, ErrServiceWrongType)
+	assert.EqualError(t, err, "service in the DI container has the wrong type: LoggingClient is a *logrus.Logger, not a *http.Server")
+
+	_, err = Get[registry.RegistryClient](dic, common.RegistryClientName)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	assert.Contains(t, err.Error(), common.RegistryClientName)
+}
+
+func TestDIContainer_MustGet(t *testing.T) {
+	dic := NewDIContainer()
+	dic.Add("answer", 42)
+
+	assert.Equal(t, 42, MustGet[int](dic, "answer"))
+	assert.PanicsWithError(t, "service not found in the DI container: missing", func() {
+		MustGet[int](dic, "missing")
+	})
+}
+
+func TestDIContainer_TypedAccessors(t *testing.T) {
+	dic := NewDIContainer()
+
+	_, err := GetLoggingClient(dic)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	_, err = GetConfiguration(dic)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	_, err = GetHealthRegistry(dic)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	_, err = GetRegistryClient(dic)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+	_, err = GetMessageClient(dic)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+
+	logger := logrus.New()
+	config := NewBaseConfig(59880)
+	healthRegistry := NewHealthRegistry()
+	dic.Add(common.LoggingClientName, logger)
+	dic.Add(common.ConfigurationName, config)
+	dic.Add(common.HealthRegistryName, healthRegistry)
+	dic.Add(common.MessagingClientName, "not a client")
+
+	gotLogger, err := GetLoggingClient(dic)
+	require.NoError(t, err)
+	assert.Same(t, logger, gotLogger)
+	gotConfig, err := GetConfiguration(dic)
+	require.NoError(t, err)
+	assert.Equal(t, 59880, gotConfig.BaseConfiguration().Service.Port)
+	gotRegistry, err := GetHealthRegistry(dic)
+	require.NoError(t, err)
+	assert.Same(t, healthRegistry, gotRegistry)
+	_, err = GetMessageClient(dic)
+	assert.ErrorIs(t, err, ErrServiceWrongType)
+}
@@ -0,0 +1,112 @@
+package bootstrap
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// CORSConfig controls which cross-origin requests are allowed. With no AllowedOrigins, CORS is off and
+// no cross-origin request is allowed. MaxAge is how long, in seconds, browsers may cache a preflight.
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"AllowedOrigins" yaml:"AllowedOrigins" toml:"AllowedOrigins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string `json:"AllowedMethods" yaml:"AllowedMethods" toml:"AllowedMethods" env:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string `json:"AllowedHeaders" yaml:"AllowedHeaders" toml:"AllowedHeaders" env:"CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string `json:"ExposedHeaders" yaml:"ExposedHeaders" toml:"ExposedHeaders" env:"CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool     `json:"AllowCredentials" yaml:"AllowCredentials" toml:"AllowCredentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           int      `json:"MaxAge" yaml:"MaxAge" toml:"MaxAge" env:"CORS_MAX_AGE"`
+}
+
+// DefaultCORSConfig returns the methods and headers the services use, but no allowed origins,
+// so CORS stays off until origins are configured (e.g. CORS_ALLOWED_ORIGINS=https://ui.example.com)
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{common.ContentType, common.CorrelationHeader, "Authorization"},
+		ExposedHeaders: []string{common.CorrelationHeader},
+		MaxAge:         3600,
+	}
+}
+
+// Enabled reports whether any origin is allowed
+func (c CORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// withCORS applies config around the whole router, as Bootstrap does, so preflight requests are answered
+// before routing and never reach authentication, rate limiting or the service handlers. Without allowed
+// origins the router is returned as it is.
+func withCORS(router *mux.Router, config CORSConfig) http.Handler {
+	if !config.Enabled() {
+		return router
+	}
+	return CORSMiddleware(config)(router)
+}
+
+// CORSMiddleware returns middleware applying the given CORS configuration
+func CORSMiddleware(config CORSConfig) mux.MiddlewareFunc {
+	allowedMethods := strings.Join(config.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || !config.originAllowed(origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			headers := w.Header()
+			headers.Add("Vary", "Origin")
+			if config.allowsAnyOrigin() && !config.AllowCredentials {
+				headers.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				headers.Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				headers.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			// Preflight requests are answered here without reaching the route handler
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				headers.Set("Access-Control-Allow-Methods", allowedMethods)
+				headers.Set("Access-Control-Allow-Headers", allowedHeaders)
+				if config.MaxAge > 0 {
+					headers.Set("Access-Control-Max-Age", strconv.Itoa(config.MaxAge))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposedHeaders != "" {
+				headers.Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowsAnyOrigin reports whether the wildcard origin is configured
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// originAllowed reports whether the request origin may access the API
+func (c CORSConfig) originAllowed(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package bootstrap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// newCORSHandler wraps a router serving a ping route with config, as Bootstrap wraps the service router
+func newCORSHandler(config CORSConfig) http.Handler {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("pong"))
+	}).Methods("GET")
+	return withCORS(router, config)
+}
+
+// anyOriginCORSConfig is the default configuration opened to every origin
+func anyOriginCORSConfig() CORSConfig {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"*"}
+	return config
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler := newCORSHandler(anyOriginCORSConfig())
+
+	req, err := http.NewRequest("OPTIONS", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://dashboard.local")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rr.Header().Get("Access-Control-Allow-Methods"), "DELETE")
+	assert.Contains(t, rr.Header().Get("Access-Control-Allow-Headers"), common.CorrelationHeader)
+	assert.Equal(t, "3600", rr.Header().Get("Access-Control-Max-Age"))
+	assert.Empty(t, rr.Body.String())
+
+	// Preflights are answered before routing, so no OPTIONS route is needed
+	req.URL.Path = "/api/v3/unrouted"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+}
+
+func TestCORS_SimpleGet(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"http://dashboard.local"}
+	config.AllowCredentials = true
+	handler := newCORSHandler(config)
+
+	req, err := http.NewRequest("GET", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://dashboard.local")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "pong", rr.Body.String())
+	assert.Equal(t, "http://dashboard.local", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, common.CorrelationHeader, rr.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"http://dashboard.local"}
+	handler := newCORSHandler(config)
+
+	req, err := http.NewRequest("GET", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://evil.example")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORS_RoutesUnaffected(t *testing.T) {
+	handler := newCORSHandler(anyOriginCORSConfig())
+
+	// No Origin header: plain request behaves exactly as before
+	req, err := http.NewRequest("GET", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+
+	// Unsupported method on an existing route is still rejected
+	req, err = http.NewRequest("DELETE", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+// newBootstrapCORSHandler wraps a router whose every route requires authentication, as Bootstrap
+// wraps the service router, and counts the requests reaching the router
+func newBootstrapCORSHandler(config CORSConfig) (http.Handler, *int) {
+	reached := 0
+	router := mux.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reached++
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.HandleFunc("/api/v3/device", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	return withCORS(router, config), &reached
+}
+
+func TestWithCORS_DeniedByDefault(t *testing.T) {
+	handler, reached := newBootstrapCORSHandler(DefaultCORSConfig())
+
+	req := httptest.NewRequest("OPTIONS", "/api/v3/device", nil)
+	req.Header.Set("Origin", "http://dashboard.local")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, 0, *reached)
+}
+
+func TestWithCORS_Preflight(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"http://dashboard.local"}
+	handler, reached := newBootstrapCORSHandler(config)
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", "/api/v3/device", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Answered before routing, so authentication never sees the preflight
+	rr := preflight("http://dashboard.local")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "http://dashboard.local", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, rr.Header().Get("Access-Control-Allow-Methods"), "POST")
+	assert.Contains(t, rr.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	assert.Equal(t, 0, *reached)
+
+	rr = preflight("http://evil.example")
+	assert.NotEqual(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestWithCORS_SimpleRequest(t *testing.T) {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"http://dashboard.local"}
+	handler, reached := newBootstrapCORSHandler(config)
+
+	post := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v3/device", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Authorization", "Bearer token")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post("http://dashboard.local")
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "http://dashboard.local", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, common.CorrelationHeader, rr.Header().Get("Access-Control-Expose-Headers"))
+
+	// A disallowed origin still reaches the handler, but the browser won't let it read the response
+	rr = post("http://evil.example")
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, 2, *reached)
+}
+
+func TestCORSConfig_FromEnvironment(t *testing.T) {
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://ui.example.com, http://localhost:3000")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_MAX_AGE", "600")
+
+	config := NewBaseConfig(59880)
+	assert.False(t, config.CORS.Enabled())
+	require.NoError(t, LoadConfig("", &config))
+
+	assert.True(t, config.CORS.Enabled())
+	assert.Equal(t, []string{"https://ui.example.com", "http://localhost:3000"}, config.CORS.AllowedOrigins)
+	assert.True(t, config.CORS.AllowCredentials)
+	assert.Equal(t, 600, config.CORS.MaxAge)
+	assert.Equal(t, DefaultCORSConfig().AllowedMethods, config.CORS.AllowedMethods)
+}
@@ -0,0 +1,267 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// Health statuses
+const (
+	HealthStatusUp       = "UP"
+	HealthStatusDown     = "DOWN"
+	HealthStatusDegraded = "DEGRADED"
+)
+
+// DefaultHealthCheckTimeout bounds how long the health endpoint waits for all checks
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// StartupCheckName names the pseudo-check the ready route reports while bootstrap handlers are still initializing
+const StartupCheckName = "startup"
+
+// HealthChecker is a dependency that can report whether it is reachable, such as a store or message client
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthCheckFunc probes one dependency and returns an error if it is unavailable.
+// It should return promptly once ctx is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single health check
+type CheckResult struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Message  string `json:"message,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// HealthReport aggregates the results of every registered check
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// registeredCheck is a health check and whether its failure makes the service unhealthy
+type registeredCheck struct {
+	check    HealthCheckFunc
+	critical bool
+}
+
+// HealthRegistry holds the dependency checks a service contributes. Bootstrap adds the registry
+// serving the common health and ready routes to the DIContainer under common.HealthRegistryName,
+// so handlers can register checks for the dependencies they set up in Initialize.
+type HealthRegistry struct {
+	checks  map[string]registeredCheck
+	started bool
+	mutex   sync.RWMutex
+}
+
+// NewHealthRegistry creates an empty health registry
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		checks: make(map[string]registeredCheck),
+	}
+}
+
+// Register adds or replaces a named check. A failing critical check makes the service DOWN;
+// a failing non-critical check only makes it DEGRADED.
+func (h *HealthRegistry) Register(name string, check HealthCheckFunc, critical bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.checks[name] = registeredCheck{check: check, critical: critical}
+}
+
+// Unregister removes a named check
+func (h *HealthRegistry) Unregister(name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.checks, name)
+}
+
+// MarkStarted records that every bootstrap handler has initialized, so readiness now only depends on the checks
+func (h *HealthRegistry) MarkStarted() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.started = true
+}
+
+// Started reports whether MarkStarted has been called
+func (h *HealthRegistry) Started() bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.started
+}
+
+// Names returns the registered check names in sorted order
+func (h *HealthRegistry) Names() []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes every check concurrently and aggregates the results
+func (h *HealthRegistry) Run(ctx context.Context) HealthReport {
+	h.mutex.RLock()
+	checks := make(map[string]registeredCheck, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mutex.RUnlock()
+
+	report := HealthReport{
+		Status: HealthStatusUp,
+		Checks: make(map[string]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	for name, registered := range checks {
+		wg.Add(1)
+		go func(name string, registered registeredCheck) {
+			defer wg.Done()
+			result := runCheck(ctx, registered)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[name] = result
+			if result.Status == HealthStatusUp {
+				return
+			}
+			if registered.critical {
+				report.Status = HealthStatusDown
+			} else if report.Status == HealthStatusUp {
+				report.Status = HealthStatusDegraded
+			}
+		}(name, registered)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck executes one check, converting a panic or a check that ignores ctx into a failure
+func runCheck(ctx context.Context, registered registeredCheck) (result CheckResult) {
+	start := time.Now()
+	result = CheckResult{Status: HealthStatusUp, Critical: registered.critical}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- fmt.Errorf("check panicked: %v", recovered)
+			}
+		}()
+		done <- registered.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+
+	result.Duration = time.Since(start).String()
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// Handler serves the aggregated health report, returning 503 when a critical check fails
+func (h *HealthRegistry) Handler(serviceName string, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		writeHealthReport(w, serviceName, h.Run(ctx))
+	}
+}
+
+// ReadyHandler serves readiness for traffic: the same report as Handler, but also DOWN with a failing
+// StartupCheckName check until MarkStarted is called. Unlike the ping route, which answers as soon as
+// the listener is up, it returns 503 until the service can actually serve requests.
+func (h *HealthRegistry) ReadyHandler(serviceName string, timeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		report := h.Run(ctx)
+		if !h.Started() {
+			report.Status = HealthStatusDown
+			report.Checks[StartupCheckName] = CheckResult{
+				Status:   HealthStatusDown,
+				Critical: true,
+				Message:  "bootstrap handlers are still initializing",
+				Duration: time.Duration(0).String(),
+			}
+		}
+		writeHealthReport(w, serviceName, report)
+	}
+}
+
+// RequireStarted returns middleware answering every route but ping and ready with 503 until MarkStarted
+// is called, so no request reaches a bootstrap handler before its Initialize has run.
+func (h *HealthRegistry) RequireStarted() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !h.Started() && r.URL.Path != common.ApiPingRoute && r.URL.Path != common.ApiReadyRoute {
+				common.WriteError(w, http.StatusServiceUnavailable, "Service is still starting")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeHealthReport writes report, with 503 when it is DOWN
+func writeHealthReport(w http.ResponseWriter, serviceName string, report HealthReport) {
+	statusCode := http.StatusOK
+	if report.Status == HealthStatusDown {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set(common.ContentType, common.ContentTypeJSON)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"apiVersion":  common.ServiceVersion,
+		"statusCode":  statusCode,
+		"serviceName": serviceName,
+		"status":      report.Status,
+		"checks":      report.Checks,
+	})
+}
+
+// defaultHealthRegistry backs the health and ready routes registered by AddCommonRoutes
+var defaultHealthRegistry = NewHealthRegistry()
+
+// RegisterHealthCheck adds a critical dependency check to the service health endpoint
+func RegisterHealthCheck(name string, check func(ctx context.Context) error) {
+	defaultHealthRegistry.Register(name, check, true)
+}
+
+// RegisterOptionalHealthCheck adds a non-critical dependency check to the service health endpoint
+func RegisterOptionalHealthCheck(name string, check func(ctx context.Context) error) {
+	defaultHealthRegistry.Register(name, check, false)
+}
+
+// DefaultHealthRegistry returns the registry served by the common health and ready routes
+func DefaultHealthRegistry() *HealthRegistry {
+	return defaultHealthRegistry
+}
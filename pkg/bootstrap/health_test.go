@@ -0,0 +1,225 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func passingCheck(ctx context.Context) error {
+	return nil
+}
+
+func failingCheck(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+// healthResponse mirrors the JSON served by the health route
+type healthResponse struct {
+	StatusCode  int                    `json:"statusCode"`
+	ServiceName string                 `json:"serviceName"`
+	Status      string                 `json:"status"`
+	Checks      map[string]CheckResult `json:"checks"`
+}
+
+// serveHealth calls handler and decodes the response
+func serveHealth(t *testing.T, handler http.HandlerFunc) (int, healthResponse) {
+	req, err := http.NewRequest("GET", common.ApiHealthRoute, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var response healthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	return rr.Code, response
+}
+
+func TestHealthRegistry_AllPassing(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("database", passingCheck, true)
+	registry.Register("registry", passingCheck, true)
+
+	code, response := serveHealth(t, registry.Handler("test-service", time.Second))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "test-service", response.ServiceName)
+	assert.Equal(t, HealthStatusUp, response.Status)
+	require.Len(t, response.Checks, 2)
+	assert.Equal(t, HealthStatusUp, response.Checks["database"].Status)
+	assert.Equal(t, HealthStatusUp, response.Checks["registry"].Status)
+}
+
+func TestHealthRegistry_CriticalFailure(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("database", failingCheck, true)
+	registry.Register("registry", passingCheck, true)
+
+	code, response := serveHealth(t, registry.Handler("test-service", time.Second))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	assert.Equal(t, HealthStatusDown, response.Status)
+	assert.Equal(t, HealthStatusDown, response.Checks["database"].Status)
+	assert.Equal(t, "connection refused", response.Checks["database"].Message)
+	assert.True(t, response.Checks["database"].Critical)
+	assert.Equal(t, HealthStatusUp, response.Checks["registry"].Status)
+}
+
+func TestHealthRegistry_OptionalFailure(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("database", passingCheck, true)
+	registry.Register("messagebus", failingCheck, false)
+
+	code, response := serveHealth(t, registry.Handler("test-service", time.Second))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusDegraded, response.Status)
+	assert.Equal(t, HealthStatusDown, response.Checks["messagebus"].Status)
+	assert.False(t, response.Checks["messagebus"].Critical)
+}
+
+func TestHealthRegistry_Timeout(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, true)
+
+	start := time.Now()
+	code, response := serveHealth(t, registry.Handler("test-service", 20*time.Millisecond))
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, response.Checks["slow"].Message, "timed out")
+}
+
+func TestHealthRegistry_Panic(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("broken", func(ctx context.Context) error {
+		panic("boom")
+	}, true)
+
+	code, response := serveHealth(t, registry.Handler("test-service", time.Second))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, response.Checks["broken"].Message, "boom")
+}
+
+func TestHealthRegistry_NoChecks(t *testing.T) {
+	code, response := serveHealth(t, NewHealthRegistry().Handler("test-service", time.Second))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusUp, response.Status)
+	assert.Empty(t, response.Checks)
+}
+
+func TestHealthRegistry_Unregister(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("b", passingCheck, true)
+	registry.Register("a", failingCheck, true)
+	assert.Equal(t, []string{"a", "b"}, registry.Names())
+
+	registry.Unregister("a")
+	assert.Equal(t, []string{"b"}, registry.Names())
+	assert.Equal(t, HealthStatusUp, registry.Run(context.Background()).Status)
+}
+
+func TestAddCommonRoutes_Health(t *testing.T) {
+	RegisterHealthCheck("test-dependency", failingCheck)
+	defer DefaultHealthRegistry().Unregister("test-dependency")
+
+	router := mux.NewRouter()
+	AddCommonRoutes(router, "test-service", "1.0.0", nil)
+
+	req, err := http.NewRequest("GET", common.ApiHealthRoute, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var response healthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, HealthStatusDown, response.Checks["test-dependency"].Status)
+}
+
+func TestHealthRegistry_Ready(t *testing.T) {
+	registry := NewHealthRegistry()
+	registry.Register("database", passingCheck, true)
+	handler := registry.ReadyHandler("test-service", time.Second)
+
+	// Not ready while the handlers are initializing, with the checks still reported
+	code, response := serveHealth(t, handler)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusDown, response.Status)
+	assert.Equal(t, HealthStatusDown, response.Checks[StartupCheckName].Status)
+	assert.Equal(t, HealthStatusUp, response.Checks["database"].Status)
+
+	registry.MarkStarted()
+	code, response = serveHealth(t, handler)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusUp, response.Status)
+	assert.NotContains(t, response.Checks, StartupCheckName)
+
+	registry.Register("messagebus", failingCheck, true)
+	code, response = serveHealth(t, handler)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "connection refused", response.Checks["messagebus"].Message)
+
+	registry.Register("messagebus", failingCheck, false)
+	code, response = serveHealth(t, handler)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusDegraded, response.Status)
+}
+
+func TestAddCommonRoutes_Ready(t *testing.T) {
+	router := mux.NewRouter()
+	AddCommonRoutes(router, "test-service", "1.0.0", nil)
+
+	// Ping is liveness and answers even though the service has not finished starting
+	for path, expected := range map[string]int{
+		common.ApiPingRoute:  http.StatusOK,
+		common.ApiReadyRoute: http.StatusServiceUnavailable,
+	} {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, expected, rr.Code, path)
+	}
+}
+
+func TestHealthRegistry_RequireStarted(t *testing.T) {
+	registry := NewHealthRegistry()
+	router := mux.NewRouter()
+	router.HandleFunc(common.ApiPingRoute, func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	router.HandleFunc(common.ApiReadyRoute, func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	router.HandleFunc("/api/v3/event/all", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	router.Use(registry.RequireStarted())
+
+	serveCode := func(path string) int {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Only the probes answer while the handlers initialize
+	assert.Equal(t, http.StatusOK, serveCode(common.ApiPingRoute))
+	assert.Equal(t, http.StatusOK, serveCode(common.ApiReadyRoute))
+	assert.Equal(t, http.StatusServiceUnavailable, serveCode("/api/v3/event/all"))
+
+	registry.MarkStarted()
+	assert.Equal(t, http.StatusOK, serveCode("/api/v3/event/all"))
+}
@@ -0,0 +1,217 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
+)
+
+// contextKey is the type for values this package stores in a request context
+type contextKey string
+
+const correlationIDKey contextKey = "correlationID"
+
+// ApplyMiddleware registers the standard EdgeX middleware on the router.
+// The logger is taken from the DI container, falling back to the logrus standard logger, and the
+// access log and panic recovery can each be disabled by the MiddlewareConfig of the configuration there.
+func ApplyMiddleware(router *mux.Router, dic *DIContainer) {
+	logger, err := GetLoggingClient(dic)
+	if err != nil {
+		logger = logrus.StandardLogger()
+	}
+	var config MiddlewareConfig
+	if configuration, err := GetConfiguration(dic); err == nil {
+		config = configuration.BaseConfiguration().Middleware
+	}
+
+	// Correlation runs first so later middleware can read the id from the context,
+	// and recovery runs inside logging so recovered panics are logged as 500s
+	router.Use(CorrelationIDMiddleware)
+	if !config.DisableAccessLog {
+		router.Use(LoggingMiddleware(logger))
+	}
+	if !config.DisableRecovery {
+		router.Use(RecoveryMiddleware(logger))
+	}
+}
+
+// CorrelationIDMiddleware propagates the X-Correlation-ID header, generating one when the caller didn't send it.
+// The id is stored in the request context and echoed on the response.
+func CorrelationIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID := r.Header.Get(common.CorrelationHeader)
+		if correlationID == "" {
+			correlationID = models.GenerateUUID()
+		}
+
+		w.Header().Set(common.CorrelationHeader, correlationID)
+		next.ServeHTTP(w, r.WithContext(WithCorrelationID(r.Context(), correlationID)))
+	})
+}
+
+// WithCorrelationID returns a copy of ctx carrying the correlation id, for work that does not start from a request
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation id of the request, or an empty string if there is none
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDKey).(string)
+	return correlationID
+}
+
+// LoggingMiddleware writes one access log entry per request
+func LoggingMiddleware(logger *logrus.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(recorder, r)
+
+			logger.WithFields(logrus.Fields{
+				"method":        r.Method,
+				"path":          r.URL.Path,
+				"status":        recorder.status,
+				"size":          recorder.size,
+				"duration":      time.Since(start).String(),
+				"correlationId": CorrelationIDFromContext(r.Context()),
+			}).Info("HTTP request")
+		})
+	}
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code and body size
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it on
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of body bytes written
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+// Unwrap exposes the underlying writer so http.ResponseController can flush streaming responses
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// LimitRequestBody returns middleware capping request bodies at maxBytes, or DefaultMaxRequestBodySize
+// when maxBytes isn't positive. Bodies declared larger are refused with 413 straight away; others are
+// cut off while being read, which common.DecodeJSON reports as 413.
+func LimitRequestBody(maxBytes int64) mux.MiddlewareFunc {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxRequestBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				common.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytes))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireContentType returns middleware refusing POST, PUT and PATCH requests whose body is not declared
+// as one of mediaTypes with 415 Unsupported Media Type. Parameters such as charset are ignored, and
+// requests without a body are let through.
+func RequireContentType(mediaTypes ...string) mux.MiddlewareFunc {
+	supported := strings.Join(mediaTypes, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasBody(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get(common.ContentType))
+			if err == nil {
+				for _, allowed := range mediaTypes {
+					if strings.EqualFold(mediaType, allowed) {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+
+			message := fmt.Sprintf("Content-Type must be one of: %s", supported)
+			common.WriteError(w, http.StatusUnsupportedMediaType, message)
+		})
+	}
+}
+
+// hasBody reports whether a write request carries a body. Chunked requests of unknown length count as having one.
+func hasBody(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return r.ContentLength != 0
+	default:
+		return false
+	}
+}
+
+// RecoveryMiddleware converts a handler panic into a 500 JSON response instead of a dropped connection
+func RecoveryMiddleware(logger *logrus.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// http.ErrAbortHandler is the sanctioned way to abort a response; let net/http handle it
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.WithFields(logrus.Fields{
+					"method":        r.Method,
+					"path":          r.URL.Path,
+					"correlationId": CorrelationIDFromContext(r.Context()),
+					"stack":         string(debug.Stack()),
+				}).Errorf("Recovered from panic: %v", recovered)
+
+				w.Header().Set(common.ContentType, common.ContentTypeJSON)
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"apiVersion": common.ServiceVersion,
+					"statusCode": http.StatusInternalServerError,
+					"message":    "Internal server error",
+				})
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// newCorrelationRouter returns a router whose single route echoes the correlation id it saw in its context
+func newCorrelationRouter(seen *string) *mux.Router {
+	router := mux.NewRouter()
+	ApplyMiddleware(router, NewDIContainer())
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {
+		*seen = CorrelationIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	return router
+}
+
+func TestCorrelationIDMiddleware_PreservesHeader(t *testing.T) {
+	var seen string
+	router := newCorrelationRouter(&seen)
+
+	req, err := http.NewRequest("GET", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set(common.CorrelationHeader, "abc-123")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "abc-123", rr.Header().Get(common.CorrelationHeader))
+	assert.Equal(t, "abc-123", seen)
+}
+
+func TestCorrelationIDMiddleware_GeneratesWhenMissing(t *testing.T) {
+	var seen string
+	router := newCorrelationRouter(&seen)
+
+	req, err := http.NewRequest("GET", "/api/v3/ping", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	generated := rr.Header().Get(common.CorrelationHeader)
+	assert.Len(t, generated, 36)
+	assert.Equal(t, generated, seen)
+
+	// Each request gets its own id
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.NotEqual(t, generated, rr.Header().Get(common.CorrelationHeader))
+}
+
+func TestCorrelationIDFromContext_Empty(t *testing.T) {
+	assert.Equal(t, "", CorrelationIDFromContext(context.Background()))
+}
+
+func TestLoggingMiddleware_LogsRequest(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	dic := NewDIContainer()
+	dic.Add(common.LoggingClientName, logger)
+
+	router := mux.NewRouter()
+	ApplyMiddleware(router, dic)
+	router.HandleFunc("/api/v3/device/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Device not found", http.StatusNotFound)
+	}).Methods("GET")
+
+	req, err := http.NewRequest("GET", "/api/v3/device/name/missing", nil)
+	require.NoError(t, err)
+	req.Header.Set(common.CorrelationHeader, "abc-123")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Len(t, hook.AllEntries(), 1)
+	entry := hook.LastEntry()
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "GET", entry.Data["method"])
+	assert.Equal(t, "/api/v3/device/name/missing", entry.Data["path"])
+	assert.Equal(t, http.StatusNotFound, entry.Data["status"])
+	assert.Equal(t, rr.Body.Len(), entry.Data["size"])
+	assert.Equal(t, "abc-123", entry.Data["correlationId"])
+	assert.NotEmpty(t, entry.Data["duration"])
+}
+
+func TestRecoveryMiddleware_Panic(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	dic := NewDIContainer()
+	dic.Add(common.LoggingClientName, logger)
+
+	router := mux.NewRouter()
+	ApplyMiddleware(router, dic)
+	router.HandleFunc("/api/v3/boom", func(w http.ResponseWriter, r *http.Request) {
+		var device map[string]string
+		device["name"] = "nil map write"
+	}).Methods("GET")
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/api/v3/boom", nil)
+	require.NoError(t, err)
+	req.Header.Set(common.CorrelationHeader, "abc-123")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, common.ContentTypeJSON, resp.Header.Get(common.ContentType))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, common.ServiceVersion, body["apiVersion"])
+	assert.Equal(t, float64(http.StatusInternalServerError), body["statusCode"])
+	assert.NotEmpty(t, body["message"])
+
+	var panicEntry, accessEntry *logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		switch entry.Level {
+		case logrus.ErrorLevel:
+			panicEntry = entry
+		case logrus.InfoLevel:
+			accessEntry = entry
+		}
+	}
+	require.NotNil(t, panicEntry)
+	assert.Equal(t, "abc-123", panicEntry.Data["correlationId"])
+	assert.Contains(t, panicEntry.Data["stack"], "goroutine")
+	require.NotNil(t, accessEntry)
+	assert.Equal(t, http.StatusInternalServerError, accessEntry.Data["status"])
+}
+
+func TestApplyMiddleware_Disabled(t *testing.T) {
+	newRouter := func(config MiddlewareConfig) (*mux.Router, *test.Hook) {
+		logger, hook := test.NewNullLogger()
+		dic := NewDIContainer()
+		dic.Add(common.LoggingClientName, logger)
+		dic.Add(common.ConfigurationName, BaseConfig{Middleware: config})
+
+		router := mux.NewRouter()
+		ApplyMiddleware(router, dic)
+		router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+		router.HandleFunc("/api/v3/boom", func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}).Methods("GET")
+		return router, hook
+	}
+	request := func(path string) *http.Request {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		return req
+	}
+
+	router, hook := newRouter(MiddlewareConfig{DisableAccessLog: true})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, request("/api/v3/ping"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get(common.CorrelationHeader), "correlation ids are always set")
+	assert.Empty(t, hook.AllEntries())
+
+	// Without the access log, recovered panics are still logged
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, request("/api/v3/boom"))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+
+	router, hook = newRouter(MiddlewareConfig{DisableRecovery: true})
+	assert.PanicsWithValue(t, "boom", func() { router.ServeHTTP(httptest.NewRecorder(), request("/api/v3/boom")) })
+	router.ServeHTTP(httptest.NewRecorder(), request("/api/v3/ping"))
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Equal(t, "HTTP request", hook.LastEntry().Message)
+}
+
+func TestLimitRequestBody(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/device", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := common.DecodeJSON(w, r, &payload); err != nil {
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	router.Use(LimitRequestBody(64))
+
+	small := `{"name":"TestDevice"}`
+	large := `{"name":"` + strings.Repeat("x", 100) + `"}`
+
+	tests := []struct {
+		name         string
+		body         io.Reader
+		expectedCode int
+	}{
+		{"Within limit", strings.NewReader(small), http.StatusCreated},
+		{"Declared length over limit", strings.NewReader(large), http.StatusRequestEntityTooLarge},
+		// A reader of unknown length is only cut off while being decoded
+		{"Streamed body over limit", io.MultiReader(strings.NewReader(large)), http.StatusRequestEntityTooLarge},
+		{"Malformed", strings.NewReader("{"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/v3/device", tt.body)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusRequestEntityTooLarge {
+				var response common.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, "Request body exceeds 64 bytes", response.Message)
+			}
+		})
+	}
+}
+
+func TestRequireContentType(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/event", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST", "PUT", "GET")
+	router.Use(RequireContentType(common.ContentTypeJSON, common.ContentTypeCBOR))
+
+	tests := []struct {
+		name         string
+		method       string
+		contentType  string
+		body         string
+		expectedCode int
+	}{
+		{"JSON", "POST", common.ContentTypeJSON, `{}`, http.StatusCreated},
+		{"JSON with charset", "PUT", "application/json; charset=utf-8", `{}`, http.StatusCreated},
+		{"CBOR", "POST", common.ContentTypeCBOR, "\xa0", http.StatusCreated},
+		{"Missing", "POST", "", `{}`, http.StatusUnsupportedMediaType},
+		{"Form post", "POST", "application/x-www-form-urlencoded", "name=device", http.StatusUnsupportedMediaType},
+		{"Malformed", "PUT", "application/", `{}`, http.StatusUnsupportedMediaType},
+		{"Empty body", "POST", "", "", http.StatusCreated},
+		{"Read", "GET", "text/plain", "ignored", http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "/api/v3/event", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.contentType != "" {
+				req.Header.Set(common.ContentType, tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusUnsupportedMediaType {
+				var response common.ErrorResponse
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Contains(t, response.Message, common.ContentTypeJSON)
+			}
+		})
+	}
+}
+
+func TestAddCommonRoutes_Metrics(t *testing.T) {
+	router := mux.NewRouter()
+	AddCommonRoutes(router, "bootstrap-test", common.ServiceVersion, nil)
+
+	scrape := func() string {
+		req, err := http.NewRequest("GET", common.ApiMetricsRoute, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	req, err := http.NewRequest("GET", common.ApiPingRoute, nil)
+	require.NoError(t, err)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, scrape(), `edgex_http_requests_total{class="2xx",code="200",method="GET",route="/api/v3/ping",service="bootstrap-test"} 2`)
+}
@@ -0,0 +1,172 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// OpenAPIVersion is the OpenAPI specification version of generated documents
+const OpenAPIVersion = "3.0.3"
+
+// pathVariable matches a mux path variable, with an optional pattern, such as {id} or {id:[0-9]+}
+var pathVariable = regexp.MustCompile(`\{([^{}:]+)(?::[^{}]*)?\}`)
+
+// OpenAPIDocument is a minimal OpenAPI 3 document describing the routes of a service
+type OpenAPIDocument struct {
+	OpenAPI string                          `json:"openapi"`
+	Info    OpenAPIInfo                     `json:"info"`
+	Paths   map[string]map[string]Operation `json:"paths"`
+}
+
+// OpenAPIInfo identifies the described service
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Operation describes one method of a path
+type Operation struct {
+	Summary    string              `json:"summary,omitempty"`
+	Parameters []Parameter         `json:"parameters,omitempty"`
+	Responses  map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path variable of an operation
+type Parameter struct {
+	Name     string          `json:"name"`
+	In       string          `json:"in"`
+	Required bool            `json:"required"`
+	Schema   ParameterSchema `json:"schema"`
+}
+
+// ParameterSchema is the type of a parameter
+type ParameterSchema struct {
+	Type string `json:"type"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string `json:"description"`
+}
+
+// OperationRegistry holds the summaries services contribute to their API description
+type OperationRegistry struct {
+	summaries map[string]string
+	mutex     sync.RWMutex
+}
+
+// NewOperationRegistry creates an empty operation registry
+func NewOperationRegistry() *OperationRegistry {
+	return &OperationRegistry{
+		summaries: make(map[string]string),
+	}
+}
+
+// Describe sets the summary of the operation for method on the path template
+func (o *OperationRegistry) Describe(method, path, summary string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.summaries[operationKey(method, path)] = summary
+}
+
+// Summary returns the summary of an operation, or an empty string if it has none
+func (o *OperationRegistry) Summary(method, path string) string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.summaries[operationKey(method, path)]
+}
+
+// operationKey identifies an operation by its method and OpenAPI path
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + openAPIPath(path)
+}
+
+// BuildOpenAPIDocument walks the routes of router and describes every route that declares its methods.
+// Summaries come from operations; routes without one are listed without a summary.
+func BuildOpenAPIDocument(router *mux.Router, title, version string, operations *OperationRegistry) (OpenAPIDocument, error) {
+	document := OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    OpenAPIInfo{Title: title, Version: version},
+		Paths:   make(map[string]map[string]Operation),
+	}
+
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			// Routes matching on something other than a path have nothing to describe
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		path := openAPIPath(template)
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]Operation)
+		}
+		for _, method := range methods {
+			document.Paths[path][strings.ToLower(method)] = Operation{
+				Summary:    operations.Summary(method, template),
+				Parameters: pathParameters(template),
+				Responses:  map[string]Response{"default": {Description: "JSON response; errors use the standard error envelope"}},
+			}
+		}
+		return nil
+	})
+	return document, err
+}
+
+// openAPIPath converts a mux path template to OpenAPI syntax by dropping variable patterns
+func openAPIPath(template string) string {
+	return pathVariable.ReplaceAllString(template, "{$1}")
+}
+
+// pathParameters lists the variables of a mux path template as required string parameters
+func pathParameters(template string) []Parameter {
+	var parameters []Parameter
+	for _, match := range pathVariable.FindAllStringSubmatch(template, -1) {
+		parameters = append(parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   ParameterSchema{Type: "string"},
+		})
+	}
+	return parameters
+}
+
+// OpenAPIHandler serves the OpenAPI document of router. Routes are walked on every request,
+// so routes registered after the handler are included.
+func OpenAPIHandler(router *mux.Router, title, version string, operations *OperationRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		document, err := BuildOpenAPIDocument(router, title, version, operations)
+		if err != nil {
+			common.WriteError(w, http.StatusInternalServerError, "Failed to describe API: "+err.Error())
+			return
+		}
+
+		w.Header().Set(common.ContentType, common.ContentTypeJSON)
+		json.NewEncoder(w).Encode(document)
+	}
+}
+
+// defaultOperationRegistry backs the OpenAPI route registered by AddCommonRoutes
+var defaultOperationRegistry = NewOperationRegistry()
+
+// DescribeOperation adds a summary for an operation to the service API description
+func DescribeOperation(method, path, summary string) {
+	defaultOperationRegistry.Describe(method, path, summary)
+}
+
+// DefaultOperationRegistry returns the registry served by the common OpenAPI route
+func DefaultOperationRegistry() *OperationRegistry {
+	return defaultOperationRegistry
+}
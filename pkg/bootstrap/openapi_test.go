@@ -0,0 +1,59 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestBuildOpenAPIDocument(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/event", noopHandler).Methods("POST")
+	router.HandleFunc("/api/v3/event/id/{id:[0-9a-f-]+}", noopHandler).Methods("GET", "DELETE")
+	router.PathPrefix("/static").HandlerFunc(noopHandler)
+
+	operations := NewOperationRegistry()
+	operations.Describe("post", "/api/v3/event", "Add an event")
+	operations.Describe("GET", "/api/v3/event/id/{id}", "Get an event")
+
+	document, err := BuildOpenAPIDocument(router, "core-data", "3.0.0", operations)
+	require.NoError(t, err)
+
+	assert.Equal(t, OpenAPIVersion, document.OpenAPI)
+	assert.Equal(t, OpenAPIInfo{Title: "core-data", Version: "3.0.0"}, document.Info)
+	assert.Len(t, document.Paths, 2, "routes without methods are not described")
+
+	require.Contains(t, document.Paths["/api/v3/event"], "post")
+	assert.Equal(t, "Add an event", document.Paths["/api/v3/event"]["post"].Summary)
+	assert.Empty(t, document.Paths["/api/v3/event"]["post"].Parameters)
+
+	byId := document.Paths["/api/v3/event/id/{id}"]
+	require.Len(t, byId, 2)
+	assert.Equal(t, "Get an event", byId["get"].Summary)
+	assert.Empty(t, byId["delete"].Summary)
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: ParameterSchema{Type: "string"}}}, byId["delete"].Parameters)
+}
+
+func TestOpenAPIHandler_IncludesLaterRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/openapi", OpenAPIHandler(router, "test-service", "1.0.0", NewOperationRegistry())).Methods("GET")
+	router.HandleFunc("/api/v3/device", noopHandler).Methods("PUT")
+
+	req, err := http.NewRequest("GET", "/api/v3/openapi", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var document OpenAPIDocument
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &document))
+	assert.Contains(t, document.Paths["/api/v3/device"], "put")
+	assert.Contains(t, document.Paths["/api/v3/openapi"], "get")
+}
@@ -0,0 +1,311 @@
+package bootstrap
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// Values accepted by RateLimitConfig.KeyBy
+const (
+	RateLimitKeyByIP            = "ip"
+	RateLimitKeyByCorrelationID = "correlationId"
+)
+
+// rateLimitIdleTimeout is how long a keyed bucket may go unused before it is forgotten
+const rateLimitIdleTimeout = 10 * time.Minute
+
+// DefaultRateLimitMaxKeys is how many keys get a bucket of their own when RateLimitConfig.MaxKeys is zero
+const DefaultRateLimitMaxKeys = 10000
+
+// RateLimitKeyFunc returns the key a request is rate limited under
+type RateLimitKeyFunc func(r *http.Request) string
+
+// EnableRateLimit throttles the routes of the router according to config.
+// It does nothing when no request rate is configured. Bootstrap calls it after ApplyMiddleware, so
+// rejected requests carry a correlation id and are access logged.
+func EnableRateLimit(router *mux.Router, config RateLimitConfig) error {
+	if !config.Enabled() {
+		return nil
+	}
+	keyFunc, err := rateLimitKeyFunc(config)
+	if err != nil {
+		return err
+	}
+	router.Use(RateLimitRoutes(config, keyFunc))
+	return nil
+}
+
+// rateLimitKeyFunc returns the key function RateLimitConfig.KeyBy names
+func rateLimitKeyFunc(config RateLimitConfig) (RateLimitKeyFunc, error) {
+	switch config.KeyBy {
+	case RateLimitKeyByIP:
+		if len(config.TrustedProxies) == 0 {
+			return ClientIPKey, nil
+		}
+		trustedProxies, err := ParseTrustedProxies(config.TrustedProxies)
+		if err != nil {
+			return nil, err
+		}
+		return ForwardedClientIPKey(trustedProxies), nil
+	case RateLimitKeyByCorrelationID:
+		return CorrelationIDKey, nil
+	}
+	return sharedKey, nil
+}
+
+// sharedKey puts every request in the same bucket
+func sharedKey(r *http.Request) string {
+	return ""
+}
+
+// RateLimit returns middleware allowing rps requests per second across all callers, with bursts of up to burst requests.
+// Requests over the limit get 429 Too Many Requests with a Retry-After header.
+func RateLimit(rps float64, burst int) mux.MiddlewareFunc {
+	return RateLimitBy(rps, burst, sharedKey)
+}
+
+// RateLimitBy is like RateLimit but keeps a separate token bucket for each key returned by keyFunc,
+// for up to DefaultRateLimitMaxKeys keys
+func RateLimitBy(rps float64, burst int, keyFunc RateLimitKeyFunc) mux.MiddlewareFunc {
+	return RateLimitRoutes(RateLimitConfig{RequestsPerSecond: rps, Burst: burst}, keyFunc)
+}
+
+// RateLimitRoutes is like RateLimitBy, with the global limit and the per-route limits of config.
+// Each route has buckets of its own, so e.g. a flood of writes leaves the reads' limit untouched.
+func RateLimitRoutes(config RateLimitConfig, keyFunc RateLimitKeyFunc) mux.MiddlewareFunc {
+	routes := make([]routeLimiters, 0, len(config.Routes))
+	for _, route := range config.Routes {
+		methods := make([]string, 0, len(route.Methods))
+		for _, method := range route.Methods {
+			methods = append(methods, strings.ToUpper(method))
+		}
+		routes = append(routes, routeLimiters{
+			pathPrefix: route.PathPrefix,
+			methods:    methods,
+			limiters:   newKeyedLimiters(route.RequestsPerSecond, route.Burst, config.MaxKeys),
+		})
+	}
+	global := newKeyedLimiters(config.RequestsPerSecond, config.Burst, config.MaxKeys)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiters := global
+			for i := range routes {
+				if routes[i].matches(r) {
+					limiters = routes[i].limiters
+					break
+				}
+			}
+
+			if limiters != nil && !limiters.allow(w, keyFunc(r), time.Now()) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeLimiters are the buckets of one RouteRateLimitConfig; nil limiters mean the route is not limited
+type routeLimiters struct {
+	pathPrefix string
+	methods    []string
+	limiters   *keyedLimiters
+}
+
+// matches reports whether the route's limit applies to r
+func (l routeLimiters) matches(r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, l.pathPrefix) {
+		return false
+	}
+	if len(l.methods) == 0 {
+		return true
+	}
+	for _, method := range l.methods {
+		if method == r.Method {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIPKey keys requests by the IP address of the connection they arrived on. X-Forwarded-For is
+// ignored, since any caller can set it; see ForwardedClientIPKey for services behind a proxy.
+func ClientIPKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ParseTrustedProxies parses proxy addresses and CIDR ranges, e.g. "10.0.0.1" or "10.0.0.0/8"
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ForwardedClientIPKey keys requests like ClientIPKey, except that requests arriving from one of
+// trustedProxies are keyed by the X-Forwarded-For entry nearest to the proxy that is not itself a
+// trusted proxy. Entries further left were written by the caller and are never used.
+func ForwardedClientIPKey(trustedProxies []netip.Prefix) RateLimitKeyFunc {
+	trusted := func(ip string) bool {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(r *http.Request) string {
+		client := ClientIPKey(r)
+		if !trusted(client) {
+			return client
+		}
+		// Each proxy appends the address it was reached from, so walk the entries from the right
+		values := r.Header.Values("X-Forwarded-For")
+		for i := len(values) - 1; i >= 0; i-- {
+			entries := values[i]
+			for entries != "" {
+				var entry string
+				if comma := strings.LastIndexByte(entries, ','); comma >= 0 {
+					entries, entry = entries[:comma], entries[comma+1:]
+				} else {
+					entries, entry = "", entries
+				}
+				entry = strings.TrimSpace(entry)
+				if entry == "" {
+					continue
+				}
+				client = entry
+				if !trusted(entry) {
+					return client
+				}
+			}
+		}
+		return client
+	}
+}
+
+// CorrelationIDKey keys requests by the correlation id the caller sent. Callers that sent none share
+// a bucket, rather than each request getting its own under the id CorrelationIDMiddleware generated.
+func CorrelationIDKey(r *http.Request) string {
+	return r.Header.Get(common.CorrelationHeader)
+}
+
+// bucket is the token bucket of one key
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// keyedLimiters holds a token bucket per key, forgetting buckets that have been idle for a while.
+// Once maxKeys keys have buckets, new keys share the overflow bucket, so a caller cycling through
+// keys can't exhaust memory.
+type keyedLimiters struct {
+	limit     rate.Limit
+	burst     int
+	maxKeys   int
+	buckets   map[string]*bucket
+	overflow  *rate.Limiter
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+// newKeyedLimiters returns buckets allowing rps requests per second with bursts of up to burst for up
+// to maxKeys keys, or nil if rps is not positive. A maxKeys of zero means DefaultRateLimitMaxKeys.
+func newKeyedLimiters(rps float64, burst int, maxKeys int) *keyedLimiters {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	if maxKeys <= 0 {
+		maxKeys = DefaultRateLimitMaxKeys
+	}
+	return &keyedLimiters{
+		limit:     rate.Limit(rps),
+		burst:     burst,
+		maxKeys:   maxKeys,
+		buckets:   make(map[string]*bucket),
+		overflow:  rate.NewLimiter(rate.Limit(rps), burst),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow takes a token from key's bucket, or else answers 429 with a Retry-After header and returns false.
+// Allowed requests don't allocate; a rejected one reserves a token only to learn when the next is due.
+func (k *keyedLimiters) allow(w http.ResponseWriter, key string, now time.Time) bool {
+	limiter := k.get(key, now)
+	if limiter.AllowN(now, 1) {
+		return true
+	}
+
+	// Give the token back; this request is rejected rather than delayed
+	reservation := limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	common.WriteError(w, http.StatusTooManyRequests, "Too many requests")
+	return false
+}
+
+// get returns the limiter for key, creating it on first use while there is room for another
+func (k *keyedLimiters) get(key string, now time.Time) *rate.Limiter {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if now.Sub(k.lastSweep) > rateLimitIdleTimeout {
+		for name, b := range k.buckets {
+			if now.Sub(b.lastSeen) > rateLimitIdleTimeout {
+				delete(k.buckets, name)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	b, exists := k.buckets[key]
+	if !exists {
+		if len(k.buckets) >= k.maxKeys {
+			return k.overflow
+		}
+		b = &bucket{limiter: rate.NewLimiter(k.limit, k.burst)}
+		k.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
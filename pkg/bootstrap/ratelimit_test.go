@@ -0,0 +1,240 @@
+package bootstrap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+func newRateLimitedRouter(middleware mux.MiddlewareFunc) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}).Methods("GET")
+	router.Use(middleware)
+	return router
+}
+
+func sendPing(router *mux.Router, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/api/v3/ping", nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimit_RejectsPastBurst(t *testing.T) {
+	router := newRateLimitedRouter(RateLimit(1, 3))
+
+	for i := 0; i < 3; i++ {
+		rr := sendPing(router, "10.0.0.1:1234", nil)
+		require.Equal(t, http.StatusOK, rr.Code, "request %d", i)
+	}
+
+	rr := sendPing(router, "10.0.0.1:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, retryAfter)
+
+	// The limit is shared, so a different caller is throttled too
+	rr = sendPing(router, "10.0.0.2:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestRateLimit_RejectedRequestsDoNotConsumeTokens(t *testing.T) {
+	// One token every 100ms; rejected requests must not push the next token further out
+	router := newRateLimitedRouter(RateLimit(10, 1))
+
+	require.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", nil).Code)
+	for i := 0; i < 20; i++ {
+		require.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", nil).Code)
+	}
+
+	assert.Eventually(t, func() bool {
+		return sendPing(router, "10.0.0.1:1234", nil).Code == http.StatusOK
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestRateLimitBy_ClientIP(t *testing.T) {
+	router := newRateLimitedRouter(RateLimitBy(1, 2, ClientIPKey))
+
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", nil).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:5678", nil).Code)
+
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.2:1234", nil).Code)
+
+	// A caller can't escape its limit by claiming to be someone else
+	forwarded := map[string]string{"X-Forwarded-For": "192.168.1.7"}
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", forwarded).Code)
+}
+
+func TestForwardedClientIPKey(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.1", "172.16.0.0/12"})
+	require.NoError(t, err)
+	keyFunc := ForwardedClientIPKey(trustedProxies)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{"Direct caller", "192.168.1.7:1234", nil, "192.168.1.7"},
+		{"Untrusted caller's header ignored", "192.168.1.7:1234", []string{"203.0.113.9"}, "192.168.1.7"},
+		{"Trusted proxy", "10.0.0.1:1234", []string{"203.0.113.9"}, "203.0.113.9"},
+		{"Proxy without header", "10.0.0.1:1234", nil, "10.0.0.1"},
+		{"Spoofed entries left of the client ignored", "10.0.0.1:1234", []string{"1.2.3.4, 203.0.113.9"}, "203.0.113.9"},
+		{"Chain of trusted proxies", "10.0.0.1:1234", []string{"1.2.3.4, 203.0.113.9, 172.16.4.2"}, "203.0.113.9"},
+		{"Header appended as a second line", "10.0.0.1:1234", []string{"1.2.3.4", "203.0.113.9"}, "203.0.113.9"},
+		{"Only trusted proxies", "10.0.0.1:1234", []string{"172.16.4.2"}, "172.16.4.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v3/ping", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			assert.Equal(t, tt.expected, keyFunc(req))
+		})
+	}
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
+
+func TestEnableRateLimit_TrustedProxies(t *testing.T) {
+	config := RateLimitConfig{RequestsPerSecond: 1, Burst: 1, KeyBy: RateLimitKeyByIP, TrustedProxies: []string{"10.0.0.1"}}
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	require.NoError(t, EnableRateLimit(router, config))
+
+	first := map[string]string{"X-Forwarded-For": "192.168.1.7"}
+	second := map[string]string{"X-Forwarded-For": "192.168.1.8"}
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", first).Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", first).Code)
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", second).Code)
+
+	config.TrustedProxies = []string{"not-an-address"}
+	assert.Error(t, EnableRateLimit(mux.NewRouter(), config))
+}
+
+func TestRateLimitRoutes_MaxKeys(t *testing.T) {
+	router := newRateLimitedRouter(RateLimitRoutes(RateLimitConfig{RequestsPerSecond: 1, Burst: 1, MaxKeys: 2}, ClientIPKey))
+
+	// The first two callers get buckets of their own...
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", nil).Code)
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.2:1234", nil).Code)
+
+	// ...and the rest share one, so fresh addresses can't add buckets or dodge the limit
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.3:1234", nil).Code)
+	for i := 4; i < 20; i++ {
+		assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0."+strconv.Itoa(i)+":1234", nil).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", nil).Code)
+}
+
+func TestRateLimitBy_CorrelationID(t *testing.T) {
+	router := newRateLimitedRouter(RateLimitBy(1, 1, CorrelationIDKey))
+	first := map[string]string{common.CorrelationHeader: "first"}
+	second := map[string]string{common.CorrelationHeader: "second"}
+
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", first).Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", first).Code)
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", second).Code)
+}
+
+func TestEnableRateLimit_DisabledByDefault(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	require.NoError(t, EnableRateLimit(router, NewBaseConfig(59880).RateLimit))
+
+	for i := 0; i < 100; i++ {
+		require.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", nil).Code)
+	}
+}
+
+func TestRateLimitRoutes_PerRoute(t *testing.T) {
+	config := RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             2,
+		Routes: []RouteRateLimitConfig{
+			{PathPrefix: "/api/v3/event", Methods: []string{"post"}, RequestsPerSecond: 10, Burst: 1},
+			{PathPrefix: common.ApiPingRoute},
+		},
+	}
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/api/v3/event", ok).Methods("GET", "POST")
+	router.HandleFunc(common.ApiPingRoute, ok).Methods("GET")
+	require.NoError(t, EnableRateLimit(router, config))
+
+	send := func(method, path string) int {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, path, nil))
+		return rr.Code
+	}
+
+	// Writes have their own bucket, so using it up leaves reads alone
+	require.Equal(t, http.StatusOK, send("POST", "/api/v3/event"))
+	assert.Equal(t, http.StatusTooManyRequests, send("POST", "/api/v3/event"))
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, send("GET", "/api/v3/event"), "read %d", i)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/api/v3/event"))
+
+	// A route with no rate is exempt from the global limit
+	for i := 0; i < 20; i++ {
+		require.Equal(t, http.StatusOK, send("GET", common.ApiPingRoute))
+	}
+
+	// Writes recover once their 100ms window has passed
+	assert.Eventually(t, func() bool {
+		return send("POST", "/api/v3/event") == http.StatusOK
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestEnableRateLimit_RoutesOnly(t *testing.T) {
+	config := NewBaseConfig(59880).RateLimit
+	config.Routes = []RouteRateLimitConfig{{PathPrefix: "/api/v3/ping", RequestsPerSecond: 1, Burst: 1}}
+	require.True(t, config.Enabled())
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v3/ping", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	require.NoError(t, EnableRateLimit(router, config))
+
+	assert.Equal(t, http.StatusOK, sendPing(router, "10.0.0.1:1234", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendPing(router, "10.0.0.1:1234", nil).Code)
+}
+
+func TestRateLimit_AllowedRequestsDoNotAllocate(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	handler := RateLimitBy(1e9, 1e6, ForwardedClientIPKey(trustedProxies))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest("GET", "/api/v3/ping", nil)
+	req.RemoteAddr = "10.0.0.2:1234"
+	req.Header.Set("X-Forwarded-For", "192.168.1.7, 10.0.0.1")
+	rr := httptest.NewRecorder()
+
+	allocs := testing.AllocsPerRun(100, func() { handler.ServeHTTP(rr, req) })
+	assert.Zero(t, allocs)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
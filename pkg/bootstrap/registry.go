@@ -0,0 +1,125 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/registry"
+)
+
+// Backoff between attempts to register with the registry: the first retry waits
+// DefaultRegistryRetryInterval, each one after twice as long, up to MaxRegistryRetryInterval
+const (
+	DefaultRegistryRetryInterval = time.Second
+	MaxRegistryRetryInterval     = time.Minute
+)
+
+// RegistryHandler registers the service with the registry when it starts and deregisters it on
+// shutdown. Registration is retried in the background until it succeeds, so an unreachable
+// registry never stops the service from starting.
+type RegistryHandler struct {
+	client       registry.RegistryClient
+	registration registry.ServiceRegistration
+	logger       *logrus.Logger
+	clock        common.Clock
+	registered   chan struct{} // closed once registered
+}
+
+// NewRegistryHandler returns a handler registering the service described by registration with client
+func NewRegistryHandler(client registry.RegistryClient, registration registry.ServiceRegistration, logger *logrus.Logger) *RegistryHandler {
+	return &RegistryHandler{
+		client:       client,
+		registration: registration,
+		logger:       logger,
+		clock:        common.RealClock{},
+		registered:   make(chan struct{}),
+	}
+}
+
+// SetClock sets the clock that paces retries. Must be called before Initialize.
+func (h *RegistryHandler) SetClock(clock common.Clock) {
+	h.clock = clock
+}
+
+// Registered is closed once the service is registered
+func (h *RegistryHandler) Registered() <-chan struct{} {
+	return h.registered
+}
+
+// Initialize implements the BootstrapHandler interface. The registry client is added to the
+// DIContainer under common.RegistryClientName straight away, so services can discover each other
+// while their own registration is still being retried.
+func (h *RegistryHandler) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *DIContainer) bool {
+	dic.Add(common.RegistryClientName, h.client)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if !h.register(ctx) {
+			return
+		}
+		<-ctx.Done()
+		if err := h.client.Deregister(h.registration.ServiceID); err != nil {
+			h.logger.Warnf("Failed to deregister %s from the registry: %v", h.registration.ServiceID, err)
+		}
+	}()
+	return true
+}
+
+// register retries registering the service, backing off between attempts, until it succeeds or ctx
+// is cancelled. It reports whether the service was registered.
+func (h *RegistryHandler) register(ctx context.Context) bool {
+	wait := DefaultRegistryRetryInterval
+	for {
+		err := h.client.Register(h.registration)
+		if err == nil {
+			h.logger.Infof("Registered %s with the registry", h.registration.ServiceID)
+			close(h.registered)
+			return true
+		}
+		h.logger.Warnf("Failed to register %s with the registry, retrying in %s: %v", h.registration.ServiceID, wait, err)
+
+		timer := h.clock.NewTimer(wait)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+		wait *= 2
+		if wait > MaxRegistryRetryInterval {
+			wait = MaxRegistryRetryInterval
+		}
+	}
+}
+
+// newRegistryHandler returns the handler registering the service with the registry in config, or
+// nil if config names no registry host
+func newRegistryHandler(serviceInfo ServiceInfo, config BaseConfig, logger *logrus.Logger) (*RegistryHandler, error) {
+	if config.Registry.Host == "" {
+		return nil, nil
+	}
+	address := fmt.Sprintf("%s:%d", config.Registry.Host, config.Registry.Port)
+	client, err := registry.NewConsulRegistryClient(address, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(serviceInfo.Port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service port %q: %w", serviceInfo.Port, err)
+	}
+	scheme := "http"
+	if config.Service.TLS.Enabled() {
+		scheme = "https"
+	}
+	healthCheckURL := fmt.Sprintf("%s://%s:%d%s", scheme, config.Service.Host, port, common.ApiPingRoute)
+	registration := registry.CreateServiceRegistration(serviceInfo.ServiceName, serviceInfo.ServiceName, config.Service.Host, port, healthCheckURL)
+	return NewRegistryHandler(client, registration, logger), nil
+}
@@ -0,0 +1,144 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/registry"
+)
+
+// fakeRegistryClient fails the first failures registrations and records the rest
+type fakeRegistryClient struct {
+	mutex        sync.Mutex
+	failures     int
+	attempts     int
+	registered   []registry.ServiceRegistration
+	deregistered []string
+}
+
+func (f *fakeRegistryClient) Register(service registry.ServiceRegistration) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.attempts++
+	if f.attempts <= f.failures {
+		return errors.New("registry unreachable")
+	}
+	f.registered = append(f.registered, service)
+	return nil
+}
+
+func (f *fakeRegistryClient) Deregister(serviceID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.deregistered = append(f.deregistered, serviceID)
+	return nil
+}
+
+func (f *fakeRegistryClient) GetService(string) ([]registry.ServiceEndpoint, error) { return nil, nil }
+func (f *fakeRegistryClient) GetAllServices() (map[string][]registry.ServiceEndpoint, error) {
+	return nil, nil
+}
+func (f *fakeRegistryClient) IsServiceAvailable(string) bool { return false }
+func (f *fakeRegistryClient) WatchService(string, registry.ServiceChangeCallback) error {
+	return nil
+}
+
+func (f *fakeRegistryClient) counts() (attempts int, deregistered []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.attempts, append([]string(nil), f.deregistered...)
+}
+
+func TestRegistryHandler_RetriesWithBackoff(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := &fakeRegistryClient{failures: 8}
+	logger, _ := test.NewNullLogger()
+	registration := registry.CreateServiceRegistration("core-data", "core-data", "localhost", 59880, "http://localhost:59880"+common.ApiPingRoute)
+	handler := NewRegistryHandler(client, registration, logger)
+	handler.SetClock(clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	dic := NewDIContainer()
+	require.True(t, handler.Initialize(ctx, &wg, dic), "a failing registry does not stop the service")
+
+	registryClient, ok := GetAs[registry.RegistryClient](dic, common.RegistryClientName)
+	require.True(t, ok)
+	assert.Same(t, client, registryClient)
+
+	// Each retry waits twice as long as the last, up to the maximum
+	for i, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, time.Minute, time.Minute} {
+		clock.BlockUntil(1)
+		clock.Advance(wait - time.Millisecond)
+		attempts, _ := client.counts()
+		assert.Equal(t, i+1, attempts, "retry %d came early", i+1)
+		clock.Advance(time.Millisecond)
+	}
+	select {
+	case <-handler.Registered():
+	case <-time.After(time.Second):
+		t.Fatal("service was not registered")
+	}
+	assert.Equal(t, []registry.ServiceRegistration{registration}, client.registered)
+
+	// Shutting down deregisters the service
+	cancel()
+	wg.Wait()
+	_, deregistered := client.counts()
+	assert.Equal(t, []string{"core-data"}, deregistered)
+}
+
+func TestRegistryHandler_ShutdownWhileRetrying(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := &fakeRegistryClient{failures: 1000}
+	logger, _ := test.NewNullLogger()
+	handler := NewRegistryHandler(client, registry.ServiceRegistration{ServiceID: "core-data"}, logger)
+	handler.SetClock(clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	require.True(t, handler.Initialize(ctx, &wg, NewDIContainer()))
+	clock.BlockUntil(1)
+
+	cancel()
+	wg.Wait()
+	attempts, deregistered := client.counts()
+	assert.Equal(t, 1, attempts)
+	assert.Empty(t, deregistered, "a service never registered is not deregistered")
+	assert.Zero(t, clock.Waiters(), "the retry timer is stopped")
+}
+
+func TestNewRegistryHandler(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	serviceInfo := ServiceInfo{ServiceName: common.CoreDataServiceKey, Port: "59880"}
+
+	config := NewBaseConfig(59880)
+	config.Service.Host = "core-data"
+	handler, err := newRegistryHandler(serviceInfo, config, logger)
+	require.NoError(t, err)
+	require.NotNil(t, handler)
+	assert.Equal(t, common.CoreDataServiceKey, handler.registration.ServiceID)
+	assert.Equal(t, "core-data", handler.registration.Host)
+	assert.Equal(t, 59880, handler.registration.Port)
+	assert.Equal(t, "http://core-data:59880"+common.ApiPingRoute, handler.registration.Check.HTTP)
+
+	// Services serving HTTPS are health-checked over it
+	config.Service.TLS = TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}
+	handler, err = newRegistryHandler(serviceInfo, config, logger)
+	require.NoError(t, err)
+	assert.Equal(t, "https://core-data:59880"+common.ApiPingRoute, handler.registration.Check.HTTP)
+
+	// Without a registry host the service is not registered
+	config.Registry.Host = ""
+	handler, err = newRegistryHandler(serviceInfo, config, logger)
+	require.NoError(t, err)
+	assert.Nil(t, handler)
+}
@@ -0,0 +1,113 @@
+package bootstrap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// Server timeouts used when the configuration does not set them. Slow clients are cut off rather than
+// holding connections open indefinitely; long-lived responses such as event streams lift the write
+// deadline themselves.
+const (
+	DefaultReadTimeout  = 30 * time.Second
+	DefaultWriteTimeout = time.Minute
+	DefaultIdleTimeout  = 2 * time.Minute
+)
+
+// Keys of the PEM certificate and private key in the secret at TLSConfig.SecretPath
+const (
+	TLSCertKey = "cert"
+	TLSKeyKey  = "key"
+)
+
+// newHTTPServer returns the server for the service, listening on port of config's BindHost. It
+// serves HTTPS when config enables TLS, in which case the certificate is loaded straight away so a
+// missing or invalid one stops the service from starting.
+func newHTTPServer(config ServiceConfig, port string, handler http.Handler, secretsClient secrets.SecretsClient) (*http.Server, error) {
+	server := &http.Server{
+		Addr:         net.JoinHostPort(config.BindHost, port),
+		Handler:      handler,
+		ReadTimeout:  orDefault(config.ReadTimeout, DefaultReadTimeout),
+		WriteTimeout: orDefault(config.WriteTimeout, DefaultWriteTimeout),
+		IdleTimeout:  orDefault(config.IdleTimeout, DefaultIdleTimeout),
+	}
+	if !config.TLS.Enabled() {
+		return server, nil
+	}
+
+	tlsConfig, err := loadTLSConfig(config.TLS, secretsClient)
+	if err != nil {
+		return nil, err
+	}
+	server.TLSConfig = tlsConfig
+	return server, nil
+}
+
+// orDefault returns timeout, or fallback if timeout is not positive
+func orDefault(timeout, fallback time.Duration) time.Duration {
+	if timeout <= 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// loadTLSConfig loads the certificate config names, from its files or else its secret
+func loadTLSConfig(config TLSConfig, secretsClient secrets.SecretsClient) (*tls.Config, error) {
+	minVersion, err := tlsVersion(config.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificate tls.Certificate
+	switch {
+	case config.CertFile != "" || config.KeyFile != "":
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, errors.New("TLS needs both a CertFile and a KeyFile")
+		}
+		if certificate, err = tls.LoadX509KeyPair(config.CertFile, config.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate %s: %w", config.CertFile, err)
+		}
+	default:
+		if secretsClient == nil {
+			return nil, fmt.Errorf("TLS certificate secret %s needs a secrets client", config.SecretPath)
+		}
+		secret, err := secretsClient.GetSecret(config.SecretPath, TLSCertKey, TLSKeyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS certificate secret %s: %w", config.SecretPath, err)
+		}
+		if certificate, err = tls.X509KeyPair([]byte(secret[TLSCertKey]), []byte(secret[TLSKeyKey])); err != nil {
+			return nil, fmt.Errorf("invalid TLS certificate in secret %s: %w", config.SecretPath, err)
+		}
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   minVersion,
+	}, nil
+}
+
+// tlsVersion parses a TLSConfig.MinVersion
+func tlsVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS MinVersion %q, must be 1.2 or 1.3", version)
+	}
+}
+
+// serve runs server until it is shut down, over HTTPS if it has a TLS configuration
+func serve(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+	return server.ListenAndServe()
+}
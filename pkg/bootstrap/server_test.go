@@ -0,0 +1,151 @@
+package bootstrap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
+)
+
+// selfSignedPEM returns a PEM certificate for 127.0.0.1 and its private key
+func selfSignedPEM(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "edgex-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeCertificate writes a self-signed certificate and key to files and returns their paths
+func writeCertificate(t *testing.T) (string, string) {
+	certPEM, keyPEM := selfSignedPEM(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	return certFile, keyFile
+}
+
+func TestNewHTTPServer_Plain(t *testing.T) {
+	server, err := newHTTPServer(ServiceConfig{}, "59880", http.NotFoundHandler(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, ":59880", server.Addr)
+	assert.Nil(t, server.TLSConfig)
+	assert.Equal(t, DefaultReadTimeout, server.ReadTimeout)
+	assert.Equal(t, DefaultWriteTimeout, server.WriteTimeout)
+	assert.Equal(t, DefaultIdleTimeout, server.IdleTimeout)
+
+	server, err = newHTTPServer(ServiceConfig{
+		BindHost:     "127.0.0.1",
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  time.Minute,
+	}, "59880", http.NotFoundHandler(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:59880", server.Addr)
+	assert.Equal(t, 5*time.Second, server.ReadTimeout)
+	assert.Equal(t, 10*time.Second, server.WriteTimeout)
+	assert.Equal(t, time.Minute, server.IdleTimeout)
+}
+
+func TestNewHTTPServer_TLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	config := ServiceConfig{BindHost: "127.0.0.1", TLS: TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"}}
+	server, err := newHTTPServer(config, "0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), nil)
+	require.NoError(t, err)
+	require.NotNil(t, server.TLSConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), server.TLSConfig.MinVersion)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.ServeTLS(listener, "", "")
+	defer server.Close()
+
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	resp, err := client.Get("https://" + listener.Addr().String() + "/api/v3/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, uint16(tls.VersionTLS13), resp.TLS.Version)
+
+	// Clients limited to older versions are refused
+	oldClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, MaxVersion: tls.VersionTLS12}}}
+	_, err = oldClient.Get("https://" + listener.Addr().String() + "/api/v3/ping")
+	assert.Error(t, err)
+}
+
+func TestNewHTTPServer_TLSFromSecret(t *testing.T) {
+	certPEM, keyPEM := selfSignedPEM(t)
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, secretsClient.StoreSecret("edgex/tls", map[string]string{TLSCertKey: string(certPEM), TLSKeyKey: string(keyPEM)}))
+
+	server, err := newHTTPServer(ServiceConfig{TLS: TLSConfig{SecretPath: "edgex/tls"}}, "59880", http.NotFoundHandler(), secretsClient)
+	require.NoError(t, err)
+	require.NotNil(t, server.TLSConfig)
+	assert.Len(t, server.TLSConfig.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS12), server.TLSConfig.MinVersion)
+}
+
+func TestNewHTTPServer_InvalidTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	garbage := filepath.Join(t.TempDir(), "garbage.pem")
+	require.NoError(t, os.WriteFile(garbage, []byte("not a certificate"), 0o600))
+	secretsClient := secrets.NewInMemorySecretsClient(logrus.New())
+	require.NoError(t, secretsClient.StoreSecret("edgex/bad-tls", map[string]string{TLSCertKey: "x", TLSKeyKey: "y"}))
+
+	tests := []struct {
+		name          string
+		config        TLSConfig
+		secretsClient secrets.SecretsClient
+		expectError   string
+	}{
+		{"Missing cert file", TLSConfig{CertFile: "/nonexistent/cert.pem", KeyFile: keyFile}, nil, "/nonexistent/cert.pem"},
+		{"Invalid cert file", TLSConfig{CertFile: garbage, KeyFile: keyFile}, nil, "failed to load TLS certificate"},
+		{"Key without cert", TLSConfig{KeyFile: keyFile}, nil, "both a CertFile and a KeyFile"},
+		{"Bad min version", TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.0"}, nil, `invalid TLS MinVersion "1.0"`},
+		{"Secret without client", TLSConfig{SecretPath: "edgex/tls"}, nil, "needs a secrets client"},
+		{"Missing secret", TLSConfig{SecretPath: "edgex/tls"}, secretsClient, "failed to read TLS certificate secret"},
+		{"Invalid secret", TLSConfig{SecretPath: "edgex/bad-tls"}, secretsClient, "invalid TLS certificate in secret edgex/bad-tls"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newHTTPServer(ServiceConfig{TLS: tt.config}, "59880", http.NotFoundHandler(), tt.secretsClient)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
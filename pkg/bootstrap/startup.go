@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,13 +15,35 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/metrics"
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/secrets"
 )
 
+// DefaultShutdownTimeout is how long in-flight requests and workers get to finish when ServiceInfo does not say
+const DefaultShutdownTimeout = 30 * time.Second
+
+// DefaultMaxRequestBodySize is the largest request body accepted when ServiceInfo does not say, 4 MiB
+const DefaultMaxRequestBodySize int64 = 4 << 20
+
 // ServiceInfo contains service identification information
 type ServiceInfo struct {
 	ServiceName    string
 	ServiceVersion string
 	Port           string
+	// ShutdownTimeout bounds how long in-flight requests may drain on shutdown; zero means DefaultShutdownTimeout
+	ShutdownTimeout time.Duration
+	// MaxRequestBodySize caps request bodies in bytes; zero means DefaultMaxRequestBodySize
+	MaxRequestBodySize int64
+	// ContentTypes lists the media types write requests may send; empty means JSON only
+	ContentTypes []string
+	// Config is the loaded service configuration, added to the DIContainer under common.ConfigurationName.
+	// Its Service section sets where and how the HTTP server listens.
+	Config Configuration
+	// SecretsClient is where a TLS certificate named by a TLSConfig.SecretPath is read from
+	SecretsClient secrets.SecretsClient
+	// Middleware is installed on every route after the standard middleware and rate limiting, so the
+	// requests it rejects, such as with 401, are access logged with their correlation id
+	Middleware []mux.MiddlewareFunc
 }
 
 // BootstrapHandler interface for service initialization
@@ -28,31 +51,11 @@ type BootstrapHandler interface {
 	Initialize(ctx context.Context, wg *sync.WaitGroup, dic *DIContainer) bool
 }
 
-// DIContainer provides dependency injection
-type DIContainer struct {
-	services map[string]interface{}
-	mutex    sync.RWMutex
-}
-
-// NewDIContainer creates a new dependency injection container
-func NewDIContainer() *DIContainer {
-	return &DIContainer{
-		services: make(map[string]interface{}),
-	}
-}
-
-// Add adds a service to the container
-func (c *DIContainer) Add(name string, service interface{}) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	c.services[name] = service
-}
-
-// Get retrieves a service from the container
-func (c *DIContainer) Get(name string) interface{} {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	return c.services[name]
+// ShutdownHandler is implemented by BootstrapHandlers that need to stop or flush their work on
+// graceful shutdown. Bootstrap calls Shutdown once in-flight requests have drained, before waiting
+// for the workers started in Initialize; it should return once its work has stopped or ctx is done.
+type ShutdownHandler interface {
+	Shutdown(ctx context.Context) error
 }
 
 // Bootstrap starts the EdgeX service with proper lifecycle management
@@ -69,32 +72,60 @@ func Bootstrap(
 	
 	dic := NewDIContainer()
 	dic.Add(common.LoggingClientName, logger)
+	dic.Add(common.HealthRegistryName, defaultHealthRegistry)
+	if serviceInfo.Config != nil {
+		dic.Add(common.ConfigurationName, serviceInfo.Config)
+	}
+
+	// Set up the HTTP server first, so a bad TLS certificate stops the service before anything starts
+	var serverConfig ServiceConfig
+	var corsConfig CORSConfig
+	if serviceInfo.Config != nil {
+		serverConfig = serviceInfo.Config.BaseConfiguration().Service
+		corsConfig = serviceInfo.Config.BaseConfiguration().CORS
+	}
+	server, err := newHTTPServer(serverConfig, serviceInfo.Port, withCORS(router, corsConfig), serviceInfo.SecretsClient)
+	if err != nil {
+		logger.Errorf("Failed to set up the HTTP server: %v", err)
+		os.Exit(1)
+	}
 
 	var wg sync.WaitGroup
 
-	// Initialize all bootstrap handlers
-	for _, handler := range handlers {
-		if !handler.Initialize(ctx, &wg, dic) {
-			logger.Error("Failed to initialize bootstrap handler")
-			os.Exit(1)
+	// Register with the configured registry first, so the other handlers find its client
+	if serviceInfo.Config != nil {
+		registryHandler, err := newRegistryHandler(serviceInfo, serviceInfo.Config.BaseConfiguration(), logger)
+		if err != nil {
+			logger.Errorf("Not registering with the registry: %v", err)
+		} else if registryHandler != nil {
+			handlers = append([]BootstrapHandler{registryHandler}, handlers...)
 		}
 	}
 
-	// Setup HTTP server
-	server := &http.Server{
-		Addr:    ":" + serviceInfo.Port,
-		Handler: router,
+	if err := ConfigureRouter(router, dic, serviceInfo); err != nil {
+		logger.Errorf("Failed to set up the router: %v", err)
+		os.Exit(1)
 	}
 
-	// Start HTTP server in goroutine
+	// Start HTTP server in goroutine, so the service answers liveness probes while the handlers initialize;
+	// other routes answer 503 until they have
 	go func() {
-		logger.Infof("Starting %s service on port %s", serviceInfo.ServiceName, serviceInfo.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Infof("Starting %s service on %s (TLS %t)", serviceInfo.ServiceName, server.Addr, server.TLSConfig != nil)
+		if err := serve(server); err != nil && err != http.ErrServerClosed {
 			logger.Errorf("HTTP server error: %v", err)
 			cancel()
 		}
 	}()
 
+	// Initialize all bootstrap handlers, then report ready
+	for _, handler := range handlers {
+		if !handler.Initialize(ctx, &wg, dic) {
+			logger.Error("Failed to initialize bootstrap handler")
+			os.Exit(1)
+		}
+	}
+	defaultHealthRegistry.MarkStarted()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -106,12 +137,63 @@ func Bootstrap(
 		logger.Info("Context cancelled")
 	}
 
-	// Graceful shutdown
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := serviceInfo.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdown(server, cancel, handlers, &wg, timeout, logger)
+
+	logger.Infof("%s service stopped", serviceInfo.ServiceName)
+}
+
+// ConfigureRouter installs the middleware Bootstrap serves every route with: the standard middleware,
+// the startup gate and the configured rate limiting, then serviceInfo.Middleware and the request body
+// checks. Running after the standard middleware, the requests the others reject are logged with their
+// correlation id.
+func ConfigureRouter(router *mux.Router, dic *DIContainer, serviceInfo ServiceInfo) error {
+	ApplyMiddleware(router, dic)
+	router.Use(defaultHealthRegistry.RequireStarted())
+	if serviceInfo.Config != nil {
+		if err := EnableRateLimit(router, serviceInfo.Config.BaseConfiguration().RateLimit); err != nil {
+			return fmt.Errorf("failed to set up rate limiting: %w", err)
+		}
+	}
+	router.Use(serviceInfo.Middleware...)
+
+	contentTypes := serviceInfo.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{common.ContentTypeJSON}
+	}
+	router.Use(LimitRequestBody(serviceInfo.MaxRequestBodySize), RequireContentType(contentTypes...))
+	return nil
+}
+
+// shutdown stops background workers by cancelling their context, then drains in-flight requests and
+// shuts the handlers down in reverse order. Requests still running after timeout are cut off, as are
+// handler shutdowns and workers that have not returned by then.
+func shutdown(server *http.Server, cancel context.CancelFunc, handlers []BootstrapHandler, wg *sync.WaitGroup, timeout time.Duration, logger *logrus.Logger) {
+	// Workers were started with this context in Initialize; stop them first so nothing new begins while draining
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), timeout)
 	defer shutdownCancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Errorf("Server forced to shutdown: %v", err)
+		logger.Warnf("In-flight requests did not finish within %v, forcing connections closed: %v", timeout, err)
+		server.Close()
+	}
+
+	// Later handlers may depend on earlier ones, so they are shut down first
+	for i := len(handlers) - 1; i >= 0; i-- {
+		shutdownHandler, ok := handlers[i].(ShutdownHandler)
+		if !ok {
+			continue
+		}
+		handlerCtx, handlerCancel := context.WithTimeout(context.Background(), timeout)
+		if err := shutdownHandler.Shutdown(handlerCtx); err != nil {
+			logger.Errorf("Failed to shut down %T: %v", handlers[i], err)
+		}
+		handlerCancel()
 	}
 
 	// Wait for all goroutines to finish
@@ -124,15 +206,27 @@ func Bootstrap(
 	select {
 	case <-done:
 		logger.Info("All goroutines finished")
-	case <-time.After(30 * time.Second):
+	case <-time.After(timeout):
 		logger.Warn("Timeout waiting for goroutines to finish")
 	}
-
-	logger.Infof("%s service stopped", serviceInfo.ServiceName)
 }
 
-// AddCommonRoutes adds standard EdgeX routes to the router
-func AddCommonRoutes(router *mux.Router, serviceName string, serviceVersion string) {
+// AddCommonRoutes adds standard EdgeX routes to the router. config is the loaded service
+// configuration served, redacted, on the config route.
+func AddCommonRoutes(router *mux.Router, serviceName string, serviceVersion string, config interface{}) {
+	// Record HTTP metrics for every route and expose them for scraping
+	router.Use(metrics.Middleware(serviceName))
+	router.Handle(common.ApiMetricsRoute, metrics.Handler()).Methods("GET")
+
+	// Aggregate the dependency checks services contribute via RegisterHealthCheck
+	router.HandleFunc(common.ApiHealthRoute, defaultHealthRegistry.Handler(serviceName, DefaultHealthCheckTimeout)).Methods("GET")
+
+	// Readiness holds off traffic until the bootstrap handlers are up and the checks pass; ping is liveness
+	router.HandleFunc(common.ApiReadyRoute, defaultHealthRegistry.ReadyHandler(serviceName, DefaultHealthCheckTimeout)).Methods("GET")
+
+	// Describe every registered route, enriched by the summaries services contribute via DescribeOperation
+	router.HandleFunc(common.ApiOpenAPIRoute, OpenAPIHandler(router, serviceName, serviceVersion, defaultOperationRegistry)).Methods("GET")
+
 	router.HandleFunc(common.ApiPingRoute, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -155,8 +249,10 @@ func AddCommonRoutes(router *mux.Router, serviceName string, serviceVersion stri
 	router.HandleFunc(common.ApiConfigRoute, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{
-			"config": "Configuration endpoint for %s"
-		}`, serviceName)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"apiVersion":  serviceVersion,
+			"serviceName": serviceName,
+			"config":      RedactConfig(config),
+		})
 	}).Methods("GET")
 }
\ No newline at end of file
@@ -0,0 +1,166 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startTestServer serves handler on a random local port until the test ends
+func startTestServer(t *testing.T, handler http.Handler) (*http.Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return server, "http://" + listener.Addr().String()
+}
+
+// hasWarning reports whether hook captured a warning containing text
+func hasWarning(hook *test.Hook, text string) bool {
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && strings.Contains(entry.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestShutdown_ForcesCloseAfterTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server, url := startTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+
+	go http.Get(url)
+	<-started
+
+	logger, hook := test.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	shutdown(server, cancel, nil, &wg, 50*time.Millisecond, logger)
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Error(t, ctx.Err())
+	assert.True(t, hasWarning(hook, "forcing connections closed"))
+}
+
+func TestShutdown_DrainsRequestsAndStopsWorkers(t *testing.T) {
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	server, url := startTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	}))
+
+	go http.Get(url)
+	<-started
+
+	logger, hook := test.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// A worker that only stops once its context is cancelled
+	var wg sync.WaitGroup
+	workerStopped := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		close(workerStopped)
+	}()
+
+	shutdown(server, cancel, nil, &wg, time.Second, logger)
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("in-flight request was not allowed to finish")
+	}
+	select {
+	case <-workerStopped:
+	default:
+		t.Fatal("worker was not stopped")
+	}
+	assert.False(t, hasWarning(hook, "forcing connections closed"))
+}
+
+// shutdownRecorder is a handler with a Shutdown hook that records it was called and returns err
+type shutdownRecorder struct {
+	name  string
+	calls *[]string
+	err   error
+	block bool // wait for the shutdown context to be done
+}
+
+func (h shutdownRecorder) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *DIContainer) bool {
+	return true
+}
+
+func (h shutdownRecorder) Shutdown(ctx context.Context) error {
+	*h.calls = append(*h.calls, h.name)
+	if h.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return h.err
+}
+
+// initOnlyHandler is a handler without a Shutdown hook
+type initOnlyHandler struct{}
+
+func (initOnlyHandler) Initialize(ctx context.Context, wg *sync.WaitGroup, dic *DIContainer) bool {
+	return true
+}
+
+func TestShutdown_CallsHandlersInReverseOrder(t *testing.T) {
+	server, _ := startTestServer(t, http.NotFoundHandler())
+	logger, hook := test.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls []string
+	handlers := []BootstrapHandler{
+		shutdownRecorder{name: "registry", calls: &calls},
+		initOnlyHandler{},
+		shutdownRecorder{name: "store", calls: &calls, err: errors.New("flush failed")},
+		shutdownRecorder{name: "generators", calls: &calls, block: true},
+	}
+	var wg sync.WaitGroup
+
+	start := time.Now()
+	shutdown(server, cancel, handlers, &wg, 50*time.Millisecond, logger)
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Error(t, ctx.Err())
+	assert.Equal(t, []string{"generators", "store", "registry"}, calls)
+
+	var errorsLogged []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.ErrorLevel {
+			errorsLogged = append(errorsLogged, entry.Message)
+		}
+	}
+	require.Len(t, errorsLogged, 2)
+	assert.Contains(t, errorsLogged[0], "context deadline exceeded")
+	assert.Contains(t, errorsLogged[1], "flush failed")
+}
@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit states
+const (
+	stateClosed   = "closed"
+	stateOpen     = "open"
+	stateHalfOpen = "half-open"
+)
+
+// breaker is the circuit breaker of one host. It opens after threshold calls in a row fail and rejects
+// calls until openTimeout has passed, then lets a single probe through: if it succeeds the circuit
+// closes, and if it fails the circuit opens again.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	state       string
+	failures    int
+	openedAt    time.Time
+	probing     bool
+	mutex       sync.Mutex
+}
+
+// allow reports whether a call may be sent now
+func (b *breaker) allow(now time.Time) bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if now.Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = stateHalfOpen
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// succeeded records a call the host answered, closing the circuit
+func (b *breaker) succeeded() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.state = stateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failed records a call the host failed, opening the circuit once there are threshold in a row or a probe fails
+func (b *breaker) failed(now time.Time) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == stateHalfOpen || b.failures >= b.threshold {
+		b.state = stateOpen
+		b.openedAt = now
+	}
+}
+
+// released records a call that ended without telling whether the host works, freeing the probe slot
+func (b *breaker) released() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
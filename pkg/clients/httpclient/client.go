@@ -0,0 +1,209 @@
+// Package httpclient provides the HTTP client services use to call each other: every attempt is bounded by a
+// timeout, failed attempts are retried with exponential backoff, and a circuit breaker per host stops calling
+// a service that keeps failing until it has had time to recover.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// Defaults used by DefaultConfig
+const (
+	DefaultTimeout          = 10 * time.Second
+	DefaultMaxRetries       = 2
+	DefaultInitialBackoff   = 100 * time.Millisecond
+	DefaultMaxBackoff       = 5 * time.Second
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+// Config controls a Client
+type Config struct {
+	// Timeout bounds each attempt, including reading the response body; zero means no timeout
+	Timeout time.Duration
+	// MaxRetries is how many times a failed attempt is retried; zero disables retries
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, doubling for each later one up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// FailureThreshold is how many calls to a host must fail in a row to open its circuit; zero disables the breaker
+	FailureThreshold int
+	// OpenTimeout is how long an open circuit rejects calls before letting one through to probe the host
+	OpenTimeout time.Duration
+}
+
+// DefaultConfig returns the configuration for typical inter-service calls
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          DefaultTimeout,
+		MaxRetries:       DefaultMaxRetries,
+		InitialBackoff:   DefaultInitialBackoff,
+		MaxBackoff:       DefaultMaxBackoff,
+		FailureThreshold: DefaultFailureThreshold,
+		OpenTimeout:      DefaultOpenTimeout,
+	}
+}
+
+// Client sends requests to other services. It is safe for concurrent use.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	clock      common.Clock
+	breakers   map[string]*breaker
+	mutex      sync.Mutex
+}
+
+// NewClient creates a client with the given configuration
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		clock:      common.RealClock{},
+		breakers:   make(map[string]*breaker),
+	}
+}
+
+// SetClock replaces the clock that times backoffs and open circuits. Must be called before the client is used.
+func (c *Client) SetClock(clock common.Clock) {
+	c.clock = clock
+}
+
+// Do sends req with ctx, retrying connection failures, timeouts and 429/5xx responses. A request with a
+// body is only retried if it can be replayed, as it can when created by http.NewRequest from a bytes or
+// strings reader. A 2xx response is returned for the caller to read and close; any other outcome is an
+// *Error and the response, if any, has already been closed.
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	breaker := c.breaker(req.URL.Host)
+	if !breaker.allow(c.clock.Now()) {
+		return nil, newError(KindCircuitOpen, req, 0, 0, ErrCircuitOpen)
+	}
+
+	attempts := 0
+	for {
+		attempts++
+		resp, err := c.attempt(ctx, req, attempts)
+		if err == nil {
+			breaker.succeeded()
+			return resp, nil
+		}
+
+		failure := err.(*Error)
+		if ctx.Err() != nil {
+			// The caller gave up, which says nothing about the host
+			breaker.released()
+			return nil, failure
+		}
+		if !failure.retryable() || attempts > c.config.MaxRetries || !replayable(req) {
+			c.record(breaker, failure)
+			return nil, failure
+		}
+
+		timer := c.clock.NewTimer(c.backoff(attempts))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			c.record(breaker, failure)
+			return nil, c.contextError(ctx, req, attempts)
+		case <-timer.C():
+		}
+	}
+}
+
+// attempt sends req once, turning anything but a 2xx response into an *Error
+func (c *Client) attempt(ctx context.Context, req *http.Request, attempts int) (*http.Response, error) {
+	attemptReq := req.Clone(ctx)
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, newError(KindConnection, req, 0, attempts, err)
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := c.httpClient.Do(attemptReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, c.contextError(ctx, req, attempts)
+		}
+		if isTimeout(err) {
+			return nil, newError(KindTimeout, req, 0, attempts, err)
+		}
+		return nil, newError(KindConnection, req, 0, attempts, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, newError(KindStatus, req, resp.StatusCode, attempts, fmt.Errorf("unexpected status %s", resp.Status))
+	}
+	return resp, nil
+}
+
+// contextError describes a call cut short by ctx
+func (c *Client) contextError(ctx context.Context, req *http.Request, attempts int) *Error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return newError(KindTimeout, req, 0, attempts, ctx.Err())
+	}
+	return newError(KindCanceled, req, 0, attempts, ctx.Err())
+}
+
+// backoff returns how long to wait before the given retry, counting from 1
+func (c *Client) backoff(retry int) time.Duration {
+	delay := c.config.InitialBackoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if c.config.MaxBackoff > 0 && delay >= c.config.MaxBackoff {
+			return c.config.MaxBackoff
+		}
+	}
+	if c.config.MaxBackoff > 0 && delay > c.config.MaxBackoff {
+		return c.config.MaxBackoff
+	}
+	return delay
+}
+
+// record counts a failed call against the host's circuit. Only failures of the host count:
+// 4xx responses other than 429 mean the request was wrong, and cancellation is the caller's doing.
+func (c *Client) record(breaker *breaker, failure *Error) {
+	switch {
+	case failure.Kind == KindCanceled:
+		breaker.released()
+	case !failure.retryable():
+		breaker.succeeded()
+	default:
+		breaker.failed(c.clock.Now())
+	}
+}
+
+// breaker returns the circuit breaker of host, creating it on first use
+func (c *Client) breaker(host string) *breaker {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	b, exists := c.breakers[host]
+	if !exists {
+		b = &breaker{threshold: c.config.FailureThreshold, openTimeout: c.config.OpenTimeout, state: stateClosed}
+		c.breakers[host] = b
+	}
+	return b
+}
+
+// replayable reports whether req can be sent again
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isTimeout reports whether err is a request timing out
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
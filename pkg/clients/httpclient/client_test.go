@@ -0,0 +1,261 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
+)
+
+// fastConfig retries quickly and leaves the breaker off
+func fastConfig() Config {
+	return Config{
+		Timeout:        time.Second,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+// newStatusServer answers with the current value of status, counting the requests it receives
+func newStatusServer(t *testing.T, status *atomic.Int32) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// get sends a GET to url and returns the error, closing any response
+func get(t *testing.T, client *Client, url string) error {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(context.Background(), req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+// clientError asserts that err is an *Error and returns it
+func clientError(t *testing.T, err error) *Error {
+	t.Helper()
+	var clientErr *Error
+	require.True(t, errors.As(err, &clientErr), "expected *Error, got %v", err)
+	return clientErr
+}
+
+func TestClient_RetriesThenSucceeds(t *testing.T) {
+	var hits atomic.Int32
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if hits.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	client := NewClient(fastConfig())
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"action":"run"}`))
+	require.NoError(t, err)
+	req.Header.Set(common.ContentType, common.ContentTypeJSON)
+
+	resp, err := client.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "done", string(body))
+	assert.Equal(t, int32(3), hits.Load())
+	// Every attempt carried the whole body
+	assert.Equal(t, []string{`{"action":"run"}`, `{"action":"run"}`, `{"action":"run"}`}, bodies)
+}
+
+func TestClient_StatusErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           int
+		expectedAttempts int32
+	}{
+		{"Server error retried until exhausted", http.StatusInternalServerError, 3},
+		{"Too many requests retried", http.StatusTooManyRequests, 3},
+		{"Client error not retried", http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status atomic.Int32
+			status.Store(int32(tt.status))
+			server, hits := newStatusServer(t, &status)
+
+			failure := clientError(t, get(t, NewClient(fastConfig()), server.URL))
+			assert.Equal(t, KindStatus, failure.Kind)
+			assert.Equal(t, tt.status, failure.StatusCode)
+			assert.Equal(t, int(tt.expectedAttempts), failure.Attempts)
+			assert.Equal(t, tt.expectedAttempts, hits.Load())
+			assert.Equal(t, http.MethodGet, failure.Method)
+		})
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := fastConfig()
+	config.Timeout = 20 * time.Millisecond
+	config.MaxRetries = 1
+
+	start := time.Now()
+	failure := clientError(t, get(t, NewClient(config), server.URL))
+	assert.Equal(t, KindTimeout, failure.Kind)
+	assert.Equal(t, 2, failure.Attempts)
+	assert.Zero(t, failure.StatusCode)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	address := server.URL
+	server.Close()
+
+	err := get(t, NewClient(fastConfig()), address)
+	failure := clientError(t, err)
+	assert.Equal(t, KindConnection, failure.Kind)
+	assert.Equal(t, 3, failure.Attempts)
+	assert.Equal(t, KindConnection, KindOf(err))
+	assert.Equal(t, ErrorKind(""), KindOf(errors.New("other")))
+}
+
+func TestClient_Canceled(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server, hits := newStatusServer(t, &status)
+
+	config := fastConfig()
+	config.InitialBackoff = time.Hour
+	config.FailureThreshold = 1
+	client := NewClient(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	go func() {
+		for hits.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	_, err = client.Do(ctx, req)
+	failure := clientError(t, err)
+	assert.Equal(t, KindCanceled, failure.Kind)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	failing, failingHits := newStatusServer(t, &status)
+	var healthyStatus atomic.Int32
+	healthyStatus.Store(http.StatusOK)
+	healthy, _ := newStatusServer(t, &healthyStatus)
+
+	clock := common.NewFakeClock(time.Now())
+	client := NewClient(Config{Timeout: time.Second, FailureThreshold: 2, OpenTimeout: time.Minute})
+	client.SetClock(clock)
+
+	// Two failures in a row open the circuit, which then rejects calls without sending them
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, KindStatus, KindOf(get(t, client, failing.URL)))
+	}
+	err := get(t, client, failing.URL)
+	assert.Equal(t, KindCircuitOpen, KindOf(err))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), failingHits.Load())
+
+	// Other hosts have their own circuit
+	assert.NoError(t, get(t, client, healthy.URL))
+
+	// After the open timeout a failed probe opens the circuit again straight away
+	clock.Advance(time.Minute)
+	assert.Equal(t, KindStatus, KindOf(get(t, client, failing.URL)))
+	assert.Equal(t, KindCircuitOpen, KindOf(get(t, client, failing.URL)))
+	assert.Equal(t, int32(3), failingHits.Load())
+
+	// A successful probe closes it
+	status.Store(http.StatusOK)
+	clock.Advance(time.Minute)
+	assert.NoError(t, get(t, client, failing.URL))
+	assert.NoError(t, get(t, client, failing.URL))
+	assert.Equal(t, int32(5), failingHits.Load())
+
+	// A client error means the host is up, so it doesn't count towards opening the circuit
+	status.Store(http.StatusBadRequest)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, KindStatus, KindOf(get(t, client, failing.URL)))
+	}
+	assert.Equal(t, int32(8), failingHits.Load())
+}
+
+func TestClient_HalfOpenAllowsOneProbe(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		entered <- struct{}{}
+		<-release
+	}))
+	defer server.Close()
+
+	clock := common.NewFakeClock(time.Now())
+	client := NewClient(Config{Timeout: 5 * time.Second, FailureThreshold: 1, OpenTimeout: time.Minute})
+	client.SetClock(clock)
+
+	assert.Equal(t, KindStatus, KindOf(get(t, client, server.URL)))
+	failing.Store(false)
+	clock.Advance(time.Minute)
+
+	probe := make(chan error, 1)
+	go func() { probe <- get(t, client, server.URL) }()
+	<-entered
+
+	// While the probe is in flight the circuit stays closed to everyone else
+	assert.Equal(t, KindCircuitOpen, KindOf(get(t, client, server.URL)))
+
+	close(release)
+	assert.NoError(t, <-probe)
+	go func() { <-entered }()
+	assert.NoError(t, get(t, client, server.URL))
+}
@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorKind says why a call failed
+type ErrorKind string
+
+// Kinds of Error
+const (
+	// KindTimeout means an attempt, or the caller's context, ran out of time
+	KindTimeout ErrorKind = "timeout"
+	// KindConnection means no response was received, e.g. the connection was refused
+	KindConnection ErrorKind = "connection"
+	// KindStatus means the service answered with a status other than 2xx
+	KindStatus ErrorKind = "status"
+	// KindCircuitOpen means the call was not sent because the host's circuit is open
+	KindCircuitOpen ErrorKind = "circuit open"
+	// KindCanceled means the caller's context was canceled
+	KindCanceled ErrorKind = "canceled"
+)
+
+// ErrCircuitOpen is the cause of a KindCircuitOpen error
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Error is returned by Client.Do for every failed call
+type Error struct {
+	Kind   ErrorKind
+	Method string
+	URL    string
+	// StatusCode is the status of the last response, or zero if none was received
+	StatusCode int
+	// Attempts is how many times the request was sent
+	Attempts int
+	Err      error
+}
+
+// newError describes a failed call of req
+func newError(kind ErrorKind, req *http.Request, statusCode, attempts int, err error) *Error {
+	return &Error{
+		Kind:       kind,
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+		StatusCode: statusCode,
+		Attempts:   attempts,
+		Err:        err,
+	}
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s failed (%s) after %d attempt(s): %v", e.Method, e.URL, e.Kind, e.Attempts, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// retryable reports whether the call may succeed if tried again
+func (e *Error) retryable() bool {
+	switch e.Kind {
+	case KindTimeout, KindConnection:
+		return true
+	case KindStatus:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	}
+	return false
+}
+
+// KindOf returns the kind of err if it is, or wraps, an *Error, and an empty kind otherwise
+func KindOf(err error) ErrorKind {
+	var clientErr *Error
+	if errors.As(err, &clientErr) {
+		return clientErr.Kind
+	}
+	return ""
+}
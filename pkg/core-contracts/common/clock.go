@@ -0,0 +1,207 @@
+package common
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for code that reads the time or waits on it. Services use RealClock
+// unless a test swaps in a FakeClock to drive timers and tickers without sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	NewTimer(d time.Duration) Timer
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker delivers the time on C at every period until stopped
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer delivers the time on C once, after its duration, unless stopped first
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// RealClock is the Clock backed by the time package
+type RealClock struct{}
+
+// Now returns the current time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker returns a ticker firing every d
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+// NewTimer returns a timer firing after d
+func (RealClock) NewTimer(d time.Duration) Timer {
+	return realTimer{timer: time.NewTimer(d)}
+}
+
+// After waits for d and then sends the current time on the returned channel
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t realTicker) Stop()               { t.ticker.Stop() }
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.timer.C }
+func (t realTimer) Stop() bool          { return t.timer.Stop() }
+
+// FakeClock is a Clock whose time only moves when Advance is called. Timers and tickers fire,
+// in deadline order, as Advance carries the clock past them. Like the time package, a ticker
+// whose receiver falls behind drops ticks rather than queueing them.
+type FakeClock struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// fakeWaiter is a pending timer or ticker on a FakeClock
+type fakeWaiter struct {
+	clock    *FakeClock
+	deadline time.Time
+	period   time.Duration // Zero for timers
+	ch       chan time.Time
+}
+
+// NewFakeClock creates a fake clock reading start
+func NewFakeClock(start time.Time) *FakeClock {
+	clock := &FakeClock{now: start}
+	clock.changed = sync.NewCond(&clock.mutex)
+	return clock
+}
+
+// Now returns the fake clock's current time
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker firing every d of fake time
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+	return fakeTicker{c.addWaiter(d, d)}
+}
+
+// NewTimer returns a timer firing once d of fake time has passed
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	return fakeTimer{c.addWaiter(d, 0)}
+}
+
+// After returns a channel receiving the fake time once d of it has passed
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that falls due on the way
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	end := c.now.Add(d)
+	for len(c.waiters) > 0 && !c.waiters[0].deadline.After(end) {
+		waiter := c.waiters[0]
+		if waiter.deadline.After(c.now) {
+			c.now = waiter.deadline
+		}
+		select {
+		case waiter.ch <- c.now:
+		default:
+		}
+		if waiter.period > 0 {
+			waiter.deadline = waiter.deadline.Add(waiter.period)
+			c.sortLocked()
+		} else {
+			c.waiters = c.waiters[1:]
+		}
+	}
+	c.now = end
+	c.changed.Broadcast()
+}
+
+// Waiters returns the number of timers and tickers waiting to fire
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are waiting to fire, so a test can
+// advance the clock knowing the goroutines under test have started waiting on it.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// addWaiter registers a timer or ticker due after d. One already due fires straight away.
+func (c *FakeClock) addWaiter(d, period time.Duration) *fakeWaiter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	waiter := &fakeWaiter{clock: c, deadline: c.now.Add(d), period: period, ch: make(chan time.Time, 1)}
+	if d <= 0 && period == 0 {
+		waiter.ch <- c.now
+		return waiter
+	}
+	c.waiters = append(c.waiters, waiter)
+	c.sortLocked()
+	c.changed.Broadcast()
+	return waiter
+}
+
+// remove stops a waiter, reporting whether it was still pending
+func (c *FakeClock) remove(waiter *fakeWaiter) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i, w := range c.waiters {
+		if w == waiter {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// sortLocked keeps waiters in deadline order. Caller must hold the lock.
+func (c *FakeClock) sortLocked() {
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+}
+
+type fakeTicker struct {
+	waiter *fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+func (t fakeTicker) Stop()               { t.waiter.clock.remove(t.waiter) }
+
+type fakeTimer struct {
+	waiter *fakeWaiter
+}
+
+func (t fakeTimer) C() <-chan time.Time { return t.waiter.ch }
+func (t fakeTimer) Stop() bool          { return t.waiter.clock.remove(t.waiter) }
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fakeStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// received returns what is waiting on ch without blocking
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock_Timer(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	_, fired := received(timer.C())
+	assert.False(t, fired, "fired early")
+
+	clock.Advance(time.Second)
+	at, fired := received(timer.C())
+	assert.True(t, fired)
+	assert.Equal(t, fakeStart.Add(time.Minute), at)
+	assert.Equal(t, fakeStart.Add(time.Minute), clock.Now())
+	assert.False(t, timer.Stop(), "stopping a fired timer")
+	assert.Zero(t, clock.Waiters())
+}
+
+func TestFakeClock_StoppedTimer(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	timer := clock.NewTimer(time.Minute)
+	assert.True(t, timer.Stop())
+
+	clock.Advance(time.Hour)
+	_, fired := received(timer.C())
+	assert.False(t, fired)
+}
+
+func TestFakeClock_After(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+
+	_, fired := received(clock.After(0))
+	assert.True(t, fired, "zero duration fires straight away")
+
+	ch := clock.After(time.Second)
+	clock.Advance(time.Second)
+	_, fired = received(ch)
+	assert.True(t, fired)
+}
+
+func TestFakeClock_Ticker(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	ticker := clock.NewTicker(10 * time.Second)
+
+	for i := 1; i <= 3; i++ {
+		clock.Advance(10 * time.Second)
+		at, fired := received(ticker.C())
+		assert.True(t, fired, "tick %d", i)
+		assert.Equal(t, fakeStart.Add(time.Duration(i)*10*time.Second), at)
+	}
+
+	// Ticks the receiver missed are dropped rather than queued
+	clock.Advance(time.Minute)
+	_, fired := received(ticker.C())
+	assert.True(t, fired)
+	_, fired = received(ticker.C())
+	assert.False(t, fired)
+
+	ticker.Stop()
+	clock.Advance(time.Minute)
+	_, fired = received(ticker.C())
+	assert.False(t, fired, "stopped ticker fired")
+}
+
+func TestFakeClock_FiresInDeadlineOrder(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	late := clock.NewTimer(2 * time.Second)
+	early := clock.NewTimer(time.Second)
+
+	clock.Advance(5 * time.Second)
+	earlyAt, _ := received(early.C())
+	lateAt, _ := received(late.C())
+	assert.Equal(t, fakeStart.Add(time.Second), earlyAt)
+	assert.Equal(t, fakeStart.Add(2*time.Second), lateAt)
+	assert.Equal(t, fakeStart.Add(5*time.Second), clock.Now())
+}
+
+func TestFakeClock_BlockUntil(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-clock.After(time.Minute)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not released")
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	var clock Clock = RealClock{}
+	assert.WithinDuration(t, time.Now(), clock.Now(), time.Second)
+
+	ticker := clock.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("real ticker did not tick")
+	}
+}
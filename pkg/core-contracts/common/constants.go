@@ -17,14 +17,23 @@ const (
         ApiPingRoute     = ApiBase + "/ping"
         ApiVersionRoute  = ApiBase + "/version"
         ApiConfigRoute   = ApiBase + "/config"
+        ApiHealthRoute   = ApiBase + "/health"
+        ApiReadyRoute    = ApiBase + "/ready"
+        ApiMetricsRoute  = "/metrics"
+        ApiOpenAPIRoute  = ApiBase + "/openapi"
         
         // Core Data Routes
         ApiEventRoute               = ApiBase + "/event"
         ApiEventByIdRoute          = ApiBase + "/event/id/{id}"
         ApiEventByDeviceNameRoute  = ApiBase + "/event/device/name/{name}"
+        ApiEventByTimeRangeRoute   = ApiBase + "/event/start/{start}/end/{end}"
+        ApiEventStreamRoute        = ApiBase + "/event/stream"
+        ApiEventBatchRoute         = ApiBase + "/event/batch"
         ApiReadingRoute            = ApiBase + "/reading"
         ApiReadingByIdRoute        = ApiBase + "/reading/id/{id}"
         ApiReadingByDeviceNameRoute = ApiBase + "/reading/device/name/{name}"
+        ApiReadingByResourceNameRoute = ApiBase + "/reading/resourceName/{name}"
+        ApiReadingStatsByResourceNameRoute = ApiReadingByResourceNameRoute + "/stats"
         
         // Core Metadata Routes
         ApiDeviceRoute             = ApiBase + "/device"
@@ -36,17 +45,30 @@ const (
         ApiDeviceServiceRoute      = ApiBase + "/deviceservice"
         ApiDeviceServiceByIdRoute  = ApiBase + "/deviceservice/id/{id}"
         ApiDeviceServiceByNameRoute = ApiBase + "/deviceservice/name/{name}"
+        ApiDeviceCallbackRoute     = ApiBase + "/callback/device"
+        ApiDeviceLastConnectedRoute = ApiBase + "/device/name/{name}/lastconnected"
+        ApiDeviceOperatingStateRoute = ApiBase + "/device/name/{name}/operatingstate/{state}"
+        ApiDeviceLastReportedRoute = ApiBase + "/device/name/{name}/lastreported"
+        ApiDeviceStaleRoute        = ApiBase + "/device/stale"
+        ApiDeviceImportRoute       = ApiBase + "/device/import"
+        ApiDeviceExportRoute       = ApiBase + "/device/export"
         
         // Core Command Routes
         ApiDeviceByNameCommandRoute = ApiBase + "/device/name/{name}/command"
         ApiCommandRoute           = ApiBase + "/device/name/{name}/{command}"
         ApiCommandAllRoute        = ApiBase + "/device/all"
+        ApiCommandResponseByIdRoute = ApiBase + "/command/response/id/{id}"
 )
 
 // HTTP Headers
 const (
         ContentType     = "Content-Type"
         ContentTypeJSON = "application/json"
+        ContentTypeCBOR = "application/cbor"
+        ContentTypeYAML = "application/yaml"
+        ContentTypeXML  = "application/xml"
+        ContentTypeMultipartForm = "multipart/form-data"
+        Accept          = "Accept"
         CorrelationHeader = "X-Correlation-ID"
 )
 
@@ -57,6 +79,8 @@ const (
         Command  = "command"
         Offset   = "offset"
         Limit    = "limit"
+        Start    = "start"
+        End      = "end"
 )
 
 // Default Values
@@ -96,6 +120,13 @@ const (
         ValueTypeBinary  = "Binary"
 )
 
+// Read/write access of a device resource
+const (
+        ReadWriteR  = "R"
+        ReadWriteW  = "W"
+        ReadWriteRW = "RW"
+)
+
 // DI Container Keys
 const (
         LoggingClientName = "LoggingClient"
@@ -103,6 +134,7 @@ const (
         MessagingClientName = "MessagingClient"
         RegistryClientName  = "RegistryClient"
         ConfigurationName   = "Configuration"
+        HealthRegistryName  = "HealthRegistry"
 )
 
 // Service Version
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Paginate returns the window of items starting at offset and at most limit long, along with
+// len(items) for the response's totalCount. A window past the end is empty rather than an error,
+// and a negative limit takes every item from offset on.
+func Paginate[T any](items []T, offset, limit int) ([]T, int) {
+	total := len(items)
+	start := offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := total
+	if limit >= 0 && limit < total-start {
+		end = start + limit
+	}
+	if items == nil {
+		return []T{}, 0
+	}
+	return items[start:end], total
+}
+
+// ParsePagination reads the offset and limit query parameters of a list request, defaulting to
+// DefaultOffset and DefaultLimit. The error, meant for a 400 reply, names the invalid parameter.
+func ParsePagination(r *http.Request) (int, int, error) {
+	offset, limit := DefaultOffset, DefaultLimit
+	query := r.URL.Query()
+
+	if raw := query.Get(Offset); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q: must be a non-negative integer", raw)
+		}
+		offset = value
+	}
+	if raw := query.Get(Limit); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > MaxLimit {
+			return 0, 0, fmt.Errorf("invalid limit %q: must be between 0 and %d", raw, MaxLimit)
+		}
+		limit = value
+	}
+	return offset, limit, nil
+}
+
+// NewestFirst reports whether an item created at createdA with id idA sorts before one created at
+// createdB with idB: newest first, tie-broken by id. List endpoints use this order so that paging
+// through them is stable.
+func NewestFirst(createdA int64, idA string, createdB int64, idB string) bool {
+	if createdA != createdB {
+		return createdA > createdB
+	}
+	return idA < idB
+}
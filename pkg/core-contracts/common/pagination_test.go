@@ -0,0 +1,71 @@
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name   string
+		items  []int
+		offset int
+		limit  int
+		expect []int
+	}{
+		{"First page", items, 0, 2, []int{1, 2}},
+		{"Middle page", items, 2, 2, []int{3, 4}},
+		{"Last partial page", items, 4, 2, []int{5}},
+		{"Offset at end", items, 5, 2, []int{}},
+		{"Offset past end", items, 10, 2, []int{}},
+		{"Negative offset", items, -1, 2, []int{1, 2}},
+		{"Limit past end", items, 1, 100, []int{2, 3, 4, 5}},
+		{"Zero limit", items, 0, 0, []int{}},
+		{"Nil items", nil, 0, 10, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, total := Paginate(tt.items, tt.offset, tt.limit)
+			assert.Equal(t, tt.expect, page)
+			assert.NotNil(t, page)
+			assert.Equal(t, len(tt.items), total)
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		expectOffset int
+		expectLimit  int
+		expectError  string
+	}{
+		{"Defaults", "", DefaultOffset, DefaultLimit, ""},
+		{"Both set", "?offset=5&limit=50", 5, 50, ""},
+		{"Limit at max", "?limit=1000", DefaultOffset, MaxLimit, ""},
+		{"Limit over max", "?limit=1001", 0, 0, `invalid limit "1001": must be between 0 and 1000`},
+		{"Negative limit", "?limit=-1", 0, 0, `invalid limit "-1": must be between 0 and 1000`},
+		{"Negative offset", "?offset=-1", 0, 0, `invalid offset "-1": must be a non-negative integer`},
+		{"Non-numeric offset", "?offset=abc", 0, 0, `invalid offset "abc": must be a non-negative integer`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, limit, err := ParsePagination(httptest.NewRequest("GET", "/api/v3/device/all"+tt.query, nil))
+			if tt.expectError != "" {
+				require.EqualError(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectOffset, offset)
+			assert.Equal(t, tt.expectLimit, limit)
+		})
+	}
+}
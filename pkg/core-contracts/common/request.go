@@ -0,0 +1,29 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DecodeJSON decodes the JSON request body into v. On failure it writes the error response and
+// returns the error, so handlers only need to log it and return.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		WriteDecodeError(w, err, "Invalid JSON")
+	}
+	return err
+}
+
+// WriteDecodeError reports a request body that could not be read: 413 when it was cut off by the
+// size limit, otherwise 400 with the given message
+func WriteDecodeError(w http.ResponseWriter, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WriteError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	WriteError(w, http.StatusBadRequest, message)
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ErrorResponse is the JSON envelope returned for failed requests
+type ErrorResponse struct {
+	ApiVersion string   `json:"apiVersion"`
+	StatusCode int      `json:"statusCode"`
+	Message    string   `json:"message"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// WriteError replies to the request with the given status code and message in the standard JSON envelope
+func WriteError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set(ContentType, ContentTypeJSON)
+	// Drop any length set for a body that is no longer sent
+	w.Header().Del("Content-Length")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		ApiVersion: ServiceVersion,
+		StatusCode: statusCode,
+		Message:    message,
+	})
+}
+
+// WriteValidationErrors replies with 422 Unprocessable Entity, listing every problem found in the request
+func WriteValidationErrors(w http.ResponseWriter, message string, problems []string) {
+	w.Header().Set(ContentType, ContentTypeJSON)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		ApiVersion: ServiceVersion,
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    message,
+		Errors:     problems,
+	})
+}
+
+// WriteVersionConflict replies with 409 Conflict to an update based on a stale version of what,
+// so the client can re-read it and retry
+func WriteVersionConflict(w http.ResponseWriter, what string, sent, current int64) {
+	WriteError(w, http.StatusConflict, fmt.Sprintf("%s was modified: version %d was sent but the current version is %d", what, sent, current))
+}
@@ -20,6 +20,7 @@ type Device struct {
 	Protocols      map[string]ProtocolProperties `json:"protocols"`
 	AutoEvents     []AutoEvent                   `json:"autoEvents,omitempty"`
 	Notify         bool                          `json:"notify,omitempty"`
+	Version        int64                         `json:"version"` // incremented on every update; updates must send the version they read
 	Created        int64                         `json:"created"`
 	Modified       int64                         `json:"modified"`
 }
@@ -61,6 +61,14 @@ func (r *RedisMessageClient) Connect() error {
 	return nil
 }
 
+// Ping checks that the message bus is reachable; suitable as a health check
+func (r *RedisMessageClient) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("message bus unreachable: %w", err)
+	}
+	return nil
+}
+
 // Disconnect closes the Redis connection
 func (r *RedisMessageClient) Disconnect() error {
 	r.cancel()
@@ -171,13 +179,15 @@ func (r *RedisMessageClient) handleMessage(topic string, message redis.XMessage)
 
 // MessageTopics defines common message topics
 var MessageTopics = struct {
-	Events      string
-	Commands    string
-	Metadata    string
-	Metrics     string
+	Events        string
+	Commands      string
+	Metadata      string
+	Metrics       string
+	Notifications string
 }{
-	Events:   "edgex.events",
-	Commands: "edgex.commands",
-	Metadata: "edgex.metadata",
-	Metrics:  "edgex.metrics",
+	Events:        "edgex.events",
+	Commands:      "edgex.commands",
+	Metadata:      "edgex.metadata",
+	Metrics:       "edgex.metrics",
+	Notifications: "edgex.notifications",
 }
\ No newline at end of file
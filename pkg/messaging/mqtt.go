@@ -0,0 +1,177 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults applied to MQTTOptions fields left zero
+const (
+	DefaultMQTTKeepAlive      = 60 * time.Second
+	DefaultMQTTConnectTimeout = 10 * time.Second
+)
+
+// mqttPublishAttempts is how many times Publish sends a message that was not acknowledged in time
+const mqttPublishAttempts = 2
+
+// ErrMQTTClosed is returned by Publish after Close
+var ErrMQTTClosed = errors.New("mqtt client closed")
+
+// MQTTOptions configures an MQTTClient
+type MQTTOptions struct {
+	// Address is the broker's host:port
+	Address  string
+	ClientID string
+	Username string
+	Password string
+	// KeepAlive is the interval announced to the broker, in whole seconds; zero means DefaultMQTTKeepAlive
+	KeepAlive time.Duration
+	// ConnectTimeout bounds connecting, each PUBACK and each PINGRESP; zero means DefaultMQTTConnectTimeout
+	ConnectTimeout time.Duration
+}
+
+// MQTTClient publishes to an MQTT 3.1.1 broker at QoS 0 or 1 using the Eclipse Paho client.
+// Paho keeps the connection alive and drops it when the broker stops answering pings; the next
+// Publish reconnects, so callers need not track the broker's health.
+type MQTTClient struct {
+	options MQTTOptions
+	logger  *logrus.Logger
+	client  mqtt.Client
+
+	mutex  sync.Mutex
+	closed bool
+	// lost is closed once Paho has finished tearing down the current connection after losing it
+	lost chan struct{}
+}
+
+// NewMQTTClient creates an MQTT client; it connects on Connect or the first Publish
+func NewMQTTClient(options MQTTOptions, logger *logrus.Logger) *MQTTClient {
+	if options.KeepAlive <= 0 {
+		options.KeepAlive = DefaultMQTTKeepAlive
+	}
+	if options.ConnectTimeout <= 0 {
+		options.ConnectTimeout = DefaultMQTTConnectTimeout
+	}
+
+	c := &MQTTClient{options: options, logger: logger}
+	clientOptions := mqtt.NewClientOptions().
+		AddBroker("tcp://" + options.Address).
+		SetClientID(options.ClientID).
+		SetUsername(options.Username).
+		SetPassword(options.Password).
+		SetProtocolVersion(4).
+		SetKeepAlive(options.KeepAlive).
+		SetPingTimeout(options.ConnectTimeout).
+		SetConnectTimeout(options.ConnectTimeout).
+		SetWriteTimeout(options.ConnectTimeout).
+		// Publish resends unacknowledged messages itself, so a lost connection must fail their
+		// tokens rather than leave Paho to replay them from a kept session
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectionLostHandler(c.connectionLost)
+	c.client = mqtt.NewClient(clientOptions)
+	return c
+}
+
+// connectionLost is called by Paho once a dropped connection has been torn down
+func (c *MQTTClient) connectionLost(_ mqtt.Client, err error) {
+	c.logger.Warnf("MQTT connection to %s lost: %v", c.options.Address, err)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.lost != nil {
+		close(c.lost)
+		c.lost = nil
+	}
+}
+
+// Connect establishes the broker connection if it is not already up
+func (c *MQTTClient) Connect() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return ErrMQTTClosed
+	}
+	return c.connectLocked()
+}
+
+// connectLocked connects unless Paho already holds a connection, first waiting for a lost one to be
+// torn down since Paho refuses to connect until then
+func (c *MQTTClient) connectLocked() error {
+	if c.client.IsConnected() {
+		return nil
+	}
+	if lost := c.lost; lost != nil {
+		c.mutex.Unlock()
+		select {
+		case <-lost:
+		case <-time.After(c.options.ConnectTimeout):
+		}
+		c.mutex.Lock()
+		if c.closed {
+			return ErrMQTTClosed
+		}
+	}
+
+	token := c.client.Connect()
+	if !token.WaitTimeout(c.options.ConnectTimeout) {
+		return fmt.Errorf("no CONNACK from %s within %v", c.options.Address, c.options.ConnectTimeout)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", c.options.Address, err)
+	}
+	c.lost = make(chan struct{})
+	return nil
+}
+
+// Publish sends payload to topic. At QoS 1 it waits for the broker's PUBACK, sending the message
+// again, over a new connection if the old one was lost, when none arrives in time; the broker may
+// then see it twice, as QoS 1 allows.
+func (c *MQTTClient) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	if qos > 1 {
+		return fmt.Errorf("unsupported QoS %d", qos)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.publishOnce(topic, qos, retained, payload)
+		if err == nil || errors.Is(err, ErrMQTTClosed) || attempt == mqttPublishAttempts {
+			return err
+		}
+		c.logger.Warnf("MQTT publish to %s on %s failed, sending again: %v", topic, c.options.Address, err)
+	}
+}
+
+// publishOnce connects if needed and sends payload, waiting at QoS 1 for its PUBACK
+func (c *MQTTClient) publishOnce(topic string, qos byte, retained bool, payload []byte) error {
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return ErrMQTTClosed
+	}
+	err := c.connectLocked()
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	token := c.client.Publish(topic, qos, retained, payload)
+	if !token.WaitTimeout(c.options.ConnectTimeout) {
+		return fmt.Errorf("no PUBACK from %s within %v", c.options.Address, c.options.ConnectTimeout)
+	}
+	return token.Error()
+}
+
+// Close disconnects from the broker; later publishes fail with ErrMQTTClosed
+func (c *MQTTClient) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	if c.client.IsConnected() {
+		c.client.Disconnect(uint(c.options.ConnectTimeout / time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,352 @@
+package messaging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MQTT control packet types (MQTT 3.1.1 section 2.2.1), already shifted into the high nibble
+const (
+	mqttConnect    byte = 1 << 4
+	mqttConnack    byte = 2 << 4
+	mqttPublish    byte = 3 << 4
+	mqttPuback     byte = 4 << 4
+	mqttPingreq    byte = 12 << 4
+	mqttPingresp   byte = 13 << 4
+	mqttDisconnect byte = 14 << 4
+)
+
+// readMQTTPacket reads one packet, returning its fixed header byte and body
+func readMQTTPacket(reader *bufio.Reader) (byte, []byte, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7F) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// publishedMessage is a PUBLISH packet as seen by the fake broker
+type publishedMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  string
+}
+
+// fakeBroker accepts MQTT connections on a loopback port and records what is published
+type fakeBroker struct {
+	listener net.Listener
+	password string // CONNECT is refused with code 4 unless it carries this password, when set
+
+	mutex sync.Mutex
+	// dropBeforeAck is how many QoS 1 publishes to answer by closing the connection instead of acknowledging
+	dropBeforeAck int
+	// skipAcks is how many QoS 1 publishes to leave unacknowledged on a connection that stays up
+	skipAcks int
+	// ignorePings stops the broker answering PINGREQ
+	ignorePings bool
+
+	conns     []net.Conn
+	clientIDs []string
+	usernames []string
+	messages  []publishedMessage
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	broker := &fakeBroker{listener: listener}
+	go broker.accept()
+	t.Cleanup(func() {
+		listener.Close()
+		broker.dropConnections()
+	})
+	return broker
+}
+
+func (b *fakeBroker) accept() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mutex.Lock()
+		b.conns = append(b.conns, conn)
+		b.mutex.Unlock()
+		go b.serve(conn)
+	}
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return
+		}
+		switch header & 0xF0 {
+		case mqttConnect:
+			code := b.connect(body)
+			conn.Write([]byte{mqttConnack, 2, 0, code})
+			if code != 0 {
+				return
+			}
+		case mqttPublish:
+			qos := (header >> 1) & 0x03
+			topicLength := int(binary.BigEndian.Uint16(body))
+			message := publishedMessage{
+				topic:    string(body[2 : 2+topicLength]),
+				qos:      qos,
+				retained: header&1 == 1,
+			}
+			rest := body[2+topicLength:]
+			var packetID []byte
+			if qos > 0 {
+				packetID, rest = rest[:2], rest[2:]
+			}
+			message.payload = string(rest)
+
+			b.mutex.Lock()
+			b.messages = append(b.messages, message)
+			drop, skip := false, false
+			if qos > 0 && b.dropBeforeAck > 0 {
+				b.dropBeforeAck--
+				drop = true
+			} else if qos > 0 && b.skipAcks > 0 {
+				b.skipAcks--
+				skip = true
+			}
+			b.mutex.Unlock()
+
+			if drop {
+				return
+			}
+			if qos > 0 && !skip {
+				conn.Write(append([]byte{mqttPuback, 2}, packetID...))
+			}
+		case mqttPingreq:
+			b.mutex.Lock()
+			ignore := b.ignorePings
+			b.mutex.Unlock()
+			if !ignore {
+				conn.Write([]byte{mqttPingresp, 0})
+			}
+		case mqttDisconnect:
+			return
+		}
+	}
+}
+
+// connect records the CONNECT payload and returns the CONNACK return code
+func (b *fakeBroker) connect(body []byte) byte {
+	flags := body[7]
+	fields := readStrings(body[10:])
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.clientIDs = append(b.clientIDs, fields[0])
+	if flags&0x80 != 0 {
+		b.usernames = append(b.usernames, fields[1])
+	}
+	if b.password != "" && (flags&0x40 == 0 || fields[2] != b.password) {
+		return 4
+	}
+	return 0
+}
+
+func readStrings(b []byte) []string {
+	var fields []string
+	for len(b) >= 2 {
+		length := int(binary.BigEndian.Uint16(b))
+		fields = append(fields, string(b[2:2+length]))
+		b = b[2+length:]
+	}
+	return fields
+}
+
+func (b *fakeBroker) dropConnections() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) published() []publishedMessage {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]publishedMessage(nil), b.messages...)
+}
+
+func (b *fakeBroker) connections() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.clientIDs)
+}
+
+func TestMQTTClient_Publish(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.password = "secret"
+	client := NewMQTTClient(MQTTOptions{
+		Address:  broker.listener.Addr().String(),
+		ClientID: "exporter",
+		Username: "edgex",
+		Password: "secret",
+	}, logrus.New())
+	defer client.Close()
+
+	require.NoError(t, client.Publish("edgex/qos0", 0, false, []byte("first")))
+	require.NoError(t, client.Publish("edgex/qos1", 1, true, []byte("second")))
+
+	// The QoS 1 publish returned only after its PUBACK, so both messages have arrived
+	assert.Equal(t, []publishedMessage{
+		{topic: "edgex/qos0", qos: 0, payload: "first"},
+		{topic: "edgex/qos1", qos: 1, retained: true, payload: "second"},
+	}, broker.published())
+	assert.Equal(t, []string{"exporter"}, broker.clientIDs)
+	assert.Equal(t, []string{"edgex"}, broker.usernames)
+}
+
+func TestMQTTClient_ConnectRefused(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.password = "secret"
+	client := NewMQTTClient(MQTTOptions{
+		Address:  broker.listener.Addr().String(),
+		ClientID: "exporter",
+		Username: "edgex",
+		Password: "wrong",
+	}, logrus.New())
+
+	err := client.Connect()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad user name or password")
+}
+
+func TestMQTTClient_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+
+	client := NewMQTTClient(MQTTOptions{Address: address, ClientID: "exporter", ConnectTimeout: time.Second}, logrus.New())
+	assert.Error(t, client.Publish("edgex/export", 0, false, []byte("lost")))
+}
+
+func TestMQTTClient_Reconnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	client := NewMQTTClient(MQTTOptions{Address: broker.listener.Addr().String(), ClientID: "exporter"}, logrus.New())
+	defer client.Close()
+
+	require.NoError(t, client.Publish("edgex/export", 1, false, []byte("before")))
+	broker.dropConnections()
+
+	// The client may not have noticed the drop yet; the publish must reconnect either way
+	require.Eventually(t, func() bool {
+		return client.Publish("edgex/export", 1, false, []byte("after")) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	messages := broker.published()
+	assert.Equal(t, "after", messages[len(messages)-1].payload)
+	assert.Equal(t, 2, broker.connections())
+}
+
+func TestMQTTClient_Closed(t *testing.T) {
+	broker := newFakeBroker(t)
+	client := NewMQTTClient(MQTTOptions{Address: broker.listener.Addr().String(), ClientID: "exporter"}, logrus.New())
+	require.NoError(t, client.Connect())
+	require.NoError(t, client.Close())
+
+	assert.ErrorIs(t, client.Publish("edgex/export", 0, false, []byte("late")), ErrMQTTClosed)
+	assert.Error(t, client.Publish("edgex/export", 2, false, []byte("unsupported")))
+}
+
+// payloads returns the payloads the broker received, in order
+func (b *fakeBroker) payloads() []string {
+	var payloads []string
+	for _, message := range b.published() {
+		payloads = append(payloads, message.payload)
+	}
+	return payloads
+}
+
+func TestMQTTClient_DropWhileWaitingForAck(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.dropBeforeAck = 1
+	client := NewMQTTClient(MQTTOptions{Address: broker.listener.Addr().String(), ClientID: "exporter", ConnectTimeout: 2 * time.Second}, logrus.New())
+	defer client.Close()
+
+	// The broker drops the connection instead of acknowledging; the message is sent again once reconnected
+	start := time.Now()
+	require.NoError(t, client.Publish("edgex/export", 1, false, []byte("reading")))
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.GreaterOrEqual(t, broker.connections(), 2)
+	assert.Equal(t, []string{"reading", "reading"}, broker.payloads())
+}
+
+func TestMQTTClient_MissingPuback(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.skipAcks = 1
+	client := NewMQTTClient(MQTTOptions{Address: broker.listener.Addr().String(), ClientID: "exporter", ConnectTimeout: 200 * time.Millisecond}, logrus.New())
+	defer client.Close()
+
+	// The first PUBACK never comes, so the message is sent again and that one is acknowledged
+	require.NoError(t, client.Publish("edgex/export", 1, false, []byte("reading")))
+	assert.Equal(t, []string{"reading", "reading"}, broker.payloads())
+
+	// A broker that never acknowledges fails the publish
+	broker.mutex.Lock()
+	broker.skipAcks = mqttPublishAttempts
+	broker.mutex.Unlock()
+	assert.Error(t, client.Publish("edgex/export", 1, false, []byte("lost")))
+}
+
+func TestMQTTClient_MissingPingresp(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.ignorePings = true
+	client := NewMQTTClient(MQTTOptions{
+		Address:        broker.listener.Addr().String(),
+		ClientID:       "exporter",
+		KeepAlive:      time.Second,
+		ConnectTimeout: 500 * time.Millisecond,
+	}, logrus.New())
+	defer client.Close()
+	require.NoError(t, client.Connect())
+
+	// An unanswered ping means the connection is half-open, so the client drops it...
+	require.Eventually(t, func() bool { return !client.client.IsConnected() }, 10*time.Second, 50*time.Millisecond)
+
+	// ...and the next publish reconnects
+	require.NoError(t, client.Publish("edgex/export", 1, false, []byte("after")))
+	assert.Equal(t, 2, broker.connections())
+	assert.Equal(t, []string{"after"}, broker.payloads())
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric exposed by EdgeX services
+const Namespace = "edgex"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	requestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests handled, by route, status code and status class.",
+	}, []string{"service", "method", "route", "code", "class"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency in seconds, by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method", "route"})
+
+	requestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	}, []string{"service"})
+
+	// gauges holds domain gauges and counters by name so a service can re-register on restart of its handlers
+	gauges      = make(map[string]prometheus.Collector)
+	gaugesMutex sync.Mutex
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestCount,
+		requestDuration,
+		requestsInFlight,
+	)
+}
+
+// Register adds a custom collector to the registry served on /metrics
+func Register(collector prometheus.Collector) error {
+	return registry.Register(collector)
+}
+
+// Unregister removes a collector previously added with Register
+func Unregister(collector prometheus.Collector) bool {
+	return registry.Unregister(collector)
+}
+
+// RegisterGaugeFunc exposes a domain gauge such as the number of stored events, sampled from fn at scrape time.
+// Registering the same name again replaces the previous gauge.
+func RegisterGaugeFunc(name, help string, fn func() float64) error {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      name,
+		Help:      help,
+	}, fn)
+
+	gaugesMutex.Lock()
+	defer gaugesMutex.Unlock()
+
+	if previous, exists := gauges[name]; exists {
+		registry.Unregister(previous)
+	}
+	if err := registry.Register(gauge); err != nil {
+		return err
+	}
+	gauges[name] = gauge
+	return nil
+}
+
+// RegisterCounterFunc exposes a domain counter such as the number of evicted events, sampled from fn
+// at scrape time. fn must never decrease. Registering the same name again replaces the previous counter.
+func RegisterCounterFunc(name, help string, fn func() float64) error {
+	counter := prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      name,
+		Help:      help,
+	}, fn)
+
+	gaugesMutex.Lock()
+	defer gaugesMutex.Unlock()
+
+	if previous, exists := gauges[name]; exists {
+		registry.Unregister(previous)
+	}
+	if err := registry.Register(counter); err != nil {
+		return err
+	}
+	gauges[name] = counter
+	return nil
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records request count, latency and in-flight requests for the service.
+// Requests are labelled by route template rather than raw path to keep cardinality bounded.
+func Middleware(serviceName string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.URL.Path
+			if current := mux.CurrentRoute(r); current != nil {
+				if template, err := current.GetPathTemplate(); err == nil {
+					route = template
+				}
+			}
+
+			inFlight := requestsInFlight.WithLabelValues(serviceName)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			requestDuration.WithLabelValues(serviceName, r.Method, route).Observe(time.Since(start).Seconds())
+			requestCount.WithLabelValues(serviceName, r.Method, route, strconv.Itoa(recorder.status), statusClass(recorder.status)).Inc()
+		})
+	}
+}
+
+// statusClass groups a status code by its first digit, such as 4xx, so error rates can be summed
+// without listing every code
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it on
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write marks the header as written with the implicit 200
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer so http.ResponseController can flush streaming responses
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
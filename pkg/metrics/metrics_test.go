@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, router *mux.Router) string {
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func newMetricsRouter(serviceName string) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(Middleware(serviceName))
+	router.Handle("/metrics", Handler()).Methods("GET")
+	router.HandleFunc("/api/v3/device/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Device not found", http.StatusNotFound)
+	}).Methods("GET")
+	return router
+}
+
+func TestMiddleware_CountsRequests(t *testing.T) {
+	router := newMetricsRouter("metrics-test")
+	counter := requestCount.WithLabelValues("metrics-test", "GET", "/api/v3/device/name/{name}", "404", "4xx")
+	before := testutil.ToFloat64(counter)
+
+	for _, name := range []string{"a", "b", "c"} {
+		req, err := http.NewRequest("GET", "/api/v3/device/name/"+name, nil)
+		require.NoError(t, err)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, before+3, testutil.ToFloat64(counter))
+
+	body := scrape(t, router)
+	assert.Contains(t, body, `edgex_http_requests_total{class="4xx",code="404",method="GET",route="/api/v3/device/name/{name}",service="metrics-test"}`)
+	assert.Contains(t, body, `edgex_http_request_duration_seconds_bucket{method="GET",route="/api/v3/device/name/{name}",service="metrics-test"`)
+	assert.Contains(t, body, "edgex_http_requests_in_flight")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestRegisterGaugeFunc(t *testing.T) {
+	router := newMetricsRouter("metrics-test")
+
+	require.NoError(t, RegisterGaugeFunc("test_widgets", "Widgets.", func() float64 { return 7 }))
+	assert.Contains(t, scrape(t, router), "edgex_test_widgets 7")
+
+	// Re-registering replaces the previous gauge
+	require.NoError(t, RegisterGaugeFunc("test_widgets", "Widgets.", func() float64 { return 9 }))
+	body := scrape(t, router)
+	assert.Contains(t, body, "edgex_test_widgets 9")
+	assert.Equal(t, 1, strings.Count(body, "edgex_test_widgets 9"))
+}
+
+func TestRegisterCounterFunc(t *testing.T) {
+	router := newMetricsRouter("metrics-test")
+
+	evicted := 3.0
+	require.NoError(t, RegisterCounterFunc("test_evictions_total", "Evictions.", func() float64 { return evicted }))
+	body := scrape(t, router)
+	assert.Contains(t, body, "# TYPE edgex_test_evictions_total counter")
+	assert.Contains(t, body, "edgex_test_evictions_total 3")
+
+	evicted = 5
+	assert.Contains(t, scrape(t, router), "edgex_test_evictions_total 5")
+}
+
+func TestRegister_CustomCollector(t *testing.T) {
+	router := newMetricsRouter("metrics-test")
+
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Namespace: Namespace, Name: "test_custom_total", Help: "Custom."})
+	require.NoError(t, Register(counter))
+	defer Unregister(counter)
+	counter.Add(2)
+
+	assert.Contains(t, scrape(t, router), "edgex_test_custom_total 2")
+	assert.Error(t, Register(counter))
+}
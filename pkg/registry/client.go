@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -101,6 +102,15 @@ func (c *ConsulRegistryClient) Register(service ServiceRegistration) error {
 	return nil
 }
 
+// Ping checks that the registry agent is reachable; suitable as a health check
+func (c *ConsulRegistryClient) Ping(ctx context.Context) error {
+	options := (&api.QueryOptions{}).WithContext(ctx)
+	if _, err := c.client.Status().LeaderWithQueryOptions(options); err != nil {
+		return fmt.Errorf("registry unreachable: %w", err)
+	}
+	return nil
+}
+
 // Deregister removes a service from the registry
 func (c *ConsulRegistryClient) Deregister(serviceID string) error {
 	err := c.client.Agent().ServiceDeregister(serviceID)
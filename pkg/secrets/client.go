@@ -170,6 +170,12 @@ func (sp *SecretProvider) GetMessagingCredentials(serviceName string) (username,
 	return username, password, nil
 }
 
+// GetServiceSecrets retrieves the named secret of a service, e.g. the credentials of an external provider
+func (sp *SecretProvider) GetServiceSecrets(serviceName, secretName string, keys ...string) (map[string]string, error) {
+	path := fmt.Sprintf("edgex/%s/%s", serviceName, secretName)
+	return sp.client.GetSecret(path, keys...)
+}
+
 // StoreServiceCredentials stores credentials for a service
 func (sp *SecretProvider) StoreServiceCredentials(serviceName, credType string, credentials map[string]string) error {
 	path := fmt.Sprintf("edgex/%s/%s", serviceName, credType)
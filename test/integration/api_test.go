@@ -1,9 +1,10 @@
+//go:build integration
+
 package integration
 
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"testing"
 	"time"
@@ -129,9 +130,9 @@ func (suite *EdgeXAPITestSuite) TestCoreMetadataAPIFlow() {
 		ServiceName: "integration-test-service",
 		Protocols: map[string]models.ProtocolProperties{
 			"modbus": {
-				"Address": "192.168.1.100",
-				"Port":    "502",
-				"UnitID":  "1",
+				Address: "192.168.1.100",
+				Port:    "502",
+				Other:   map[string]interface{}{"UnitID": "1"},
 			},
 		},
 		Labels: []string{"test", "integration"},
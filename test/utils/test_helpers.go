@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/common"
 	"github.com/Hell0W0rID/edgex-go-clone/pkg/core-contracts/models"
 )
 
@@ -65,9 +66,9 @@ func (h *TestHelper) CreateTestDevice(name, profileName, serviceName string) mod
 		ServiceName: serviceName,
 		Protocols: map[string]models.ProtocolProperties{
 			"modbus": {
-				"Address": "192.168.1.100",
-				"Port":    "502",
-				"UnitID":  "1",
+				Address: "192.168.1.100",
+				Port:    "502",
+				Other:   map[string]interface{}{"UnitID": "1"},
 			},
 		},
 		Labels: []string{"test"},
@@ -81,18 +82,26 @@ func (h *TestHelper) CreateTestDeviceProfile(name string) models.DeviceProfile {
 		Description:  "Test device profile",
 		Manufacturer: "Test Manufacturer",
 		Model:        "Test Model",
+		DeviceResources: []models.DeviceResource{
+			{
+				Name: "Temperature",
+				Properties: models.ResourceProperties{
+					ValueType: common.ValueTypeFloat64,
+					ReadWrite: common.ReadWriteR,
+				},
+			},
+		},
 		DeviceCommands: []models.DeviceCommand{
 			{
 				Name: "Temperature",
-				Get:  true,
-				Set:  false,
+				ReadWrite: "R",
 			},
 		},
-		CoreCommands: []models.CoreCommand{
+		CoreCommands: []models.Command{
 			{
 				Name: "Temperature",
 				Get:  true,
-				Set:  false,
+				Put:  false,
 			},
 		},
 	}